@@ -0,0 +1,209 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/dapplink-labs/multichain-sync-account/common/clock"
+	"github.com/dapplink-labs/multichain-sync-account/common/tasks"
+	"github.com/dapplink-labs/multichain-sync-account/config"
+)
+
+type Severity string
+
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Alert 一条告警，同一规则下 Subject 相同的告警视为同一个事件，按 Key 去重
+type Alert struct {
+	Rule      string            `json:"rule"`
+	Severity  Severity          `json:"severity"`
+	Subject   string            `json:"subject"`
+	Summary   string            `json:"summary"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Resolved  bool              `json:"resolved"`
+	Timestamp int64             `json:"timestamp"`
+}
+
+func (a *Alert) Key() string {
+	return a.Rule + "/" + a.Subject
+}
+
+func (a *Alert) Title() string {
+	if a.Resolved {
+		return fmt.Sprintf("[resolved] %s %s", a.Rule, a.Subject)
+	}
+	return fmt.Sprintf("[%s] %s %s", a.Severity, a.Rule, a.Subject)
+}
+
+// Sink 告警的发送渠道
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, alert *Alert) error
+}
+
+// alertState 正在触发的事件，CoolDown 内重复触发不再发送
+type alertState struct {
+	alert    *Alert
+	lastSent time.Time
+}
+
+const defaultQueueSize = 256
+
+// Alerter 按规则评估观测值并去重后异步发送到所有渠道，nil 的 Alerter 不做任何事，未启用告警时可以直接传 nil
+type Alerter struct {
+	rules    Rules
+	sinks    []Sink
+	clock    clock.Clock
+	coolDown time.Duration
+
+	mu         sync.Mutex
+	firing     map[string]*alertState
+	broadcasts map[string]*outcomeWindow
+
+	queue          chan *Alert
+	resourceCtx    context.Context
+	resourceCancel context.CancelFunc
+	tasks          tasks.Group
+}
+
+func NewAlerter(rules Rules, coolDown time.Duration, sinks []Sink, clk clock.Clock) *Alerter {
+	if clk == nil {
+		clk = clock.SystemClock
+	}
+	resCtx, resCancel := context.WithCancel(context.Background())
+	return &Alerter{
+		rules:          rules.withDefaults(),
+		sinks:          sinks,
+		clock:          clk,
+		coolDown:       coolDown,
+		firing:         make(map[string]*alertState),
+		broadcasts:     make(map[string]*outcomeWindow),
+		queue:          make(chan *Alert, defaultQueueSize),
+		resourceCtx:    resCtx,
+		resourceCancel: resCancel,
+		tasks: tasks.Group{HandleCrit: func(err error) {
+			log.Error("critical error in alerter", "err", err)
+		}},
+	}
+}
+
+// NewAlerterFromConfig 未启用告警时返回 nil
+func NewAlerterFromConfig(cfg config.AlertConfig) (*Alerter, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+	sinks, err := NewSinks(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(sinks) == 0 {
+		return nil, errors.New("alert enabled without any sink configured")
+	}
+	rules := Rules{
+		SyncLagBlocks:        cfg.SyncLagBlocks,
+		BroadcastFailureRate: cfg.BroadcastFailureRate,
+		BroadcastWindow:      cfg.BroadcastWindow,
+		NotifyBacklogAge:     cfg.NotifyBacklogAge,
+	}
+	return NewAlerter(rules, cfg.CoolDown, sinks, clock.SystemClock), nil
+}
+
+func (a *Alerter) Start() error {
+	if a == nil {
+		return nil
+	}
+	log.Info("start alerter......", "sinks", len(a.sinks))
+	a.tasks.Go(func() error {
+		for {
+			select {
+			case alert := <-a.queue:
+				a.dispatch(alert)
+			case <-a.resourceCtx.Done():
+				log.Info("stop alerter")
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+func (a *Alerter) Close() error {
+	if a == nil {
+		return nil
+	}
+	a.resourceCancel()
+	if err := a.tasks.Wait(); err != nil {
+		return fmt.Errorf("failed to await alerter %w", err)
+	}
+	return nil
+}
+
+// Raise 触发告警，同一事件在 CoolDown 内只发送一次
+func (a *Alerter) Raise(alert *Alert) {
+	if a == nil {
+		return
+	}
+	now := a.clock.Now()
+	alert.Timestamp = now.Unix()
+	a.mu.Lock()
+	state, ok := a.firing[alert.Key()]
+	if ok && now.Sub(state.lastSent) < a.coolDown {
+		state.alert = alert
+		a.mu.Unlock()
+		return
+	}
+	a.firing[alert.Key()] = &alertState{alert: alert, lastSent: now}
+	a.mu.Unlock()
+	a.enqueue(alert)
+}
+
+// Resolve 事件恢复时发送一次恢复通知，之后再次触发会立即发送
+func (a *Alerter) Resolve(rule string, subject string) {
+	if a == nil {
+		return
+	}
+	key := rule + "/" + subject
+	a.mu.Lock()
+	state, ok := a.firing[key]
+	delete(a.firing, key)
+	a.mu.Unlock()
+	if !ok {
+		return
+	}
+	resolved := *state.alert
+	resolved.Resolved = true
+	resolved.Summary = "resolved: " + state.alert.Summary
+	resolved.Timestamp = a.clock.Now().Unix()
+	a.enqueue(&resolved)
+}
+
+func (a *Alerter) enqueue(alert *Alert) {
+	log.Warn("raise alert", "rule", alert.Rule, "subject", alert.Subject, "severity", alert.Severity, "resolved", alert.Resolved, "summary", alert.Summary)
+	select {
+	case a.queue <- alert:
+	default:
+		log.Error("alert queue is full, drop alert", "rule", alert.Rule, "subject", alert.Subject)
+	}
+}
+
+func (a *Alerter) dispatch(alert *Alert) {
+	var result error
+	for _, sink := range a.sinks {
+		ctx, cancel := context.WithTimeout(a.resourceCtx, 10*time.Second)
+		if err := sink.Send(ctx, alert); err != nil {
+			result = errors.Join(result, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+		cancel()
+	}
+	if result != nil {
+		log.Error("send alert fail", "rule", alert.Rule, "subject", alert.Subject, "err", result)
+	}
+}
@@ -0,0 +1,164 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapplink-labs/multichain-sync-account/common/clock"
+)
+
+func newTestAlerter(rules Rules) (*Alerter, *clock.DeterministicClock) {
+	clk := clock.NewDeterministicClock(time.Unix(1700000000, 0))
+	return NewAlerter(rules, time.Minute, nil, clk), clk
+}
+
+// drain 取出尚未发送的告警
+func drain(a *Alerter) []*Alert {
+	var alerts []*Alert
+	for {
+		select {
+		case alert := <-a.queue:
+			alerts = append(alerts, alert)
+		default:
+			return alerts
+		}
+	}
+}
+
+func TestRaiseDeduplicatesWithinCoolDown(t *testing.T) {
+	a, clk := newTestAlerter(Rules{SyncLagBlocks: 10})
+
+	a.SyncLag("ethereum", 100, 50)
+	a.SyncLag("ethereum", 101, 50)
+	require.Len(t, drain(a), 1)
+
+	clk.AdvanceTime(time.Minute)
+	a.SyncLag("ethereum", 102, 50)
+	alerts := drain(a)
+	require.Len(t, alerts, 1)
+	require.Equal(t, "102", alerts[0].Labels["latest"])
+}
+
+func TestResolveSendsOnceAndResetsCoolDown(t *testing.T) {
+	a, _ := newTestAlerter(Rules{SyncLagBlocks: 10})
+
+	a.SyncLag("ethereum", 100, 95)
+	require.Empty(t, drain(a))
+
+	a.SyncLag("ethereum", 100, 50)
+	a.SyncLag("ethereum", 100, 95)
+	a.SyncLag("ethereum", 100, 96)
+	alerts := drain(a)
+	require.Len(t, alerts, 2)
+	require.False(t, alerts[0].Resolved)
+	require.True(t, alerts[1].Resolved)
+
+	a.SyncLag("ethereum", 100, 50)
+	require.Len(t, drain(a), 1)
+}
+
+func TestBroadcastFailureRate(t *testing.T) {
+	a, _ := newTestAlerter(Rules{BroadcastFailureRate: 0.5, BroadcastWindow: 4})
+	failed := errors.New("nonce too low")
+
+	a.BroadcastResult("ethereum", failed)
+	require.Empty(t, drain(a), "not enough samples")
+	a.BroadcastResult("ethereum", failed)
+	alerts := drain(a)
+	require.Len(t, alerts, 1)
+	require.Equal(t, RuleBroadcastFailure, alerts[0].Rule)
+	require.Equal(t, "nonce too low", alerts[0].Labels["last_error"])
+
+	for i := 0; i < 3; i++ {
+		a.BroadcastResult("ethereum", nil)
+	}
+	alerts = drain(a)
+	require.Len(t, alerts, 1)
+	require.True(t, alerts[0].Resolved)
+}
+
+func TestNotifyBacklogAndHotWallet(t *testing.T) {
+	a, clk := newTestAlerter(Rules{NotifyBacklogAge: 10 * time.Minute})
+
+	a.NotifyBacklog("ethereum", "business", time.Time{})
+	a.NotifyBacklog("ethereum", "business", clk.Now().Add(-5*time.Minute))
+	require.Empty(t, drain(a))
+	a.NotifyBacklog("ethereum", "business", clk.Now().Add(-15*time.Minute))
+	require.Len(t, drain(a), 1)
+
+	a.HotWalletBalance("ethereum", "business", "0xhot", "", big.NewInt(5), nil)
+	a.HotWalletBalance("ethereum", "business", "0xhot", "", big.NewInt(5), big.NewInt(5))
+	require.Empty(t, drain(a))
+	a.HotWalletBalance("ethereum", "business", "0xhot", "", big.NewInt(4), big.NewInt(5))
+	alerts := drain(a)
+	require.Len(t, alerts, 1)
+	require.Equal(t, RuleHotWalletBelowMin, alerts[0].Rule)
+}
+
+func TestNilAlerter(t *testing.T) {
+	var a *Alerter
+	require.NoError(t, a.Start())
+	a.SyncLag("ethereum", 1000, 0)
+	a.BroadcastResult("ethereum", errors.New("fail"))
+	require.NoError(t, a.Close())
+}
+
+func TestWebhookAndSlackSinks(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	alert := &Alert{Rule: RuleSyncLag, Severity: SeverityCritical, Subject: "ethereum", Summary: "behind", Labels: map[string]string{"chain": "ethereum"}}
+	require.NoError(t, NewWebhookSink(server.URL).Send(context.Background(), alert))
+	require.NoError(t, NewSlackSink(server.URL).Send(context.Background(), alert))
+	require.Error(t, NewWebhookSink(server.URL+"/fail").Send(context.Background(), alert))
+
+	require.Equal(t, RuleSyncLag, bodies[0]["rule"])
+	require.Contains(t, bodies[1]["text"], "[critical] sync_lag ethereum")
+	require.Contains(t, bodies[1]["text"], "chain: ethereum")
+}
+
+func TestSMTPSinkHonoursDeadline(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	// 接受连接后不发送问候语，模拟卡住的 SMTP 服务
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		time.Sleep(5 * time.Second)
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	portNum, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	sink, err := NewSMTPSink(SMTPConfig{Host: host, Port: portNum, From: "alert@example.com", To: []string{"ops@example.com"}})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	require.Error(t, sink.Send(ctx, &Alert{Rule: RuleSyncLag, Subject: "ethereum"}))
+	require.Less(t, time.Since(start), 2*time.Second)
+}
@@ -0,0 +1,212 @@
+package alerting
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	RuleSyncLag           = "sync_lag"
+	RuleRpcOutage         = "rpc_outage"
+	RuleBroadcastFailure  = "broadcast_failure_rate"
+	RuleNotifyBacklog     = "notify_backlog"
+	RuleHotWalletBelowMin = "hot_wallet_below_minimum"
+	RuleBalanceMismatch   = "balance_mismatch"
+)
+
+const (
+	defaultSyncLagBlocks        = 100
+	defaultBroadcastFailureRate = 0.5
+	defaultBroadcastWindow      = 20
+	defaultNotifyBacklogAge     = 10 * time.Minute
+)
+
+// Rules 告警阈值，为 0 时使用默认值
+type Rules struct {
+	// SyncLagBlocks 链上最新高度领先已同步高度超过该值时告警
+	SyncLagBlocks uint64
+	// BroadcastFailureRate 最近 BroadcastWindow 次广播中失败的比例超过该值时告警，样本不足一半窗口时不评估
+	BroadcastFailureRate float64
+	BroadcastWindow      int
+	// NotifyBacklogAge 最早一条待通知记录等待超过该时长时告警
+	NotifyBacklogAge time.Duration
+}
+
+func (r Rules) withDefaults() Rules {
+	if r.SyncLagBlocks == 0 {
+		r.SyncLagBlocks = defaultSyncLagBlocks
+	}
+	if r.BroadcastFailureRate <= 0 {
+		r.BroadcastFailureRate = defaultBroadcastFailureRate
+	}
+	if r.BroadcastWindow <= 0 {
+		r.BroadcastWindow = defaultBroadcastWindow
+	}
+	if r.NotifyBacklogAge <= 0 {
+		r.NotifyBacklogAge = defaultNotifyBacklogAge
+	}
+	return r
+}
+
+// outcomeWindow 最近若干次结果的环形缓冲，true 表示失败
+type outcomeWindow struct {
+	outcomes []bool
+	next     int
+	size     int
+}
+
+func newOutcomeWindow(capacity int) *outcomeWindow {
+	return &outcomeWindow{outcomes: make([]bool, capacity)}
+}
+
+func (w *outcomeWindow) add(failed bool) {
+	w.outcomes[w.next] = failed
+	w.next = (w.next + 1) % len(w.outcomes)
+	if w.size < len(w.outcomes) {
+		w.size++
+	}
+}
+
+func (w *outcomeWindow) failureRate() float64 {
+	if w.size == 0 {
+		return 0
+	}
+	failures := 0
+	for i := 0; i < w.size; i++ {
+		if w.outcomes[i] {
+			failures++
+		}
+	}
+	return float64(failures) / float64(w.size)
+}
+
+// SyncLag 同步落后链上最新高度超过阈值时告警，追上后恢复
+func (a *Alerter) SyncLag(chainName string, latest uint64, synced uint64) {
+	if a == nil {
+		return
+	}
+	if latest <= synced || latest-synced <= a.rules.SyncLagBlocks {
+		a.Resolve(RuleSyncLag, chainName)
+		return
+	}
+	a.Raise(&Alert{
+		Rule:     RuleSyncLag,
+		Severity: SeverityCritical,
+		Subject:  chainName,
+		Summary:  fmt.Sprintf("sync is %d blocks behind chain head %d (threshold %d)", latest-synced, latest, a.rules.SyncLagBlocks),
+		Labels:   map[string]string{"chain": chainName, "latest": fmt.Sprint(latest), "synced": fmt.Sprint(synced)},
+	})
+}
+
+// RpcResult 链节点调用失败时告警，调用成功后恢复
+func (a *Alerter) RpcResult(chainName string, method string, err error) {
+	if a == nil {
+		return
+	}
+	subject := chainName + "/" + method
+	if err == nil {
+		a.Resolve(RuleRpcOutage, subject)
+		return
+	}
+	a.Raise(&Alert{
+		Rule:     RuleRpcOutage,
+		Severity: SeverityCritical,
+		Subject:  subject,
+		Summary:  fmt.Sprintf("chain rpc %s failed: %v", method, err),
+		Labels:   map[string]string{"chain": chainName, "method": method},
+	})
+}
+
+// BroadcastResult 记录一次广播结果，窗口内失败比例超过阈值时告警
+func (a *Alerter) BroadcastResult(chainName string, err error) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	window, ok := a.broadcasts[chainName]
+	if !ok {
+		window = newOutcomeWindow(a.rules.BroadcastWindow)
+		a.broadcasts[chainName] = window
+	}
+	window.add(err != nil)
+	rate, samples := window.failureRate(), window.size
+	a.mu.Unlock()
+
+	if samples*2 < a.rules.BroadcastWindow {
+		return
+	}
+	if rate <= a.rules.BroadcastFailureRate {
+		a.Resolve(RuleBroadcastFailure, chainName)
+		return
+	}
+	alert := &Alert{
+		Rule:     RuleBroadcastFailure,
+		Severity: SeverityCritical,
+		Subject:  chainName,
+		Summary:  fmt.Sprintf("%.0f%% of the last %d broadcasts failed (threshold %.0f%%)", rate*100, samples, a.rules.BroadcastFailureRate*100),
+		Labels:   map[string]string{"chain": chainName},
+	}
+	if err != nil {
+		alert.Labels["last_error"] = err.Error()
+	}
+	a.Raise(alert)
+}
+
+// NotifyBacklog oldest 为最早一条待通知记录的时间，为零值表示没有积压
+func (a *Alerter) NotifyBacklog(chainName string, businessId string, oldest time.Time) {
+	if a == nil {
+		return
+	}
+	subject := chainName + "/" + businessId
+	age := a.clock.Since(oldest)
+	if oldest.IsZero() || age <= a.rules.NotifyBacklogAge {
+		a.Resolve(RuleNotifyBacklog, subject)
+		return
+	}
+	a.Raise(&Alert{
+		Rule:     RuleNotifyBacklog,
+		Severity: SeverityWarning,
+		Subject:  subject,
+		Summary:  fmt.Sprintf("oldest pending notification is %s old (threshold %s)", age.Truncate(time.Second), a.rules.NotifyBacklogAge),
+		Labels:   map[string]string{"chain": chainName, "business": businessId},
+	})
+}
+
+// HotWalletBalance 热钱包可用余额低于代币配置的下限时告警，补充后恢复
+func (a *Alerter) HotWalletBalance(chainName string, businessId string, address string, tokenAddress string, available *big.Int, minBalance *big.Int) {
+	if a == nil || minBalance == nil || minBalance.Sign() <= 0 {
+		return
+	}
+	subject := chainName + "/" + businessId + "/" + address + "/" + tokenAddress
+	if available.Cmp(minBalance) >= 0 {
+		a.Resolve(RuleHotWalletBelowMin, subject)
+		return
+	}
+	a.Raise(&Alert{
+		Rule:     RuleHotWalletBelowMin,
+		Severity: SeverityWarning,
+		Subject:  subject,
+		Summary:  fmt.Sprintf("hot wallet available balance %s is below minimum %s", available, minBalance),
+		Labels:   map[string]string{"chain": chainName, "business": businessId, "address": address, "token": tokenAddress},
+	})
+}
+
+// BalanceMismatch 对账发现记录余额和链上余额的差异超过通知阈值时告警，差异消除后恢复
+func (a *Alerter) BalanceMismatch(chainName string, businessId string, address string, tokenAddress string, stored *big.Int, onChain *big.Int, mismatched bool) {
+	if a == nil {
+		return
+	}
+	subject := chainName + "/" + businessId + "/" + address + "/" + tokenAddress
+	if !mismatched {
+		a.Resolve(RuleBalanceMismatch, subject)
+		return
+	}
+	a.Raise(&Alert{
+		Rule:     RuleBalanceMismatch,
+		Severity: SeverityWarning,
+		Subject:  subject,
+		Summary:  fmt.Sprintf("stored balance %s differs from chain balance %s", stored, onChain),
+		Labels:   map[string]string{"chain": chainName, "business": businessId, "address": address, "token": tokenAddress},
+	})
+}
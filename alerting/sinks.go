@@ -0,0 +1,201 @@
+package alerting
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	gresty "github.com/go-resty/resty/v2"
+
+	"github.com/dapplink-labs/multichain-sync-account/config"
+)
+
+// NewSinks 按配置创建发送渠道，未配置地址的渠道不启用
+func NewSinks(cfg config.AlertConfig) ([]Sink, error) {
+	var sinks []Sink
+	if cfg.WebhookUrl != "" {
+		sinks = append(sinks, NewWebhookSink(cfg.WebhookUrl))
+	}
+	if cfg.SlackWebhookUrl != "" {
+		sinks = append(sinks, NewSlackSink(cfg.SlackWebhookUrl))
+	}
+	if cfg.SmtpHost != "" {
+		sink, err := NewSMTPSink(SMTPConfig{
+			Host:     cfg.SmtpHost,
+			Port:     cfg.SmtpPort,
+			User:     cfg.SmtpUser,
+			Password: cfg.SmtpPassword,
+			From:     cfg.SmtpFrom,
+			To:       cfg.SmtpTo,
+		})
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// WebhookSink 以 JSON 格式 POST 整条告警
+type WebhookSink struct {
+	url    string
+	client *gresty.Client
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{url: url, client: newHttpClient()}
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *WebhookSink) Send(ctx context.Context, alert *Alert) error {
+	_, err := s.client.R().SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(alert).
+		Post(s.url)
+	return err
+}
+
+// SlackSink 兼容 Slack incoming webhook 的 {"text": ...} 格式，飞书、Mattermost 等同类 webhook 也可以使用
+type SlackSink struct {
+	url    string
+	client *gresty.Client
+}
+
+func NewSlackSink(url string) *SlackSink {
+	return &SlackSink{url: url, client: newHttpClient()}
+}
+
+func (s *SlackSink) Name() string {
+	return "slack"
+}
+
+func (s *SlackSink) Send(ctx context.Context, alert *Alert) error {
+	_, err := s.client.R().SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]string{"text": "*" + alert.Title() + "*\n" + alertText(alert)}).
+		Post(s.url)
+	return err
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	From     string
+	To       []string
+}
+
+// SMTPSink 以纯文本邮件发送告警，User 为空时不做认证
+type SMTPSink struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPSink(cfg SMTPConfig) (*SMTPSink, error) {
+	if cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return nil, fmt.Errorf("smtp sink requires host, from and to")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 25
+	}
+	return &SMTPSink{cfg: cfg}, nil
+}
+
+func (s *SMTPSink) Name() string {
+	return "smtp"
+}
+
+func (s *SMTPSink) Send(ctx context.Context, alert *Alert) error {
+	msg := strings.Join([]string{
+		"From: " + s.cfg.From,
+		"To: " + strings.Join(s.cfg.To, ", "),
+		"Subject: " + alert.Title(),
+		"Date: " + time.Unix(alert.Timestamp, 0).UTC().Format(time.RFC1123Z),
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		alertText(alert),
+	}, "\r\n")
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// net/smtp 不接受 context，连接建立后用 ctx 的截止时间限制整个会话的读写
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if s.cfg.User != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.User, s.cfg.Password, s.cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range s.cfg.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func newHttpClient() *gresty.Client {
+	client := gresty.New()
+	client.OnAfterResponse(func(c *gresty.Client, r *gresty.Response) error {
+		if r.StatusCode() >= 400 {
+			return fmt.Errorf("%d cannot %s %s", r.StatusCode(), r.Request.Method, r.Request.URL)
+		}
+		return nil
+	})
+	return client
+}
+
+// alertText 告警正文，标签按名称排序
+func alertText(alert *Alert) string {
+	var b strings.Builder
+	b.WriteString(alert.Summary)
+	keys := make([]string, 0, len(alert.Labels))
+	for k := range alert.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n%s: %s", k, alert.Labels[k])
+	}
+	fmt.Fprintf(&b, "\ntime: %s", time.Unix(alert.Timestamp, 0).UTC().Format(time.RFC3339))
+	return b.String()
+}
@@ -3,21 +3,22 @@ package main
 import (
 	"context"
 	"fmt"
-
+	"strconv"
 	"time"
 
 	"github.com/urfave/cli/v2"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 
 	multichain_transaction_syncs "github.com/dapplink-labs/multichain-sync-account"
+	"github.com/dapplink-labs/multichain-sync-account/alerting"
 	"github.com/dapplink-labs/multichain-sync-account/common/cliapp"
 	"github.com/dapplink-labs/multichain-sync-account/common/opio"
+	"github.com/dapplink-labs/multichain-sync-account/common/tlsutil"
 	"github.com/dapplink-labs/multichain-sync-account/config"
 	"github.com/dapplink-labs/multichain-sync-account/database"
+	"github.com/dapplink-labs/multichain-sync-account/database/dynamic"
 	flags2 "github.com/dapplink-labs/multichain-sync-account/flags"
 	"github.com/dapplink-labs/multichain-sync-account/notifier"
 	"github.com/dapplink-labs/multichain-sync-account/rpcclient"
@@ -38,6 +39,10 @@ func runMultichainSync(ctx *cli.Context, shutdown context.CancelCauseFunc) (clia
 		log.Error("failed to load config", "err", err)
 		return nil, err
 	}
+	if err := loadChainRegistry(ctx, &cfg); err != nil {
+		log.Error("failed to load chain registry", "err", err)
+		return nil, err
+	}
 	return multichain_transaction_syncs.NewMultiChainSync(ctx.Context, &cfg, shutdown)
 }
 
@@ -48,9 +53,44 @@ func runRpc(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifecycl
 		log.Error("failed to load config", "err", err)
 		return nil, err
 	}
+	if err := loadChainRegistry(ctx, &cfg); err != nil {
+		log.Error("failed to load chain registry", "err", err)
+		return nil, err
+	}
 	grpcServerCfg := &services.BusinessMiddleConfig{
-		GrpcHostname: cfg.RpcServer.Host,
-		GrpcPort:     cfg.RpcServer.Port,
+		GrpcHostname:   cfg.RpcServer.Host,
+		GrpcPort:       cfg.RpcServer.Port,
+		AllowedClients: cfg.RpcServerTLS.AllowedClients,
+		ArchiveDir:     cfg.ArchiveDir,
+	}
+	grpcServerCfg.FeeStrategies, err = services.LoadFeeStrategies(cfg.FeeStrategy)
+	if err != nil {
+		log.Error("failed to load fee strategies", "err", err)
+		return nil, err
+	}
+	// 客户端白名单按校验过的客户端证书匹配，只有要求客户端证书时才有意义，否则所有调用都会被拒绝
+	if len(cfg.RpcServerTLS.AllowedClients) > 0 && !cfg.RpcServerTLS.Enable {
+		return nil, fmt.Errorf("rpc tls allowed clients require rpc tls with client auth %s", tlsutil.ClientAuthRequired)
+	}
+	if cfg.RpcServerTLS.Enable {
+		clientAuth, err := tlsutil.ParseClientAuthMode(cfg.RpcServerTLS.ClientAuth)
+		if err != nil {
+			log.Error("invalid rpc tls client auth", "err", err)
+			return nil, err
+		}
+		if len(cfg.RpcServerTLS.AllowedClients) > 0 && clientAuth != tlsutil.ClientAuthRequired {
+			return nil, fmt.Errorf("rpc tls allowed clients require client auth %s, got %s", tlsutil.ClientAuthRequired, clientAuth)
+		}
+		grpcServerCfg.TLS, err = tlsutil.NewServerTLSConfig(tlsutil.ServerConfig{
+			CertFile:     cfg.RpcServerTLS.CertFile,
+			KeyFile:      cfg.RpcServerTLS.KeyFile,
+			ClientCAFile: cfg.RpcServerTLS.CAFile,
+			ClientAuth:   clientAuth,
+		})
+		if err != nil {
+			log.Error("failed to build rpc tls config", "err", err)
+			return nil, err
+		}
 	}
 	db, err := database.NewDB(ctx.Context, cfg.MasterDB)
 	if err != nil {
@@ -59,13 +99,13 @@ func runRpc(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifecycl
 	}
 
 	log.Info("Chain account rpc", "rpc uri", cfg.ChainAccountRpc)
-	conn, err := grpc.NewClient(cfg.ChainAccountRpc, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := rpcclient.DialChainAccount(cfg.ChainAccountRpc, cfg.ChainAccountTLS)
 	if err != nil {
 		log.Error("Connect to da retriever fail", "err", err)
 		return nil, err
 	}
 	client := account.NewWalletAccountServiceClient(conn)
-	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, cfg.ChainNode.ChainName)
+	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, cfg.ChainNode.ChainName, database.GetNetwork(cfg.ChainNode.ChainName))
 	if err != nil {
 		log.Error("new wallet account client fail", "err", err)
 		return nil, err
@@ -73,14 +113,34 @@ func runRpc(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifecycl
 	return services.NewBusinessMiddleWireServices(db, grpcServerCfg, accountClient)
 }
 
-func runMigrations(ctx *cli.Context) error {
+// loadChainRegistry 合并链配置文件，未指定 --confirmations 时使用链的默认确认数
+func loadChainRegistry(ctx *cli.Context, cfg *config.Config) error {
+	if err := database.LoadChainRegistry(cfg.ChainsConfig); err != nil {
+		return err
+	}
+	if !database.IsSupportedChain(cfg.ChainNode.ChainName) {
+		return fmt.Errorf("chain %s is not in the chain registry", cfg.ChainNode.ChainName)
+	}
+	if !ctx.IsSet(flags2.ConfirmationsFlag.Name) {
+		if confirmations := database.GetDefaultConfirmations(cfg.ChainNode.ChainName); confirmations > 0 {
+			cfg.ChainNode.Confirmations = uint(confirmations)
+		}
+	}
+	log.Info("loaded chain registry", "chains", database.SupportedChains(), "network", database.GetNetwork(cfg.ChainNode.ChainName), "confirmations", cfg.ChainNode.Confirmations)
+	return nil
+}
+
+func withMigrationDB(ctx *cli.Context, fn func(db *database.DB, migrationsDir string) error) error {
 	ctx.Context = opio.CancelOnInterrupt(ctx.Context)
-	log.Info("running migrations...")
 	cfg, err := config.LoadConfig(ctx)
 	if err != nil {
 		log.Error("failed to load config", "err", err)
 		return err
 	}
+	if err := loadChainRegistry(ctx, &cfg); err != nil {
+		log.Error("failed to load chain registry", "err", err)
+		return err
+	}
 	db, err := database.NewDB(ctx.Context, cfg.MasterDB)
 	if err != nil {
 		log.Error("failed to connect to database", "err", err)
@@ -92,7 +152,173 @@ func runMigrations(ctx *cli.Context) error {
 			log.Error("fail to close database", "err", err)
 		}
 	}(db)
-	return db.ExecuteSQLMigration(cfg.Migrations)
+	return fn(db, cfg.Migrations)
+}
+
+var DryRunFlag = &cli.BoolFlag{
+	Name:  "dry-run",
+	Usage: "Only report drift between dynamic tables and their templates",
+}
+
+func runMigrations(ctx *cli.Context) error {
+	log.Info("running migrations...")
+	return withMigrationDB(ctx, func(db *database.DB, migrationsDir string) error {
+		if err := db.MigrateUp(migrationsDir); err != nil {
+			return err
+		}
+		return syncDynamicTables(db, false)
+	})
+}
+
+func runSyncTables(ctx *cli.Context) error {
+	return withMigrationDB(ctx, func(db *database.DB, migrationsDir string) error {
+		return syncDynamicTables(db, ctx.Bool(DryRunFlag.Name))
+	})
+}
+
+func syncDynamicTables(db *database.DB, dryRun bool) error {
+	driftList, err := dynamic.SyncTemplateTables(db, dryRun)
+	for _, drift := range driftList {
+		fmt.Printf("%s (template %s)\n", drift.Table, drift.Template)
+		if drift.MissingTable {
+			fmt.Printf("  missing table\n")
+		}
+		for _, column := range drift.MissingColumns {
+			fmt.Printf("  missing column:     %s\n", column)
+		}
+		for _, change := range drift.ChangedColumns {
+			fmt.Printf("  changed column:     %s\n", change)
+		}
+		for _, column := range drift.ExtraColumns {
+			fmt.Printf("  extra column:       %s (not dropped)\n", column)
+		}
+		for _, index := range drift.MissingIndexes {
+			fmt.Printf("  missing index:      %s\n", index)
+		}
+		for _, constraint := range drift.MissingConstraints {
+			fmt.Printf("  missing constraint: %s\n", constraint)
+		}
+		if dryRun {
+			for _, statement := range drift.Statements {
+				fmt.Printf("  would run: %s\n", statement)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	log.Info("dynamic tables checked", "drifted", len(driftList), "dryRun", dryRun)
+	return nil
+}
+
+func runMigrationStatus(ctx *cli.Context) error {
+	return withMigrationDB(ctx, func(db *database.DB, migrationsDir string) error {
+		statusList, err := db.MigrationStatus(migrationsDir)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-10s %-40s %-8s %-20s %s\n", "VERSION", "NAME", "APPLIED", "APPLIED AT", "CHECKSUM")
+		for _, status := range statusList {
+			appliedAt := "-"
+			if status.Applied {
+				appliedAt = time.Unix(int64(status.AppliedAt), 0).Format(time.DateTime)
+			}
+			checksum := "ok"
+			if status.ChecksumMismatch {
+				checksum = "MISMATCH"
+			} else if !status.Applied {
+				checksum = "-"
+			}
+			fmt.Printf("%-10d %-40s %-8t %-20s %s\n", status.Version, status.Name, status.Applied, appliedAt, checksum)
+		}
+		return nil
+	})
+}
+
+func runMigrationDown(ctx *cli.Context) error {
+	steps := 1
+	if ctx.Args().Present() {
+		n, err := strconv.Atoi(ctx.Args().First())
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid migration steps: %s", ctx.Args().First())
+		}
+		steps = n
+	}
+	log.Info("reverting migrations...", "steps", steps)
+	return withMigrationDB(ctx, func(db *database.DB, migrationsDir string) error {
+		return db.MigrateDown(migrationsDir, steps)
+	})
+}
+
+func runMigrationTo(ctx *cli.Context) error {
+	if !ctx.Args().Present() {
+		return fmt.Errorf("target migration version is required")
+	}
+	version, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid migration version: %s", ctx.Args().First())
+	}
+	log.Info("migrating to version...", "version", version)
+	return withMigrationDB(ctx, func(db *database.DB, migrationsDir string) error {
+		if err := db.MigrateTo(migrationsDir, version); err != nil {
+			return err
+		}
+		return syncDynamicTables(db, false)
+	})
+}
+
+var BusinessIdFlag = &cli.StringFlag{
+	Name:     "business-id",
+	Usage:    "The request id the business registered with",
+	Required: true,
+}
+
+func runBusinessStatus(status database.BusinessStatus, from database.BusinessStatus) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		businessUid := ctx.String(BusinessIdFlag.Name)
+		return withMigrationDB(ctx, func(db *database.DB, migrationsDir string) error {
+			business, err := db.Business.QueryBusinessByUuid(businessUid)
+			if err != nil {
+				return fmt.Errorf("query business %s fail: %w", businessUid, err)
+			}
+			if business.Status == status {
+				log.Info("business status unchanged", "businessUid", businessUid, "status", status)
+				return nil
+			}
+			if business.Status != from {
+				return fmt.Errorf("can not change business %s from %s to %s", businessUid, business.Status, status)
+			}
+			if err := db.Business.UpdateBusinessStatus(businessUid, status); err != nil {
+				return err
+			}
+			log.Info("business status changed", "businessUid", businessUid, "from", from, "to", status)
+			return nil
+		})
+	}
+}
+
+func runBusinessArchive(ctx *cli.Context) error {
+	businessUid := ctx.String(BusinessIdFlag.Name)
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		log.Error("failed to load config", "err", err)
+		return err
+	}
+	return withMigrationDB(ctx, func(db *database.DB, migrationsDir string) error {
+		business, err := db.Business.QueryBusinessByUuid(businessUid)
+		if err != nil {
+			return fmt.Errorf("query business %s fail: %w", businessUid, err)
+		}
+		if business.Status != database.BusinessStatusSuspended {
+			return fmt.Errorf("business %s must be suspended before archive, current status %s", businessUid, business.Status)
+		}
+		archiveFile, err := dynamic.ArchiveBusinessTables(db, businessUid, cfg.ArchiveDir)
+		if err != nil {
+			return err
+		}
+		log.Info("business archived", "businessUid", businessUid, "archive", archiveFile)
+		return nil
+	})
 }
 
 func runNotify(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifecycle, error) {
@@ -102,12 +328,21 @@ func runNotify(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifec
 		log.Error("failed to load config", "err", err)
 		return nil, err
 	}
+	if err := loadChainRegistry(ctx, &cfg); err != nil {
+		log.Error("failed to load chain registry", "err", err)
+		return nil, err
+	}
 	db, err := database.NewDB(ctx.Context, cfg.MasterDB)
 	if err != nil {
 		log.Error("failed to connect to database", "err", err)
 		return nil, err
 	}
-	return notifier.NewNotifier(db, shutdown, cfg.ChainNode.ChainName)
+	alerter, err := alerting.NewAlerterFromConfig(cfg.Alert)
+	if err != nil {
+		log.Error("failed to create alerter", "err", err)
+		return nil, err
+	}
+	return notifier.NewNotifier(db, shutdown, cfg.ChainNode.ChainName, alerter)
 }
 
 func NewCli(GitCommit string, GitData string) *cli.App {
@@ -140,6 +375,64 @@ func NewCli(GitCommit string, GitData string) *cli.App {
 				Flags:       flags,
 				Description: "Run database migrations",
 				Action:      runMigrations,
+				Subcommands: []*cli.Command{
+					{
+						Name:        "status",
+						Flags:       flags,
+						Description: "Show applied and pending migrations",
+						Action:      runMigrationStatus,
+					},
+					{
+						Name:        "up",
+						Flags:       flags,
+						Description: "Apply all pending migrations",
+						Action:      runMigrations,
+					},
+					{
+						Name:        "down",
+						Flags:       flags,
+						ArgsUsage:   "[steps]",
+						Description: "Revert the latest applied migrations, one step by default",
+						Action:      runMigrationDown,
+					},
+					{
+						Name:        "to",
+						Flags:       flags,
+						ArgsUsage:   "<version>",
+						Description: "Migrate up or down to the given version",
+						Action:      runMigrationTo,
+					},
+					{
+						Name:        "sync-tables",
+						Flags:       append([]cli.Flag{DryRunFlag}, flags...),
+						Description: "Apply template table schema changes to every business dynamic table",
+						Action:      runSyncTables,
+					},
+				},
+			},
+			{
+				Name:        "business",
+				Description: "Manage registered business status",
+				Subcommands: []*cli.Command{
+					{
+						Name:        "suspend",
+						Flags:       append([]cli.Flag{BusinessIdFlag}, flags...),
+						Description: "Stop withdraw and notify for a business, deposits are still recorded",
+						Action:      runBusinessStatus(database.BusinessStatusSuspended, database.BusinessStatusActive),
+					},
+					{
+						Name:        "activate",
+						Flags:       append([]cli.Flag{BusinessIdFlag}, flags...),
+						Description: "Resume a suspended business",
+						Action:      runBusinessStatus(database.BusinessStatusActive, database.BusinessStatusSuspended),
+					},
+					{
+						Name:        "archive",
+						Flags:       append([]cli.Flag{BusinessIdFlag}, flags...),
+						Description: "Export a suspended business's tables to a tar.gz archive and drop them, retry if a new deposit lands during the export",
+						Action:      runBusinessArchive,
+					},
+				},
 			},
 			{
 				Name:        "version",
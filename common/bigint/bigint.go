@@ -1,7 +1,9 @@
 package bigint
 
 import (
+	"fmt"
 	"math/big"
+	"strings"
 )
 
 var (
@@ -41,3 +43,49 @@ func StringToBigInt(input string) (num *big.Int) {
 	}
 	return
 }
+
+// FormatUnits 按精度把最小单位的整数格式化为小数字符串，去掉末尾多余的 0，如 1500000000000000000 (18) -> 1.5
+func FormatUnits(amount *big.Int, decimals uint8) string {
+	if amount == nil {
+		return "0"
+	}
+	negative := amount.Sign() < 0
+	digits := new(big.Int).Abs(amount).String()
+	if decimals > 0 {
+		if len(digits) <= int(decimals) {
+			digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+		}
+		point := len(digits) - int(decimals)
+		integer, fraction := digits[:point], strings.TrimRight(digits[point:], "0")
+		digits = integer
+		if fraction != "" {
+			digits += "." + fraction
+		}
+	}
+	if negative {
+		return "-" + digits
+	}
+	return digits
+}
+
+// ParseUnits 把小数字符串按精度转换为最小单位的整数，小数位超过精度时返回错误
+func ParseUnits(value string, decimals uint8) (*big.Int, error) {
+	value = strings.TrimSpace(value)
+	integer, fraction, hasPoint := strings.Cut(value, ".")
+	if integer == "" && fraction == "" || hasPoint && fraction == "" {
+		return nil, fmt.Errorf("invalid amount: %q", value)
+	}
+	if strings.HasPrefix(integer, "-") || strings.HasPrefix(integer, "+") {
+		return nil, fmt.Errorf("amount must be unsigned: %q", value)
+	}
+	trimmed := strings.TrimRight(fraction, "0")
+	if len(trimmed) > int(decimals) {
+		return nil, fmt.Errorf("amount %q has more than %d decimal places", value, decimals)
+	}
+	digits := integer + trimmed + strings.Repeat("0", int(decimals)-len(trimmed))
+	amount, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount: %q", value)
+	}
+	return amount, nil
+}
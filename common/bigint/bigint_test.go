@@ -21,3 +21,36 @@ func TestClamp(t *testing.T) {
 	require.False(t, end == result)
 	require.Equal(t, uint64(5), result.Uint64())
 }
+
+func TestFormatUnits(t *testing.T) {
+	amount, _ := new(big.Int).SetString("1500000000000000000", 10)
+	require.Equal(t, "1.5", FormatUnits(amount, 18))
+	require.Equal(t, "0.000001", FormatUnits(big.NewInt(1), 6))
+	require.Equal(t, "100", FormatUnits(big.NewInt(100), 0))
+	require.Equal(t, "2", FormatUnits(big.NewInt(200000000), 8))
+	require.Equal(t, "-0.5", FormatUnits(big.NewInt(-50), 2))
+	require.Equal(t, "0", FormatUnits(nil, 18))
+}
+
+func TestParseUnits(t *testing.T) {
+	amount, err := ParseUnits("1.5", 18)
+	require.NoError(t, err)
+	require.Equal(t, "1500000000000000000", amount.String())
+
+	amount, err = ParseUnits("0.10", 1)
+	require.NoError(t, err)
+	require.Equal(t, "1", amount.String())
+
+	amount, err = ParseUnits(".25", 2)
+	require.NoError(t, err)
+	require.Equal(t, "25", amount.String())
+
+	_, err = ParseUnits("0.0000001", 6)
+	require.ErrorContains(t, err, "more than 6 decimal places")
+	_, err = ParseUnits("1.", 6)
+	require.Error(t, err)
+	_, err = ParseUnits("-1", 6)
+	require.Error(t, err)
+	_, err = ParseUnits("1e5", 6)
+	require.Error(t, err)
+}
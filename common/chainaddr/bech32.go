@@ -0,0 +1,61 @@
+package chainaddr
+
+import "strings"
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// isBech32 校验 bech32 和 bech32m (BIP-173/BIP-350) 的字符集和校验和
+func isBech32(address string) bool {
+	if len(address) < 8 || len(address) > 90 {
+		return false
+	}
+	lower := strings.ToLower(address)
+	if address != lower && address != strings.ToUpper(address) {
+		return false
+	}
+	separator := strings.LastIndexByte(lower, '1')
+	if separator < 1 || separator+7 > len(lower) {
+		return false
+	}
+	hrp, data := lower[:separator], lower[separator+1:]
+	values := make([]byte, 0, len(hrp)*2+1+len(data))
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return false
+		}
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+	for i := 0; i < len(data); i++ {
+		index := strings.IndexByte(bech32Charset, data[i])
+		if index < 0 {
+			return false
+		}
+		values = append(values, byte(index))
+	}
+	polymod := bech32Polymod(values)
+	return polymod == bech32Const || polymod == bech32mConst
+}
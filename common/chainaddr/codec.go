@@ -0,0 +1,218 @@
+package chainaddr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrInvalidAddress = errors.New("invalid address")
+
+// Codec 一条链的地址规则，Normalize 校验地址并返回入库使用的规范形式，
+// LookupForms 返回查询时需要匹配的形式，兼容规范化之前已入库的旧数据
+type Codec interface {
+	Normalize(address string) (string, error)
+	LookupForms(address string) []string
+}
+
+const (
+	bitcoinAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	rippleAlphabet  = "rpshnaf39wBUDNEGHJKLM4PQRST7VWXYZ2bcdeCg65jkm8oFqi1tuvAxyz"
+)
+
+var (
+	// Passthrough 未知的链只去掉首尾空白，大小写保持原样
+	Passthrough Codec = passthroughCodec{}
+	EVM         Codec = evmCodec{}
+	Tron        Codec = tronCodec{}
+	Solana      Codec = base58Codec{name: "solana", alphabet: bitcoinAlphabet, minLen: 32, maxLen: 44}
+	Xrp         Codec = base58Codec{name: "xrp", alphabet: rippleAlphabet, prefix: "r", minLen: 25, maxLen: 35}
+	Ton         Codec = tonCodec{}
+	Cosmos      Codec = bech32Codec{}
+	UTXO        Codec = utxoCodec{}
+)
+
+func invalid(chain string, address string) error {
+	return fmt.Errorf("%w for %s: %q", ErrInvalidAddress, chain, address)
+}
+
+func uniqueForms(forms ...string) []string {
+	result := make([]string, 0, len(forms))
+	for _, form := range forms {
+		duplicate := false
+		for _, existing := range result {
+			if existing == form {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			result = append(result, form)
+		}
+	}
+	return result
+}
+
+// lookupForms 无法规范化的地址按原样查询
+func lookupForms(codec Codec, address string, legacy func(string) string) []string {
+	canonical, err := codec.Normalize(address)
+	if err != nil {
+		return []string{strings.TrimSpace(address)}
+	}
+	if legacy == nil {
+		return []string{canonical}
+	}
+	return uniqueForms(canonical, legacy(canonical))
+}
+
+type passthroughCodec struct{}
+
+func (passthroughCodec) Normalize(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return "", invalid("unknown chain", address)
+	}
+	return address, nil
+}
+
+func (c passthroughCodec) LookupForms(address string) []string {
+	return lookupForms(c, address, nil)
+}
+
+// evmCodec 规范形式为 EIP-55 校验和地址，大小写混合的输入必须校验和正确
+type evmCodec struct{}
+
+func (evmCodec) Normalize(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if !strings.HasPrefix(address, "0x") && !strings.HasPrefix(address, "0X") {
+		return "", invalid("evm", address)
+	}
+	if !common.IsHexAddress(address) {
+		return "", invalid("evm", address)
+	}
+	checksum := common.HexToAddress(address).Hex()
+	body := address[2:]
+	if body != strings.ToLower(body) && body != strings.ToUpper(body) && body != checksum[2:] {
+		return "", fmt.Errorf("%w: bad EIP-55 checksum %q", ErrInvalidAddress, address)
+	}
+	return checksum, nil
+}
+
+// LookupForms 规范化之前的数据按小写入库，查询时同时匹配
+func (c evmCodec) LookupForms(address string) []string {
+	return lookupForms(c, address, strings.ToLower)
+}
+
+// base58Codec base58 地址区分大小写，规范形式即原样
+type base58Codec struct {
+	name     string
+	alphabet string
+	prefix   string
+	minLen   int
+	maxLen   int
+}
+
+func (c base58Codec) Normalize(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if len(address) < c.minLen || len(address) > c.maxLen || !strings.HasPrefix(address, c.prefix) {
+		return "", invalid(c.name, address)
+	}
+	if !inAlphabet(address, c.alphabet) {
+		return "", invalid(c.name, address)
+	}
+	return address, nil
+}
+
+func (c base58Codec) LookupForms(address string) []string {
+	return lookupForms(c, address, nil)
+}
+
+func inAlphabet(value string, alphabet string) bool {
+	for _, r := range value {
+		if !strings.ContainsRune(alphabet, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// tronCodec 接受 T 开头的 base58 地址和 41 开头的 hex 地址，hex 地址统一小写
+type tronCodec struct{}
+
+func (tronCodec) Normalize(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if len(address) == 42 && strings.HasPrefix(address, "41") {
+		if !isHex(address) {
+			return "", invalid("tron", address)
+		}
+		return strings.ToLower(address), nil
+	}
+	return base58Codec{name: "tron", alphabet: bitcoinAlphabet, prefix: "T", minLen: 34, maxLen: 34}.Normalize(address)
+}
+
+func (c tronCodec) LookupForms(address string) []string {
+	return lookupForms(c, address, nil)
+}
+
+func isHex(value string) bool {
+	for _, r := range value {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// tonCodec 接受 <workchain>:<hex> 的原始地址和 48 位 base64 的用户友好地址，
+// 原始地址的 hex 统一小写，用户友好地址区分大小写
+type tonCodec struct{}
+
+func (tonCodec) Normalize(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if workchain, hash, ok := strings.Cut(address, ":"); ok {
+		if (workchain != "0" && workchain != "-1") || len(hash) != 64 || !isHex(hash) {
+			return "", invalid("ton", address)
+		}
+		return workchain + ":" + strings.ToLower(hash), nil
+	}
+	if len(address) != 48 || !inAlphabet(address, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/-_") {
+		return "", invalid("ton", address)
+	}
+	return address, nil
+}
+
+func (c tonCodec) LookupForms(address string) []string {
+	return lookupForms(c, address, nil)
+}
+
+// bech32Codec bech32 地址不允许大小写混合，规范形式为小写
+type bech32Codec struct{}
+
+func (bech32Codec) Normalize(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if !isBech32(address) {
+		return "", invalid("bech32", address)
+	}
+	return strings.ToLower(address), nil
+}
+
+func (c bech32Codec) LookupForms(address string) []string {
+	return lookupForms(c, address, nil)
+}
+
+// utxoCodec segwit 地址按 bech32 处理，legacy 和 p2sh 地址按 base58 处理
+type utxoCodec struct{}
+
+func (utxoCodec) Normalize(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if isBech32(address) {
+		return strings.ToLower(address), nil
+	}
+	return base58Codec{name: "utxo", alphabet: bitcoinAlphabet, minLen: 26, maxLen: 35}.Normalize(address)
+}
+
+func (c utxoCodec) LookupForms(address string) []string {
+	return lookupForms(c, address, nil)
+}
@@ -0,0 +1,74 @@
+package chainaddr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEVMCodec(t *testing.T) {
+	const checksum = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+	normalized, err := EVM.Normalize("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	require.NoError(t, err)
+	require.Equal(t, checksum, normalized)
+
+	normalized, err = EVM.Normalize(" " + checksum + " ")
+	require.NoError(t, err)
+	require.Equal(t, checksum, normalized)
+
+	_, err = EVM.Normalize("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+	require.ErrorIs(t, err, ErrInvalidAddress)
+	_, err = EVM.Normalize("5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	require.ErrorIs(t, err, ErrInvalidAddress)
+
+	require.Equal(t, []string{checksum, "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"}, EVM.LookupForms(checksum))
+}
+
+func TestCaseSensitiveCodecs(t *testing.T) {
+	cases := []struct {
+		codec   Codec
+		address string
+	}{
+		{Solana, "4Nd1mBQtrMJVYVfKf2PJy9NZUZdTAsp7D4xWLs4gDB4T"},
+		{Tron, "TLa2f6VPqDgRE67v1736s7bJ8Ray5wYjU7"},
+		{Xrp, "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"},
+		{Ton, "EQDtFpEwcFAEcRe5mLVh2N6C0x-_hJEM7W61_JLnSF74p4q2"},
+		{UTXO, "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"},
+	}
+	for _, c := range cases {
+		normalized, err := c.codec.Normalize(c.address)
+		require.NoError(t, err, c.address)
+		require.Equal(t, c.address, normalized)
+		require.Equal(t, []string{c.address}, c.codec.LookupForms(c.address))
+	}
+
+	_, err := Solana.Normalize("0OIl" + "Nd1mBQtrMJVYVfKf2PJy9NZUZdTAsp7D4xWLs4gDB4T")
+	require.ErrorIs(t, err, ErrInvalidAddress)
+	_, err = Xrp.Normalize("1Hb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	require.ErrorIs(t, err, ErrInvalidAddress)
+	_, err = Tron.Normalize("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	require.ErrorIs(t, err, ErrInvalidAddress)
+}
+
+func TestBech32Codecs(t *testing.T) {
+	const segwit = "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"
+
+	normalized, err := UTXO.Normalize("BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4")
+	require.NoError(t, err)
+	require.Equal(t, segwit, normalized)
+
+	taproot := "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"
+	normalized, err = UTXO.Normalize(taproot)
+	require.NoError(t, err)
+	require.Equal(t, taproot, normalized)
+
+	_, err = UTXO.Normalize("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5")
+	require.ErrorIs(t, err, ErrInvalidAddress)
+	_, err = UTXO.Normalize("bc1qW508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4")
+	require.ErrorIs(t, err, ErrInvalidAddress)
+
+	normalized, err = Cosmos.Normalize("COSMOS1QYPQXPQ9QCRSSZG2PVXQ6RS0ZQG3YYC5LZV7XU")
+	require.NoError(t, err)
+	require.Equal(t, "cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu", normalized)
+}
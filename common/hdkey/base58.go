@@ -0,0 +1,63 @@
+package hdkey
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/ripemd160"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var bigRadix = big.NewInt(58)
+
+func decodeBase58(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errors.New("empty extended key")
+	}
+	value := new(big.Int)
+	for _, c := range s {
+		digit := strings.IndexRune(base58Alphabet, c)
+		if digit < 0 {
+			return nil, errors.New("invalid base58 character")
+		}
+		value.Mul(value, bigRadix)
+		value.Add(value, big.NewInt(int64(digit)))
+	}
+	decoded := value.Bytes()
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), decoded...), nil
+}
+
+func encodeBase58(data []byte) string {
+	value := new(big.Int).SetBytes(data)
+	mod := new(big.Int)
+	var out []byte
+	for value.Sign() > 0 {
+		value.DivMod(value, bigRadix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+func hash160(data []byte) []byte {
+	sha := sha256.Sum256(data)
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	return hasher.Sum(nil)
+}
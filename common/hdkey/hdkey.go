@@ -0,0 +1,184 @@
+package hdkey
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// HardenedOffset 之后的序号是强化派生，扩展公钥无法派生
+const HardenedOffset uint32 = 0x80000000
+
+const serializedKeyLength = 78
+
+var (
+	ErrPrivateKey    = errors.New("extended private keys are not accepted")
+	ErrHardenedChild = errors.New("cannot derive a hardened child from an extended public key")
+	ErrInvalidChild  = errors.New("invalid child key, use the next index")
+)
+
+// 扩展公钥的版本号，决定 bitcoin 系链上默认的地址格式
+var publicVersions = map[[4]byte]string{
+	{0x04, 0x88, 0xb2, 0x1e}: "xpub",
+	{0x04, 0x9d, 0x7c, 0xb2}: "ypub",
+	{0x04, 0xb2, 0x47, 0x46}: "zpub",
+	{0x04, 0x35, 0x87, 0xcf}: "tpub",
+	{0x04, 0x4a, 0x52, 0x62}: "upub",
+	{0x04, 0x5f, 0x1c, 0xf6}: "vpub",
+}
+
+var privateVersions = map[[4]byte]bool{
+	{0x04, 0x88, 0xad, 0xe4}: true, // xprv
+	{0x04, 0x9d, 0x78, 0x78}: true, // yprv
+	{0x04, 0xb2, 0x43, 0x0c}: true, // zprv
+	{0x04, 0x35, 0x83, 0x94}: true, // tprv
+	{0x04, 0x4a, 0x4e, 0x28}: true, // uprv
+	{0x04, 0x5f, 0x18, 0xbc}: true, // vprv
+}
+
+// ExtendedKey BIP32 secp256k1 扩展公钥
+type ExtendedKey struct {
+	version     [4]byte
+	depth       uint8
+	fingerprint [4]byte
+	childIndex  uint32
+	chainCode   []byte
+	publicKey   []byte // 压缩格式
+}
+
+// ParseExtendedKey 解析 base58check 编码的扩展公钥，拒绝扩展私钥
+func ParseExtendedKey(s string) (*ExtendedKey, error) {
+	data, err := decodeBase58(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != serializedKeyLength+4 {
+		return nil, fmt.Errorf("invalid extended key length %d", len(data))
+	}
+	payload, checksum := data[:serializedKeyLength], data[serializedKeyLength:]
+	if !bytes.Equal(doubleSha256(payload)[:4], checksum) {
+		return nil, errors.New("invalid extended key checksum")
+	}
+	key := &ExtendedKey{
+		depth:      payload[4],
+		childIndex: binary.BigEndian.Uint32(payload[9:13]),
+		chainCode:  append([]byte(nil), payload[13:45]...),
+		publicKey:  append([]byte(nil), payload[45:78]...),
+	}
+	copy(key.version[:], payload[0:4])
+	copy(key.fingerprint[:], payload[5:9])
+	if privateVersions[key.version] || key.publicKey[0] == 0x00 {
+		return nil, ErrPrivateKey
+	}
+	if _, ok := publicVersions[key.version]; !ok {
+		return nil, fmt.Errorf("unknown extended key version %x", key.version)
+	}
+	if _, err := crypto.DecompressPubkey(key.publicKey); err != nil {
+		return nil, fmt.Errorf("invalid extended public key: %w", err)
+	}
+	return key, nil
+}
+
+// Prefix 扩展公钥的前缀，如 xpub、zpub
+func (k *ExtendedKey) Prefix() string {
+	return publicVersions[k.version]
+}
+
+// Depth 扩展公钥在派生路径中的深度
+func (k *ExtendedKey) Depth() uint8 {
+	return k.depth
+}
+
+// PublicKey 压缩格式的公钥
+func (k *ExtendedKey) PublicKey() []byte {
+	return append([]byte(nil), k.publicKey...)
+}
+
+// UncompressedPublicKey 65 字节的非压缩公钥，evm 和 tron 地址由它生成
+func (k *ExtendedKey) UncompressedPublicKey() []byte {
+	pub, _ := crypto.DecompressPubkey(k.publicKey)
+	return crypto.FromECDSAPub(pub)
+}
+
+// Child 按 BIP32 CKDpub 派生非强化子公钥
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if index >= HardenedOffset {
+		return nil, ErrHardenedChild
+	}
+	data := make([]byte, 37)
+	copy(data, k.publicKey)
+	binary.BigEndian.PutUint32(data[33:], index)
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	curve := crypto.S256()
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(curve.Params().N) >= 0 {
+		return nil, ErrInvalidChild
+	}
+	parent, err := crypto.DecompressPubkey(k.publicKey)
+	if err != nil {
+		return nil, err
+	}
+	ilx, ily := curve.ScalarBaseMult(sum[:32])
+	x, y := curve.Add(ilx, ily, parent.X, parent.Y)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, ErrInvalidChild
+	}
+	child := &ExtendedKey{
+		version:    k.version,
+		depth:      k.depth + 1,
+		childIndex: index,
+		chainCode:  append([]byte(nil), sum[32:]...),
+		publicKey:  compress(x, y),
+	}
+	copy(child.fingerprint[:], hash160(k.publicKey)[:4])
+	return child, nil
+}
+
+// Derive 依次派生路径上的每一级，如 Derive(0, 5) 为外部链第 5 个地址
+func (k *ExtendedKey) Derive(path ...uint32) (*ExtendedKey, error) {
+	key := k
+	for _, index := range path {
+		child, err := key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+		key = child
+	}
+	return key, nil
+}
+
+// String base58check 编码，版本号与父密钥相同
+func (k *ExtendedKey) String() string {
+	payload := make([]byte, 0, serializedKeyLength+4)
+	payload = append(payload, k.version[:]...)
+	payload = append(payload, k.depth)
+	payload = append(payload, k.fingerprint[:]...)
+	payload = binary.BigEndian.AppendUint32(payload, k.childIndex)
+	payload = append(payload, k.chainCode...)
+	payload = append(payload, k.publicKey...)
+	payload = append(payload, doubleSha256(payload)[:4]...)
+	return encodeBase58(payload)
+}
+
+func compress(x, y *big.Int) []byte {
+	out := make([]byte, 33)
+	out[0] = 0x02 + byte(y.Bit(0))
+	x.FillBytes(out[1:])
+	return out
+}
+
+func doubleSha256(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:]
+}
@@ -0,0 +1,50 @@
+package hdkey
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// BIP32 test vector 1: m/0H 的扩展公钥及其非强化子路径
+const (
+	vector1M0H      = "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw"
+	vector1M0H1     = "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ"
+	vector1M0H12H2  = "xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV"
+	vector1M0H12H2B = "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy"
+	vector1Private  = "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"
+)
+
+func TestDeriveBip32Vector(t *testing.T) {
+	key, err := ParseExtendedKey(vector1M0H)
+	require.NoError(t, err)
+	require.Equal(t, "xpub", key.Prefix())
+	require.Equal(t, vector1M0H, key.String())
+
+	child, err := key.Child(1)
+	require.NoError(t, err)
+	require.Equal(t, vector1M0H1, child.String())
+
+	deep, err := ParseExtendedKey(vector1M0H12H2)
+	require.NoError(t, err)
+	leaf, err := deep.Derive(1000000000)
+	require.NoError(t, err)
+	require.Equal(t, vector1M0H12H2B, leaf.String())
+	require.Len(t, leaf.PublicKey(), 33)
+	require.Len(t, leaf.UncompressedPublicKey(), 65)
+	require.Equal(t, hex.EncodeToString(leaf.PublicKey()[1:]), hex.EncodeToString(leaf.UncompressedPublicKey()[1:33]))
+}
+
+func TestParseExtendedKeyRejects(t *testing.T) {
+	_, err := ParseExtendedKey(vector1Private)
+	require.ErrorIs(t, err, ErrPrivateKey)
+
+	_, err = ParseExtendedKey(vector1M0H[:len(vector1M0H)-1] + "x")
+	require.Error(t, err)
+
+	key, err := ParseExtendedKey(vector1M0H)
+	require.NoError(t, err)
+	_, err = key.Child(HardenedOffset)
+	require.ErrorIs(t, err, ErrHardenedChild)
+}
@@ -0,0 +1,333 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type ClientAuthMode string
+
+const (
+	ClientAuthNone     ClientAuthMode = "none"
+	ClientAuthRequest  ClientAuthMode = "request"
+	ClientAuthRequired ClientAuthMode = "require"
+)
+
+func ParseClientAuthMode(s string) (ClientAuthMode, error) {
+	switch strings.ToLower(s) {
+	case "", string(ClientAuthNone):
+		return ClientAuthNone, nil
+	case string(ClientAuthRequest):
+		return ClientAuthRequest, nil
+	case string(ClientAuthRequired):
+		return ClientAuthRequired, nil
+	default:
+		return "", fmt.Errorf("invalid client auth mode: %s", s)
+	}
+}
+
+// ServerConfig 入站 gRPC 服务的 TLS 配置，ClientCAFile 非空时校验客户端证书
+type ServerConfig struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	ClientAuth   ClientAuthMode
+}
+
+// ClientConfig 出站连接的 TLS 配置，CAFile 用于固定服务端证书的签发 CA
+type ClientConfig struct {
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+// KeyPairReloader 在证书文件发生变化时重新加载证书，每次握手前检查文件修改时间
+type KeyPairReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func NewKeyPairReloader(certFile, keyFile string) (*KeyPairReloader, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("cert file and key file cannot be empty")
+	}
+	r := &KeyPairReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *KeyPairReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load key pair fail: %w", err)
+	}
+	modTime, err := latestModTime(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.cert = &cert
+	r.modTime = modTime
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *KeyPairReloader) maybeReload() {
+	modTime, err := latestModTime(r.certFile, r.keyFile)
+	if err != nil {
+		log.Warn("stat certificate fail, keep current certificate", "cert", r.certFile, "err", err)
+		return
+	}
+	r.mu.RLock()
+	changed := modTime.After(r.modTime)
+	r.mu.RUnlock()
+	if !changed {
+		return
+	}
+	if err := r.reload(); err != nil {
+		log.Error("reload certificate fail, keep current certificate", "cert", r.certFile, "err", err)
+		return
+	}
+	log.Info("certificate reloaded", "cert", r.certFile)
+}
+
+func (r *KeyPairReloader) Certificate() *tls.Certificate {
+	r.maybeReload()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+func (r *KeyPairReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+func (r *KeyPairReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// CertPoolReloader 在 CA 文件发生变化时重新加载证书池
+type CertPoolReloader struct {
+	caFile string
+
+	mu      sync.RWMutex
+	pool    *x509.CertPool
+	modTime time.Time
+}
+
+func NewCertPoolReloader(caFile string) (*CertPoolReloader, error) {
+	r := &CertPoolReloader{caFile: caFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *CertPoolReloader) reload() error {
+	pool, err := LoadCertPool(r.caFile)
+	if err != nil {
+		return err
+	}
+	modTime, err := latestModTime(r.caFile)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.pool = pool
+	r.modTime = modTime
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *CertPoolReloader) Pool() *x509.CertPool {
+	modTime, err := latestModTime(r.caFile)
+	if err == nil {
+		r.mu.RLock()
+		changed := modTime.After(r.modTime)
+		r.mu.RUnlock()
+		if changed {
+			if err := r.reload(); err != nil {
+				log.Error("reload ca file fail, keep current pool", "ca", r.caFile, "err", err)
+			} else {
+				log.Info("ca file reloaded", "ca", r.caFile)
+			}
+		}
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.pool
+}
+
+func LoadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("read ca file fail: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no valid certificate found in %s", caFile)
+	}
+	return pool, nil
+}
+
+// NewServerTLSConfig 构建服务端 TLS 配置，证书与客户端 CA 均支持热加载
+func NewServerTLSConfig(cfg ServerConfig) (*tls.Config, error) {
+	keyPair, err := NewKeyPairReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	base := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: keyPair.GetCertificate,
+	}
+	if cfg.ClientAuth == ClientAuthNone {
+		return base, nil
+	}
+	if cfg.ClientCAFile == "" {
+		return nil, errors.New("client ca file is required when client auth is enabled")
+	}
+	clientCAs, err := NewCertPoolReloader(cfg.ClientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	clientAuth := tls.RequireAndVerifyClientCert
+	if cfg.ClientAuth == ClientAuthRequest {
+		clientAuth = tls.VerifyClientCertIfGiven
+	}
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: keyPair.GetCertificate,
+			ClientAuth:     clientAuth,
+			ClientCAs:      clientCAs.Pool(),
+		}, nil
+	}
+	return base, nil
+}
+
+// NewClientTLSConfig 构建客户端 TLS 配置，CAFile 为空时使用系统根证书，证书与 CA 均支持热加载
+func NewClientTLSConfig(cfg ClientConfig) (*tls.Config, error) {
+	tlsCfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+	if cfg.CAFile != "" {
+		rootCAs, err := NewCertPoolReloader(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		// RootCAs 只在创建时读取一次，改为在 VerifyConnection 中按最新的 CA 校验服务端证书
+		tlsCfg.InsecureSkipVerify = true
+		tlsCfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			return verifyServerChain(cs, rootCAs.Pool(), cfg.ServerName)
+		}
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		keyPair, err := NewKeyPairReloader(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.GetClientCertificate = keyPair.GetClientCertificate
+	}
+	return tlsCfg, nil
+}
+
+// verifyServerChain 按 crypto/tls 默认的规则校验服务端证书链和主机名，IP 地址不会出现在 SNI 中，优先使用配置的 serverName
+func verifyServerChain(cs tls.ConnectionState, roots *x509.CertPool, serverName string) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("server did not present a certificate")
+	}
+	if serverName == "" {
+		serverName = cs.ServerName
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		DNSName:       serverName,
+	})
+	if err != nil {
+		return fmt.Errorf("verify server certificate fail: %w", err)
+	}
+	return nil
+}
+
+// Identity 从客户端证书中提取的身份信息
+type Identity struct {
+	CommonName   string
+	Organization []string
+	DNSNames     []string
+	URIs         []string
+}
+
+func IdentityFromCertificate(cert *x509.Certificate) *Identity {
+	if cert == nil {
+		return nil
+	}
+	identity := &Identity{
+		CommonName:   cert.Subject.CommonName,
+		Organization: cert.Subject.Organization,
+		DNSNames:     cert.DNSNames,
+	}
+	for _, uri := range cert.URIs {
+		identity.URIs = append(identity.URIs, uri.String())
+	}
+	return identity
+}
+
+// Names 返回身份中所有可用于授权匹配的名字
+func (i *Identity) Names() []string {
+	if i == nil {
+		return nil
+	}
+	var names []string
+	if i.CommonName != "" {
+		names = append(names, i.CommonName)
+	}
+	names = append(names, i.DNSNames...)
+	names = append(names, i.URIs...)
+	return names
+}
+
+func (i *Identity) Matches(allowed []string) bool {
+	for _, name := range i.Names() {
+		for _, a := range allowed {
+			if name == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func latestModTime(files ...string) (time.Time, error) {
+	var latest time.Time
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("stat %s fail: %w", file, err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,168 @@
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCert(t *testing.T, cn string, parent *testCert, isCA bool) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:              []string{cn},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key}
+}
+
+func (c *testCert) write(t *testing.T, dir, name string) (string, string) {
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0600))
+	keyDer, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
+
+func TestKeyPairReloader(t *testing.T) {
+	dir := t.TempDir()
+	first := newTestCert(t, "first", nil, false)
+	certFile, keyFile := first.write(t, dir, "server")
+
+	reloader, err := NewKeyPairReloader(certFile, keyFile)
+	require.NoError(t, err)
+	require.Equal(t, first.cert.Raw, reloader.Certificate().Certificate[0])
+
+	second := newTestCert(t, "second", nil, false)
+	second.write(t, dir, "server")
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, future, future))
+	require.Equal(t, second.cert.Raw, reloader.Certificate().Certificate[0])
+}
+
+func TestMutualTLSHandshake(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "test-ca", nil, true)
+	caFile, _ := ca.write(t, dir, "ca")
+	serverCertFile, serverKeyFile := newTestCert(t, "localhost", ca, false).write(t, dir, "server")
+	clientCertFile, clientKeyFile := newTestCert(t, "business-a", ca, false).write(t, dir, "client")
+
+	serverCfg, err := NewServerTLSConfig(ServerConfig{
+		CertFile:     serverCertFile,
+		KeyFile:      serverKeyFile,
+		ClientCAFile: caFile,
+		ClientAuth:   ClientAuthRequired,
+	})
+	require.NoError(t, err)
+	clientCfg, err := NewClientTLSConfig(ClientConfig{
+		CAFile:     caFile,
+		CertFile:   clientCertFile,
+		KeyFile:    clientKeyFile,
+		ServerName: "localhost",
+	})
+	require.NoError(t, err)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverCfg)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	identities := make(chan *Identity, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			identities <- nil
+			return
+		}
+		defer conn.Close()
+		tlsConn := conn.(*tls.Conn)
+		if err := tlsConn.Handshake(); err != nil {
+			identities <- nil
+			return
+		}
+		identities <- IdentityFromCertificate(tlsConn.ConnectionState().VerifiedChains[0][0])
+	}()
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientCfg)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	identity := <-identities
+	require.NotNil(t, identity)
+	require.Equal(t, "business-a", identity.CommonName)
+	require.True(t, identity.Matches([]string{"business-a"}))
+	require.False(t, identity.Matches([]string{"business-b"}))
+}
+
+func TestClientCAReload(t *testing.T) {
+	dir := t.TempDir()
+	oldCA := newTestCert(t, "old-ca", nil, true)
+	newCA := newTestCert(t, "new-ca", nil, true)
+	caFile, _ := oldCA.write(t, dir, "ca")
+	serverCertFile, serverKeyFile := newTestCert(t, "localhost", newCA, false).write(t, dir, "server")
+
+	serverCfg, err := NewServerTLSConfig(ServerConfig{CertFile: serverCertFile, KeyFile: serverKeyFile, ClientAuth: ClientAuthNone})
+	require.NoError(t, err)
+	clientCfg, err := NewClientTLSConfig(ClientConfig{CAFile: caFile, ServerName: "localhost"})
+	require.NoError(t, err)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverCfg)
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	_, err = tls.Dial("tcp", listener.Addr().String(), clientCfg)
+	require.Error(t, err)
+
+	newCA.write(t, dir, "ca")
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(caFile, future, future))
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientCfg)
+	require.NoError(t, err)
+	conn.Close()
+}
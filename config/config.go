@@ -14,10 +14,14 @@ const (
 	defaultSynchronizerInterval = 5000
 	defaultWorkerInterval       = 500
 	defaultBlocksStep           = 500
+	defaultTxExpiry             = 24 * time.Hour
 )
 
 type Config struct {
 	Migrations      string
+	ArchiveDir      string
+	ChainsConfig    string
+	FeeStrategy     string
 	ChainNode       ChainNodeConfig
 	MasterDB        DBConfig
 	SlaveDB         DBConfig
@@ -25,8 +29,12 @@ type Config struct {
 	ApiCacheEnable  bool
 	CacheConfig     CacheConfig
 	RpcServer       ServerConfig
+	RpcServerTLS    TLSConfig
 	MetricsServer   ServerConfig
 	ChainAccountRpc string
+	ChainAccountTLS TLSConfig
+	Reconcile       ReconcileConfig
+	Alert           AlertConfig
 }
 
 type ChainNodeConfig struct {
@@ -38,6 +46,35 @@ type ChainNodeConfig struct {
 	SynchronizerInterval time.Duration
 	WorkerInterval       time.Duration
 	BlocksStep           uint64
+	// TxExpiry 提现和内部交易创建后超过该时长仍未签名或未广播成功时过期，释放锁定的 UTXO
+	TxExpiry time.Duration
+}
+
+// ReconcileConfig 余额对账配置，Tolerance 和 NotifyThreshold 为链上最小单位的整数
+type ReconcileConfig struct {
+	Enable          bool
+	Interval        time.Duration
+	AutoCorrect     bool
+	Tolerance       string
+	NotifyThreshold string
+}
+
+// AlertConfig 告警配置，至少配置一个发送渠道，未配置的渠道不启用
+type AlertConfig struct {
+	Enable               bool
+	SyncLagBlocks        uint64
+	BroadcastFailureRate float64
+	BroadcastWindow      int
+	NotifyBacklogAge     time.Duration
+	CoolDown             time.Duration
+	WebhookUrl           string
+	SlackWebhookUrl      string
+	SmtpHost             string
+	SmtpPort             int
+	SmtpUser             string
+	SmtpPassword         string
+	SmtpFrom             string
+	SmtpTo               []string
 }
 
 type DBConfig struct {
@@ -60,6 +97,17 @@ type ServerConfig struct {
 	Port int
 }
 
+// TLSConfig 服务端使用 CAFile 校验客户端证书，客户端使用 CAFile 固定服务端证书的签发 CA
+type TLSConfig struct {
+	Enable         bool
+	CertFile       string
+	KeyFile        string
+	CAFile         string
+	ClientAuth     string
+	ServerName     string
+	AllowedClients []string
+}
+
 func LoadConfig(cliCtx *cli.Context) (Config, error) {
 	var cfg Config
 	cfg = NewConfig(cliCtx)
@@ -80,6 +128,10 @@ func LoadConfig(cliCtx *cli.Context) (Config, error) {
 		cfg.ChainNode.BlocksStep = defaultBlocksStep
 	}
 
+	if cfg.ChainNode.TxExpiry == 0 {
+		cfg.ChainNode.TxExpiry = defaultTxExpiry
+	}
+
 	log.Info("loaded chain config", "config", cfg.ChainNode)
 	return cfg, nil
 }
@@ -87,6 +139,9 @@ func LoadConfig(cliCtx *cli.Context) (Config, error) {
 func NewConfig(ctx *cli.Context) Config {
 	return Config{
 		Migrations:      ctx.String(flags.MigrationsFlag.Name),
+		ArchiveDir:      ctx.String(flags.ArchiveDirFlag.Name),
+		ChainsConfig:    ctx.String(flags.ChainsConfigFlag.Name),
+		FeeStrategy:     ctx.String(flags.FeeStrategyConfigFlag.Name),
 		ChainAccountRpc: ctx.String(flags.ChainAccountRpcFlag.Name),
 		ChainNode: ChainNodeConfig{
 			ChainId:              ctx.Uint64(flags.ChainIdFlag.Name),
@@ -97,6 +152,7 @@ func NewConfig(ctx *cli.Context) Config {
 			SynchronizerInterval: ctx.Duration(flags.SynchronizerIntervalFlag.Name),
 			WorkerInterval:       ctx.Duration(flags.WorkerIntervalFlag.Name),
 			BlocksStep:           ctx.Uint64(flags.BlocksStepFlag.Name),
+			TxExpiry:             ctx.Duration(flags.TxExpiryFlag.Name),
 		},
 		MasterDB: DBConfig{
 			Host:     ctx.String(flags.MasterDbHostFlag.Name),
@@ -124,6 +180,44 @@ func NewConfig(ctx *cli.Context) Config {
 			Host: ctx.String(flags.RpcHostFlag.Name),
 			Port: ctx.Int(flags.RpcPortFlag.Name),
 		},
+		RpcServerTLS: TLSConfig{
+			Enable:         ctx.Bool(flags.RpcTLSEnableFlag.Name),
+			CertFile:       ctx.String(flags.RpcTLSCertFlag.Name),
+			KeyFile:        ctx.String(flags.RpcTLSKeyFlag.Name),
+			CAFile:         ctx.String(flags.RpcTLSClientCAFlag.Name),
+			ClientAuth:     ctx.String(flags.RpcTLSClientAuthFlag.Name),
+			AllowedClients: ctx.StringSlice(flags.RpcTLSAllowedClientsFlag.Name),
+		},
+		ChainAccountTLS: TLSConfig{
+			Enable:     ctx.Bool(flags.ChainAccountTLSEnableFlag.Name),
+			CertFile:   ctx.String(flags.ChainAccountTLSCertFlag.Name),
+			KeyFile:    ctx.String(flags.ChainAccountTLSKeyFlag.Name),
+			CAFile:     ctx.String(flags.ChainAccountTLSCAFlag.Name),
+			ServerName: ctx.String(flags.ChainAccountTLSServerNameFlag.Name),
+		},
+		Reconcile: ReconcileConfig{
+			Enable:          ctx.Bool(flags.ReconcileEnableFlag.Name),
+			Interval:        ctx.Duration(flags.ReconcileIntervalFlag.Name),
+			AutoCorrect:     ctx.Bool(flags.ReconcileAutoCorrectFlag.Name),
+			Tolerance:       ctx.String(flags.ReconcileToleranceFlag.Name),
+			NotifyThreshold: ctx.String(flags.ReconcileNotifyThresholdFlag.Name),
+		},
+		Alert: AlertConfig{
+			Enable:               ctx.Bool(flags.AlertEnableFlag.Name),
+			SyncLagBlocks:        ctx.Uint64(flags.AlertSyncLagBlocksFlag.Name),
+			BroadcastFailureRate: ctx.Float64(flags.AlertBroadcastFailureRateFlag.Name),
+			BroadcastWindow:      ctx.Int(flags.AlertBroadcastWindowFlag.Name),
+			NotifyBacklogAge:     ctx.Duration(flags.AlertNotifyBacklogAgeFlag.Name),
+			CoolDown:             ctx.Duration(flags.AlertCoolDownFlag.Name),
+			WebhookUrl:           ctx.String(flags.AlertWebhookUrlFlag.Name),
+			SlackWebhookUrl:      ctx.String(flags.AlertSlackWebhookUrlFlag.Name),
+			SmtpHost:             ctx.String(flags.AlertSmtpHostFlag.Name),
+			SmtpPort:             ctx.Int(flags.AlertSmtpPortFlag.Name),
+			SmtpUser:             ctx.String(flags.AlertSmtpUserFlag.Name),
+			SmtpPassword:         ctx.String(flags.AlertSmtpPasswordFlag.Name),
+			SmtpFrom:             ctx.String(flags.AlertSmtpFromFlag.Name),
+			SmtpTo:               ctx.StringSlice(flags.AlertSmtpToFlag.Name),
+		},
 		MetricsServer: ServerConfig{
 			Host: ctx.String(flags.MetricsHostFlag.Name),
 			Port: ctx.Int(flags.MetricsPortFlag.Name),
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"strings"
+
+	"github.com/dapplink-labs/multichain-sync-account/common/chainaddr"
+)
+
+// GetAddressCodec 按链的交易族选择地址规则，未登记的链地址保持原样
+func GetAddressCodec(chainName string) chainaddr.Codec {
+	switch GetTxFamily(chainName) {
+	case TxFamilyEVM1559, TxFamilyEVMLegacy:
+		return chainaddr.EVM
+	case TxFamilyTron:
+		return chainaddr.Tron
+	case TxFamilySolana:
+		return chainaddr.Solana
+	case TxFamilyXrp:
+		return chainaddr.Xrp
+	case TxFamilyTon:
+		return chainaddr.Ton
+	case TxFamilyCosmos:
+		return chainaddr.Cosmos
+	case TxFamilyUTXO:
+		return chainaddr.UTXO
+	default:
+		return chainaddr.Passthrough
+	}
+}
+
+// ValidateAddress 按链的本地规则校验地址，返回入库使用的规范形式
+func ValidateAddress(chainName string, address string) (string, error) {
+	return GetAddressCodec(chainName).Normalize(address)
+}
+
+// NormalizeAddress 写入和查询前统一地址形式，无法识别的地址(如空的主币合约地址)原样返回
+func NormalizeAddress(chainName string, address string) string {
+	normalized, err := GetAddressCodec(chainName).Normalize(address)
+	if err != nil {
+		return strings.TrimSpace(address)
+	}
+	return normalized
+}
+
+// addressLookupForms 查询地址时需要匹配的所有形式
+func addressLookupForms(chainName string, address string) []string {
+	return GetAddressCodec(chainName).LookupForms(address)
+}
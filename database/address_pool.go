@@ -0,0 +1,219 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/dapplink-labs/multichain-sync-account/database/utils"
+)
+
+type AddressPoolStatus string
+
+const (
+	// AddressPoolPending 公钥已上传，等待 worker 生成地址
+	AddressPoolPending AddressPoolStatus = "pending"
+	// AddressPoolAvailable 地址已生成并登记为充值地址，尚未分配给用户
+	AddressPoolAvailable AddressPoolStatus = "available"
+	AddressPoolAssigned  AddressPoolStatus = "assigned"
+	// AddressPoolFailed chain-account 无法从公钥生成地址
+	AddressPoolFailed AddressPoolStatus = "failed"
+)
+
+// AddressPool 业务方批量上传的公钥，生成地址后按需分配给业务方的用户
+type AddressPool struct {
+	GUID      uuid.UUID `gorm:"primaryKey" json:"guid"`
+	PublicKey string    `gorm:"type:varchar;not null" json:"public_key"`
+	Address   string    `gorm:"type:varchar;not null;default:''" json:"address"`
+	// AddressFormat 生成地址时传给 chain-account 的地址格式，空为链的默认格式
+	AddressFormat string            `gorm:"type:varchar;not null;default:''" json:"address_format"`
+	Status        AddressPoolStatus `gorm:"type:varchar(10);not null;default:'pending'" json:"status"`
+	UserId        string            `gorm:"type:varchar;not null;default:''" json:"user_id"`
+	AssignedAt    uint64            `gorm:"not null;default:0" json:"assigned_at"`
+	Timestamp     uint64            `gorm:"not null;check:timestamp > 0" json:"timestamp"`
+}
+
+// PoolWatermarkNotice 已通知过低水位的业务和链，地址池补充到水位以上后删除
+type PoolWatermarkNotice struct {
+	BusinessUid string `gorm:"primaryKey;type:varchar" json:"business_uid"`
+	ChainName   string `gorm:"primaryKey;type:varchar" json:"chain_name"`
+	Timestamp   uint64 `gorm:"not null;check:timestamp > 0" json:"timestamp"`
+}
+
+// AddressPoolStats 地址池中各状态的数量
+type AddressPoolStats struct {
+	Pending   int64
+	Available int64
+	Assigned  int64
+	Failed    int64
+}
+
+type AddressPoolView interface {
+	QueryPendingPoolKeys(requestId string, chainName string, limit int) ([]*AddressPool, error)
+	QueryPoolAddressByUserId(requestId string, chainName string, userId string) (*AddressPool, error)
+	QueryAddressPoolStats(requestId string, chainName string) (*AddressPoolStats, error)
+	QueryPoolLowNotified(requestId string, chainName string) (bool, error)
+}
+
+type AddressPoolDB interface {
+	AddressPoolView
+
+	StorePoolKeys(requestId string, chainName string, poolList []*AddressPool) (int64, error)
+	UpdatePoolLowNotified(requestId string, chainName string, notified bool) error
+	UpdatePoolKeysDerived(requestId string, chainName string, poolList []*AddressPool) error
+	AssignPoolAddress(requestId string, chainName string, userId string) (*AddressPool, error)
+}
+
+type addressPoolDB struct {
+	gorm *gorm.DB
+}
+
+func NewAddressPoolDB(db *gorm.DB) AddressPoolDB {
+	return &addressPoolDB{gorm: db}
+}
+
+// StorePoolKeys 重复上传的公钥不做任何操作，返回新加入地址池的数量
+func (db *addressPoolDB) StorePoolKeys(requestId string, chainName string, poolList []*AddressPool) (int64, error) {
+	if len(poolList) == 0 {
+		return 0, nil
+	}
+	tableName := utils.GetTableName("address_pool", requestId, chainName)
+	result := db.gorm.Table(tableName).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "public_key"}},
+			DoNothing: true,
+		}).
+		CreateInBatches(poolList, len(poolList))
+	if result.Error != nil {
+		return 0, fmt.Errorf("store address pool keys failed: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (db *addressPoolDB) QueryPendingPoolKeys(requestId string, chainName string, limit int) ([]*AddressPool, error) {
+	var poolList []*AddressPool
+	tableName := utils.GetTableName("address_pool", requestId, chainName)
+	err := db.gorm.Table(tableName).
+		Where("status = ?", AddressPoolPending).
+		Order("timestamp ASC").
+		Limit(limit).
+		Find(&poolList).Error
+	if err != nil {
+		return nil, fmt.Errorf("query pending address pool keys failed: %w", err)
+	}
+	return poolList, nil
+}
+
+// UpdatePoolKeysDerived 保存 worker 生成的地址和状态，只更新仍为 pending 的记录
+func (db *addressPoolDB) UpdatePoolKeysDerived(requestId string, chainName string, poolList []*AddressPool) error {
+	tableName := utils.GetTableName("address_pool", requestId, chainName)
+	for _, pool := range poolList {
+		result := db.gorm.Table(tableName).
+			Where("guid = ? AND status = ?", pool.GUID, AddressPoolPending).
+			Updates(map[string]interface{}{"address": pool.Address, "status": pool.Status})
+		if result.Error != nil {
+			return fmt.Errorf("update address pool key failed: %w", result.Error)
+		}
+	}
+	return nil
+}
+
+func (db *addressPoolDB) QueryPoolAddressByUserId(requestId string, chainName string, userId string) (*AddressPool, error) {
+	var pool AddressPool
+	tableName := utils.GetTableName("address_pool", requestId, chainName)
+	err := db.gorm.Table(tableName).
+		Where("user_id = ? AND status = ?", userId, AddressPoolAssigned).
+		Take(&pool).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query pool address by user id failed: %w", err)
+	}
+	return &pool, nil
+}
+
+// AssignPoolAddress 取最早生成的可用地址分配给用户，并发分配时跳过被锁定的记录，地址池为空时返回 nil
+func (db *addressPoolDB) AssignPoolAddress(requestId string, chainName string, userId string) (*AddressPool, error) {
+	tableName := utils.GetTableName("address_pool", requestId, chainName)
+	var poolList []*AddressPool
+	err := db.gorm.Raw(fmt.Sprintf(`UPDATE %s SET status = ?, user_id = ?, assigned_at = ?
+		WHERE guid = (SELECT guid FROM %s WHERE status = ? ORDER BY timestamp ASC LIMIT 1 FOR UPDATE SKIP LOCKED)
+		RETURNING *`, tableName, tableName),
+		AddressPoolAssigned, userId, uint64(time.Now().Unix()), AddressPoolAvailable).
+		Scan(&poolList).Error
+	if err != nil {
+		return nil, fmt.Errorf("assign pool address failed: %w", err)
+	}
+	if len(poolList) == 0 {
+		return nil, nil
+	}
+	return poolList[0], nil
+}
+
+func (db *addressPoolDB) QueryAddressPoolStats(requestId string, chainName string) (*AddressPoolStats, error) {
+	var rows []struct {
+		Status AddressPoolStatus
+		Total  int64
+	}
+	tableName := utils.GetTableName("address_pool", requestId, chainName)
+	err := db.gorm.Table(tableName).
+		Select("status, COUNT(*) AS total").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("query address pool stats failed: %w", err)
+	}
+	stats := &AddressPoolStats{}
+	for _, row := range rows {
+		switch row.Status {
+		case AddressPoolPending:
+			stats.Pending = row.Total
+		case AddressPoolAvailable:
+			stats.Available = row.Total
+		case AddressPoolAssigned:
+			stats.Assigned = row.Total
+		case AddressPoolFailed:
+			stats.Failed = row.Total
+		}
+	}
+	return stats, nil
+}
+
+// Total 地址池中所有状态的公钥数量，为 0 表示业务没有使用地址池
+func (s *AddressPoolStats) Total() int64 {
+	return s.Pending + s.Available + s.Assigned + s.Failed
+}
+
+func (db *addressPoolDB) QueryPoolLowNotified(requestId string, chainName string) (bool, error) {
+	var count int64
+	err := db.gorm.Table("pool_watermark_notices").
+		Where("business_uid = ? AND chain_name = ?", requestId, chainName).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("query pool watermark notice fail: %w", err)
+	}
+	return count > 0, nil
+}
+
+// UpdatePoolLowNotified notified 为 true 时记录已通知低水位，为 false 时清除记录
+func (db *addressPoolDB) UpdatePoolLowNotified(requestId string, chainName string, notified bool) error {
+	var err error
+	if notified {
+		err = db.gorm.Table("pool_watermark_notices").
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&PoolWatermarkNotice{BusinessUid: requestId, ChainName: chainName, Timestamp: uint64(time.Now().Unix())}).Error
+	} else {
+		err = db.gorm.Table("pool_watermark_notices").
+			Where("business_uid = ? AND chain_name = ?", requestId, chainName).
+			Delete(&PoolWatermarkNotice{}).Error
+	}
+	if err != nil {
+		return fmt.Errorf("update pool watermark notice fail: %w", err)
+	}
+	return nil
+}
@@ -12,6 +12,9 @@ import (
 	"github.com/dapplink-labs/multichain-sync-account/common/json2"
 )
 
+// CurrentChain 测试业务表使用的链名
+const CurrentChain = "ethereum"
+
 func TestAddressesDB_StoreAndQuery(t *testing.T) {
 	const (
 		CurrentRequestId = 1
@@ -24,30 +27,30 @@ func TestAddressesDB_StoreAndQuery(t *testing.T) {
 
 	address := &Addresses{
 		GUID:        uuid.New(),
-		Address:     common.HexToAddress("0x1234567890abcdef1234567890abcdef12345678"),
+		Address:     common.HexToAddress("0x1234567890abcdef1234567890abcdef12345678").String(),
 		AddressType: AddressTypeEOA,
 		PublicKey:   "public_key_example",
 		Timestamp:   uint64(time.Now().Unix()),
 	}
 
-	err := addressesDB.StoreAddresses(strconv.Itoa(CurrentRequestId), []*Addresses{address})
+	err := addressesDB.StoreAddresses(strconv.Itoa(CurrentRequestId), CurrentChain, []*Addresses{address})
 	if err != nil {
 		t.Errorf("Failed to store balances: %v", err)
 	}
 
 	// Test AddressExist
-	exists, addrType := addressesDB.AddressExist(strconv.Itoa(CurrentRequestId), &address.Address)
+	exists, addrType := addressesDB.AddressExist(strconv.Itoa(CurrentRequestId), CurrentChain, address.Address)
 	assert.True(t, exists)
 	assert.Equal(t, AddressTypeEOA, addrType)
 
 	// Test QueryAddressesByToAddress
-	result, err := addressesDB.QueryAddressesByToAddress(strconv.Itoa(CurrentRequestId), &address.Address)
+	result, err := addressesDB.QueryAddressesByToAddress(strconv.Itoa(CurrentRequestId), CurrentChain, address.Address)
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	t.Logf("result %v", json2.ToPrettyJSON(result))
 
 	// Test GetAllAddresses
-	allAddresses, err := addressesDB.GetAllAddresses(strconv.Itoa(CurrentRequestId))
+	allAddresses, err := addressesDB.GetAllAddresses(strconv.Itoa(CurrentRequestId), CurrentChain)
 	assert.NoError(t, err)
 	assert.Len(t, allAddresses, 1)
 	t.Logf("result %v", json2.ToPrettyJSON(allAddresses))
@@ -65,7 +68,7 @@ func TestAddressesDB_QueryHotAndColdWalletInfo(t *testing.T) {
 
 	hotAddress := &Addresses{
 		GUID:        uuid.New(),
-		Address:     common.HexToAddress("0xabcdefabcdefabcdefabcdefabcdefabcde1"),
+		Address:     common.HexToAddress("0xabcdefabcdefabcdefabcdefabcdefabcde1").String(),
 		AddressType: AddressTypeHot,
 		PublicKey:   "hot_public_key",
 		Timestamp:   uint64(time.Now().Unix()),
@@ -73,23 +76,23 @@ func TestAddressesDB_QueryHotAndColdWalletInfo(t *testing.T) {
 
 	coldAddress := &Addresses{
 		GUID:        uuid.New(),
-		Address:     common.HexToAddress("0xabcdefabcdefabcdefabcdefabcdefabcde2"),
+		Address:     common.HexToAddress("0xabcdefabcdefabcdefabcdefabcdefabcde2").String(),
 		AddressType: AddressTypeCold,
 		PublicKey:   "cold_public_key",
 		Timestamp:   uint64(time.Now().Unix()),
 	}
 
-	err := addressesDB.StoreAddresses(strconv.Itoa(CurrentRequestId), []*Addresses{hotAddress, coldAddress})
+	err := addressesDB.StoreAddresses(strconv.Itoa(CurrentRequestId), CurrentChain, []*Addresses{hotAddress, coldAddress})
 	assert.NoError(t, err)
 
 	// Test QueryHotWalletInfo
-	hotResult, err := addressesDB.QueryHotWalletInfo(strconv.Itoa(CurrentRequestId))
+	hotResult, err := addressesDB.QueryHotWalletInfo(strconv.Itoa(CurrentRequestId), CurrentChain)
 	assert.NoError(t, err)
 	assert.NotNil(t, hotResult)
 	t.Logf("hotResult %v", json2.ToPrettyJSON(hotResult))
 
 	// Test QueryColdWalletInfo
-	coldResult, err := addressesDB.QueryColdWalletInfo(strconv.Itoa(CurrentRequestId))
+	coldResult, err := addressesDB.QueryColdWalletInfo(strconv.Itoa(CurrentRequestId), CurrentChain)
 	assert.NoError(t, err)
 	assert.NotNil(t, coldResult)
 	t.Logf("coldResult %v", json2.ToPrettyJSON(coldResult))
@@ -2,7 +2,7 @@ package database
 
 import (
 	"errors"
-	"strings"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
@@ -17,13 +17,19 @@ type Addresses struct {
 	Address     string      `gorm:"type:varchar;unique;not null" json:"address"`
 	AddressType AddressType `gorm:"type:varchar(10);not null;default:'eoa'" json:"address_type"`
 	PublicKey   string      `gorm:"type:varchar;not null" json:"public_key"`
-	Timestamp   uint64      `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+	// UserId 地址池分配地址时业务方传入的用户 id，直接导出的地址为空
+	UserId string `gorm:"type:varchar;not null;default:''" json:"user_id"`
+	// ExtendedKeyId 和 DerivationIndex 为服务端从扩展公钥派生的地址来源，直接导出的地址为空
+	ExtendedKeyId   string `gorm:"type:varchar;not null;default:''" json:"extended_key_id"`
+	DerivationIndex uint32 `gorm:"type:bigint;not null;default:0" json:"derivation_index"`
+	Timestamp       uint64 `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
 }
 
 type AddressesView interface {
 	AddressExist(requestId string, chainName string, address string) (bool, AddressType)
 	QueryAddressesByToAddress(requestId string, chainName string, address string) (*Addresses, error)
 	QueryHotWalletInfo(requestId string, chainName string) (*Addresses, error)
+	QueryHotWallets(requestId string, chainName string) ([]*Addresses, error)
 	QueryColdWalletInfo(requestId string, chainName string) (*Addresses, error)
 	GetAllAddresses(requestId string, chainName string) ([]*Addresses, error)
 }
@@ -32,6 +38,7 @@ type AddressesDB interface {
 	AddressesView
 
 	StoreAddresses(requestId string, chainName string, addresses []*Addresses) error
+	UpdateAddressUserId(requestId string, chainName string, address string, userId string) error
 }
 
 func NewAddressesDB(db *gorm.DB) AddressesDB {
@@ -46,7 +53,7 @@ func (db *addressesDB) AddressExist(requestId string, chainName string, address
 	var addressEntry Addresses
 	tableName := utils.GetTableName("addresses", requestId, chainName)
 	err := db.gorm.Table(tableName).
-		Where("address = ?", strings.ToLower(address)).
+		Where("address IN ?", addressLookupForms(chainName, address)).
 		First(&addressEntry).Error
 
 	if err != nil {
@@ -62,7 +69,7 @@ func (db *addressesDB) QueryAddressesByToAddress(requestId string, chainName str
 	var addressEntry Addresses
 	tableName := utils.GetTableName("addresses", requestId, chainName)
 	err := db.gorm.Table(tableName).
-		Where("address = ?", strings.ToLower(address)).
+		Where("address IN ?", addressLookupForms(chainName, address)).
 		Take(&addressEntry).Error
 
 	if err != nil {
@@ -77,6 +84,9 @@ func (db *addressesDB) QueryAddressesByToAddress(requestId string, chainName str
 // StoreAddresses store address, if address already exists, do nothing
 func (db *addressesDB) StoreAddresses(requestId string, chainName string, addressList []*Addresses) error {
 	tableName := utils.GetTableName("addresses", requestId, chainName)
+	for _, address := range addressList {
+		address.Address = NormalizeAddress(chainName, address.Address)
+	}
 
 	// 使用 OnConflict.DoNothing() 在冲突时不更新
 	return db.gorm.Table(tableName).
@@ -87,11 +97,28 @@ func (db *addressesDB) StoreAddresses(requestId string, chainName string, addres
 		CreateInBatches(addressList, len(addressList)).Error
 }
 
+// UpdateAddressUserId 地址池分配地址后记录所属用户，充值通知按该字段带 user_id
+func (db *addressesDB) UpdateAddressUserId(requestId string, chainName string, address string, userId string) error {
+	tableName := utils.GetTableName("addresses", requestId, chainName)
+	result := db.gorm.Table(tableName).
+		Where("address IN ?", addressLookupForms(chainName, address)).
+		Update("user_id", userId)
+	if result.Error != nil {
+		return fmt.Errorf("update address user id failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("update address user id failed: address %s not found", address)
+	}
+	return nil
+}
+
+// QueryHotWalletInfo 返回最早登记的热钱包
 func (db *addressesDB) QueryHotWalletInfo(requestId string, chainName string) (*Addresses, error) {
 	var addressEntry Addresses
 	tableName := utils.GetTableName("addresses", requestId, chainName)
 	err := db.gorm.Table(tableName).
 		Where("address_type = ?", AddressTypeHot).
+		Order("timestamp ASC").
 		Take(&addressEntry).Error
 
 	if err != nil {
@@ -103,6 +130,20 @@ func (db *addressesDB) QueryHotWalletInfo(requestId string, chainName string) (*
 	return &addressEntry, nil
 }
 
+// QueryHotWallets 业务在该链上的全部热钱包，按登记时间排序
+func (db *addressesDB) QueryHotWallets(requestId string, chainName string) ([]*Addresses, error) {
+	var hotWallets []*Addresses
+	tableName := utils.GetTableName("addresses", requestId, chainName)
+	err := db.gorm.Table(tableName).
+		Where("address_type = ?", AddressTypeHot).
+		Order("timestamp ASC").
+		Find(&hotWallets).Error
+	if err != nil {
+		return nil, fmt.Errorf("query hot wallets failed: %w", err)
+	}
+	return hotWallets, nil
+}
+
 func (db *addressesDB) QueryColdWalletInfo(requestId string, chainName string) (*Addresses, error) {
 	var addressEntry Addresses
 	tableName := utils.GetTableName("addresses", requestId, chainName)
@@ -143,7 +184,7 @@ func (a *Addresses) Validate() error {
 		return errors.New("invalid timestamp")
 	}
 	switch a.AddressType {
-	case AddressTypeEOA, AddressTypeHot, AddressTypeCold:
+	case AddressTypeEOA, AddressTypeHot, AddressTypeCold, AddressTypeFee, AddressTypeTreasury:
 		return nil
 	default:
 		return errors.New("invalid address type")
@@ -0,0 +1,161 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/dapplink-labs/multichain-sync-account/database/utils"
+)
+
+// BalanceBucket 流水记账的账户分类，external 为系统外的对手方，只记流水不维护余额
+type BalanceBucket string
+
+const (
+	BucketAvailable BalanceBucket = "available"
+	BucketLocked    BalanceBucket = "locked"
+	BucketExternal  BalanceBucket = "external"
+)
+
+type JournalDirection string
+
+const (
+	JournalCredit JournalDirection = "credit"
+	JournalDebit  JournalDirection = "debit"
+)
+
+type JournalEntryType string
+
+const (
+	JournalDeposit      JournalEntryType = "deposit"
+	JournalWithdraw     JournalEntryType = "withdraw"
+	JournalCollection   JournalEntryType = "collection"
+	JournalHot2Cold     JournalEntryType = "hot2cold"
+	JournalCold2Hot     JournalEntryType = "cold2hot"
+	JournalLock         JournalEntryType = "lock"
+	JournalUnlock       JournalEntryType = "unlock"
+	JournalFee          JournalEntryType = "fee"
+	JournalOpening      JournalEntryType = "opening"
+	JournalAdjustment   JournalEntryType = "adjustment"
+	JournalRebalance    JournalEntryType = "rebalance"
+	JournalTreasury     JournalEntryType = "treasury"
+	JournalFeeFunding   JournalEntryType = "fee_funding"
+	JournalTransfer     JournalEntryType = "transfer"
+	JournalInflow       JournalEntryType = "inflow"
+	JournalOutflow      JournalEntryType = "outflow"
+	JournalUnclassified JournalEntryType = "unclassified"
+)
+
+// 没有具体对手方地址时使用的外部账户
+const (
+	ExternalFeeAccount     = "network_fee"
+	ExternalOpeningAccount = "opening"
+	ExternalAdjustAccount  = "adjustment"
+)
+
+// BalanceJournals 不可变的余额流水，同一个 EntryGroup 内借贷金额相等，balances 表是流水的汇总结果
+type BalanceJournals struct {
+	GUID             uuid.UUID        `gorm:"primaryKey" json:"guid"`
+	Seq              uint64           `gorm:"->;column:seq" json:"seq"`
+	EntryGroup       uuid.UUID        `gorm:"type:varchar;not null" json:"entry_group"`
+	Address          string           `gorm:"type:varchar;not null" json:"address"`
+	TokenAddress     string           `gorm:"type:varchar;not null" json:"token_address"`
+	AddressType      AddressType      `gorm:"type:varchar(10);not null" json:"address_type"`
+	Bucket           BalanceBucket    `gorm:"type:varchar(10);not null" json:"bucket"`
+	Direction        JournalDirection `gorm:"type:varchar(6);not null" json:"direction"`
+	EntryType        JournalEntryType `gorm:"type:varchar(16);not null" json:"entry_type"`
+	Amount           *big.Int         `gorm:"type:numeric;not null;serializer:u256" json:"amount"`
+	BalanceAfter     *big.Int         `gorm:"type:numeric;not null;serializer:u256" json:"balance_after"`
+	LockBalanceAfter *big.Int         `gorm:"type:numeric;not null;serializer:u256" json:"lock_balance_after"`
+	TxHash           string           `gorm:"type:varchar;not null" json:"tx_hash"`
+	BlockNumber      *big.Int         `gorm:"type:numeric;serializer:u256" json:"block_number"`
+	Timestamp        uint64           `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+}
+
+// PointInTimeBalance 某个区块高度或时间点的余额，Found 为 false 表示该时间点之前没有流水
+type PointInTimeBalance struct {
+	Balance     *big.Int
+	LockBalance *big.Int
+	Found       bool
+}
+
+type BalanceJournalsView interface {
+	QueryBalanceHistory(requestId string, chainName string, address, tokenAddress string, page, pageSize int) ([]*BalanceJournals, int64, error)
+	QueryBalanceAtBlock(requestId string, chainName string, address, tokenAddress string, blockNumber *big.Int) (*PointInTimeBalance, error)
+	QueryBalanceAtTime(requestId string, chainName string, address, tokenAddress string, timestamp uint64) (*PointInTimeBalance, error)
+}
+
+type BalanceJournalsDB interface {
+	BalanceJournalsView
+}
+
+type balanceJournalsDB struct {
+	gorm *gorm.DB
+}
+
+func NewBalanceJournalsDB(db *gorm.DB) BalanceJournalsDB {
+	return &balanceJournalsDB{gorm: db}
+}
+
+// accountScope 只查询维护余额的账户流水，external 对手方流水不参与余额计算
+func (db *balanceJournalsDB) accountScope(requestId string, chainName string, address, tokenAddress string) *gorm.DB {
+	tableName := utils.GetTableName("balance_journals", requestId, chainName)
+	return db.gorm.Table(tableName).
+		Where("address IN ? AND token_address IN ? AND bucket <> ?",
+			addressLookupForms(chainName, address), addressLookupForms(chainName, tokenAddress), BucketExternal)
+}
+
+func (db *balanceJournalsDB) QueryBalanceHistory(requestId string, chainName string, address, tokenAddress string, page, pageSize int) ([]*BalanceJournals, int64, error) {
+	var (
+		total    int64
+		journals []*BalanceJournals
+	)
+	if err := db.accountScope(requestId, chainName, address, tokenAddress).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count balance history failed: %w", err)
+	}
+	err := db.accountScope(requestId, chainName, address, tokenAddress).
+		Order("seq DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&journals).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("query balance history failed: %w", err)
+	}
+	return journals, total, nil
+}
+
+// QueryBalanceAtBlock 取第一条高于该区块的流水之前的最后一条流水，锁定等链下流水按记账顺序归入所在区块
+func (db *balanceJournalsDB) QueryBalanceAtBlock(requestId string, chainName string, address, tokenAddress string, blockNumber *big.Int) (*PointInTimeBalance, error) {
+	var nextSeq *uint64
+	err := db.accountScope(requestId, chainName, address, tokenAddress).
+		Where("block_number > ?", blockNumber.String()).
+		Select("MIN(seq)").
+		Scan(&nextSeq).Error
+	if err != nil {
+		return nil, fmt.Errorf("query balance at block failed: %w", err)
+	}
+	query := db.accountScope(requestId, chainName, address, tokenAddress)
+	if nextSeq != nil {
+		query = query.Where("seq < ?", *nextSeq)
+	}
+	return takePointInTimeBalance(query)
+}
+
+func (db *balanceJournalsDB) QueryBalanceAtTime(requestId string, chainName string, address, tokenAddress string, timestamp uint64) (*PointInTimeBalance, error) {
+	return takePointInTimeBalance(db.accountScope(requestId, chainName, address, tokenAddress).Where("timestamp <= ?", timestamp))
+}
+
+func takePointInTimeBalance(query *gorm.DB) (*PointInTimeBalance, error) {
+	var journal BalanceJournals
+	err := query.Order("seq DESC").Take(&journal).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &PointInTimeBalance{Balance: big.NewInt(0), LockBalance: big.NewInt(0)}, nil
+		}
+		return nil, fmt.Errorf("query point in time balance failed: %w", err)
+	}
+	return &PointInTimeBalance{Balance: journal.BalanceAfter, LockBalance: journal.LockBalanceAfter, Found: true}, nil
+}
@@ -4,12 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/dapplink-labs/multichain-sync-account/database/utils"
 )
@@ -32,6 +33,8 @@ type BalancesView interface {
 		address,
 		tokenAddress string,
 	) (*Balances, error)
+	QueryBalanceList(requestId string, chainName string) ([]*Balances, error)
+	QueryBalancesByAddressType(requestId string, chainName string, addressType AddressType) ([]*Balances, error)
 }
 
 type BalancesDB interface {
@@ -39,8 +42,9 @@ type BalancesDB interface {
 
 	UpdateOrCreate(requestId string, chainName string, balances []*TokenBalance) error
 	StoreBalances(requestId string, chainName string, balances []*Balances) error
-	UpdateBalanceListByTwoAddress(requestId string, chainName string, balances []*Balances) error
+	LockBalances(requestId string, chainName string, locks []*TokenBalance) error
 	UpdateBalance(requestId string, chainName string, balance *Balances) error
+	CorrectBalance(requestId string, chainName string, guid uuid.UUID, storedBalance *big.Int, chainBalance *big.Int) (bool, error)
 }
 
 type balancesDB struct {
@@ -51,106 +55,273 @@ func NewBalancesDB(db *gorm.DB) BalancesDB {
 	return &balancesDB{gorm: db}
 }
 
-func (db *balancesDB) StoreBalances(requestId string, chainName string, balanceList []*Balances) error {
-	valueList := make([]Balances, len(balanceList))
-	for i, balance := range balanceList {
-		if balance != nil {
-			valueList[i] = *balance
+// balanceAccount 记账账户，同一地址的可用余额和锁定余额是两个账户
+type balanceAccount struct {
+	address      string
+	tokenAddress string
+	addressType  AddressType
+	bucket       BalanceBucket
+}
+
+// normalized 外部账户中不是链上地址的(如期初、调整账户)由 NormalizeAddress 原样返回
+func (a balanceAccount) normalized(chainName string) balanceAccount {
+	a.address = NormalizeAddress(chainName, a.address)
+	a.tokenAddress = NormalizeAddress(chainName, a.tokenAddress)
+	return a
+}
+
+func availableAccount(addressType AddressType, address, tokenAddress string) balanceAccount {
+	return balanceAccount{address: address, tokenAddress: tokenAddress, addressType: addressType, bucket: BucketAvailable}
+}
+
+func lockedAccount(addressType AddressType, address, tokenAddress string) balanceAccount {
+	return balanceAccount{address: address, tokenAddress: tokenAddress, addressType: addressType, bucket: BucketLocked}
+}
+
+func externalAccount(address, tokenAddress string) balanceAccount {
+	return balanceAccount{address: address, tokenAddress: tokenAddress, bucket: BucketExternal}
+}
+
+// journalMeta 流水关联的交易和区块，链下的锁定和调整流水没有区块
+type journalMeta struct {
+	entryType   JournalEntryType
+	txHash      string
+	blockNumber *big.Int
+}
+
+// postTransfer 写入一组借贷相等的流水，并同步更新 balances 表
+func (db *balancesDB) postTransfer(tx *gorm.DB, requestId string, chainName string, meta journalMeta, from, to balanceAccount, amount *big.Int) error {
+	if amount == nil || amount.Sign() == 0 {
+		return nil
+	}
+	group := uuid.New()
+	if err := db.postEntry(tx, requestId, chainName, group, meta, from, JournalDebit, amount); err != nil {
+		return err
+	}
+	return db.postEntry(tx, requestId, chainName, group, meta, to, JournalCredit, amount)
+}
+
+func (db *balancesDB) postEntry(tx *gorm.DB, requestId string, chainName string, group uuid.UUID, meta journalMeta, account balanceAccount, direction JournalDirection, amount *big.Int) error {
+	now := uint64(time.Now().Unix())
+	account = account.normalized(chainName)
+	entry := &BalanceJournals{
+		GUID:             uuid.New(),
+		EntryGroup:       group,
+		Address:          account.address,
+		TokenAddress:     account.tokenAddress,
+		AddressType:      account.addressType,
+		Bucket:           account.bucket,
+		Direction:        direction,
+		EntryType:        meta.entryType,
+		Amount:           amount,
+		BalanceAfter:     big.NewInt(0),
+		LockBalanceAfter: big.NewInt(0),
+		TxHash:           meta.txHash,
+		BlockNumber:      meta.blockNumber,
+		Timestamp:        now,
+	}
+
+	if account.bucket != BucketExternal {
+		tableName := utils.GetTableName("balances", requestId, chainName)
+		current, err := db.loadOrCreateBalance(tx, tableName, chainName, account)
+		if err != nil {
+			return err
+		}
+		delta := new(big.Int).Set(amount)
+		if direction == JournalDebit {
+			delta.Neg(delta)
 		}
+		target := current.Balance
+		if account.bucket == BucketLocked {
+			target = current.LockBalance
+		}
+		target.Add(target, delta)
+		if target.Sign() < 0 {
+			return fmt.Errorf("insufficient %s balance of %s token %s for %s %s", account.bucket, account.address, account.tokenAddress, meta.entryType, amount)
+		}
+		current.Timestamp = now
+		if err := tx.Table(tableName).Save(current).Error; err != nil {
+			return fmt.Errorf("save balance failed: %w", err)
+		}
+		entry.AddressType = current.AddressType
+		entry.BalanceAfter = current.Balance
+		entry.LockBalanceAfter = current.LockBalance
 	}
-	tableName := utils.GetTableName("balances", requestId, chainName)
-	return db.gorm.Table(tableName).CreateInBatches(&valueList, len(valueList)).Error
+
+	journalTable := utils.GetTableName("balance_journals", requestId, chainName)
+	if err := tx.Table(journalTable).Create(entry).Error; err != nil {
+		return fmt.Errorf("store balance journal failed: %w", err)
+	}
+	log.Debug("balance journal posted", "table", journalTable, "address", account.address, "token", account.tokenAddress,
+		"bucket", account.bucket, "direction", direction, "type", meta.entryType, "amount", amount)
+	return nil
 }
 
-func (db *balancesDB) UpdateBalance(requestId string, chainName string, balance *Balances) error {
-	if balance == nil {
-		return fmt.Errorf("balance cannot be nil")
+// loadOrCreateBalance 读取并锁定余额行，事务提交前其他 worker 和对账修正不能读写同一行，避免并发覆盖；
+// 行还不存在时用事务级 advisory lock 串行化创建，避免并发插入重复行
+func (db *balancesDB) loadOrCreateBalance(tx *gorm.DB, tableName string, chainName string, account balanceAccount) (*Balances, error) {
+	var current Balances
+	account = account.normalized(chainName)
+	lockKey := tableName + "/" + account.address + "/" + account.tokenAddress
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", lockKey).Error; err != nil {
+		return nil, fmt.Errorf("lock balance failed: %w", err)
+	}
+	err := tx.Table(tableName).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("address IN ? AND token_address IN ?",
+			addressLookupForms(chainName, account.address),
+			addressLookupForms(chainName, account.tokenAddress),
+		).
+		Take(&current).Error
+	if err == nil {
+		return &current, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("query balance failed: %w", err)
+	}
+	created := &Balances{
+		GUID:         uuid.New(),
+		Address:      account.address,
+		TokenAddress: account.tokenAddress,
+		AddressType:  account.addressType,
+		Balance:      big.NewInt(0),
+		LockBalance:  big.NewInt(0),
+		Timestamp:    uint64(time.Now().Unix()),
+	}
+	if err := tx.Table(tableName).Create(created).Error; err != nil {
+		return nil, fmt.Errorf("create initial balance failed: %w", err)
 	}
+	return created, nil
+}
 
+// StoreBalances 导入地址时以链上余额作为期初余额入账
+func (db *balancesDB) StoreBalances(requestId string, chainName string, balanceList []*Balances) error {
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
 		tableName := utils.GetTableName("balances", requestId, chainName)
-		return db.UpdateAndSaveBalance(tx, tableName, balance)
+		for _, balance := range balanceList {
+			if balance == nil {
+				continue
+			}
+			opening := balance.Balance
+			value := *balance
+			value.Address = NormalizeAddress(chainName, balance.Address)
+			value.TokenAddress = NormalizeAddress(chainName, balance.TokenAddress)
+			value.Balance = big.NewInt(0)
+			value.LockBalance = big.NewInt(0)
+			if err := tx.Table(tableName).Create(&value).Error; err != nil {
+				return err
+			}
+			meta := journalMeta{entryType: JournalOpening}
+			from := externalAccount(ExternalOpeningAccount, balance.TokenAddress)
+			to := availableAccount(balance.AddressType, balance.Address, balance.TokenAddress)
+			if err := db.postTransfer(tx, requestId, chainName, meta, from, to, opening); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 }
 
-func (db *balancesDB) UpdateAndSaveBalance(tx *gorm.DB, tableName string, balance *Balances) error {
+func (db *balancesDB) UpdateBalance(requestId string, chainName string, balance *Balances) error {
 	if balance == nil {
 		return fmt.Errorf("balance cannot be nil")
 	}
 
-	var currentBalance Balances
-	result := tx.Table(tableName).
-		Where("address = ? AND token_address = ?",
-			strings.ToLower(balance.Address),
-			strings.ToLower(balance.TokenAddress),
-		).
-		Take(&currentBalance)
-
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			log.Debug("Balance record not found",
-				"tableName", tableName,
-				"address", balance.Address,
-				"tokenAddress", balance.TokenAddress)
-			return nil
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		tableName := utils.GetTableName("balances", requestId, chainName)
+		account := availableAccount(balance.AddressType, balance.Address, balance.TokenAddress)
+		current, err := db.loadOrCreateBalance(tx, tableName, chainName, account)
+		if err != nil {
+			return err
 		}
-		return fmt.Errorf("query balance failed: %w", result.Error)
-	}
-
-	currentBalance.Balance = balance.Balance //上游修改这里不做重复计算
-	currentBalance.LockBalance = new(big.Int).Add(currentBalance.LockBalance, balance.LockBalance)
-	currentBalance.Timestamp = uint64(time.Now().Unix())
+		if err := db.adjust(tx, requestId, chainName, journalMeta{entryType: JournalAdjustment}, account, current.Balance, balance.Balance); err != nil {
+			return err
+		}
+		if balance.LockBalance != nil && balance.LockBalance.Sign() > 0 {
+			meta := journalMeta{entryType: JournalLock}
+			return db.postTransfer(tx, requestId, chainName, meta, account, lockedAccount(balance.AddressType, balance.Address, balance.TokenAddress), balance.LockBalance)
+		}
+		return nil
+	})
+}
 
-	if err := tx.Table(tableName).Save(&currentBalance).Error; err != nil {
-		log.Error("Failed to save balance",
-			"tableName", tableName,
-			"address", balance.Address,
-			"error", err)
-		return fmt.Errorf("save balance failed: %w", err)
+// adjust 用调整流水把账户余额从 current 调整到 target
+func (db *balancesDB) adjust(tx *gorm.DB, requestId string, chainName string, meta journalMeta, account balanceAccount, current, target *big.Int) error {
+	difference := new(big.Int).Sub(target, current)
+	counterparty := externalAccount(ExternalAdjustAccount, account.tokenAddress)
+	switch difference.Sign() {
+	case 1:
+		return db.postTransfer(tx, requestId, chainName, meta, counterparty, account, difference)
+	case -1:
+		return db.postTransfer(tx, requestId, chainName, meta, account, counterparty, difference.Neg(difference))
+	default:
+		return nil
 	}
-
-	log.Debug("Balance updated and saved successfully",
-		"tableName", tableName,
-		"address", balance.Address,
-		"tokenAddress", balance.TokenAddress,
-		"newBalance", currentBalance.Balance.String(),
-		"lockBalance", currentBalance.LockBalance.String())
-
-	return nil
 }
 
-func (db *balancesDB) UpdateBalanceListByTwoAddress(requestId string, chainName string, balanceList []*Balances) error {
-	if len(balanceList) == 0 {
+// LockBalances 交易广播后把发送方的金额从可用余额转入锁定余额，交易确认时再解锁扣减
+func (db *balancesDB) LockBalances(requestId string, chainName string, locks []*TokenBalance) error {
+	if len(locks) == 0 {
 		return nil
 	}
-
-	tableName := utils.GetTableName("balances", requestId, chainName)
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
-		for _, balance := range balanceList {
-			var currentBalance Balances
-			result := tx.Table(tableName).
-				Where("address = ? AND token_address = ?",
-					balance.Address,
-					balance.TokenAddress).
-				Take(&currentBalance)
-
-			if result.Error != nil {
-				if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-					continue
-				}
-				return fmt.Errorf("query balance failed: %w", result.Error)
+		for _, lock := range locks {
+			addressType := addressTypeOfSender(lock.TxType)
+			meta := journalMeta{entryType: JournalLock, txHash: lock.TxHash}
+			from := availableAccount(addressType, lock.FromAddress, lock.TokenAddress)
+			to := lockedAccount(addressType, lock.FromAddress, lock.TokenAddress)
+			if err := db.postTransfer(tx, requestId, chainName, meta, from, to, lock.Balance); err != nil {
+				return err
 			}
+		}
+		return nil
+	})
+}
 
-			currentBalance.Balance = new(big.Int).Sub(currentBalance.Balance, balance.LockBalance)
-			currentBalance.LockBalance = balance.LockBalance
-			currentBalance.Timestamp = uint64(time.Now().Unix())
+func (db *balancesDB) QueryBalanceList(requestId string, chainName string) ([]*Balances, error) {
+	var balanceList []*Balances
+	tableName := utils.GetTableName("balances", requestId, chainName)
+	if err := db.gorm.Table(tableName).Order("timestamp ASC").Find(&balanceList).Error; err != nil {
+		return nil, fmt.Errorf("query balance list failed: %w", err)
+	}
+	return balanceList, nil
+}
 
-			if err := tx.Table(tableName).Save(&currentBalance).Error; err != nil {
-				return fmt.Errorf("save balance failed: %w", err)
-			}
+// QueryBalancesByAddressType 只读查询某类地址的全部余额，不存在的余额不会被创建
+func (db *balancesDB) QueryBalancesByAddressType(requestId string, chainName string, addressType AddressType) ([]*Balances, error) {
+	var balanceList []*Balances
+	tableName := utils.GetTableName("balances", requestId, chainName)
+	if err := db.gorm.Table(tableName).Where("address_type = ?", addressType).Order("timestamp ASC").Find(&balanceList).Error; err != nil {
+		return nil, fmt.Errorf("query balances by address type failed: %w", err)
+	}
+	return balanceList, nil
+}
+
+// CorrectBalance 对账时把链上余额与读取时数据库余额的差额记为调整流水，只调整观察到的差额；
+// 读取之后余额行有过入账或锁定时不修正，由下一轮对账重新比较，返回是否已修正
+func (db *balancesDB) CorrectBalance(requestId string, chainName string, guid uuid.UUID, storedBalance *big.Int, chainBalance *big.Int) (bool, error) {
+	var corrected bool
+	err := db.gorm.Transaction(func(tx *gorm.DB) error {
+		var current Balances
+		tableName := utils.GetTableName("balances", requestId, chainName)
+		if err := tx.Table(tableName).Where("guid = ?", guid).Take(&current).Error; err != nil {
+			return fmt.Errorf("query balance failed: %w", err)
 		}
-		return nil
+		account := availableAccount(current.AddressType, current.Address, current.TokenAddress)
+		locked, err := db.loadOrCreateBalance(tx, tableName, chainName, account)
+		if err != nil {
+			return err
+		}
+		if locked.Balance.Cmp(storedBalance) != 0 || (locked.LockBalance != nil && locked.LockBalance.Sign() != 0) {
+			return nil
+		}
+		corrected = true
+		return db.adjust(tx, requestId, chainName, journalMeta{entryType: JournalAdjustment}, account, storedBalance, chainBalance)
 	})
+	if err != nil {
+		return false, err
+	}
+	return corrected, nil
 }
 
 func (db *balancesDB) QueryWalletBalanceByTokenAndAddress(
@@ -181,9 +352,9 @@ func (db *balancesDB) queryBalance(
 	var balance Balances
 	tableName := utils.GetTableName("balances", requestId, chainName)
 	err := db.gorm.Table(tableName).
-		Where("address = ? AND token_address = ?",
-			strings.ToLower(address),
-			strings.ToLower(tokenAddress),
+		Where("address IN ? AND token_address IN ?",
+			addressLookupForms(chainName, address),
+			addressLookupForms(chainName, tokenAddress),
 		).
 		Take(&balance).
 		Error
@@ -202,18 +373,14 @@ func (db *balancesDB) createInitialBalance(
 	address,
 	tokenAddress string,
 ) (*Balances, error) {
-	balance := &Balances{
-		GUID:         uuid.New(),
-		Address:      address,
-		TokenAddress: tokenAddress,
-		AddressType:  addressType,
-		Balance:      big.NewInt(0),
-		LockBalance:  big.NewInt(0),
-		Timestamp:    uint64(time.Now().Unix()),
-	}
-
+	var balance *Balances
 	tableName := utils.GetTableName("balances", requestId, chainName)
-	if err := db.gorm.Table(tableName).Create(balance).Error; err != nil {
+	err := db.gorm.Transaction(func(tx *gorm.DB) error {
+		var err error
+		balance, err = db.loadOrCreateBalance(tx, tableName, chainName, availableAccount(addressType, address, tokenAddress))
+		return err
+	})
+	if err != nil {
 		log.Error("Failed to create initial balance",
 			"tableName", tableName,
 			"address", address,
@@ -255,112 +422,66 @@ func (db *balancesDB) UpdateOrCreate(requestId string, chainName string, balance
 }
 
 func (db *balancesDB) handleBalanceUpdate(tx *gorm.DB, requestId string, chainName string, balance *TokenBalance) error {
-	switch balance.TxType {
-	case TxTypeDeposit:
-		return db.handleDeposit(tx, requestId, chainName, balance)
-	case TxTypeWithdraw:
-		return db.handleWithdraw(tx, requestId, chainName, balance)
-	case TxTypeCollection:
-		return db.handleCollection(tx, requestId, chainName, balance)
-	case TxTypeHot2Cold:
-		return db.handleHotToCold(tx, requestId, chainName, balance)
-	case TxTypeCold2Hot:
-		return db.handleColdToHot(tx, requestId, chainName, balance)
-	default:
-		return fmt.Errorf("unsupported transaction type: %s", balance.TxType)
-	}
-}
-
-func (db *balancesDB) handleDeposit(tx *gorm.DB, requestId string, chainName string, balance *TokenBalance) error {
-	userAddress, err := db.QueryWalletBalanceByTokenAndAddress(requestId, chainName, AddressTypeEOA, balance.ToAddress, balance.TokenAddress)
+	rule, err := balance.FlowRule()
 	if err != nil {
-		log.Error("Query user address failed", "err", err)
 		return err
 	}
-	log.Info("Processing handleDeposit",
-		"txType", balance.TxType,
-		"from", balance.FromAddress,
-		"to", balance.ToAddress,
-		"token", balance.TokenAddress,
-		"amount", balance.Balance,
-		"userAddress.Balance,", userAddress.Balance)
-	userAddress.Balance = new(big.Int).Add(userAddress.Balance, balance.Balance)
-	log.Info("userAddress.Balance after", new(big.Int).Add(userAddress.Balance, balance.Balance))
-	tableName := utils.GetTableName("balances", requestId, chainName)
-	return db.UpdateAndSaveBalance(tx, tableName, userAddress)
-}
-
-func (db *balancesDB) handleWithdraw(tx *gorm.DB, requestId string, chainName string, balance *TokenBalance) error {
-	hotWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, chainName, AddressTypeHot, balance.FromAddress, balance.TokenAddress)
-	if err != nil {
-		log.Error("Query hot wallet failed", "err", err)
-		return err
+	meta := journalMeta{entryType: rule.Entry, txHash: balance.TxHash, blockNumber: balance.BlockNumber}
+	from := externalAccount(balance.FromAddress, balance.TokenAddress)
+	to := externalAccount(balance.ToAddress, balance.TokenAddress)
+	if rule.Receiver != BucketExternal {
+		to = availableAccount(rule.ToRole, balance.ToAddress, balance.TokenAddress)
 	}
-
-	hotWallet.Balance = new(big.Int).Sub(hotWallet.Balance, balance.Balance)
-	tableName := utils.GetTableName("balances", requestId, chainName)
-	return db.UpdateAndSaveBalance(tx, tableName, hotWallet)
-}
-
-func (db *balancesDB) handleCollection(tx *gorm.DB, requestId string, chainName string, balance *TokenBalance) error {
-	userWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, chainName, AddressTypeEOA, balance.FromAddress, balance.TokenAddress)
-	if err != nil {
-		log.Error("Query user wallet failed", "err", err)
-		return err
+	if rule.Sender != BucketExternal {
+		if from, err = db.senderAccount(tx, requestId, chainName, balance, rule); err != nil {
+			return err
+		}
 	}
-	userWallet.Balance = new(big.Int).Sub(userWallet.Balance, balance.Balance)
-	tableName := utils.GetTableName("balances", requestId, chainName)
-	if err := db.UpdateAndSaveBalance(tx, tableName, userWallet); err != nil {
+	if err := db.postTransfer(tx, requestId, chainName, meta, from, to, balance.Balance); err != nil {
 		return err
 	}
-
-	hotWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, chainName, AddressTypeHot, balance.ToAddress, balance.TokenAddress)
-	if err != nil {
-		log.Error("Query hot wallet failed", "err", err)
-		return err
+	if from.bucket != BucketExternal {
+		return db.postFee(tx, requestId, chainName, balance, rule.FromRole)
 	}
-	hotWallet.Balance = new(big.Int).Add(hotWallet.Balance, balance.Balance)
-	return db.UpdateAndSaveBalance(tx, tableName, hotWallet)
+	return nil
 }
 
-func (db *balancesDB) handleHotToCold(tx *gorm.DB, requestId string, chainName string, balance *TokenBalance) error {
-	hotWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, chainName, AddressTypeHot, balance.FromAddress, balance.TokenAddress)
+// senderAccount 发送方广播时已锁定金额的，先解锁再从锁定余额扣减，否则直接扣减可用余额；
+// 非 Strict 的规则可用余额不足时只记接收方，由对账修正发送方余额，避免阻塞整个批次
+func (db *balancesDB) senderAccount(tx *gorm.DB, requestId string, chainName string, balance *TokenBalance, rule FlowRule) (balanceAccount, error) {
+	available := availableAccount(rule.FromRole, balance.FromAddress, balance.TokenAddress)
+	tableName := utils.GetTableName("balances", requestId, chainName)
+	current, err := db.loadOrCreateBalance(tx, tableName, chainName, available)
 	if err != nil {
-		log.Error("Query hot wallet failed", "err", err)
-		return err
+		return available, err
 	}
-	hotWallet.Balance = new(big.Int).Sub(hotWallet.Balance, balance.Balance)
-	tableName := utils.GetTableName("balances", requestId, chainName)
-	if err := db.UpdateAndSaveBalance(tx, tableName, hotWallet); err != nil {
-		return err
+	if current.LockBalance.Cmp(balance.Balance) >= 0 {
+		return lockedAccount(rule.FromRole, balance.FromAddress, balance.TokenAddress), nil
 	}
-
-	coldWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, chainName, AddressTypeCold, balance.ToAddress, balance.TokenAddress)
-	if err != nil {
-		log.Error("Query cold wallet failed", "err", err)
-		return err
+	if !rule.Strict && current.Balance.Cmp(balance.Balance) < 0 {
+		log.Warn("insufficient sender balance, leave it to reconciliation", "type", rule.TxType, "address", balance.FromAddress,
+			"token", balance.TokenAddress, "amount", balance.Balance, "balance", current.Balance, "txHash", balance.TxHash)
+		return externalAccount(balance.FromAddress, balance.TokenAddress), nil
 	}
-	coldWallet.Balance = new(big.Int).Add(coldWallet.Balance, balance.Balance)
-	return db.UpdateAndSaveBalance(tx, tableName, coldWallet)
+	return available, nil
 }
 
-func (db *balancesDB) handleColdToHot(tx *gorm.DB, requestId string, chainName string, balance *TokenBalance) error {
-	coldWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, chainName, AddressTypeCold, balance.FromAddress, balance.TokenAddress)
-	if err != nil {
-		log.Error("Query cold wallet failed", "err", err)
-		return err
+// postFee 发送方用主币支付手续费，可用余额不足时只记录日志，由对账修正
+func (db *balancesDB) postFee(tx *gorm.DB, requestId string, chainName string, balance *TokenBalance, payerType AddressType) error {
+	if balance.Fee == nil || balance.Fee.Sign() <= 0 {
+		return nil
 	}
-	coldWallet.Balance = new(big.Int).Sub(coldWallet.Balance, balance.Balance)
+	nativeToken := common.Address{}.String()
+	payer := availableAccount(payerType, balance.FromAddress, nativeToken)
 	tableName := utils.GetTableName("balances", requestId, chainName)
-	if err := db.UpdateAndSaveBalance(tx, tableName, coldWallet); err != nil {
-		return err
-	}
-
-	hotWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, chainName, AddressTypeHot, balance.ToAddress, balance.TokenAddress)
+	current, err := db.loadOrCreateBalance(tx, tableName, chainName, payer)
 	if err != nil {
-		log.Error("Query hot wallet failed", "err", err)
 		return err
 	}
-	hotWallet.Balance = new(big.Int).Add(hotWallet.Balance, balance.Balance)
-	return db.UpdateAndSaveBalance(tx, tableName, hotWallet)
+	if current.Balance.Cmp(balance.Fee) < 0 {
+		log.Warn("insufficient native balance for fee, leave it to reconciliation", "address", balance.FromAddress, "fee", balance.Fee, "balance", current.Balance)
+		return nil
+	}
+	meta := journalMeta{entryType: JournalFee, txHash: balance.TxHash, blockNumber: balance.BlockNumber}
+	return db.postTransfer(tx, requestId, chainName, meta, payer, externalAccount(ExternalFeeAccount, nativeToken), balance.Fee)
 }
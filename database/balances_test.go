@@ -24,15 +24,15 @@ func TestStoreBalances(t *testing.T) {
 
 	balance := &Balances{
 		GUID:         uuid.New(),
-		Address:      common.HexToAddress("0x1234567890abcdef1234567890abcdef12345678"),
-		TokenAddress: common.HexToAddress("0xabcdefabcdefabcdefabcdefabcdefabcdef"),
+		Address:      common.HexToAddress("0x1234567890abcdef1234567890abcdef12345678").String(),
+		TokenAddress: common.HexToAddress("0xabcdefabcdefabcdefabcdefabcdefabcdef").String(),
 		AddressType:  "eoa",
 		Balance:      big.NewInt(1000),
 		LockBalance:  big.NewInt(100),
 		Timestamp:    uint64(time.Now().Unix()),
 	}
 
-	err := balancesDB.StoreBalances(strconv.Itoa(CurrentRequestId), []*Balances{balance})
+	err := balancesDB.StoreBalances(strconv.Itoa(CurrentRequestId), CurrentChain, []*Balances{balance})
 	if err != nil {
 		t.Errorf("Failed to store balances: %v", err)
 	}
@@ -50,22 +50,22 @@ func TestUpdateBalance(t *testing.T) {
 
 	balance := &Balances{
 		GUID:         uuid.New(),
-		Address:      common.HexToAddress("0x1234567890AbcdEF1234567890aBcdef12345678"),
-		TokenAddress: common.HexToAddress("0x0000AbCDeFabcdEfaBcDeFabCDEFAbCDEfABcDeF"),
+		Address:      common.HexToAddress("0x1234567890AbcdEF1234567890aBcdef12345678").String(),
+		TokenAddress: common.HexToAddress("0x0000AbCDeFabcdEfaBcDeFabCDEFAbCDEfABcDeF").String(),
 		AddressType:  "eoa",
 		Balance:      big.NewInt(1000),
 		LockBalance:  big.NewInt(100),
 		Timestamp:    uint64(time.Now().Unix()),
 	}
 
-	err := balancesDB.StoreBalances(strconv.Itoa(CurrentRequestId), []*Balances{balance})
+	err := balancesDB.StoreBalances(strconv.Itoa(CurrentRequestId), CurrentChain, []*Balances{balance})
 	if err != nil {
 		t.Errorf("Failed to store balances: %v", err)
 	}
 
 	// Update balance
 	balance.Balance = big.NewInt(2000)
-	err = balancesDB.UpdateBalance(strconv.Itoa(CurrentRequestId), balance)
+	err = balancesDB.UpdateBalance(strconv.Itoa(CurrentRequestId), CurrentChain, balance)
 	if err != nil {
 		t.Errorf("Failed to update balance: %v", err)
 	}
@@ -81,17 +81,17 @@ func TestQueryWalletBalanceByTokenAndAddress(t *testing.T) {
 	db := SetupDb()
 	balancesDB := NewBalancesDB(db.gorm)
 
-	address := common.HexToAddress("0x1234567890AbcdEF1234567890aBcdef12345678")
-	tokenAddress := common.HexToAddress("0x0000AbCDeFabcdEfaBcDeFabCDEFAbCDEfABcDeF")
+	address := common.HexToAddress("0x1234567890AbcdEF1234567890aBcdef12345678").String()
+	tokenAddress := common.HexToAddress("0x0000AbCDeFabcdEfaBcDeFabCDEFAbCDEfABcDeF").String()
 
 	// Query non-existing balance
-	_, err := balancesDB.QueryWalletBalanceByTokenAndAddress(strconv.Itoa(CurrentRequestId), "eoa", address, tokenAddress)
+	_, err := balancesDB.QueryWalletBalanceByTokenAndAddress(strconv.Itoa(CurrentRequestId), CurrentChain, "eoa", address, tokenAddress)
 	if err != nil {
 		t.Errorf("Expected no error for non-existing balance, got %v", err)
 	}
 
 	// Create initial balance
-	balance, err := balancesDB.QueryWalletBalanceByTokenAndAddress(strconv.Itoa(CurrentRequestId), "eoa", address, tokenAddress)
+	balance, err := balancesDB.QueryWalletBalanceByTokenAndAddress(strconv.Itoa(CurrentRequestId), CurrentChain, "eoa", address, tokenAddress)
 	if err != nil {
 		t.Errorf("Failed to create initial balance: %v", err)
 	}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"fmt"
+	"strings"
 
 	"gorm.io/gorm"
 
@@ -10,6 +11,14 @@ import (
 
 type CreateTableDB interface {
 	CreateTable(tableName, realTableName string) error
+	QueryTableNamesByPrefix(prefix string) ([]string, error)
+	QueryTableColumns(tableName string) ([]*TableColumn, error)
+	QueryTableIndexes(tableName string) ([]*TableIndex, error)
+	QueryTableConstraints(tableName string) ([]*TableConstraint, error)
+	ExecDDL(statement string) error
+	CountTableRows(tableName string) (int64, error)
+	QueryTableRows(tableName string, offset int, limit int) ([]map[string]interface{}, error)
+	DropTable(tableName string) error
 }
 
 type createTableDB struct {
@@ -28,3 +37,110 @@ func (dao *createTableDB) CreateTable(tableName, realTableName string) error {
 	}
 	return nil
 }
+
+type TableColumn struct {
+	ColumnName    string  `gorm:"column:column_name"`
+	DataType      string  `gorm:"column:data_type"`
+	IsNullable    string  `gorm:"column:is_nullable"`
+	ColumnDefault *string `gorm:"column:column_default"`
+}
+
+type TableIndex struct {
+	IndexName string `gorm:"column:indexname"`
+	IndexDef  string `gorm:"column:indexdef"`
+}
+
+type TableConstraint struct {
+	ConstraintName string `gorm:"column:conname"`
+	ConstraintType string `gorm:"column:contype"`
+	Definition     string `gorm:"column:definition"`
+}
+
+func (dao *createTableDB) QueryTableNamesByPrefix(prefix string) ([]string, error) {
+	var tableNames []string
+	err := dao.gorm.Raw(`SELECT table_name FROM information_schema.tables
+WHERE table_schema = current_schema() AND table_type = 'BASE TABLE' AND table_name LIKE ?
+ORDER BY table_name`, strings.ReplaceAll(prefix, "_", `\_`)+"%").Scan(&tableNames).Error
+	if err != nil {
+		return nil, fmt.Errorf("query tables with prefix %s fail: %w", prefix, err)
+	}
+	return tableNames, nil
+}
+
+func (dao *createTableDB) QueryTableColumns(tableName string) ([]*TableColumn, error) {
+	var columns []*TableColumn
+	err := dao.gorm.Raw(`SELECT column_name,
+       CASE WHEN domain_name IS NOT NULL THEN domain_name
+            WHEN data_type = 'character varying' AND character_maximum_length IS NOT NULL
+                THEN 'varchar(' || character_maximum_length || ')'
+            WHEN data_type = 'character varying' THEN 'varchar'
+            ELSE data_type END AS data_type,
+       is_nullable,
+       column_default
+FROM information_schema.columns
+WHERE table_schema = current_schema() AND table_name = ?
+ORDER BY ordinal_position`, tableName).Scan(&columns).Error
+	if err != nil {
+		return nil, fmt.Errorf("query columns of %s fail: %w", tableName, err)
+	}
+	return columns, nil
+}
+
+func (dao *createTableDB) QueryTableIndexes(tableName string) ([]*TableIndex, error) {
+	var indexes []*TableIndex
+	err := dao.gorm.Raw(`SELECT indexname, indexdef FROM pg_indexes
+WHERE schemaname = current_schema() AND tablename = ?
+ORDER BY indexname`, tableName).Scan(&indexes).Error
+	if err != nil {
+		return nil, fmt.Errorf("query indexes of %s fail: %w", tableName, err)
+	}
+	return indexes, nil
+}
+
+func (dao *createTableDB) QueryTableConstraints(tableName string) ([]*TableConstraint, error) {
+	var constraints []*TableConstraint
+	err := dao.gorm.Raw(`SELECT c.conname, c.contype, pg_get_constraintdef(c.oid) AS definition
+FROM pg_constraint c
+JOIN pg_class t ON t.oid = c.conrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+WHERE n.nspname = current_schema() AND t.relname = ? AND c.contype IN ('c', 'u', 'p')
+ORDER BY c.conname`, tableName).Scan(&constraints).Error
+	if err != nil {
+		return nil, fmt.Errorf("query constraints of %s fail: %w", tableName, err)
+	}
+	return constraints, nil
+}
+
+func (dao *createTableDB) ExecDDL(statement string) error {
+	if err := dao.gorm.Exec(statement).Error; err != nil {
+		log.Error("exec ddl fail", "statement", statement, "err", err)
+		return fmt.Errorf("exec ddl fail: %w", err)
+	}
+	return nil
+}
+
+func (dao *createTableDB) CountTableRows(tableName string) (int64, error) {
+	var count int64
+	if err := dao.gorm.Table(tableName).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count rows of %s fail: %w", tableName, err)
+	}
+	return count, nil
+}
+
+// QueryTableRows 按 guid 排序分页读取表中的原始数据
+func (dao *createTableDB) QueryTableRows(tableName string, offset int, limit int) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	err := dao.gorm.Table(tableName).Order("guid").Offset(offset).Limit(limit).Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("query rows of %s fail: %w", tableName, err)
+	}
+	return rows, nil
+}
+
+func (dao *createTableDB) DropTable(tableName string) error {
+	if err := dao.gorm.Exec("DROP TABLE IF EXISTS " + tableName).Error; err != nil {
+		log.Error("drop table fail", "table", tableName, "err", err)
+		return fmt.Errorf("failed to drop table %s: %w", tableName, err)
+	}
+	return nil
+}
@@ -2,21 +2,158 @@ package database
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+type BusinessStatus string
+
+const (
+	BusinessStatusActive    BusinessStatus = "active"
+	BusinessStatusSuspended BusinessStatus = "suspended"
+	BusinessStatusArchived  BusinessStatus = "archived"
+)
+
+func ParseBusinessStatus(s string) (BusinessStatus, error) {
+	switch strings.ToLower(s) {
+	case string(BusinessStatusActive):
+		return BusinessStatusActive, nil
+	case string(BusinessStatusSuspended):
+		return BusinessStatusSuspended, nil
+	case string(BusinessStatusArchived):
+		return BusinessStatusArchived, nil
+	default:
+		return "", fmt.Errorf("invalid business status: %s", s)
+	}
+}
+
+// UnlistedTokenPolicy 未在 tokens 表登记的代币充值的处理方式
+type UnlistedTokenPolicy string
+
+// DefaultAddressPoolWatermark 注册业务时未指定地址池水位使用的默认值
+const DefaultAddressPoolWatermark uint64 = 100
+
+const (
+	// UnlistedTokenIgnore 直接丢弃
+	UnlistedTokenIgnore UnlistedTokenPolicy = "ignore"
+	// UnlistedTokenQuarantine 存入 quarantined_deposits 表，不入账不通知
+	UnlistedTokenQuarantine UnlistedTokenPolicy = "quarantine"
+	// UnlistedTokenNotify 存为充值并带 unlisted_token 标记通知业务方，不入账
+	UnlistedTokenNotify UnlistedTokenPolicy = "notify"
+)
+
+func ParseUnlistedTokenPolicy(s string) (UnlistedTokenPolicy, error) {
+	switch strings.ToLower(s) {
+	case string(UnlistedTokenIgnore):
+		return UnlistedTokenIgnore, nil
+	case string(UnlistedTokenQuarantine):
+		return UnlistedTokenQuarantine, nil
+	case string(UnlistedTokenNotify):
+		return UnlistedTokenNotify, nil
+	default:
+		return "", fmt.Errorf("invalid unlisted token policy: %s", s)
+	}
+}
+
+// HotWalletStrategy 业务有多个热钱包时提现出款地址的选择方式
+type HotWalletStrategy string
+
+const (
+	// HotWalletRoundRobin 在余额足够的热钱包之间轮流出款
+	HotWalletRoundRobin HotWalletStrategy = "round_robin"
+	// HotWalletMostFunded 选择可用余额最多的热钱包
+	HotWalletMostFunded HotWalletStrategy = "most_funded"
+	// HotWalletLeastPendingNonce 选择未完成交易最少的热钱包，减少 nonce 排队
+	HotWalletLeastPendingNonce HotWalletStrategy = "least_pending_nonce"
+)
+
+func ParseHotWalletStrategy(s string) (HotWalletStrategy, error) {
+	switch strings.ToLower(s) {
+	case string(HotWalletRoundRobin):
+		return HotWalletRoundRobin, nil
+	case string(HotWalletMostFunded):
+		return HotWalletMostFunded, nil
+	case string(HotWalletLeastPendingNonce):
+		return HotWalletLeastPendingNonce, nil
+	default:
+		return "", fmt.Errorf("invalid hot wallet strategy: %s", s)
+	}
+}
+
+// OutflowPolicy 发现不是系统创建的转出交易时的处理方式
+type OutflowPolicy string
+
+const (
+	// OutflowAlert 记录事件并通知业务方
+	OutflowAlert OutflowPolicy = "alert"
+	// OutflowPause 记录事件、通知业务方并暂停该业务的提现，由业务方确认后恢复
+	OutflowPause OutflowPolicy = "pause"
+)
+
+func ParseOutflowPolicy(s string) (OutflowPolicy, error) {
+	switch strings.ToLower(s) {
+	case string(OutflowAlert):
+		return OutflowAlert, nil
+	case string(OutflowPause):
+		return OutflowPause, nil
+	default:
+		return "", fmt.Errorf("invalid outflow policy: %s", s)
+	}
+}
+
 type Business struct {
-	GUID        uuid.UUID `gorm:"primaryKey" json:"guid"`
-	BusinessUid string    `json:"business_uid"`
-	NotifyUrl   string    `json:"notify_url"`
-	Timestamp   uint64
+	GUID        uuid.UUID      `gorm:"primaryKey" json:"guid"`
+	BusinessUid string         `json:"business_uid"`
+	NotifyUrl   string         `json:"notify_url"`
+	Status      BusinessStatus `gorm:"type:varchar(10);not null;default:'active'" json:"status"`
+	// UnlistedTokenPolicy 为空时按 quarantine 处理
+	UnlistedTokenPolicy UnlistedTokenPolicy `gorm:"type:varchar(10);not null;default:'quarantine'" json:"unlisted_token_policy"`
+	// AddressPoolWatermark 地址池可用地址低于该数量时通知业务方上传公钥
+	AddressPoolWatermark uint64 `gorm:"not null;default:100" json:"address_pool_watermark"`
+	// HotWalletStrategy 为空时按 round_robin 处理
+	HotWalletStrategy HotWalletStrategy `gorm:"type:varchar(20);not null;default:'round_robin'" json:"hot_wallet_strategy"`
+	// CollectionTarget 归集的目标地址，为空时归集到最早登记的热钱包
+	CollectionTarget string `gorm:"not null;default:''" json:"collection_target"`
+	// OutflowPolicy 为空时按 alert 处理
+	OutflowPolicy OutflowPolicy `gorm:"type:varchar(10);not null;default:'alert'" json:"outflow_policy"`
+	// WithdrawPaused 为 true 时不再创建、签名和广播提现
+	WithdrawPaused bool `gorm:"not null;default:false" json:"withdraw_paused"`
+	Timestamp      uint64
+}
+
+func (b *Business) IsActive() bool {
+	return b.Status == BusinessStatusActive || b.Status == ""
+}
+
+func (b *Business) TokenPolicy() UnlistedTokenPolicy {
+	if b.UnlistedTokenPolicy == "" {
+		return UnlistedTokenQuarantine
+	}
+	return b.UnlistedTokenPolicy
+}
+
+func (b *Business) WalletStrategy() HotWalletStrategy {
+	if b.HotWalletStrategy == "" {
+		return HotWalletRoundRobin
+	}
+	return b.HotWalletStrategy
+}
+
+func (b *Business) IncidentPolicy() OutflowPolicy {
+	if b.OutflowPolicy == "" {
+		return OutflowAlert
+	}
+	return b.OutflowPolicy
 }
 
 type BusinessView interface {
 	QueryBusinessList() ([]*Business, error)
+	QueryActiveBusinessList() ([]*Business, error)
+	QueryScanBusinessList() ([]*Business, error)
 	QueryBusinessByUuid(string) (*Business, error)
 }
 
@@ -24,6 +161,13 @@ type BusinessDB interface {
 	BusinessView
 
 	StoreBusiness(*Business) error
+	UpdateBusinessStatus(businessUid string, status BusinessStatus) error
+	UpdateUnlistedTokenPolicy(businessUid string, policy UnlistedTokenPolicy) error
+	UpdateAddressPoolWatermark(businessUid string, watermark uint64) error
+	UpdateHotWalletStrategy(businessUid string, strategy HotWalletStrategy) error
+	UpdateCollectionTarget(businessUid string, target string) error
+	UpdateOutflowPolicy(businessUid string, policy OutflowPolicy) error
+	UpdateWithdrawPaused(businessUid string, paused bool) error
 }
 
 type businessDB struct {
@@ -35,6 +179,21 @@ func NewBusinessDB(db *gorm.DB) BusinessDB {
 }
 
 func (db *businessDB) StoreBusiness(business *Business) error {
+	if business.Status == "" {
+		business.Status = BusinessStatusActive
+	}
+	if business.UnlistedTokenPolicy == "" {
+		business.UnlistedTokenPolicy = UnlistedTokenQuarantine
+	}
+	if business.AddressPoolWatermark == 0 {
+		business.AddressPoolWatermark = DefaultAddressPoolWatermark
+	}
+	if business.HotWalletStrategy == "" {
+		business.HotWalletStrategy = HotWalletRoundRobin
+	}
+	if business.OutflowPolicy == "" {
+		business.OutflowPolicy = OutflowAlert
+	}
 	result := db.gorm.Table("business").Create(business)
 	return result.Error
 }
@@ -51,6 +210,26 @@ func (db *businessDB) QueryBusinessList() ([]*Business, error) {
 	return business, err
 }
 
+// QueryActiveBusinessList 只返回 active 状态的业务，worker 和 notifier 只处理这些业务
+func (db *businessDB) QueryActiveBusinessList() ([]*Business, error) {
+	var business []*Business
+	err := db.gorm.Table("business").Where("status = ?", BusinessStatusActive).Find(&business).Error
+	if err != nil {
+		return nil, err
+	}
+	return business, nil
+}
+
+// QueryScanBusinessList 返回需要扫链入账的业务，暂停的业务继续记录充值和出账，只是不再提现和通知，恢复后不会漏单
+func (db *businessDB) QueryScanBusinessList() ([]*Business, error) {
+	var business []*Business
+	err := db.gorm.Table("business").Where("status IN ?", []BusinessStatus{BusinessStatusActive, BusinessStatusSuspended}).Find(&business).Error
+	if err != nil {
+		return nil, err
+	}
+	return business, nil
+}
+
 func (db *businessDB) QueryBusinessByUuid(businessUid string) (*Business, error) {
 	var business *Business
 	result := db.gorm.Table("business").Where("business_uid", businessUid).First(&business)
@@ -60,3 +239,82 @@ func (db *businessDB) QueryBusinessByUuid(businessUid string) (*Business, error)
 	}
 	return business, nil
 }
+
+func (db *businessDB) UpdateBusinessStatus(businessUid string, status BusinessStatus) error {
+	result := db.gorm.Table("business").Where("business_uid = ?", businessUid).Update("status", status)
+	if result.Error != nil {
+		return fmt.Errorf("update business status fail: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	log.Info("update business status success", "businessUid", businessUid, "status", status)
+	return nil
+}
+
+func (db *businessDB) UpdateUnlistedTokenPolicy(businessUid string, policy UnlistedTokenPolicy) error {
+	result := db.gorm.Table("business").Where("business_uid = ?", businessUid).Update("unlisted_token_policy", policy)
+	if result.Error != nil {
+		return fmt.Errorf("update unlisted token policy fail: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (db *businessDB) UpdateAddressPoolWatermark(businessUid string, watermark uint64) error {
+	result := db.gorm.Table("business").Where("business_uid = ?", businessUid).Update("address_pool_watermark", watermark)
+	if result.Error != nil {
+		return fmt.Errorf("update address pool watermark fail: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (db *businessDB) UpdateHotWalletStrategy(businessUid string, strategy HotWalletStrategy) error {
+	result := db.gorm.Table("business").Where("business_uid = ?", businessUid).Update("hot_wallet_strategy", strategy)
+	if result.Error != nil {
+		return fmt.Errorf("update hot wallet strategy fail: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (db *businessDB) UpdateCollectionTarget(businessUid string, target string) error {
+	result := db.gorm.Table("business").Where("business_uid = ?", businessUid).Update("collection_target", target)
+	if result.Error != nil {
+		return fmt.Errorf("update collection target fail: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (db *businessDB) UpdateOutflowPolicy(businessUid string, policy OutflowPolicy) error {
+	result := db.gorm.Table("business").Where("business_uid = ?", businessUid).Update("outflow_policy", policy)
+	if result.Error != nil {
+		return fmt.Errorf("update outflow policy fail: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (db *businessDB) UpdateWithdrawPaused(businessUid string, paused bool) error {
+	result := db.gorm.Table("business").Where("business_uid = ?", businessUid).Update("withdraw_paused", paused)
+	if result.Error != nil {
+		return fmt.Errorf("update withdraw paused fail: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	log.Info("update business withdraw paused success", "businessUid", businessUid, "paused", paused)
+	return nil
+}
@@ -0,0 +1,184 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	NetworkMainnet = "mainnet"
+	NetworkTestnet = "testnet"
+)
+
+// ChainSpec 链配置文件中的单条链定义，未填写的字段沿用同名内置链的配置
+type ChainSpec struct {
+	Name              string   `json:"name"`
+	NativeSymbol      string   `json:"native_symbol"`
+	DefaultToken      string   `json:"default_token"`
+	NativeAddress     string   `json:"native_address"`
+	IsEVM             *bool    `json:"is_evm"`
+	ChainId           string   `json:"chain_id"`
+	Network           string   `json:"network"`
+	Decimals          *uint8   `json:"decimals"`
+	Confirmations     uint64   `json:"confirmations"`
+	SupportsMemo      *bool    `json:"supports_memo"`
+	IsUTXO            *bool    `json:"is_utxo"`
+	TxFamily          TxFamily `json:"tx_family"`
+	MultiSendContract string   `json:"multisend_contract"`
+}
+
+// LoadChainRegistry 读取 JSON 格式的链配置文件并合并到 ChainTokenTypes，path 为空时只使用内置链
+func LoadChainRegistry(path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read chains config %s: %w", path, err)
+	}
+	var specList []ChainSpec
+	if err := json.Unmarshal(data, &specList); err != nil {
+		return fmt.Errorf("parse chains config %s: %w", path, err)
+	}
+	for _, spec := range specList {
+		if err := RegisterChain(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterChain 校验并登记一条链，已存在的链按 spec 中填写的字段覆盖
+func RegisterChain(spec ChainSpec) error {
+	name := strings.ToLower(strings.TrimSpace(spec.Name))
+	if name == "" {
+		return fmt.Errorf("chain name is required")
+	}
+	config := ChainTokenTypes[name]
+	if spec.NativeSymbol != "" {
+		config.Native = TokenType(spec.NativeSymbol)
+	}
+	if spec.DefaultToken != "" {
+		config.Default = TokenType(spec.DefaultToken)
+	}
+	if spec.NativeAddress != "" {
+		config.NativeAddress = spec.NativeAddress
+	}
+	if spec.IsEVM != nil {
+		config.IsEVM = *spec.IsEVM
+	}
+	if spec.ChainId != "" {
+		config.ChainId = spec.ChainId
+	}
+	if spec.Network != "" {
+		config.Network = strings.ToLower(spec.Network)
+	}
+	if spec.Decimals != nil {
+		config.NativeDecimals = *spec.Decimals
+	}
+	if spec.Confirmations != 0 {
+		config.Confirmations = spec.Confirmations
+	}
+	if spec.SupportsMemo != nil {
+		config.SupportsMemo = *spec.SupportsMemo
+	}
+	if spec.IsUTXO != nil {
+		config.IsUTXO = *spec.IsUTXO
+	}
+	if spec.TxFamily != "" {
+		config.TxFamily = spec.TxFamily
+	}
+	if spec.MultiSendContract != "" {
+		config.MultiSendContract = spec.MultiSendContract
+	}
+
+	if config.Network == "" {
+		config.Network = NetworkMainnet
+	}
+	if config.TxFamily == "" {
+		switch {
+		case config.IsUTXO:
+			config.TxFamily = TxFamilyUTXO
+		case config.IsEVM:
+			config.TxFamily = TxFamilyEVM1559
+		}
+	}
+	if err := validateChainConfig(name, config); err != nil {
+		return err
+	}
+	ChainTokenTypes[name] = config
+	return nil
+}
+
+func validateChainConfig(name string, config ChainConfig) error {
+	if config.Native == "" {
+		return fmt.Errorf("chain %s: native symbol is required", name)
+	}
+	if config.Default == "" {
+		return fmt.Errorf("chain %s: default token standard is required", name)
+	}
+	if config.Network != NetworkMainnet && config.Network != NetworkTestnet {
+		return fmt.Errorf("chain %s: invalid network %s", name, config.Network)
+	}
+	if config.IsEVM && config.ChainId == "" {
+		return fmt.Errorf("chain %s: evm chain requires a chain id", name)
+	}
+	if config.MultiSendContract != "" && !config.IsEVM {
+		return fmt.Errorf("chain %s: multisend contract requires an evm chain", name)
+	}
+	if config.IsEVM && config.IsUTXO {
+		return fmt.Errorf("chain %s: evm chain cannot use the utxo model", name)
+	}
+	switch config.TxFamily {
+	case TxFamilyEVM1559, TxFamilyEVMLegacy:
+		if !config.IsEVM {
+			return fmt.Errorf("chain %s: tx family %s requires an evm chain", name, config.TxFamily)
+		}
+	case TxFamilyUTXO:
+		if !config.IsUTXO {
+			return fmt.Errorf("chain %s: tx family %s requires a utxo chain", name, config.TxFamily)
+		}
+	case TxFamilyTron, TxFamilySolana, TxFamilyCosmos, TxFamilyTon, TxFamilyXrp:
+	default:
+		return fmt.Errorf("chain %s: unknown tx family %q", name, config.TxFamily)
+	}
+	return nil
+}
+
+// IsSupportedChain 链是否已登记
+func IsSupportedChain(chainName string) bool {
+	_, ok := ChainTokenTypes[strings.ToLower(chainName)]
+	return ok
+}
+
+// SupportedChains 按名称排序的已登记链
+func SupportedChains() []string {
+	nameList := make([]string, 0, len(ChainTokenTypes))
+	for name := range ChainTokenTypes {
+		nameList = append(nameList, name)
+	}
+	sort.Strings(nameList)
+	return nameList
+}
+
+// GetChainConfig 获取链的登记配置
+func GetChainConfig(chainName string) (ChainConfig, bool) {
+	config, ok := ChainTokenTypes[strings.ToLower(chainName)]
+	return config, ok
+}
+
+// GetNetwork 调用 chain-account 时使用的网络，未登记的链为 mainnet
+func GetNetwork(chainName string) string {
+	if config, ok := ChainTokenTypes[strings.ToLower(chainName)]; ok && config.Network != "" {
+		return config.Network
+	}
+	return NetworkMainnet
+}
+
+// GetDefaultConfirmations 链的默认确认数，未配置时为 0
+func GetDefaultConfirmations(chainName string) uint64 {
+	return ChainTokenTypes[strings.ToLower(chainName)].Confirmations
+}
@@ -0,0 +1,121 @@
+package database
+
+import "fmt"
+
+// AddressRoleExternal 未登记的地址，只用于交易分类，不写入地址表
+const AddressRoleExternal AddressType = "external"
+
+// FlowRule 按发送方和接收方的地址角色确定交易类型和记账方式，
+// Sender/Receiver 为双方记账的账户，external 一侧只记流水不维护余额，发送方不是 external 时由发送方支付手续费。
+// Strict 的规则发送方余额不足时报错，其余规则(链上直接发生、没有对应出款记录的流水)只记接收方，由对账修正发送方余额
+type FlowRule struct {
+	FromRole AddressType
+	ToRole   AddressType
+	TxType   TransactionType
+	Entry    JournalEntryType
+	Sender   BalanceBucket
+	Receiver BalanceBucket
+	Strict   bool
+}
+
+// Classified 未命中分类表的交易类型为 TxTypeUnKnow
+func (r FlowRule) Classified() bool {
+	return r.TxType != TxTypeUnKnow
+}
+
+func flowRule(from, to AddressType, txType TransactionType, entry JournalEntryType) FlowRule {
+	return FlowRule{FromRole: from, ToRole: to, TxType: txType, Entry: entry, Sender: roleBucket(from), Receiver: roleBucket(to)}
+}
+
+func (r FlowRule) strict() FlowRule {
+	r.Strict = true
+	return r
+}
+
+func roleBucket(role AddressType) BalanceBucket {
+	if role == AddressRoleExternal {
+		return BucketExternal
+	}
+	return BucketAvailable
+}
+
+// flowRules 交易分类表，同一交易类型的第一条规则为该类型的默认规则(锁定余额等只有交易类型的场景使用)
+var flowRules = []FlowRule{
+	flowRule(AddressRoleExternal, AddressTypeEOA, TxTypeDeposit, JournalDeposit).strict(),
+	flowRule(AddressTypeHot, AddressRoleExternal, TxTypeWithdraw, JournalWithdraw).strict(),
+	flowRule(AddressTypeEOA, AddressTypeHot, TxTypeCollection, JournalCollection).strict(),
+	// 归集地址可以配置为冷钱包或业务方自有资金地址
+	flowRule(AddressTypeEOA, AddressTypeCold, TxTypeCollection, JournalCollection).strict(),
+	flowRule(AddressTypeEOA, AddressTypeTreasury, TxTypeCollection, JournalCollection).strict(),
+	flowRule(AddressTypeHot, AddressTypeCold, TxTypeHot2Cold, JournalHot2Cold).strict(),
+	flowRule(AddressTypeCold, AddressTypeHot, TxTypeCold2Hot, JournalCold2Hot).strict(),
+	flowRule(AddressTypeHot, AddressTypeHot, TxTypeRebalance, JournalRebalance),
+	flowRule(AddressTypeCold, AddressTypeCold, TxTypeRebalance, JournalRebalance),
+	flowRule(AddressTypeHot, AddressTypeTreasury, TxTypeTreasury, JournalTreasury),
+	flowRule(AddressTypeCold, AddressTypeTreasury, TxTypeTreasury, JournalTreasury),
+	flowRule(AddressTypeTreasury, AddressTypeHot, TxTypeTreasury, JournalTreasury),
+	flowRule(AddressTypeTreasury, AddressTypeCold, TxTypeTreasury, JournalTreasury),
+	flowRule(AddressTypeFee, AddressTypeEOA, TxTypeFeeFunding, JournalFeeFunding),
+	flowRule(AddressTypeHot, AddressTypeFee, TxTypeFeeFunding, JournalFeeFunding),
+	flowRule(AddressTypeCold, AddressTypeFee, TxTypeFeeFunding, JournalFeeFunding),
+	flowRule(AddressTypeTreasury, AddressTypeFee, TxTypeFeeFunding, JournalFeeFunding),
+	flowRule(AddressTypeEOA, AddressTypeEOA, TxTypeTransfer, JournalTransfer),
+	// 外部转入非充值地址，只记余额不通知充值
+	flowRule(AddressRoleExternal, AddressTypeHot, TxTypeInflow, JournalInflow),
+	flowRule(AddressRoleExternal, AddressTypeCold, TxTypeInflow, JournalInflow),
+	flowRule(AddressRoleExternal, AddressTypeFee, TxTypeInflow, JournalInflow),
+	flowRule(AddressRoleExternal, AddressTypeTreasury, TxTypeInflow, JournalInflow),
+	// 热钱包以外的地址直接转出到外部，不经过提现流程
+	flowRule(AddressTypeEOA, AddressRoleExternal, TxTypeOutflow, JournalOutflow),
+	flowRule(AddressTypeCold, AddressRoleExternal, TxTypeOutflow, JournalOutflow),
+	flowRule(AddressTypeFee, AddressRoleExternal, TxTypeOutflow, JournalOutflow),
+	flowRule(AddressTypeTreasury, AddressRoleExternal, TxTypeOutflow, JournalOutflow),
+}
+
+// AddressRole 地址在交易分类中的角色，未登记的地址为 external
+func AddressRole(exist bool, addressType AddressType) AddressType {
+	if !exist {
+		return AddressRoleExternal
+	}
+	return addressType
+}
+
+// ClassifyFlow 查分类表确定交易类型，未命中时返回 TxTypeUnKnow 的规则，登记地址一侧照常记账
+func ClassifyFlow(fromRole, toRole AddressType) FlowRule {
+	for _, rule := range flowRules {
+		if rule.FromRole == fromRole && rule.ToRole == toRole {
+			return rule
+		}
+	}
+	return flowRule(fromRole, toRole, TxTypeUnKnow, JournalUnclassified)
+}
+
+// defaultFlowRule 交易类型的默认规则
+func defaultFlowRule(txType TransactionType) (FlowRule, bool) {
+	for _, rule := range flowRules {
+		if rule.TxType == txType {
+			return rule, true
+		}
+	}
+	return FlowRule{}, false
+}
+
+// FlowRule 余额变动记录了双方角色时按角色分类，否则使用交易类型的默认规则
+func (b *TokenBalance) FlowRule() (FlowRule, error) {
+	if b.FromRole != "" && b.ToRole != "" {
+		return ClassifyFlow(b.FromRole, b.ToRole), nil
+	}
+	rule, ok := defaultFlowRule(b.TxType)
+	if !ok {
+		return rule, fmt.Errorf("unsupported transaction type: %s", b.TxType)
+	}
+	return rule, nil
+}
+
+// addressTypeOfSender 只有交易类型时发送方的地址类型，取默认规则的发送方，外部转入和未知类型按热钱包处理
+func addressTypeOfSender(txType TransactionType) AddressType {
+	if rule, ok := defaultFlowRule(txType); ok && rule.Sender != BucketExternal {
+		return rule.FromRole
+	}
+	return AddressTypeHot
+}
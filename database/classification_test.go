@@ -0,0 +1,61 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyFlow(t *testing.T) {
+	const (
+		ext      = AddressRoleExternal
+		eoa      = AddressTypeEOA
+		hot      = AddressTypeHot
+		cold     = AddressTypeCold
+		fee      = AddressTypeFee
+		treasury = AddressTypeTreasury
+	)
+	// 每一行为发送方角色，列依次为接收方 external/eoa/hot/cold/fee/treasury
+	roles := []AddressType{ext, eoa, hot, cold, fee, treasury}
+	expected := map[AddressType][]TransactionType{
+		ext:      {TxTypeUnKnow, TxTypeDeposit, TxTypeInflow, TxTypeInflow, TxTypeInflow, TxTypeInflow},
+		eoa:      {TxTypeOutflow, TxTypeTransfer, TxTypeCollection, TxTypeCollection, TxTypeUnKnow, TxTypeCollection},
+		hot:      {TxTypeWithdraw, TxTypeUnKnow, TxTypeRebalance, TxTypeHot2Cold, TxTypeFeeFunding, TxTypeTreasury},
+		cold:     {TxTypeOutflow, TxTypeUnKnow, TxTypeCold2Hot, TxTypeRebalance, TxTypeFeeFunding, TxTypeTreasury},
+		fee:      {TxTypeOutflow, TxTypeFeeFunding, TxTypeUnKnow, TxTypeUnKnow, TxTypeUnKnow, TxTypeUnKnow},
+		treasury: {TxTypeOutflow, TxTypeUnKnow, TxTypeTreasury, TxTypeTreasury, TxTypeFeeFunding, TxTypeUnKnow},
+	}
+	strict := map[[2]AddressType]bool{
+		{ext, eoa}:      true,
+		{hot, ext}:      true,
+		{eoa, hot}:      true,
+		{eoa, cold}:     true,
+		{eoa, treasury}: true,
+		{hot, cold}:     true,
+		{cold, hot}:     true,
+	}
+	for _, from := range roles {
+		for i, to := range roles {
+			txType := expected[from][i]
+			t.Run(string(from)+"->"+string(to), func(t *testing.T) {
+				rule := ClassifyFlow(from, to)
+				require.Equal(t, txType, rule.TxType)
+				require.Equal(t, from, rule.FromRole)
+				require.Equal(t, to, rule.ToRole)
+				require.Equal(t, txType != TxTypeUnKnow, rule.Classified())
+				require.Equal(t, strict[[2]AddressType{from, to}], rule.Strict)
+				require.Equal(t, from == ext, rule.Sender == BucketExternal)
+				require.Equal(t, to == ext, rule.Receiver == BucketExternal)
+				if !rule.Classified() {
+					require.Equal(t, JournalUnclassified, rule.Entry)
+				}
+			})
+		}
+	}
+}
+
+func TestAddressRole(t *testing.T) {
+	require.Equal(t, AddressRoleExternal, AddressRole(false, AddressTypeHot))
+	require.Equal(t, AddressTypeFee, AddressRole(true, AddressTypeFee))
+	require.Equal(t, AddressTypeTreasury, AddressRole(true, AddressTypeTreasury))
+}
@@ -15,95 +15,218 @@ const (
 	TxStatusWalletDone     TxStatus = "wallet_done"
 	TxStatusNotified       TxStatus = "notified"
 	TxStatusSuccess        TxStatus = "success"
+	// TxStatusBelowMinimum 充值金额低于代币最小充值额，不入账不通知
+	TxStatusBelowMinimum TxStatus = "below_minimum"
+	// TxStatusSuspense 共用充值地址上缺少 memo 或 memo 未登记的充值，无法归属到用户，不通知
+	TxStatusSuspense TxStatus = "suspense"
+	// TxStatusExpired 创建后超时仍未签名或未广播成功的提现和内部交易，锁定的 UTXO 已释放，不能再签名
+	TxStatusExpired TxStatus = "expired"
+)
+
+// TxFamily selects the transaction builder of a chain
+type TxFamily string
+
+const (
+	TxFamilyEVM1559   TxFamily = "evm1559"
+	TxFamilyEVMLegacy TxFamily = "evm_legacy"
+	TxFamilyTron      TxFamily = "tron"
+	TxFamilySolana    TxFamily = "solana"
+	TxFamilyCosmos    TxFamily = "cosmos"
+	TxFamilyTon       TxFamily = "ton"
+	TxFamilyXrp       TxFamily = "xrp"
+	TxFamilyUTXO      TxFamily = "utxo"
 )
 
 // ChainConfig defines the configuration for a blockchain
 type ChainConfig struct {
-	Native        TokenType // Native token type for the chain
-	Default       TokenType // Default token type for the chain
-	IsEVM         bool      // Whether this is an EVM compatible chain
-	NativeAddress string    // Native token contract address
+	Native         TokenType // Native token type for the chain
+	Default        TokenType // Default token type for the chain
+	IsEVM          bool      // Whether this is an EVM compatible chain
+	NativeAddress  string    // Native token contract address
+	NativeDecimals uint8     // Decimals of the native token
+	SupportsMemo   bool      // Whether deposits are told apart by memo / destination tag on a shared address
+	IsUTXO         bool      // Whether balances are held as unspent outputs instead of account counters
+	TxFamily       TxFamily  // Transaction builder used for unsigned and signed transactions
+	ChainId        string    // Chain id passed to the transaction builders, empty for chains without one
+	Network        string    // Network name passed to chain-account, mainnet or testnet
+	Confirmations  uint64    // Default confirmation depth used when no --confirmations flag is given
+	// MultiSendContract is the EVM contract that pays several recipients in one transaction, empty disables multi-send
+	MultiSendContract string
 }
 
-// ChainTokenTypes defines the mapping of chain names to their configurations
+// ChainTokenTypes is the chain registry, the built-in chains below are the defaults and
+// chains loaded from the chains config file are merged on top of them at startup
 var ChainTokenTypes = map[string]ChainConfig{
 	// EVM compatible chains (all use same zero address format)
 	"ethereum": {
-		Native:        "ETH",
-		Default:       "ERC20",
-		IsEVM:         true,
-		NativeAddress: "0x0000000000000000000000000000000000000000",
+		Native:         "ETH",
+		Default:        "ERC20",
+		IsEVM:          true,
+		NativeAddress:  "0x0000000000000000000000000000000000000000",
+		NativeDecimals: 18,
+		TxFamily:       TxFamilyEVM1559,
+		ChainId:        "1",
+		Network:        "mainnet",
+		Confirmations:  64,
 	},
 	"bsc": {
-		Native:        "BNB",
-		Default:       "BEP20",
-		IsEVM:         true,
-		NativeAddress: "0x0000000000000000000000000000000000000000",
+		Native:         "BNB",
+		Default:        "BEP20",
+		IsEVM:          true,
+		NativeAddress:  "0x0000000000000000000000000000000000000000",
+		NativeDecimals: 18,
+		TxFamily:       TxFamilyEVMLegacy,
+		ChainId:        "56",
+		Network:        "mainnet",
+		Confirmations:  15,
 	},
 	"polygon": {
-		Native:        "MATIC",
-		Default:       "ERC20",
-		IsEVM:         true,
-		NativeAddress: "0x0000000000000000000000000000000000000000",
+		Native:         "MATIC",
+		Default:        "ERC20",
+		IsEVM:          true,
+		NativeAddress:  "0x0000000000000000000000000000000000000000",
+		NativeDecimals: 18,
+		TxFamily:       TxFamilyEVM1559,
+		ChainId:        "137",
+		Network:        "mainnet",
+		Confirmations:  128,
 	},
 	"avalanche-c": {
-		Native:        "AVAX",
-		Default:       "ERC20",
-		IsEVM:         true,
-		NativeAddress: "0x0000000000000000000000000000000000000000",
+		Native:         "AVAX",
+		Default:        "ERC20",
+		IsEVM:          true,
+		NativeAddress:  "0x0000000000000000000000000000000000000000",
+		NativeDecimals: 18,
+		TxFamily:       TxFamilyEVM1559,
+		ChainId:        "43114",
+		Network:        "mainnet",
+		Confirmations:  12,
 	},
 	"arbitrum": {
-		Native:        "ETH",
-		Default:       "ERC20",
-		IsEVM:         true,
-		NativeAddress: "0x0000000000000000000000000000000000000000",
+		Native:         "ETH",
+		Default:        "ERC20",
+		IsEVM:          true,
+		NativeAddress:  "0x0000000000000000000000000000000000000000",
+		NativeDecimals: 18,
+		TxFamily:       TxFamilyEVM1559,
+		ChainId:        "42161",
+		Network:        "mainnet",
+		Confirmations:  20,
 	},
 	"optimism": {
-		Native:        "ETH",
-		Default:       "ERC20",
-		IsEVM:         true,
-		NativeAddress: "0x0000000000000000000000000000000000000000",
+		Native:         "ETH",
+		Default:        "ERC20",
+		IsEVM:          true,
+		NativeAddress:  "0x0000000000000000000000000000000000000000",
+		NativeDecimals: 18,
+		TxFamily:       TxFamilyEVM1559,
+		ChainId:        "10",
+		Network:        "mainnet",
+		Confirmations:  20,
 	},
 
 	// Non-EVM chains
 	"cosmos": {
-		Native:        "ATOM",
-		Default:       "CosmosCoin", // CW20
-		IsEVM:         false,
-		NativeAddress: "",
+		Native:         "ATOM",
+		Default:        "CosmosCoin", // CW20
+		IsEVM:          false,
+		NativeAddress:  "",
+		NativeDecimals: 6,
+		SupportsMemo:   true,
+		TxFamily:       TxFamilyCosmos,
+		ChainId:        "cosmoshub-4",
+		Network:        "mainnet",
+		Confirmations:  1,
 	},
 	"solana": {
-		Native:        "SOL",
-		Default:       "SPL",
-		IsEVM:         false,
-		NativeAddress: "11111111111111111111111111111111",
+		Native:         "SOL",
+		Default:        "SPL",
+		IsEVM:          false,
+		NativeAddress:  "11111111111111111111111111111111",
+		NativeDecimals: 9,
+		TxFamily:       TxFamilySolana,
+		Network:        "mainnet",
+		Confirmations:  32,
 	},
 	"ton": {
-		Native:        "TON",
-		Default:       "JettonToken", // Added specific token type for TON
-		IsEVM:         false,
-		NativeAddress: "-1:0000000000000000000000000000000000000000000000000000000000000000",
+		Native:         "TON",
+		Default:        "JettonToken", // Added specific token type for TON
+		IsEVM:          false,
+		NativeAddress:  "-1:0000000000000000000000000000000000000000000000000000000000000000",
+		NativeDecimals: 9,
+		SupportsMemo:   true,
+		TxFamily:       TxFamilyTon,
+		Network:        "mainnet",
+		Confirmations:  1,
 	},
 	"tron": {
-		Native:        "TRX",
-		Default:       "TRC20",
-		IsEVM:         false,
-		NativeAddress: "",
+		Native:         "TRX",
+		Default:        "TRC20",
+		IsEVM:          false,
+		NativeAddress:  "",
+		NativeDecimals: 6,
+		TxFamily:       TxFamilyTron,
+		Network:        "mainnet",
+		Confirmations:  19,
 	},
 	"xrp": {
-		Native:        "XRP",
-		Default:       "XRP",
-		IsEVM:         false,
-		NativeAddress: "",
+		Native:         "XRP",
+		Default:        "XRP",
+		IsEVM:          false,
+		NativeAddress:  "",
+		NativeDecimals: 6,
+		SupportsMemo:   true,
+		TxFamily:       TxFamilyXrp,
+		Network:        "mainnet",
+		Confirmations:  1,
 	},
 	"bitcoin": {
-		Native:        "BTC",
-		Default:       "BTC",
-		IsEVM:         false,
-		NativeAddress: "0000000000000000000000000000000000000000",
+		Native:         "BTC",
+		Default:        "BTC",
+		IsEVM:          false,
+		NativeAddress:  "0000000000000000000000000000000000000000",
+		NativeDecimals: 8,
+		IsUTXO:         true,
+		TxFamily:       TxFamilyUTXO,
+		Network:        "mainnet",
+		Confirmations:  6,
 	},
 }
 
+// SupportsMemo reports whether the chain carries a memo / destination tag in transfers
+func SupportsMemo(chainName string) bool {
+	config, ok := ChainTokenTypes[strings.ToLower(chainName)]
+	return ok && config.SupportsMemo
+}
+
+// IsUTXOChain reports whether the chain uses the UTXO model
+func IsUTXOChain(chainName string) bool {
+	config, ok := ChainTokenTypes[strings.ToLower(chainName)]
+	return ok && config.IsUTXO
+}
+
+// GetTxFamily returns the transaction builder family of the chain, empty for unknown chains
+func GetTxFamily(chainName string) TxFamily {
+	return ChainTokenTypes[strings.ToLower(chainName)].TxFamily
+}
+
+// GetNativeDecimals returns the native token decimals of the chain, EVM decimals for unknown chains
+func GetNativeDecimals(chainName string) uint8 {
+	if config, ok := ChainTokenTypes[strings.ToLower(chainName)]; ok {
+		return config.NativeDecimals
+	}
+	return 18
+}
+
+// IsNativeToken reports whether the token address refers to the chain native token
+func IsNativeToken(chainName string, tokenAddress string) bool {
+	switch tokenAddress {
+	case "", "0x00", "0x0000000000000000000000000000000000000000":
+		return true
+	}
+	return tokenAddress == GetNativeAddress(chainName)
+}
+
 type TokenType string
 
 func (t TokenType) String() string {
@@ -137,6 +260,9 @@ const (
 	AddressTypeEOA  AddressType = "eoa"
 	AddressTypeHot  AddressType = "hot"
 	AddressTypeCold AddressType = "cold"
+	// AddressTypeFee 为用户地址补充手续费的地址，AddressTypeTreasury 业务方自有资金(收入、储备)地址
+	AddressTypeFee      AddressType = "fee"
+	AddressTypeTreasury AddressType = "treasury"
 )
 
 func (at AddressType) String() string {
@@ -151,6 +277,10 @@ func ParseAddressType(s string) (AddressType, error) {
 		return AddressTypeHot, nil
 	case string(AddressTypeCold):
 		return AddressTypeCold, nil
+	case string(AddressTypeFee):
+		return AddressTypeFee, nil
+	case string(AddressTypeTreasury):
+		return AddressTypeTreasury, nil
 	default:
 		return "", fmt.Errorf("invalid address type: %s", s)
 	}
@@ -165,6 +295,13 @@ const (
 	TxTypeCollection TransactionType = "collection"
 	TxTypeHot2Cold   TransactionType = "hot2cold"
 	TxTypeCold2Hot   TransactionType = "cold2hot"
+	// 以下类型只由区块扫描按地址角色分类产生，不能通过接口创建
+	TxTypeRebalance  TransactionType = "rebalance"
+	TxTypeTreasury   TransactionType = "treasury"
+	TxTypeFeeFunding TransactionType = "fee_funding"
+	TxTypeTransfer   TransactionType = "transfer"
+	TxTypeInflow     TransactionType = "inflow"
+	TxTypeOutflow    TransactionType = "outflow"
 )
 
 func ParseTransactionType(s string) (TransactionType, error) {
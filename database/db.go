@@ -4,11 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
-	"path/filepath"
 	"time"
 
-	"github.com/pkg/errors"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -21,16 +18,25 @@ import (
 type DB struct {
 	gorm *gorm.DB
 
-	CreateTable  CreateTableDB
-	Blocks       BlocksDB
-	Addresses    AddressesDB
-	Balances     BalancesDB
-	Deposits     DepositsDB
-	Withdraws    WithdrawsDB
-	Transactions TransactionsDB
-	Tokens       TokensDB
-	Business     BusinessDB
-	Internals    InternalsDB
+	CreateTable     CreateTableDB
+	Blocks          BlocksDB
+	Addresses       AddressesDB
+	Balances        BalancesDB
+	Deposits        DepositsDB
+	Withdraws       WithdrawsDB
+	Transactions    TransactionsDB
+	Tokens          TokensDB
+	Business        BusinessDB
+	Internals       InternalsDB
+	Reconciliations ReconciliationsDB
+	BalanceJournals BalanceJournalsDB
+	Quarantined     QuarantinedDepositsDB
+	Memos           MemosDB
+	Utxos           UtxosDB
+	AddressPool     AddressPoolDB
+	ExtendedKeys    ExtendedKeysDB
+	LiquidityTopUps LiquidityTopUpsDB
+	Incidents       OutflowIncidentsDB
 }
 
 func NewDB(ctx context.Context, dbConfig config.DBConfig) (*DB, error) {
@@ -75,17 +81,26 @@ func NewDB(ctx context.Context, dbConfig config.DBConfig) (*DB, error) {
 	}
 
 	db := &DB{
-		gorm:         gormDbBox,
-		CreateTable:  NewCreateTableDB(gormDbBox),
-		Blocks:       NewBlocksDB(gormDbBox),
-		Addresses:    NewAddressesDB(gormDbBox),
-		Balances:     NewBalancesDB(gormDbBox),
-		Deposits:     NewDepositsDB(gormDbBox),
-		Withdraws:    NewWithdrawsDB(gormDbBox),
-		Transactions: NewTransactionsDB(gormDbBox),
-		Tokens:       NewTokensDB(gormDbBox),
-		Business:     NewBusinessDB(gormDbBox),
-		Internals:    NewInternalsDB(gormDbBox),
+		gorm:            gormDbBox,
+		CreateTable:     NewCreateTableDB(gormDbBox),
+		Blocks:          NewBlocksDB(gormDbBox),
+		Addresses:       NewAddressesDB(gormDbBox),
+		Balances:        NewBalancesDB(gormDbBox),
+		Deposits:        NewDepositsDB(gormDbBox),
+		Withdraws:       NewWithdrawsDB(gormDbBox),
+		Transactions:    NewTransactionsDB(gormDbBox),
+		Tokens:          NewTokensDB(gormDbBox),
+		Business:        NewBusinessDB(gormDbBox),
+		Internals:       NewInternalsDB(gormDbBox),
+		Reconciliations: NewReconciliationsDB(gormDbBox),
+		BalanceJournals: NewBalanceJournalsDB(gormDbBox),
+		Quarantined:     NewQuarantinedDepositsDB(gormDbBox),
+		Memos:           NewMemosDB(gormDbBox),
+		Utxos:           NewUtxosDB(gormDbBox),
+		AddressPool:     NewAddressPoolDB(gormDbBox),
+		ExtendedKeys:    NewExtendedKeysDB(gormDbBox),
+		LiquidityTopUps: NewLiquidityTopUpsDB(gormDbBox),
+		Incidents:       NewOutflowIncidentsDB(gormDbBox),
 	}
 	return db, nil
 }
@@ -93,16 +108,26 @@ func NewDB(ctx context.Context, dbConfig config.DBConfig) (*DB, error) {
 func (db *DB) Transaction(fn func(db *DB) error) error {
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
 		txDB := &DB{
-			gorm:         tx,
-			Blocks:       NewBlocksDB(tx),
-			Addresses:    NewAddressesDB(tx),
-			Balances:     NewBalancesDB(tx),
-			Deposits:     NewDepositsDB(tx),
-			Withdraws:    NewWithdrawsDB(tx),
-			Transactions: NewTransactionsDB(tx),
-			Tokens:       NewTokensDB(tx),
-			Business:     NewBusinessDB(tx),
-			Internals:    NewInternalsDB(tx),
+			gorm:            tx,
+			CreateTable:     NewCreateTableDB(tx),
+			Blocks:          NewBlocksDB(tx),
+			Addresses:       NewAddressesDB(tx),
+			Balances:        NewBalancesDB(tx),
+			Deposits:        NewDepositsDB(tx),
+			Withdraws:       NewWithdrawsDB(tx),
+			Transactions:    NewTransactionsDB(tx),
+			Tokens:          NewTokensDB(tx),
+			Business:        NewBusinessDB(tx),
+			Internals:       NewInternalsDB(tx),
+			Reconciliations: NewReconciliationsDB(tx),
+			BalanceJournals: NewBalanceJournalsDB(tx),
+			Quarantined:     NewQuarantinedDepositsDB(tx),
+			Memos:           NewMemosDB(tx),
+			Utxos:           NewUtxosDB(tx),
+			AddressPool:     NewAddressPoolDB(tx),
+			ExtendedKeys:    NewExtendedKeysDB(tx),
+			LiquidityTopUps: NewLiquidityTopUpsDB(tx),
+			Incidents:       NewOutflowIncidentsDB(tx),
 		}
 		return fn(txDB)
 	})
@@ -115,25 +140,3 @@ func (db *DB) Close() error {
 	}
 	return sql.Close()
 }
-
-func (db *DB) ExecuteSQLMigration(migrationsFolder string) error {
-	err := filepath.Walk(migrationsFolder, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return errors.Wrap(err, fmt.Sprintf("Failed to process migration file: %s", path))
-		}
-		if info.IsDir() {
-			return nil
-		}
-		fileContent, readErr := os.ReadFile(path)
-		if readErr != nil {
-			return errors.Wrap(readErr, fmt.Sprintf("Error reading SQL file: %s", path))
-		}
-
-		execErr := db.gorm.Exec(string(fileContent)).Error
-		if execErr != nil {
-			return errors.Wrap(execErr, fmt.Sprintf("Error executing SQL script: %s", path))
-		}
-		return nil
-	})
-	return err
-}
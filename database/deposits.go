@@ -31,6 +31,8 @@ type Deposits struct {
 	GasLimit             uint64 `gorm:"not null" json:"gas_limit"`
 	MaxFeePerGas         string `gorm:"type:varchar;not null" json:"max_fee_per_gas"`
 	MaxPriorityFeePerGas string `gorm:"type:varchar;not null" json:"max_priority_fee_per_gas"`
+	// GasPrice legacy 交易的 gasPrice，此时 MaxFeePerGas 和 MaxPriorityFeePerGas 为空
+	GasPrice string `gorm:"type:varchar;not null;default:''" json:"gas_price"`
 
 	TokenType    TokenType `gorm:"type:varchar;not null" json:"token_type"`
 	TokenAddress string    `gorm:"type:varchar;not null" json:"token_address"`
@@ -38,12 +40,29 @@ type Deposits struct {
 	TokenMeta    string    `gorm:"type:varchar;not null" json:"token_meta"`
 
 	TxSignHex string `gorm:"type:varchar;not null" json:"tx_sign_hex"`
+	// UnsignedTx 创建时交给签名方的 base64 交易数据，签名时原样使用，SignHash 为 chain-account 返回的待签名哈希
+	UnsignedTx string `gorm:"type:text;not null;default:''" json:"unsigned_tx"`
+	SignHash   string `gorm:"type:varchar;not null;default:''" json:"sign_hash"`
+
+	// UnlistedToken 代币未在 tokens 表登记，只通知不入账
+	UnlistedToken bool `gorm:"not null;default:false" json:"unlisted_token"`
+
+	// MinDeposit 入账时生效的最小充值额，DustAggregated 表示该笔是累计达到最小充值额后入账的零散充值
+	MinDeposit     *big.Int `gorm:"not null;default:0;serializer:u256" json:"min_deposit"`
+	DustAggregated bool     `gorm:"not null;default:false" json:"dust_aggregated"`
+
+	// Memo 共用充值地址上区分用户的 memo / destination tag
+	Memo string `gorm:"type:varchar;not null;default:''" json:"memo"`
+	// UserId 充值地址从地址池分配时绑定的用户 id
+	UserId string `gorm:"type:varchar;not null;default:''" json:"user_id"`
 }
 
 type DepositsView interface {
 	QueryNotifyDeposits(requestId string, chainName string) ([]*Deposits, error)
 	QueryDepositsByTxHash(requestId string, chainName string, txHash common.Hash) (*Deposits, error)
 	QueryDepositsById(requestId string, chainName string, guid string) (*Deposits, error)
+	QueryBelowMinimumDeposits(requestId string, chainName string, toAddress string, memo string, tokenAddress string) ([]*Deposits, error)
+	HasUnconfirmedDeposits(requestId string, chainName string, toAddress string) (bool, error)
 }
 
 type DepositsDB interface {
@@ -56,6 +75,7 @@ type DepositsDB interface {
 	UpdateDepositsStatusByTxHash(requestId string, chainName string, status TxStatus, depositList []*Deposits) error
 	UpdateDepositListByTxHash(requestId string, chainName string, depositList []*Deposits) error
 	UpdateDepositListById(requestId string, chainName string, depositList []*Deposits) error
+	CreditDustDeposits(requestId string, chainName string, depositList []*Deposits) error
 }
 
 type depositsDB struct {
@@ -111,7 +131,59 @@ func (db *depositsDB) QueryDepositsById(requestId string, chainName string, guid
 	return &deposit, nil
 }
 
+func (db *depositsDB) QueryBelowMinimumDeposits(requestId string, chainName string, toAddress string, memo string, tokenAddress string) ([]*Deposits, error) {
+	var depositList []*Deposits
+	tableName := utils.GetTableName("deposits", requestId, chainName)
+	err := db.gorm.Table(tableName).
+		Where("to_address IN ? AND memo = ? AND token_address IN ? AND status = ?",
+			addressLookupForms(chainName, toAddress), memo, addressLookupForms(chainName, tokenAddress), TxStatusBelowMinimum).
+		Order("block_number ASC").
+		Find(&depositList).Error
+	if err != nil {
+		return nil, fmt.Errorf("query below minimum deposits failed: %w", err)
+	}
+	return depositList, nil
+}
+
+// CreditDustDeposits 累计达到最小充值额的零散充值转为已广播，之后和普通充值一样走确认和通知流程
+func (db *depositsDB) CreditDustDeposits(requestId string, chainName string, depositList []*Deposits) error {
+	if len(depositList) == 0 {
+		return nil
+	}
+	guids := make([]uuid.UUID, 0, len(depositList))
+	for _, deposit := range depositList {
+		guids = append(guids, deposit.GUID)
+	}
+	tableName := utils.GetTableName("deposits", requestId, chainName)
+	result := db.gorm.Table(tableName).
+		Where("guid IN ? AND status = ?", guids, TxStatusBelowMinimum).
+		Updates(map[string]interface{}{
+			"status":          TxStatusBroadcasted,
+			"dust_aggregated": true,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("credit dust deposits failed: %w", result.Error)
+	}
+	return nil
+}
+
 // UpdateDepositsComfirms 查询所有还没有过确认位交易，用最新区块减去对应区块更新确认，如果这个大于我们预设的确认位，那么这笔交易可以认为已经入账
+// HasUnconfirmedDeposits 地址是否有已扫描但确认数不足的充值
+func (db *depositsDB) HasUnconfirmedDeposits(requestId string, chainName string, toAddress string) (bool, error) {
+	tableName := utils.GetTableName("deposits", requestId, chainName)
+	var deposit Deposits
+	result := db.gorm.Table(tableName).
+		Where("to_address IN ? AND status = ?", addressLookupForms(chainName, toAddress), TxStatusBroadcasted).
+		Take(&deposit)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, result.Error
+	}
+	return true, nil
+}
+
 func (db *depositsDB) UpdateDepositsComfirms(requestId string, chainName string, blockNumber uint64, confirms uint64) error {
 	tableName := utils.GetTableName("deposits", requestId, chainName)
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
@@ -270,6 +342,11 @@ func (db *depositsDB) StoreDeposits(requestId string, chainName string, depositL
 	if len(depositList) == 0 {
 		return nil
 	}
+	for _, deposit := range depositList {
+		if deposit.MinDeposit == nil {
+			deposit.MinDeposit = big.NewInt(0)
+		}
+	}
 	return db.gorm.Table(tableName).CreateInBatches(depositList, len(depositList)).Error
 }
 
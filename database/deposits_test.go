@@ -30,14 +30,14 @@ func TestDepositsDB_StoreAndQuery(t *testing.T) {
 			BlockNumber:          big.NewInt(1),
 			TxHash:               common.HexToHash("0x2"),
 			TxType:               TxTypeDeposit,
-			FromAddress:          common.HexToAddress("0x3"),
-			ToAddress:            common.HexToAddress("0x4"),
+			FromAddress:          common.HexToAddress("0x3").String(),
+			ToAddress:            common.HexToAddress("0x4").String(),
 			Amount:               big.NewInt(1000),
 			GasLimit:             21000,
 			MaxFeePerGas:         "100",
 			MaxPriorityFeePerGas: "10",
-			TokenType:            TokenTypeERC20,
-			TokenAddress:         common.HexToAddress("0x5"),
+			TokenType:            TokenType("ERC20"),
+			TokenAddress:         common.HexToAddress("0x5").String(),
 			TokenId:              "1",
 			TokenMeta:            "meta",
 			TxSignHex:            "0x6",
@@ -45,13 +45,13 @@ func TestDepositsDB_StoreAndQuery(t *testing.T) {
 	}
 
 	// Store the deposit
-	err := depositsDB.StoreDeposits(requestId, depositList)
+	err := depositsDB.StoreDeposits(requestId, CurrentChain, depositList)
 	if err != nil {
 		t.Fatalf("failed to store deposit: %v", err)
 	}
 
 	// Query the deposit
-	notifyDeposits, err := depositsDB.QueryNotifyDeposits(strconv.Itoa(CurrentRequestId))
+	notifyDeposits, err := depositsDB.QueryNotifyDeposits(strconv.Itoa(CurrentRequestId), CurrentChain)
 	if err != nil {
 		t.Fatalf("failed to query notify deposits: %v", err)
 	}
@@ -59,13 +59,13 @@ func TestDepositsDB_StoreAndQuery(t *testing.T) {
 
 	blockNumber := uint64(10)
 	confirms := uint64(5)
-	err = depositsDB.UpdateDepositsComfirms(strconv.Itoa(CurrentRequestId), blockNumber, confirms)
+	err = depositsDB.UpdateDepositsComfirms(strconv.Itoa(CurrentRequestId), CurrentChain, blockNumber, confirms)
 	if err != nil {
 		t.Fatalf("failed to update deposit confirms: %v", err)
 	}
 
 	// Query the deposit
-	notifyDepositsV2, err := depositsDB.QueryNotifyDeposits(strconv.Itoa(CurrentRequestId))
+	notifyDepositsV2, err := depositsDB.QueryNotifyDeposits(strconv.Itoa(CurrentRequestId), CurrentChain)
 	if err != nil {
 		t.Fatalf("failed to query notify deposits: %v", err)
 	}
@@ -90,40 +90,40 @@ func TestUpdateDepositsNotifyStatus(t *testing.T) {
 			BlockNumber:          big.NewInt(1),
 			TxHash:               common.HexToHash("0x2"),
 			TxType:               TxTypeDeposit,
-			FromAddress:          common.HexToAddress("0x3"),
-			ToAddress:            common.HexToAddress("0x4"),
+			FromAddress:          common.HexToAddress("0x3").String(),
+			ToAddress:            common.HexToAddress("0x4").String(),
 			Amount:               big.NewInt(1000),
 			GasLimit:             21000,
 			MaxFeePerGas:         "100",
 			MaxPriorityFeePerGas: "10",
-			TokenType:            TokenTypeERC20,
-			TokenAddress:         common.HexToAddress("0x5"),
+			TokenType:            TokenType("ERC20"),
+			TokenAddress:         common.HexToAddress("0x5").String(),
 			TokenId:              "1",
 			TokenMeta:            "meta",
 			TxSignHex:            "0x6",
 		},
 	}
 
-	err := depositsDB.StoreDeposits(strconv.Itoa(CurrentRequestId), depositList)
+	err := depositsDB.StoreDeposits(strconv.Itoa(CurrentRequestId), CurrentChain, depositList)
 	if err != nil {
 		t.Fatalf("failed to store deposit: %v", err)
 	}
 
 	// Query the deposit
-	notifyDeposits, err := depositsDB.QueryDepositsByTxHash(strconv.Itoa(CurrentRequestId), depositList[0].TxHash)
+	notifyDeposits, err := depositsDB.QueryDepositsByTxHash(strconv.Itoa(CurrentRequestId), CurrentChain, depositList[0].TxHash)
 	if err != nil {
 		t.Fatalf("failed to query notify deposits: %v", err)
 	}
 	t.Logf("notifyDeposits %v", json2.ToPrettyJSON(notifyDeposits))
 
 	newStatus := TxStatusWalletDone
-	err = depositsDB.UpdateDepositsStatusById(strconv.Itoa(CurrentRequestId), newStatus, depositList)
+	err = depositsDB.UpdateDepositsStatusById(strconv.Itoa(CurrentRequestId), CurrentChain, newStatus, depositList)
 	if err != nil {
 		t.Fatalf("failed to update deposit notify status: %v", err)
 	}
 
 	// Query the deposit
-	notifyDepositsV2, err := depositsDB.QueryDepositsById(strconv.Itoa(CurrentRequestId), depositList[0].GUID.String())
+	notifyDepositsV2, err := depositsDB.QueryDepositsById(strconv.Itoa(CurrentRequestId), CurrentChain, depositList[0].GUID.String())
 	if err != nil {
 		t.Fatalf("failed to query notify deposits: %v", err)
 	}
@@ -150,14 +150,14 @@ func TestUpdateDepositList(t *testing.T) {
 			BlockNumber:          big.NewInt(1),
 			TxHash:               common.HexToHash("0x22"),
 			TxType:               TxTypeDeposit,
-			FromAddress:          common.HexToAddress("0x33"),
-			ToAddress:            common.HexToAddress("0x44"),
+			FromAddress:          common.HexToAddress("0x33").String(),
+			ToAddress:            common.HexToAddress("0x44").String(),
 			Amount:               big.NewInt(1000),
 			GasLimit:             21000,
 			MaxFeePerGas:         "100",
 			MaxPriorityFeePerGas: "10",
-			TokenType:            TokenTypeERC20,
-			TokenAddress:         common.HexToAddress("0x55"),
+			TokenType:            TokenType("ERC20"),
+			TokenAddress:         common.HexToAddress("0x55").String(),
 			TokenId:              "1",
 			TokenMeta:            "meta",
 			TxSignHex:            "0x66",
@@ -171,14 +171,14 @@ func TestUpdateDepositList(t *testing.T) {
 			BlockNumber:          big.NewInt(1),
 			TxHash:               common.HexToHash("0x2"),
 			TxType:               TxTypeDeposit,
-			FromAddress:          common.HexToAddress("0x3"),
-			ToAddress:            common.HexToAddress("0x4"),
+			FromAddress:          common.HexToAddress("0x3").String(),
+			ToAddress:            common.HexToAddress("0x4").String(),
 			Amount:               big.NewInt(1000),
 			GasLimit:             21000,
 			MaxFeePerGas:         "100",
 			MaxPriorityFeePerGas: "10",
-			TokenType:            TokenTypeERC20,
-			TokenAddress:         common.HexToAddress("0x5"),
+			TokenType:            TokenType("ERC20"),
+			TokenAddress:         common.HexToAddress("0x5").String(),
 			TokenId:              "1",
 			TokenMeta:            "meta",
 			TxSignHex:            "0x6",
@@ -186,14 +186,14 @@ func TestUpdateDepositList(t *testing.T) {
 	}
 
 	// Store initial deposits
-	err := depositsDB.StoreDeposits(strconv.Itoa(CurrentRequestId), depositList)
+	err := depositsDB.StoreDeposits(strconv.Itoa(CurrentRequestId), CurrentChain, depositList)
 	if err != nil {
 		t.Fatalf("failed to store deposits: %v", err)
 	}
 
 	// Verify updates
 	for _, deposit := range depositList {
-		temp, err := depositsDB.QueryDepositsByTxHash(strconv.Itoa(CurrentRequestId), deposit.TxHash)
+		temp, err := depositsDB.QueryDepositsByTxHash(strconv.Itoa(CurrentRequestId), CurrentChain, deposit.TxHash)
 		if err != nil {
 			t.Fatalf("failed to QueryDepositsByTxHash: %v", err)
 		}
@@ -207,13 +207,13 @@ func TestUpdateDepositList(t *testing.T) {
 		deposit.Amount = big.NewInt(deposit.Amount.Int64() + 500) // Example update
 	}
 
-	err = depositsDB.UpdateDepositListById(strconv.Itoa(CurrentRequestId), depositList)
+	err = depositsDB.UpdateDepositListById(strconv.Itoa(CurrentRequestId), CurrentChain, depositList)
 	if err != nil {
 		t.Fatalf("failed to update deposit list: %v", err)
 	}
 
 	for _, deposit := range depositList {
-		temp, err := depositsDB.QueryDepositsById(strconv.Itoa(CurrentRequestId), deposit.GUID.String())
+		temp, err := depositsDB.QueryDepositsById(strconv.Itoa(CurrentRequestId), CurrentChain, deposit.GUID.String())
 		if err != nil {
 			t.Fatalf("failed to QueryDepositsById: %v", err)
 		}
@@ -227,13 +227,13 @@ func TestUpdateDepositList(t *testing.T) {
 		deposit.Amount = big.NewInt(deposit.Amount.Int64() + 10000) // Example update
 	}
 
-	err = depositsDB.UpdateDepositListByTxHash(strconv.Itoa(CurrentRequestId), depositList)
+	err = depositsDB.UpdateDepositListByTxHash(strconv.Itoa(CurrentRequestId), CurrentChain, depositList)
 	if err != nil {
 		t.Fatalf("failed to update deposit list: %v", err)
 	}
 
 	for _, deposit := range depositList {
-		temp, err := depositsDB.QueryDepositsById(strconv.Itoa(CurrentRequestId), deposit.GUID.String())
+		temp, err := depositsDB.QueryDepositsById(strconv.Itoa(CurrentRequestId), CurrentChain, deposit.GUID.String())
 		if err != nil {
 			t.Fatalf("failed to QueryDepositsById: %v", err)
 		}
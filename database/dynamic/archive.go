@@ -0,0 +1,238 @@
+package dynamic
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+)
+
+const (
+	archiveBatchSize    = 1000
+	archiveManifestName = "manifest.json"
+)
+
+type ArchiveTable struct {
+	Table    string `json:"table"`
+	Rows     int64  `json:"rows"`
+	Checksum string `json:"checksum"`
+}
+
+type ArchiveManifest struct {
+	BusinessUid string          `json:"business_uid"`
+	Timestamp   uint64          `json:"timestamp"`
+	Tables      []*ArchiveTable `json:"tables"`
+}
+
+// BusinessTableNames 返回业务在所有链上的动态表，不包含 uid 以该业务 uid 为前缀的其他业务的表
+func BusinessTableNames(db *database.DB, businessUid string) ([]string, error) {
+	businessList, err := db.Business.QueryBusinessList()
+	if err != nil {
+		return nil, err
+	}
+	var tableNames []string
+	for _, template := range TemplateTables {
+		names, err := db.CreateTable.QueryTableNamesByPrefix(fmt.Sprintf("%s_%s_", template, businessUid))
+		if err != nil {
+			return nil, err
+		}
+		tableNames = append(tableNames, ownedTables(template, businessUid, names, businessList)...)
+	}
+	return tableNames, nil
+}
+
+// ArchiveBusinessTables 将业务的动态表导出为 tar.gz，校验归档内容后删除这些表并将业务标记为 archived
+func ArchiveBusinessTables(db *database.DB, businessUid string, archiveDir string) (string, error) {
+	tableNames, err := BusinessTableNames(db, businessUid)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(archiveDir, 0o750); err != nil {
+		return "", fmt.Errorf("create archive dir fail: %w", err)
+	}
+	archivePath := filepath.Join(archiveDir, fmt.Sprintf("%s_%d.tar.gz", businessUid, time.Now().Unix()))
+
+	manifest, err := writeArchive(db, businessUid, archivePath, tableNames)
+	if err != nil {
+		_ = os.Remove(archivePath)
+		return "", err
+	}
+	if err := verifyArchive(archivePath, manifest); err != nil {
+		return "", fmt.Errorf("verify archive %s fail: %w", archivePath, err)
+	}
+	log.Info("business archive verified", "businessUid", businessUid, "archive", archivePath, "tables", len(tableNames))
+
+	err = db.Transaction(func(tx *database.DB) error {
+		for _, table := range manifest.Tables {
+			count, err := tx.CreateTable.CountTableRows(table.Table)
+			if err != nil {
+				return err
+			}
+			if count != table.Rows {
+				return fmt.Errorf("table %s changed during archive: archived %d rows, now %d", table.Table, table.Rows, count)
+			}
+			if err := tx.CreateTable.DropTable(table.Table); err != nil {
+				return err
+			}
+		}
+		return tx.Business.UpdateBusinessStatus(businessUid, database.BusinessStatusArchived)
+	})
+	if err != nil {
+		return archivePath, fmt.Errorf("drop archived tables fail: %w", err)
+	}
+	return archivePath, nil
+}
+
+func writeArchive(db *database.DB, businessUid string, archivePath string, tableNames []string) (*ArchiveManifest, error) {
+	file, err := os.OpenFile(archivePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("create archive file fail: %w", err)
+	}
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	manifest := &ArchiveManifest{BusinessUid: businessUid, Timestamp: uint64(time.Now().Unix())}
+	for _, tableName := range tableNames {
+		table, err := writeArchiveTable(db, tarWriter, tableName)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Tables = append(manifest.Tables, table)
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := tarWriter.WriteHeader(&tar.Header{Name: archiveManifestName, Mode: 0o640, Size: int64(len(manifestData)), ModTime: time.Now()}); err != nil {
+		return nil, err
+	}
+	if _, err := tarWriter.Write(manifestData); err != nil {
+		return nil, err
+	}
+	if err := tarWriter.Close(); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, file.Sync()
+}
+
+// writeArchiveTable 先将表数据按 JSON lines 写入临时文件，再写入 tar 包
+func writeArchiveTable(db *database.DB, tarWriter *tar.Writer, tableName string) (*ArchiveTable, error) {
+	tmp, err := os.CreateTemp("", tableName+"-*.jsonl")
+	if err != nil {
+		return nil, fmt.Errorf("create temp file fail: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	writer := bufio.NewWriter(io.MultiWriter(tmp, hash))
+	encoder := json.NewEncoder(writer)
+	var rowCount int64
+	for offset := 0; ; offset += archiveBatchSize {
+		rows, err := db.CreateTable.QueryTableRows(tableName, offset, archiveBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if err := encoder.Encode(row); err != nil {
+				return nil, fmt.Errorf("encode row of %s fail: %w", tableName, err)
+			}
+		}
+		rowCount += int64(len(rows))
+		if len(rows) < archiveBatchSize {
+			break
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := tarWriter.WriteHeader(&tar.Header{Name: tableName + ".jsonl", Mode: 0o640, Size: info.Size(), ModTime: time.Now()}); err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(tarWriter, tmp); err != nil {
+		return nil, err
+	}
+	log.Info("table archived", "table", tableName, "rows", rowCount)
+	return &ArchiveTable{Table: tableName, Rows: rowCount, Checksum: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// verifyArchive 重新读取归档文件，校验每张表的行数和 sha256 与 manifest 一致
+func verifyArchive(archivePath string, manifest *ArchiveManifest) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+
+	expected := make(map[string]*ArchiveTable, len(manifest.Tables))
+	for _, table := range manifest.Tables {
+		expected[table.Table+".jsonl"] = table
+	}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		table, ok := expected[header.Name]
+		if !ok {
+			continue
+		}
+		hash := sha256.New()
+		scanner := bufio.NewScanner(io.TeeReader(tarReader, hash))
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		var rows int64
+		for scanner.Scan() {
+			if !json.Valid(scanner.Bytes()) {
+				return fmt.Errorf("invalid row in %s", header.Name)
+			}
+			rows++
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		if rows != table.Rows {
+			return fmt.Errorf("row count mismatch for %s: expected %d, got %d", table.Table, table.Rows, rows)
+		}
+		if checksum := hex.EncodeToString(hash.Sum(nil)); checksum != table.Checksum {
+			return fmt.Errorf("checksum mismatch for %s", table.Table)
+		}
+		delete(expected, header.Name)
+	}
+	if len(expected) > 0 {
+		return fmt.Errorf("%d tables missing from archive", len(expected))
+	}
+	return nil
+}
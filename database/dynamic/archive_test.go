@@ -0,0 +1,64 @@
+package dynamic
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+)
+
+func writeTestArchive(t *testing.T, files map[string]string) string {
+	archivePath := filepath.Join(t.TempDir(), "business.tar.gz")
+	file, err := os.Create(archivePath)
+	require.NoError(t, err)
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0o640, Size: int64(len(content))}))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return archivePath
+}
+
+func TestVerifyArchive(t *testing.T) {
+	content := "{\"guid\":\"1\"}\n{\"guid\":\"2\"}\n"
+	sum := sha256.Sum256([]byte(content))
+	archivePath := writeTestArchive(t, map[string]string{"deposits_1_ethereum.jsonl": content})
+
+	manifest := &ArchiveManifest{
+		BusinessUid: "1",
+		Tables:      []*ArchiveTable{{Table: "deposits_1_ethereum", Rows: 2, Checksum: hex.EncodeToString(sum[:])}},
+	}
+	require.NoError(t, verifyArchive(archivePath, manifest))
+
+	manifest.Tables[0].Rows = 3
+	require.ErrorContains(t, verifyArchive(archivePath, manifest), "row count mismatch")
+
+	manifest.Tables[0].Rows = 2
+	manifest.Tables[0].Checksum = "00"
+	require.ErrorContains(t, verifyArchive(archivePath, manifest), "checksum mismatch")
+
+	manifest.Tables[0].Checksum = hex.EncodeToString(sum[:])
+	manifest.Tables = append(manifest.Tables, &ArchiveTable{Table: "withdraws_1_ethereum"})
+	require.ErrorContains(t, verifyArchive(archivePath, manifest), "missing from archive")
+}
+
+func TestOwnedTablesExcludesPrefixedBusiness(t *testing.T) {
+	businessList := []*database.Business{{BusinessUid: "1"}, {BusinessUid: "1_2"}, {BusinessUid: "1_2_3"}}
+	tables := []string{"deposits_1_ethereum", "deposits_1_2_ethereum", "deposits_1_2_3_tron", "deposits_1_tron", "deposits_1_"}
+
+	require.Equal(t, []string{"deposits_1_ethereum", "deposits_1_tron"}, ownedTables("deposits", "1", tables, businessList))
+	require.Equal(t, []string{"deposits_1_2_ethereum"}, ownedTables("deposits", "1_2", tables, businessList))
+	require.Equal(t, []string{"deposits_1_2_3_tron"}, ownedTables("deposits", "1_2_3", tables, businessList))
+}
@@ -0,0 +1,316 @@
+package dynamic
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	"github.com/dapplink-labs/multichain-sync-account/database/utils"
+)
+
+// TemplateTables 每个业务按链克隆的模板表
+var TemplateTables = []string{
+	"addresses",
+	"tokens",
+	"balances",
+	"deposits",
+	"transactions",
+	"withdraws",
+	"internals",
+	"balance_journals",
+	"quarantined_deposits",
+	"memos",
+	"utxos",
+	"address_pool",
+	"extended_keys",
+}
+
+// postgres 标识符最大长度
+const maxIdentifierLength = 63
+
+var indexDefRegexp = regexp.MustCompile(`(?i)^CREATE (UNIQUE )?INDEX (\S+) ON (?:ONLY )?(\S+) (.*)$`)
+
+// TableDrift 动态表与模板表之间的差异，Statements 为同步差异需要执行的 DDL
+type TableDrift struct {
+	Template           string
+	Table              string
+	MissingTable       bool
+	MissingColumns     []string
+	ChangedColumns     []string
+	ExtraColumns       []string
+	MissingIndexes     []string
+	MissingConstraints []string
+	Statements         []string
+}
+
+func (d *TableDrift) HasDrift() bool {
+	return d.MissingTable || len(d.MissingColumns) > 0 || len(d.ChangedColumns) > 0 || len(d.ExtraColumns) > 0 ||
+		len(d.MissingIndexes) > 0 || len(d.MissingConstraints) > 0
+}
+
+type tableSchema struct {
+	columns     []*database.TableColumn
+	indexes     []*database.TableIndex
+	constraints []*database.TableConstraint
+}
+
+func loadTableSchema(db *database.DB, tableName string) (*tableSchema, error) {
+	columns, err := db.CreateTable.QueryTableColumns(tableName)
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := db.CreateTable.QueryTableIndexes(tableName)
+	if err != nil {
+		return nil, err
+	}
+	constraints, err := db.CreateTable.QueryTableConstraints(tableName)
+	if err != nil {
+		return nil, err
+	}
+	return &tableSchema{columns: columns, indexes: indexes, constraints: constraints}, nil
+}
+
+// SyncTemplateTables 对比所有业务的动态表与模板表，dryRun 为 false 时执行同步 DDL
+func SyncTemplateTables(db *database.DB, dryRun bool) ([]*TableDrift, error) {
+	businessList, err := db.Business.QueryBusinessList()
+	if err != nil {
+		return nil, fmt.Errorf("query business list fail: %w", err)
+	}
+
+	var driftList []*TableDrift
+	missingTables, err := missingBusinessTables(db, businessList)
+	if err != nil {
+		return nil, err
+	}
+	for _, drift := range missingTables {
+		driftList = append(driftList, drift)
+		if dryRun {
+			continue
+		}
+		log.Info("create missing dynamic table", "table", drift.Table, "template", drift.Template)
+		if err := db.CreateTable.CreateTable(drift.Table, drift.Template); err != nil {
+			return driftList, err
+		}
+	}
+
+	for _, template := range TemplateTables {
+		templateSchema, err := loadTableSchema(db, template)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, business := range businessList {
+			tableNames, err := db.CreateTable.QueryTableNamesByPrefix(fmt.Sprintf("%s_%s_", template, business.BusinessUid))
+			if err != nil {
+				return nil, err
+			}
+			for _, tableName := range tableNames {
+				if seen[tableName] {
+					continue
+				}
+				seen[tableName] = true
+				tableSchema, err := loadTableSchema(db, tableName)
+				if err != nil {
+					return nil, err
+				}
+				drift := diffTableSchema(template, tableName, templateSchema, tableSchema)
+				if !drift.HasDrift() {
+					continue
+				}
+				driftList = append(driftList, drift)
+				if dryRun {
+					continue
+				}
+				for _, statement := range drift.Statements {
+					log.Info("sync dynamic table", "table", tableName, "statement", statement)
+					if err := db.CreateTable.ExecDDL(statement); err != nil {
+						return driftList, fmt.Errorf("sync table %s fail: %w", tableName, err)
+					}
+				}
+			}
+		}
+	}
+	return driftList, nil
+}
+
+// missingBusinessTables 以 balances 动态表确定业务已注册的链，新增的模板表需要为这些链补建
+func missingBusinessTables(db *database.DB, businessList []*database.Business) ([]*TableDrift, error) {
+	var driftList []*TableDrift
+	for _, business := range businessList {
+		prefix := fmt.Sprintf("balances_%s_", business.BusinessUid)
+		tableNames, err := db.CreateTable.QueryTableNamesByPrefix(prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, chainName := range businessChains(business.BusinessUid, tableNames, businessList) {
+			for _, template := range TemplateTables {
+				tableName := utils.GetTableName(template, business.BusinessUid, chainName)
+				existing, err := db.CreateTable.QueryTableNamesByPrefix(tableName)
+				if err != nil {
+					return nil, err
+				}
+				if !slices.Contains(existing, tableName) {
+					driftList = append(driftList, &TableDrift{
+						Template:     template,
+						Table:        tableName,
+						MissingTable: true,
+						Statements:   []string{fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (LIKE %s INCLUDING ALL)", tableName, template)},
+					})
+				}
+			}
+		}
+	}
+	return driftList, nil
+}
+
+// businessChains 从 balances_<uid>_<chain> 表名中解析链名，排除属于前缀相同的其他业务的表
+func businessChains(businessUid string, balanceTables []string, businessList []*database.Business) []string {
+	prefix := fmt.Sprintf("balances_%s_", businessUid)
+	var chains []string
+	for _, tableName := range ownedTables("balances", businessUid, balanceTables, businessList) {
+		chains = append(chains, strings.TrimPrefix(tableName, prefix))
+	}
+	return chains
+}
+
+// ownedTables 从按 <template>_<uid>_ 前缀查出的表中排除 uid 以该 uid 为前缀的其他业务的表，
+// 例如业务 1 的前缀同样匹配业务 1_2 的 balances_1_2_ethereum
+func ownedTables(template string, businessUid string, tableNames []string, businessList []*database.Business) []string {
+	prefix := fmt.Sprintf("%s_%s_", template, businessUid)
+	var owned []string
+	for _, tableName := range tableNames {
+		if len(tableName) <= len(prefix) || !strings.HasPrefix(tableName, prefix) {
+			continue
+		}
+		claimed := false
+		for _, other := range businessList {
+			otherPrefix := fmt.Sprintf("%s_%s_", template, other.BusinessUid)
+			if len(otherPrefix) > len(prefix) && strings.HasPrefix(tableName, otherPrefix) {
+				claimed = true
+				break
+			}
+		}
+		if !claimed {
+			owned = append(owned, tableName)
+		}
+	}
+	return owned
+}
+
+func diffTableSchema(template, table string, templateSchema, tableSchema *tableSchema) *TableDrift {
+	drift := &TableDrift{Template: template, Table: table}
+
+	tableColumns := make(map[string]*database.TableColumn, len(tableSchema.columns))
+	for _, column := range tableSchema.columns {
+		tableColumns[column.ColumnName] = column
+	}
+	templateColumns := make(map[string]bool, len(templateSchema.columns))
+	for _, expected := range templateSchema.columns {
+		templateColumns[expected.ColumnName] = true
+		actual, ok := tableColumns[expected.ColumnName]
+		if !ok {
+			drift.MissingColumns = append(drift.MissingColumns, expected.ColumnName)
+			statement := fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s", table, expected.ColumnName, expected.DataType)
+			if expected.ColumnDefault != nil {
+				statement += " DEFAULT " + *expected.ColumnDefault
+			}
+			if expected.IsNullable == "NO" {
+				statement += " NOT NULL"
+			}
+			drift.Statements = append(drift.Statements, statement)
+			continue
+		}
+		if !strings.EqualFold(actual.DataType, expected.DataType) {
+			drift.ChangedColumns = append(drift.ChangedColumns, fmt.Sprintf("%s type %s -> %s", expected.ColumnName, actual.DataType, expected.DataType))
+			drift.Statements = append(drift.Statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s", table, expected.ColumnName, expected.DataType))
+		}
+		if actual.IsNullable != expected.IsNullable {
+			drift.ChangedColumns = append(drift.ChangedColumns, fmt.Sprintf("%s nullable %s -> %s", expected.ColumnName, actual.IsNullable, expected.IsNullable))
+			action := "DROP NOT NULL"
+			if expected.IsNullable == "NO" {
+				action = "SET NOT NULL"
+			}
+			drift.Statements = append(drift.Statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s", table, expected.ColumnName, action))
+		}
+		if defaultValue(actual) != defaultValue(expected) {
+			drift.ChangedColumns = append(drift.ChangedColumns, fmt.Sprintf("%s default %q -> %q", expected.ColumnName, defaultValue(actual), defaultValue(expected)))
+			action := "DROP DEFAULT"
+			if expected.ColumnDefault != nil {
+				action = "SET DEFAULT " + *expected.ColumnDefault
+			}
+			drift.Statements = append(drift.Statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s", table, expected.ColumnName, action))
+		}
+	}
+	// 多出来的列只报告，不自动删除
+	for _, column := range tableSchema.columns {
+		if !templateColumns[column.ColumnName] {
+			drift.ExtraColumns = append(drift.ExtraColumns, column.ColumnName)
+		}
+	}
+
+	tableConstraints := make(map[string]bool, len(tableSchema.constraints))
+	for _, constraint := range tableSchema.constraints {
+		tableConstraints[constraint.ConstraintType+":"+constraint.Definition] = true
+	}
+	constraintNames := make(map[string]bool, len(templateSchema.constraints))
+	for _, constraint := range templateSchema.constraints {
+		constraintNames[constraint.ConstraintName] = true
+		if tableConstraints[constraint.ConstraintType+":"+constraint.Definition] {
+			continue
+		}
+		drift.MissingConstraints = append(drift.MissingConstraints, constraint.ConstraintName)
+		drift.Statements = append(drift.Statements, fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s",
+			table, derivedName(table, template, constraint.ConstraintName), constraint.Definition))
+	}
+
+	tableIndexes := make(map[string]bool, len(tableSchema.indexes))
+	for _, index := range tableSchema.indexes {
+		if key, ok := indexKey(index.IndexDef); ok {
+			tableIndexes[key] = true
+		}
+	}
+	for _, index := range templateSchema.indexes {
+		// 主键和唯一约束的索引随约束一起创建
+		if constraintNames[index.IndexName] {
+			continue
+		}
+		key, ok := indexKey(index.IndexDef)
+		if !ok || tableIndexes[key] {
+			continue
+		}
+		match := indexDefRegexp.FindStringSubmatch(index.IndexDef)
+		drift.MissingIndexes = append(drift.MissingIndexes, index.IndexName)
+		drift.Statements = append(drift.Statements, fmt.Sprintf("CREATE %sINDEX IF NOT EXISTS %s ON %s %s",
+			strings.ToUpper(match[1]), derivedName(table, template, index.IndexName), table, match[4]))
+	}
+	return drift
+}
+
+func defaultValue(column *database.TableColumn) string {
+	if column.ColumnDefault == nil {
+		return ""
+	}
+	return *column.ColumnDefault
+}
+
+// indexKey 去掉索引名和表名后的索引定义，用于比较两张表是否有相同的索引
+func indexKey(indexDef string) (string, bool) {
+	match := indexDefRegexp.FindStringSubmatch(indexDef)
+	if match == nil {
+		return "", false
+	}
+	return strings.ToUpper(match[1]) + match[4], true
+}
+
+// derivedName 根据模板表上的索引或约束名生成动态表上的名字
+func derivedName(table, template, name string) string {
+	derived := table + "_" + strings.TrimPrefix(name, template+"_")
+	if len(derived) > maxIdentifierLength {
+		derived = derived[:maxIdentifierLength]
+	}
+	return derived
+}
@@ -0,0 +1,72 @@
+package dynamic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestDiffTableSchema(t *testing.T) {
+	templateSchema := &tableSchema{
+		columns: []*database.TableColumn{
+			{ColumnName: "guid", DataType: "varchar", IsNullable: "NO"},
+			{ColumnName: "amount", DataType: "uint256", IsNullable: "NO"},
+			{ColumnName: "memo", DataType: "varchar", IsNullable: "NO", ColumnDefault: strPtr("''::character varying")},
+		},
+		indexes: []*database.TableIndex{
+			{IndexName: "withdraws_pkey", IndexDef: "CREATE UNIQUE INDEX withdraws_pkey ON public.withdraws USING btree (guid)"},
+			{IndexName: "withdraws_memo", IndexDef: "CREATE INDEX withdraws_memo ON public.withdraws USING btree (memo)"},
+		},
+		constraints: []*database.TableConstraint{
+			{ConstraintName: "withdraws_pkey", ConstraintType: "p", Definition: "PRIMARY KEY (guid)"},
+		},
+	}
+	tableSchema := &tableSchema{
+		columns: []*database.TableColumn{
+			{ColumnName: "guid", DataType: "varchar", IsNullable: "NO"},
+			{ColumnName: "amount", DataType: "uint256", IsNullable: "YES"},
+			{ColumnName: "legacy", DataType: "varchar", IsNullable: "YES"},
+		},
+		indexes: []*database.TableIndex{
+			{IndexName: "withdraws_1_ethereum_pkey", IndexDef: "CREATE UNIQUE INDEX withdraws_1_ethereum_pkey ON public.withdraws_1_ethereum USING btree (guid)"},
+		},
+		constraints: []*database.TableConstraint{
+			{ConstraintName: "withdraws_1_ethereum_pkey", ConstraintType: "p", Definition: "PRIMARY KEY (guid)"},
+		},
+	}
+
+	drift := diffTableSchema("withdraws", "withdraws_1_ethereum", templateSchema, tableSchema)
+	require.True(t, drift.HasDrift())
+	require.Equal(t, []string{"memo"}, drift.MissingColumns)
+	require.Equal(t, []string{"legacy"}, drift.ExtraColumns)
+	require.Len(t, drift.ChangedColumns, 1)
+	require.Equal(t, []string{"withdraws_memo"}, drift.MissingIndexes)
+	require.Empty(t, drift.MissingConstraints)
+	require.Equal(t, []string{
+		"ALTER TABLE withdraws_1_ethereum ALTER COLUMN amount SET NOT NULL",
+		"ALTER TABLE withdraws_1_ethereum ADD COLUMN IF NOT EXISTS memo varchar DEFAULT ''::character varying NOT NULL",
+		"CREATE INDEX IF NOT EXISTS withdraws_1_ethereum_memo ON withdraws_1_ethereum USING btree (memo)",
+	}, drift.Statements)
+}
+
+func TestDiffTableSchemaNoDrift(t *testing.T) {
+	schema := &tableSchema{
+		columns: []*database.TableColumn{{ColumnName: "guid", DataType: "varchar", IsNullable: "NO"}},
+	}
+	drift := diffTableSchema("tokens", "tokens_1_ethereum", schema, schema)
+	require.False(t, drift.HasDrift())
+	require.Empty(t, drift.Statements)
+}
+
+func TestBusinessChains(t *testing.T) {
+	businessList := []*database.Business{{BusinessUid: "1"}, {BusinessUid: "1_2"}}
+	tables := []string{"balances_1_ethereum", "balances_1_2_ethereum", "balances_1_tron"}
+	require.Equal(t, []string{"ethereum", "tron"}, businessChains("1", tables, businessList))
+	require.Equal(t, []string{"ethereum"}, businessChains("1_2", []string{"balances_1_2_ethereum"}, businessList))
+}
@@ -29,6 +29,24 @@ func CreateTableFromTemplate(requestId string, chainName string, db *database.DB
 	if err := createInternals(requestId, chainName, db); err != nil {
 		return fmt.Errorf("failed to create internals table: %w", err)
 	}
+	if err := createBalanceJournals(requestId, chainName, db); err != nil {
+		return fmt.Errorf("failed to create balance journals table: %w", err)
+	}
+	if err := createQuarantinedDeposits(requestId, chainName, db); err != nil {
+		return fmt.Errorf("failed to create quarantined deposits table: %w", err)
+	}
+	if err := createMemos(requestId, chainName, db); err != nil {
+		return fmt.Errorf("failed to create memos table: %w", err)
+	}
+	if err := createUtxos(requestId, chainName, db); err != nil {
+		return fmt.Errorf("failed to create utxos table: %w", err)
+	}
+	if err := createAddressPool(requestId, chainName, db); err != nil {
+		return fmt.Errorf("failed to create address pool table: %w", err)
+	}
+	if err := createExtendedKeys(requestId, chainName, db); err != nil {
+		return fmt.Errorf("failed to create extended keys table: %w", err)
+	}
 	return nil
 }
 
@@ -73,3 +91,39 @@ func createInternals(requestId string, chainName string, db *database.DB) error
 	tableNameByChain := utils.GetTableName(tableName, requestId, chainName)
 	return db.CreateTable.CreateTable(tableNameByChain, tableName)
 }
+
+func createBalanceJournals(requestId string, chainName string, db *database.DB) error {
+	tableName := "balance_journals"
+	tableNameByChain := utils.GetTableName(tableName, requestId, chainName)
+	return db.CreateTable.CreateTable(tableNameByChain, tableName)
+}
+
+func createQuarantinedDeposits(requestId string, chainName string, db *database.DB) error {
+	tableName := "quarantined_deposits"
+	tableNameByChain := utils.GetTableName(tableName, requestId, chainName)
+	return db.CreateTable.CreateTable(tableNameByChain, tableName)
+}
+
+func createMemos(requestId string, chainName string, db *database.DB) error {
+	tableName := "memos"
+	tableNameByChain := utils.GetTableName(tableName, requestId, chainName)
+	return db.CreateTable.CreateTable(tableNameByChain, tableName)
+}
+
+func createUtxos(requestId string, chainName string, db *database.DB) error {
+	tableName := "utxos"
+	tableNameByChain := utils.GetTableName(tableName, requestId, chainName)
+	return db.CreateTable.CreateTable(tableNameByChain, tableName)
+}
+
+func createAddressPool(requestId string, chainName string, db *database.DB) error {
+	tableName := "address_pool"
+	tableNameByChain := utils.GetTableName(tableName, requestId, chainName)
+	return db.CreateTable.CreateTable(tableNameByChain, tableName)
+}
+
+func createExtendedKeys(requestId string, chainName string, db *database.DB) error {
+	tableName := "extended_keys"
+	tableNameByChain := utils.GetTableName(tableName, requestId, chainName)
+	return db.CreateTable.CreateTable(tableNameByChain, tableName)
+}
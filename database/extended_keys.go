@@ -0,0 +1,115 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/dapplink-labs/multichain-sync-account/database/utils"
+)
+
+// ExtendedKeys 业务方登记的扩展公钥，地址按 <extended_key>/0/<index> 派生，NextIndex 为下一个未使用的序号
+type ExtendedKeys struct {
+	GUID          uuid.UUID   `gorm:"primaryKey" json:"guid"`
+	ExtendedKey   string      `gorm:"type:varchar;not null" json:"extended_key"`
+	AddressFormat string      `gorm:"type:varchar;not null;default:''" json:"address_format"`
+	AddressType   AddressType `gorm:"type:varchar(10);not null;default:'eoa'" json:"address_type"`
+	NextIndex     uint32      `gorm:"type:bigint;not null;default:0" json:"next_index"`
+	Timestamp     uint64      `gorm:"not null;check:timestamp > 0" json:"timestamp"`
+}
+
+type ExtendedKeysView interface {
+	QueryExtendedKeyById(requestId string, chainName string, guid string) (*ExtendedKeys, error)
+	QueryExtendedKey(requestId string, chainName string, extendedKey string, addressFormat string) (*ExtendedKeys, error)
+}
+
+type ExtendedKeysDB interface {
+	ExtendedKeysView
+
+	StoreExtendedKey(requestId string, chainName string, key *ExtendedKeys) (*ExtendedKeys, error)
+	LockExtendedKey(requestId string, chainName string, guid string) (*ExtendedKeys, error)
+	UpdateNextIndex(requestId string, chainName string, guid string, nextIndex uint32) error
+}
+
+type extendedKeysDB struct {
+	gorm *gorm.DB
+}
+
+func NewExtendedKeysDB(db *gorm.DB) ExtendedKeysDB {
+	return &extendedKeysDB{gorm: db}
+}
+
+// StoreExtendedKey 同一扩展公钥和地址格式重复登记时返回已有记录
+func (db *extendedKeysDB) StoreExtendedKey(requestId string, chainName string, key *ExtendedKeys) (*ExtendedKeys, error) {
+	tableName := utils.GetTableName("extended_keys", requestId, chainName)
+	err := db.gorm.Table(tableName).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "extended_key"}, {Name: "address_format"}},
+			DoNothing: true,
+		}).
+		Create(key).Error
+	if err != nil {
+		return nil, fmt.Errorf("store extended key failed: %w", err)
+	}
+	return db.QueryExtendedKey(requestId, chainName, key.ExtendedKey, key.AddressFormat)
+}
+
+func (db *extendedKeysDB) QueryExtendedKeyById(requestId string, chainName string, guid string) (*ExtendedKeys, error) {
+	var key ExtendedKeys
+	tableName := utils.GetTableName("extended_keys", requestId, chainName)
+	err := db.gorm.Table(tableName).Where("guid = ?", guid).Take(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query extended key failed: %w", err)
+	}
+	return &key, nil
+}
+
+func (db *extendedKeysDB) QueryExtendedKey(requestId string, chainName string, extendedKey string, addressFormat string) (*ExtendedKeys, error) {
+	var key ExtendedKeys
+	tableName := utils.GetTableName("extended_keys", requestId, chainName)
+	err := db.gorm.Table(tableName).
+		Where("extended_key = ? AND address_format = ?", extendedKey, addressFormat).
+		Take(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query extended key failed: %w", err)
+	}
+	return &key, nil
+}
+
+// LockExtendedKey 在事务中锁定扩展公钥，派生地址和推进序号期间其他请求等待
+func (db *extendedKeysDB) LockExtendedKey(requestId string, chainName string, guid string) (*ExtendedKeys, error) {
+	var key ExtendedKeys
+	tableName := utils.GetTableName("extended_keys", requestId, chainName)
+	err := db.gorm.Table(tableName).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("guid = ?", guid).
+		Take(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lock extended key failed: %w", err)
+	}
+	return &key, nil
+}
+
+// UpdateNextIndex 序号只前进不后退
+func (db *extendedKeysDB) UpdateNextIndex(requestId string, chainName string, guid string, nextIndex uint32) error {
+	tableName := utils.GetTableName("extended_keys", requestId, chainName)
+	err := db.gorm.Table(tableName).
+		Where("guid = ? AND next_index < ?", guid, nextIndex).
+		Update("next_index", nextIndex).Error
+	if err != nil {
+		return fmt.Errorf("update extended key next index failed: %w", err)
+	}
+	return nil
+}
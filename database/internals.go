@@ -1,11 +1,13 @@
 package database
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"math/big"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -35,6 +37,10 @@ type Internals struct {
 	GasLimit             uint64 `json:"gas_limit"`
 	MaxFeePerGas         string `json:"max_fee_per_gas"`
 	MaxPriorityFeePerGas string `json:"max_priority_fee_per_gas"`
+	// GasPrice legacy 交易的 gasPrice，此时 MaxFeePerGas 和 MaxPriorityFeePerGas 为空
+	GasPrice string `json:"gas_price" gorm:"column:gas_price"`
+	// FeeStrategy 创建交易时使用的手续费策略档位，被上限截断时带 capped 后缀
+	FeeStrategy string `json:"fee_strategy" gorm:"column:fee_strategy"`
 
 	// Token 相关信息
 	TokenType    TokenType `json:"token_type" gorm:"column:token_type"` // ETH, ERC20, ERC721, ERC1155
@@ -44,6 +50,14 @@ type Internals struct {
 
 	// 交易签名
 	TxSignHex string `json:"tx_sign_hex" gorm:"column:tx_sign_hex"`
+	// UnsignedTx 创建时交给签名方的 base64 交易数据，签名时原样使用，SignHash 为 chain-account 返回的待签名哈希
+	UnsignedTx string `json:"unsigned_tx" gorm:"column:unsigned_tx"`
+	SignHash   string `json:"sign_hash" gorm:"column:sign_hash"`
+	// UTXO 链的找零地址和找零金额，没有找零时 ChangeAmount 为 nil
+	ChangeAddress string   `json:"change_address" gorm:"column:change_address"`
+	ChangeAmount  *big.Int `json:"change_amount" gorm:"serializer:u256;column:change_amount"`
+	// Nonce 账户模型链创建待签名交易时分配的 nonce，UTXO 等没有 nonce 的链为空
+	Nonce *uint64 `json:"nonce" gorm:"column:nonce"`
 }
 
 type InternalsView interface {
@@ -51,6 +65,8 @@ type InternalsView interface {
 	QueryInternalsByTxHash(requestId string, chainName string, txHash common.Hash) (*Internals, error)
 	QueryInternalsById(requestId string, chainName string, guid string) (*Internals, error)
 	UnSendInternalsList(requestId string, chainName string) ([]*Internals, error)
+	QueryPendingInternals(requestId string, chainName string) ([]*Internals, error)
+	QueryPendingNonce(requestId string, chainName string, fromAddress string) (*uint64, error)
 }
 
 type InternalsDB interface {
@@ -62,6 +78,8 @@ type InternalsDB interface {
 	UpdateInternalStatusByTxHash(requestId string, chainName string, status TxStatus, internalsList []*Internals) error
 	UpdateInternalListByHash(requestId string, chainName string, internalsList []*Internals) error
 	UpdateInternalListById(requestId string, chainName string, internalsList []*Internals) error
+	UpdateInternalUnsignedTx(requestId string, chainName string, internal *Internals) error
+	ExpireInternals(requestId string, chainName string, createdBefore uint64) ([]*Internals, error)
 }
 
 type internalsDB struct {
@@ -131,6 +149,19 @@ func (db *internalsDB) UnSendInternalsList(requestId string, chainName string) (
 	return internalsList, nil
 }
 
+// QueryPendingInternals 已创建但链上尚未确认的内部转账，超时未签名广播的记录由 Expiry worker 标记为 expired
+func (db *internalsDB) QueryPendingInternals(requestId string, chainName string) ([]*Internals, error) {
+	tableName := utils.GetTableName("internals", requestId, chainName)
+	var internalsList []*Internals
+	err := db.gorm.Table(tableName).
+		Where("status IN ?", []TxStatus{TxStatusCreateUnsigned, TxStatusSigned, TxStatusBroadcasted}).
+		Find(&internalsList).Error
+	if err != nil {
+		return nil, fmt.Errorf("query pending internals failed: %w", err)
+	}
+	return internalsList, nil
+}
+
 type GasInfo struct {
 	GasLimit             uint64
 	MaxFeePerGas         string
@@ -187,6 +218,55 @@ func (db *internalsDB) UpdateInternalById(requestId string, chainName string, id
 	return nil
 }
 
+// QueryPendingNonce 出款地址在途内部交易已分配的最大 nonce，没有时返回 nil，由 Withdraws.LockPendingNonce 的锁保护
+func (db *internalsDB) QueryPendingNonce(requestId string, chainName string, fromAddress string) (*uint64, error) {
+	tableName := utils.GetTableName("internals", requestId, chainName)
+	var maxNonce sql.NullInt64
+	err := db.gorm.Table(tableName).
+		Select("MAX(nonce)").
+		Where("from_address = ? AND nonce IS NOT NULL AND status IN ?", fromAddress, []TxStatus{TxStatusCreateUnsigned, TxStatusSigned, TxStatusBroadcasted}).
+		Scan(&maxNonce).Error
+	if err != nil {
+		return nil, fmt.Errorf("query pending internal nonce failed: %w", err)
+	}
+	if !maxNonce.Valid {
+		return nil, nil
+	}
+	nonce := uint64(maxNonce.Int64)
+	return &nonce, nil
+}
+
+// UpdateInternalUnsignedTx 为 worker 创建时还没有待签名数据的内部交易写入手续费和待签名数据，已写入过的不再覆盖
+func (db *internalsDB) UpdateInternalUnsignedTx(requestId string, chainName string, internal *Internals) error {
+	tableName := utils.GetTableName("internals", requestId, chainName)
+	updates := map[string]interface{}{
+		"gas_limit":                internal.GasLimit,
+		"max_fee_per_gas":          internal.MaxFeePerGas,
+		"max_priority_fee_per_gas": internal.MaxPriorityFeePerGas,
+		"gas_price":                internal.GasPrice,
+		"fee_strategy":             internal.FeeStrategy,
+		"unsigned_tx":              internal.UnsignedTx,
+		"sign_hash":                internal.SignHash,
+	}
+	if internal.Nonce != nil {
+		updates["nonce"] = *internal.Nonce
+	}
+	if internal.ChangeAmount != nil {
+		updates["change_address"] = internal.ChangeAddress
+		updates["change_amount"] = internal.ChangeAmount
+	}
+	result := db.gorm.Table(tableName).
+		Where("guid = ? AND status = ? AND (unsigned_tx IS NULL OR unsigned_tx = '')", internal.GUID.String(), TxStatusCreateUnsigned).
+		Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("update internal unsigned tx failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (db *internalsDB) UpdateInternalStatusByTxHash(requestId string, chainName string, status TxStatus, internalsList []*Internals) error {
 	if len(internalsList) == 0 {
 		return nil
@@ -292,3 +372,27 @@ func (db *internalsDB) UpdateInternalListByHash(requestId string, chainName stri
 		return nil
 	})
 }
+
+// ExpireInternals 将 createdBefore 之前创建、仍未签名或未广播成功的内部交易标记为 expired，返回被标记的交易
+func (db *internalsDB) ExpireInternals(requestId string, chainName string, createdBefore uint64) ([]*Internals, error) {
+	tableName := utils.GetTableName("internals", requestId, chainName)
+	var expired []*Internals
+	err := db.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Table(tableName).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("status IN ? AND timestamp < ?", []TxStatus{TxStatusCreateUnsigned, TxStatusSigned}, createdBefore).
+			Find(&expired).Error
+		if err != nil || len(expired) == 0 {
+			return err
+		}
+		guids := make([]uuid.UUID, 0, len(expired))
+		for _, internal := range expired {
+			guids = append(guids, internal.GUID)
+		}
+		return tx.Table(tableName).Where("guid IN ?", guids).Update("status", TxStatusExpired).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("expire internals failed: %w", err)
+	}
+	return expired, nil
+}
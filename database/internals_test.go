@@ -27,25 +27,25 @@ func TestQueryNotifyInternal(t *testing.T) {
 		BlockHash:            common.HexToHash("0x1"),
 		BlockNumber:          big.NewInt(1),
 		TxHash:               common.HexToHash("0x2"),
-		FromAddress:          common.HexToAddress("0x3"),
-		ToAddress:            common.HexToAddress("0x4"),
+		FromAddress:          common.HexToAddress("0x3").String(),
+		ToAddress:            common.HexToAddress("0x4").String(),
 		Amount:               big.NewInt(1000),
 		GasLimit:             21000,
 		MaxFeePerGas:         "100",
 		MaxPriorityFeePerGas: "2",
-		TokenType:            TokenTypeERC20,
-		TokenAddress:         common.HexToAddress("0x5"),
+		TokenType:            TokenType("ERC20"),
+		TokenAddress:         common.HexToAddress("0x5").String(),
 		TokenId:              "1",
 		TokenMeta:            "meta",
 		TxSignHex:            "",
 	}
 
-	err := internalsDB.StoreInternal(strconv.Itoa(requestId), internal)
+	err := internalsDB.StoreInternal(strconv.Itoa(requestId), CurrentChain, internal)
 	if err != nil {
 		t.Fatalf("failed to store internal: %v", err)
 	}
 
-	notifyInternals, err := internalsDB.QueryNotifyInternal(strconv.Itoa(requestId))
+	notifyInternals, err := internalsDB.QueryNotifyInternal(strconv.Itoa(requestId), CurrentChain)
 	if err != nil {
 		t.Fatalf("failed to query notify internals: %v", err)
 	}
@@ -68,25 +68,25 @@ func TestStoreInternal(t *testing.T) {
 		BlockHash:            common.HexToHash("0x1"),
 		BlockNumber:          big.NewInt(1),
 		TxHash:               common.HexToHash("0x2"),
-		FromAddress:          common.HexToAddress("0x3"),
-		ToAddress:            common.HexToAddress("0x4"),
+		FromAddress:          common.HexToAddress("0x3").String(),
+		ToAddress:            common.HexToAddress("0x4").String(),
 		Amount:               big.NewInt(1000),
 		GasLimit:             21000,
 		MaxFeePerGas:         "100",
 		MaxPriorityFeePerGas: "2",
-		TokenType:            TokenTypeERC20,
-		TokenAddress:         common.HexToAddress("0x5"),
+		TokenType:            TokenType("ERC20"),
+		TokenAddress:         common.HexToAddress("0x5").String(),
 		TokenId:              "1",
 		TokenMeta:            "meta",
 		TxSignHex:            "0x6",
 	}
 
-	err := internalsDB.StoreInternal(strconv.Itoa(requestId), internal)
+	err := internalsDB.StoreInternal(strconv.Itoa(requestId), CurrentChain, internal)
 	if err != nil {
 		t.Fatalf("failed to store internal: %v", err)
 	}
 
-	storedInternal, err := internalsDB.QueryInternalsByTxHash(strconv.Itoa(requestId), internal.TxHash)
+	storedInternal, err := internalsDB.QueryInternalsByTxHash(strconv.Itoa(requestId), CurrentChain, internal.TxHash)
 	if err != nil {
 		t.Fatalf("failed to query stored internal: %v", err)
 	}
@@ -109,25 +109,25 @@ func TestUnSendInternalsList(t *testing.T) {
 		BlockHash:            common.HexToHash("0x1"),
 		BlockNumber:          big.NewInt(1),
 		TxHash:               common.HexToHash("0x2"),
-		FromAddress:          common.HexToAddress("0x3"),
-		ToAddress:            common.HexToAddress("0x4"),
+		FromAddress:          common.HexToAddress("0x3").String(),
+		ToAddress:            common.HexToAddress("0x4").String(),
 		Amount:               big.NewInt(1000),
 		GasLimit:             21000,
 		MaxFeePerGas:         "100",
 		MaxPriorityFeePerGas: "2",
-		TokenType:            TokenTypeERC20,
-		TokenAddress:         common.HexToAddress("0x5"),
+		TokenType:            TokenType("ERC20"),
+		TokenAddress:         common.HexToAddress("0x5").String(),
 		TokenId:              "1",
 		TokenMeta:            "meta",
 		TxSignHex:            "0x6",
 	}
 
-	err := internalsDB.StoreInternal(strconv.Itoa(requestId), internal)
+	err := internalsDB.StoreInternal(strconv.Itoa(requestId), CurrentChain, internal)
 	if err != nil {
 		t.Fatalf("failed to store internal: %v", err)
 	}
 
-	unSendInternals, err := internalsDB.UnSendInternalsList(strconv.Itoa(requestId))
+	unSendInternals, err := internalsDB.UnSendInternalsList(strconv.Itoa(requestId), CurrentChain)
 	if err != nil {
 		t.Fatalf("failed to query unsend internals list: %v", err)
 	}
@@ -150,24 +150,24 @@ func TestUpdateInternalTx(t *testing.T) {
 		BlockHash:            common.HexToHash("0x1"),
 		BlockNumber:          big.NewInt(1),
 		TxHash:               common.HexToHash("0x2"),
-		FromAddress:          common.HexToAddress("0x3"),
-		ToAddress:            common.HexToAddress("0x4"),
+		FromAddress:          common.HexToAddress("0x3").String(),
+		ToAddress:            common.HexToAddress("0x4").String(),
 		Amount:               big.NewInt(1000),
 		GasLimit:             21000,
 		MaxFeePerGas:         "100",
 		MaxPriorityFeePerGas: "2",
-		TokenType:            TokenTypeERC20,
-		TokenAddress:         common.HexToAddress("0x5"),
+		TokenType:            TokenType("ERC20"),
+		TokenAddress:         common.HexToAddress("0x5").String(),
 		TokenId:              "1",
 		TokenMeta:            "meta",
 		TxSignHex:            "0x6",
 	}
 
-	err := internalsDB.StoreInternal(strconv.Itoa(requestId), internal)
+	err := internalsDB.StoreInternal(strconv.Itoa(requestId), CurrentChain, internal)
 	if err != nil {
 		t.Fatalf("failed to store internal: %v", err)
 	}
-	updatedInternal, err := internalsDB.QueryInternalsByTxHash(strconv.Itoa(requestId), internal.TxHash)
+	updatedInternal, err := internalsDB.QueryInternalsByTxHash(strconv.Itoa(requestId), CurrentChain, internal.TxHash)
 	if err != nil {
 		t.Fatalf("failed to query updated internal: %v", err)
 	}
@@ -175,12 +175,12 @@ func TestUpdateInternalTx(t *testing.T) {
 
 	newStatus := TxStatusSigned
 	signedTx := "0x7"
-	err = internalsDB.UpdateInternalByTxHash(strconv.Itoa(requestId), internal.TxHash, signedTx, newStatus)
+	err = internalsDB.UpdateInternalByTxHash(strconv.Itoa(requestId), CurrentChain, internal.TxHash, signedTx, newStatus)
 	if err != nil {
 		t.Fatalf("failed to update internal tx: %v", err)
 	}
 
-	updatedInternalV2, err := internalsDB.QueryInternalsByTxHash(strconv.Itoa(requestId), internal.TxHash)
+	updatedInternalV2, err := internalsDB.QueryInternalsByTxHash(strconv.Itoa(requestId), CurrentChain, internal.TxHash)
 	if err != nil {
 		t.Fatalf("failed to query updated internal: %v", err)
 	}
@@ -203,36 +203,36 @@ func TestUpdateInternalStatus(t *testing.T) {
 		BlockHash:            common.HexToHash("0x1"),
 		BlockNumber:          big.NewInt(1),
 		TxHash:               common.HexToHash("0x2"),
-		FromAddress:          common.HexToAddress("0x3"),
-		ToAddress:            common.HexToAddress("0x4"),
+		FromAddress:          common.HexToAddress("0x3").String(),
+		ToAddress:            common.HexToAddress("0x4").String(),
 		Amount:               big.NewInt(1000),
 		GasLimit:             21000,
 		MaxFeePerGas:         "100",
 		MaxPriorityFeePerGas: "2",
-		TokenType:            TokenTypeERC20,
-		TokenAddress:         common.HexToAddress("0x5"),
+		TokenType:            TokenType("ERC20"),
+		TokenAddress:         common.HexToAddress("0x5").String(),
 		TokenId:              "1",
 		TokenMeta:            "meta",
 		TxSignHex:            "0x6",
 	}
 
-	err := internalsDB.StoreInternal(strconv.Itoa(requestId), internal)
+	err := internalsDB.StoreInternal(strconv.Itoa(requestId), CurrentChain, internal)
 	if err != nil {
 		t.Fatalf("failed to store internal: %v", err)
 	}
-	updatedInternal, err := internalsDB.QueryInternalsByTxHash(strconv.Itoa(requestId), internal.TxHash)
+	updatedInternal, err := internalsDB.QueryInternalsByTxHash(strconv.Itoa(requestId), CurrentChain, internal.TxHash)
 	if err != nil {
 		t.Fatalf("failed to query updated internal: %v", err)
 	}
 	t.Logf("updatedInternal 1 %v", json2.ToPrettyJSON(updatedInternal))
 
 	newStatus := TxStatusSigned
-	err = internalsDB.UpdateInternalStatusByTxHash(strconv.Itoa(requestId), newStatus, []*Internals{internal})
+	err = internalsDB.UpdateInternalStatusByTxHash(strconv.Itoa(requestId), CurrentChain, newStatus, []*Internals{internal})
 	if err != nil {
 		t.Fatalf("failed to update internal tx: %v", err)
 	}
 
-	updatedInternalV2, err := internalsDB.QueryInternalsByTxHash(strconv.Itoa(requestId), internal.TxHash)
+	updatedInternalV2, err := internalsDB.QueryInternalsByTxHash(strconv.Itoa(requestId), CurrentChain, internal.TxHash)
 	if err != nil {
 		t.Fatalf("failed to query updated internal: %v", err)
 	}
@@ -258,8 +258,8 @@ func TestUpdateInternalList(t *testing.T) {
 			BlockHash:   common.HexToHash("0x1"),
 			BlockNumber: big.NewInt(1),
 			TxHash:      common.HexToHash("0x2"),
-			FromAddress: common.HexToAddress("0x3"),
-			ToAddress:   common.HexToAddress("0x4"),
+			FromAddress: common.HexToAddress("0x3").String(),
+			ToAddress:   common.HexToAddress("0x4").String(),
 			Amount:      big.NewInt(1000),
 		},
 		{
@@ -269,15 +269,15 @@ func TestUpdateInternalList(t *testing.T) {
 			BlockHash:   common.HexToHash("0x1"),
 			BlockNumber: big.NewInt(2),
 			TxHash:      common.HexToHash("0x3"),
-			FromAddress: common.HexToAddress("0x3"),
-			ToAddress:   common.HexToAddress("0x4"),
+			FromAddress: common.HexToAddress("0x3").String(),
+			ToAddress:   common.HexToAddress("0x4").String(),
 			Amount:      big.NewInt(2000),
 		},
 	}
 
 	// Store initial internals
 	for _, internal := range internalsList {
-		err := internalsDB.StoreInternal(strconv.Itoa(requestId), internal)
+		err := internalsDB.StoreInternal(strconv.Itoa(requestId), CurrentChain, internal)
 		if err != nil {
 			t.Fatalf("failed to store internal: %v", err)
 		}
@@ -285,7 +285,7 @@ func TestUpdateInternalList(t *testing.T) {
 
 	// Verify updates
 	for _, internal := range internalsList {
-		updatedInternal, err := internalsDB.QueryInternalsByTxHash(strconv.Itoa(requestId), internal.TxHash)
+		updatedInternal, err := internalsDB.QueryInternalsByTxHash(strconv.Itoa(requestId), CurrentChain, internal.TxHash)
 		if err != nil {
 			t.Fatalf("failed to query updated internal: %v", err)
 		}
@@ -299,14 +299,14 @@ func TestUpdateInternalList(t *testing.T) {
 		internal.Amount = big.NewInt(internal.Amount.Int64() + 500) // Example update
 	}
 
-	err := internalsDB.UpdateInternalListByHash(strconv.Itoa(requestId), internalsList)
+	err := internalsDB.UpdateInternalListByHash(strconv.Itoa(requestId), CurrentChain, internalsList)
 	if err != nil {
 		t.Fatalf("failed to update internal list: %v", err)
 	}
 
 	// Verify updates
 	for _, internal := range internalsList {
-		updatedInternal, err := internalsDB.QueryInternalsByTxHash(strconv.Itoa(requestId), internal.TxHash)
+		updatedInternal, err := internalsDB.QueryInternalsByTxHash(strconv.Itoa(requestId), CurrentChain, internal.TxHash)
 		if err != nil {
 			t.Fatalf("failed to query updated internal: %v", err)
 		}
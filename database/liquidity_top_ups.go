@@ -0,0 +1,89 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LiquidityNotifyStatus string
+
+const (
+	LiquidityNotifyPending  LiquidityNotifyStatus = "pending"
+	LiquidityNotifyNotified LiquidityNotifyStatus = "notified"
+)
+
+// LiquidityTopUps 热钱包预计余额低于下限时自动创建的冷钱包补充请求，TransactionId 为 create_unsign 状态的 cold2hot 内部交易，
+// 预计余额 = Balance - PendingOutflow + PendingInflow
+type LiquidityTopUps struct {
+	GUID           uuid.UUID             `gorm:"primaryKey" json:"guid"`
+	BusinessUid    string                `gorm:"type:varchar;not null" json:"business_uid"`
+	ChainName      string                `gorm:"type:varchar;not null" json:"chain_name"`
+	TransactionId  string                `gorm:"type:varchar;not null" json:"transaction_id"`
+	HotAddress     string                `gorm:"type:varchar;not null" json:"hot_address"`
+	ColdAddress    string                `gorm:"type:varchar;not null" json:"cold_address"`
+	TokenAddress   string                `gorm:"type:varchar;not null" json:"token_address"`
+	Balance        *big.Int              `gorm:"type:numeric;not null;default:0;serializer:u256" json:"balance"`
+	PendingOutflow *big.Int              `gorm:"type:numeric;not null;default:0;serializer:u256" json:"pending_outflow"`
+	PendingInflow  *big.Int              `gorm:"type:numeric;not null;default:0;serializer:u256" json:"pending_inflow"`
+	MinBalance     *big.Int              `gorm:"type:numeric;not null;default:0;serializer:u256" json:"min_balance"`
+	TargetBalance  *big.Int              `gorm:"type:numeric;not null;default:0;serializer:u256" json:"target_balance"`
+	Amount         *big.Int              `gorm:"type:numeric;not null;serializer:u256" json:"amount"`
+	NotifyStatus   LiquidityNotifyStatus `gorm:"type:varchar(10);not null;default:'pending'" json:"notify_status"`
+	Timestamp      uint64                `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+}
+
+type LiquidityTopUpsView interface {
+	QueryNotifyTopUps(businessUid string, chainName string) ([]*LiquidityTopUps, error)
+}
+
+type LiquidityTopUpsDB interface {
+	LiquidityTopUpsView
+
+	StoreTopUps(topUps []*LiquidityTopUps) error
+	UpdateTopUpsNotifyStatus(topUps []*LiquidityTopUps, status LiquidityNotifyStatus) error
+}
+
+type liquidityTopUpsDB struct {
+	gorm *gorm.DB
+}
+
+func NewLiquidityTopUpsDB(db *gorm.DB) LiquidityTopUpsDB {
+	return &liquidityTopUpsDB{gorm: db}
+}
+
+func (db *liquidityTopUpsDB) StoreTopUps(topUps []*LiquidityTopUps) error {
+	if len(topUps) == 0 {
+		return nil
+	}
+	return db.gorm.Table("liquidity_top_ups").CreateInBatches(topUps, len(topUps)).Error
+}
+
+func (db *liquidityTopUpsDB) QueryNotifyTopUps(businessUid string, chainName string) ([]*LiquidityTopUps, error) {
+	var topUps []*LiquidityTopUps
+	err := db.gorm.Table("liquidity_top_ups").
+		Where("business_uid = ? AND chain_name = ? AND notify_status = ?", businessUid, chainName, LiquidityNotifyPending).
+		Order("timestamp ASC").
+		Find(&topUps).Error
+	if err != nil {
+		return nil, fmt.Errorf("query notify liquidity top ups failed: %w", err)
+	}
+	return topUps, nil
+}
+
+func (db *liquidityTopUpsDB) UpdateTopUpsNotifyStatus(topUps []*LiquidityTopUps, status LiquidityNotifyStatus) error {
+	if len(topUps) == 0 {
+		return nil
+	}
+	guids := make([]uuid.UUID, 0, len(topUps))
+	for _, topUp := range topUps {
+		guids = append(guids, topUp.GUID)
+	}
+	result := db.gorm.Table("liquidity_top_ups").Where("guid IN ?", guids).Update("notify_status", status)
+	if result.Error != nil {
+		return fmt.Errorf("update liquidity top ups notify status failed: %w", result.Error)
+	}
+	return nil
+}
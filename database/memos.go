@@ -0,0 +1,74 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/dapplink-labs/multichain-sync-account/database/utils"
+)
+
+// Memos 共用充值地址的 memo / destination tag，一个 memo 对应业务方的一个用户
+type Memos struct {
+	GUID      uuid.UUID `gorm:"primaryKey" json:"guid"`
+	Address   string    `gorm:"type:varchar;not null" json:"address"`
+	Memo      string    `gorm:"type:varchar;not null" json:"memo"`
+	Timestamp uint64    `gorm:"not null;check:timestamp > 0" json:"timestamp"`
+}
+
+type MemosView interface {
+	IsMemoAddress(requestId string, chainName string, address string) (bool, error)
+	MemoExist(requestId string, chainName string, address string, memo string) (bool, error)
+}
+
+type MemosDB interface {
+	MemosView
+
+	StoreMemos(requestId string, chainName string, memoList []*Memos) error
+}
+
+type memosDB struct {
+	gorm *gorm.DB
+}
+
+func NewMemosDB(db *gorm.DB) MemosDB {
+	return &memosDB{gorm: db}
+}
+
+// StoreMemos 重复登记的 (address, memo) 不做任何操作
+func (db *memosDB) StoreMemos(requestId string, chainName string, memoList []*Memos) error {
+	if len(memoList) == 0 {
+		return nil
+	}
+	tableName := utils.GetTableName("memos", requestId, chainName)
+	for _, memo := range memoList {
+		memo.Address = NormalizeAddress(chainName, memo.Address)
+	}
+	return db.gorm.Table(tableName).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "address"}, {Name: "memo"}},
+			DoNothing: true,
+		}).
+		CreateInBatches(memoList, len(memoList)).Error
+}
+
+// IsMemoAddress 地址登记过 memo 即为共用充值地址，充值需要按 memo 区分用户
+func (db *memosDB) IsMemoAddress(requestId string, chainName string, address string) (bool, error) {
+	var count int64
+	tableName := utils.GetTableName("memos", requestId, chainName)
+	if err := db.gorm.Table(tableName).Where("address IN ?", addressLookupForms(chainName, address)).Limit(1).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("query memo address failed: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (db *memosDB) MemoExist(requestId string, chainName string, address string, memo string) (bool, error) {
+	var count int64
+	tableName := utils.GetTableName("memos", requestId, chainName)
+	if err := db.gorm.Table(tableName).Where("address IN ? AND memo = ?", addressLookupForms(chainName, address), memo).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("query memo failed: %w", err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,294 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"gorm.io/gorm"
+)
+
+// migrationLockKey pg_advisory_lock 使用的锁 id，保证同一时间只有一个实例执行迁移
+const migrationLockKey int64 = 0x6d756c7469737963
+
+// 迁移文件命名：00002_add_column.up.sql / 00002_add_column.down.sql，无 up/down 后缀视为 up
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_([A-Za-z0-9_\-]+?)(\.(up|down))?\.sql$`)
+
+type Migration struct {
+	Version  uint64
+	Name     string
+	UpSQL    string
+	DownSQL  string
+	Checksum string
+}
+
+type SchemaMigrations struct {
+	Version   uint64 `gorm:"primaryKey" json:"version"`
+	Name      string `json:"name"`
+	Checksum  string `json:"checksum"`
+	Timestamp uint64 `json:"timestamp"`
+}
+
+type MigrationStatus struct {
+	*Migration
+	Applied          bool
+	AppliedAt        uint64
+	ChecksumMismatch bool
+}
+
+// LoadMigrations 读取迁移目录，按版本号排序返回
+func LoadMigrations(migrationsFolder string) ([]*Migration, error) {
+	entries, err := os.ReadDir(migrationsFolder)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations folder fail: %w", err)
+	}
+	migrationMap := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFileRegexp.FindStringSubmatch(entry.Name())
+		if match == nil {
+			log.Warn("skip unrecognized migration file", "file", entry.Name())
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		content, err := os.ReadFile(filepath.Join(migrationsFolder, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read migration file %s fail: %w", entry.Name(), err)
+		}
+		migration, ok := migrationMap[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			migrationMap[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d has conflicting names %s and %s", version, migration.Name, match[2])
+		}
+		if match[4] == "down" {
+			migration.DownSQL = string(content)
+		} else {
+			if migration.UpSQL != "" {
+				return nil, fmt.Errorf("duplicate up migration for version %d", version)
+			}
+			migration.UpSQL = string(content)
+			sum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(migrationMap))
+	for _, migration := range migrationMap {
+		if migration.UpSQL == "" {
+			return nil, fmt.Errorf("migration version %d has no up file", migration.Version)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func (db *DB) MigrationStatus(migrationsFolder string) ([]*MigrationStatus, error) {
+	migrations, err := LoadMigrations(migrationsFolder)
+	if err != nil {
+		return nil, err
+	}
+	var statusList []*MigrationStatus
+	err = db.withMigrationLock(func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		statusList = buildMigrationStatus(migrations, applied)
+		return nil
+	})
+	return statusList, err
+}
+
+// MigrateUp 执行所有未执行的迁移
+func (db *DB) MigrateUp(migrationsFolder string) error {
+	migrations, err := LoadMigrations(migrationsFolder)
+	if err != nil {
+		return err
+	}
+	if len(migrations) == 0 {
+		return nil
+	}
+	return db.migrateTo(migrations, migrations[len(migrations)-1].Version)
+}
+
+// MigrateDown 回滚最近执行的 steps 个迁移
+func (db *DB) MigrateDown(migrationsFolder string, steps int) error {
+	migrations, err := LoadMigrations(migrationsFolder)
+	if err != nil {
+		return err
+	}
+	return db.withMigrationLock(func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]uint64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+		if steps <= 0 || steps > len(versions) {
+			steps = len(versions)
+		}
+		var target uint64
+		if steps < len(versions) {
+			target = versions[steps]
+		}
+		return applyMigrations(conn, migrations, applied, target)
+	})
+}
+
+// MigrateTo 迁移到指定版本，版本高于当前时执行 up，低于当前时执行 down
+func (db *DB) MigrateTo(migrationsFolder string, version uint64) error {
+	migrations, err := LoadMigrations(migrationsFolder)
+	if err != nil {
+		return err
+	}
+	return db.migrateTo(migrations, version)
+}
+
+func (db *DB) migrateTo(migrations []*Migration, version uint64) error {
+	return db.withMigrationLock(func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		return applyMigrations(conn, migrations, applied, version)
+	})
+}
+
+// withMigrationLock 在同一个数据库连接上持有 advisory lock 执行 fn
+func (db *DB) withMigrationLock(fn func(conn *gorm.DB) error) error {
+	return db.gorm.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return fmt.Errorf("acquire migration lock fail: %w", err)
+		}
+		defer func() {
+			if err := conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey).Error; err != nil {
+				log.Error("release migration lock fail", "err", err)
+			}
+		}()
+		if err := conn.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations
+(
+    version   BIGINT PRIMARY KEY,
+    name      VARCHAR NOT NULL,
+    checksum  VARCHAR NOT NULL,
+    timestamp BIGINT  NOT NULL
+)`).Error; err != nil {
+			return fmt.Errorf("create schema_migrations table fail: %w", err)
+		}
+		return fn(conn)
+	})
+}
+
+func appliedMigrations(conn *gorm.DB) (map[uint64]*SchemaMigrations, error) {
+	var rows []*SchemaMigrations
+	if err := conn.Table("schema_migrations").Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("query schema_migrations fail: %w", err)
+	}
+	applied := make(map[uint64]*SchemaMigrations, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+func buildMigrationStatus(migrations []*Migration, applied map[uint64]*SchemaMigrations) []*MigrationStatus {
+	statusList := make([]*MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := &MigrationStatus{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = row.Timestamp
+			status.ChecksumMismatch = row.Checksum != migration.Checksum
+		}
+		statusList = append(statusList, status)
+	}
+	return statusList
+}
+
+func verifyChecksums(migrations []*Migration, applied map[uint64]*SchemaMigrations) error {
+	known := make(map[uint64]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		row, ok := applied[migration.Version]
+		if ok && row.Checksum != migration.Checksum {
+			return fmt.Errorf("checksum mismatch for applied migration %d_%s", migration.Version, migration.Name)
+		}
+	}
+	for version, row := range applied {
+		if !known[version] {
+			return fmt.Errorf("applied migration %d_%s is missing from migrations folder", version, row.Name)
+		}
+	}
+	return nil
+}
+
+func applyMigrations(conn *gorm.DB, migrations []*Migration, applied map[uint64]*SchemaMigrations, target uint64) error {
+	if err := verifyChecksums(migrations, applied); err != nil {
+		return err
+	}
+	for _, migration := range migrations {
+		if migration.Version > target {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		log.Info("apply migration", "version", migration.Version, "name", migration.Name)
+		err := conn.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.UpSQL).Error; err != nil {
+				return err
+			}
+			return tx.Table("schema_migrations").Create(&SchemaMigrations{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Checksum:  migration.Checksum,
+				Timestamp: uint64(time.Now().Unix()),
+			}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("apply migration %d_%s fail: %w", migration.Version, migration.Name, err)
+		}
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		if migration.Version <= target {
+			break
+		}
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.DownSQL == "" {
+			return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+		}
+		log.Info("revert migration", "version", migration.Version, "name", migration.Name)
+		err := conn.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.DownSQL).Error; err != nil {
+				return err
+			}
+			return tx.Table("schema_migrations").Where("version = ?", migration.Version).Delete(&SchemaMigrations{}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("revert migration %d_%s fail: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeMigrationFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	return dir
+}
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		versions []uint64
+		names    []string
+		wantErr  string
+	}{
+		{
+			name: "sorted with optional down and unrecognized files skipped",
+			files: map[string]string{
+				"00002_add_column.sql":        "ALTER TABLE t ADD COLUMN c INT;",
+				"00001_create_table.up.sql":   "CREATE TABLE t (id INT);",
+				"00001_create_table.down.sql": "DROP TABLE t;",
+				"README.md":                   "not a migration",
+			},
+			versions: []uint64{1, 2},
+			names:    []string{"create_table", "add_column"},
+		},
+		{
+			name:    "conflicting names for one version",
+			files:   map[string]string{"00001_a.up.sql": "SELECT 1;", "00001_b.down.sql": "SELECT 1;"},
+			wantErr: "conflicting names",
+		},
+		{
+			name:    "duplicate up migration",
+			files:   map[string]string{"00001_a.up.sql": "SELECT 1;", "00001_a.sql": "SELECT 2;"},
+			wantErr: "duplicate up migration",
+		},
+		{
+			name:    "down without up",
+			files:   map[string]string{"00001_a.down.sql": "SELECT 1;"},
+			wantErr: "has no up file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := LoadMigrations(writeMigrationFiles(t, tt.files))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, migrations, len(tt.versions))
+			for i, migration := range migrations {
+				require.Equal(t, tt.versions[i], migration.Version)
+				require.Equal(t, tt.names[i], migration.Name)
+				sum := sha256.Sum256([]byte(migration.UpSQL))
+				require.Equal(t, hex.EncodeToString(sum[:]), migration.Checksum)
+			}
+			require.Equal(t, "DROP TABLE t;", migrations[0].DownSQL)
+			require.Empty(t, migrations[1].DownSQL)
+		})
+	}
+
+	_, err := LoadMigrations(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+
+	// 仓库自带的迁移版本连续且都有 down 文件
+	migrations, err := LoadMigrations("../migrations")
+	require.NoError(t, err)
+	for i, migration := range migrations {
+		require.Equal(t, uint64(i+1), migration.Version)
+		require.NotEmpty(t, migration.DownSQL, "migration %d_%s has no down file", migration.Version, migration.Name)
+	}
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	migrations := []*Migration{
+		{Version: 1, Name: "create_table", Checksum: "aaa"},
+		{Version: 2, Name: "add_column", Checksum: "bbb"},
+	}
+	tests := []struct {
+		name    string
+		applied map[uint64]*SchemaMigrations
+		wantErr string
+	}{
+		{
+			name:    "nothing applied",
+			applied: map[uint64]*SchemaMigrations{},
+		},
+		{
+			name:    "applied prefix matches",
+			applied: map[uint64]*SchemaMigrations{1: {Version: 1, Name: "create_table", Checksum: "aaa"}},
+		},
+		{
+			name:    "applied migration edited",
+			applied: map[uint64]*SchemaMigrations{1: {Version: 1, Name: "create_table", Checksum: "changed"}},
+			wantErr: "checksum mismatch for applied migration 1_create_table",
+		},
+		{
+			name: "applied migration missing from folder",
+			applied: map[uint64]*SchemaMigrations{
+				1: {Version: 1, Name: "create_table", Checksum: "aaa"},
+				3: {Version: 3, Name: "removed", Checksum: "ccc"},
+			},
+			wantErr: "applied migration 3_removed is missing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyChecksums(migrations, tt.applied)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
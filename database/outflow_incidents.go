@@ -0,0 +1,93 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IncidentNotifyStatus string
+
+const (
+	IncidentNotifyPending  IncidentNotifyStatus = "pending"
+	IncidentNotifyNotified IncidentNotifyStatus = "notified"
+)
+
+// OutflowIncidents 从登记地址转出、但没有对应 withdraws/internals 记录的交易，可能是地址私钥泄露，
+// WithdrawPaused 表示发现该事件时按业务配置暂停了提现
+type OutflowIncidents struct {
+	GUID           uuid.UUID            `gorm:"primaryKey" json:"guid"`
+	BusinessUid    string               `gorm:"type:varchar;not null" json:"business_uid"`
+	ChainName      string               `gorm:"type:varchar;not null" json:"chain_name"`
+	TxHash         string               `gorm:"type:varchar;not null" json:"tx_hash"`
+	BlockNumber    *big.Int             `gorm:"type:numeric;not null;serializer:u256" json:"block_number"`
+	FromAddress    string               `gorm:"type:varchar;not null" json:"from_address"`
+	FromRole       AddressType          `gorm:"type:varchar(10);not null" json:"from_role"`
+	ToAddress      string               `gorm:"type:varchar;not null" json:"to_address"`
+	ToRole         AddressType          `gorm:"type:varchar(10);not null" json:"to_role"`
+	TokenAddress   string               `gorm:"type:varchar;not null" json:"token_address"`
+	Amount         *big.Int             `gorm:"type:numeric;not null;serializer:u256" json:"amount"`
+	TxType         TransactionType      `gorm:"type:varchar;not null" json:"tx_type"`
+	WithdrawPaused bool                 `gorm:"not null;default:false" json:"withdraw_paused"`
+	NotifyStatus   IncidentNotifyStatus `gorm:"type:varchar(10);not null;default:'pending'" json:"notify_status"`
+	Timestamp      uint64               `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+}
+
+type OutflowIncidentsView interface {
+	QueryNotifyIncidents(businessUid string, chainName string) ([]*OutflowIncidents, error)
+}
+
+type OutflowIncidentsDB interface {
+	OutflowIncidentsView
+
+	StoreIncidents(incidents []*OutflowIncidents) error
+	UpdateIncidentsNotifyStatus(incidents []*OutflowIncidents, status IncidentNotifyStatus) error
+}
+
+type outflowIncidentsDB struct {
+	gorm *gorm.DB
+}
+
+func NewOutflowIncidentsDB(db *gorm.DB) OutflowIncidentsDB {
+	return &outflowIncidentsDB{gorm: db}
+}
+
+// StoreIncidents 同一交易重复扫描时只保留第一条记录
+func (db *outflowIncidentsDB) StoreIncidents(incidents []*OutflowIncidents) error {
+	if len(incidents) == 0 {
+		return nil
+	}
+	return db.gorm.Table("outflow_incidents").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(incidents, len(incidents)).Error
+}
+
+func (db *outflowIncidentsDB) QueryNotifyIncidents(businessUid string, chainName string) ([]*OutflowIncidents, error) {
+	var incidents []*OutflowIncidents
+	err := db.gorm.Table("outflow_incidents").
+		Where("business_uid = ? AND chain_name = ? AND notify_status = ?", businessUid, chainName, IncidentNotifyPending).
+		Order("timestamp ASC").
+		Find(&incidents).Error
+	if err != nil {
+		return nil, fmt.Errorf("query notify outflow incidents failed: %w", err)
+	}
+	return incidents, nil
+}
+
+func (db *outflowIncidentsDB) UpdateIncidentsNotifyStatus(incidents []*OutflowIncidents, status IncidentNotifyStatus) error {
+	if len(incidents) == 0 {
+		return nil
+	}
+	guids := make([]uuid.UUID, 0, len(incidents))
+	for _, incident := range incidents {
+		guids = append(guids, incident.GUID)
+	}
+	result := db.gorm.Table("outflow_incidents").Where("guid IN ?", guids).Update("notify_status", status)
+	if result.Error != nil {
+		return fmt.Errorf("update outflow incidents notify status failed: %w", result.Error)
+	}
+	return nil
+}
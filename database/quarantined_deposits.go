@@ -0,0 +1,71 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/dapplink-labs/multichain-sync-account/database/utils"
+)
+
+// QuarantinedDeposits 未登记代币的充值，不入账不通知，业务方登记代币后可人工处理
+type QuarantinedDeposits struct {
+	GUID         uuid.UUID   `gorm:"primaryKey;type:varchar(36)" json:"guid"`
+	BlockNumber  *big.Int    `gorm:"not null;check:block_number > 0;serializer:u256" json:"block_number"`
+	TxHash       common.Hash `gorm:"column:hash;type:varchar;not null;serializer:bytes" json:"hash"`
+	FromAddress  string      `gorm:"type:varchar;not null" json:"from_address"`
+	ToAddress    string      `gorm:"type:varchar;not null" json:"to_address"`
+	TokenAddress string      `gorm:"type:varchar;not null" json:"token_address"`
+	Amount       *big.Int    `gorm:"not null;serializer:u256" json:"amount"`
+	Fee          string      `gorm:"type:varchar;not null;default:''" json:"fee"`
+	Timestamp    uint64      `gorm:"not null;check:timestamp > 0" json:"timestamp"`
+}
+
+type QuarantinedDepositsView interface {
+	QueryQuarantinedDeposits(requestId string, chainName string, tokenAddress string) ([]*QuarantinedDeposits, error)
+}
+
+type QuarantinedDepositsDB interface {
+	QuarantinedDepositsView
+
+	StoreQuarantinedDeposits(requestId string, chainName string, depositList []*QuarantinedDeposits) error
+}
+
+type quarantinedDepositsDB struct {
+	gorm *gorm.DB
+}
+
+func NewQuarantinedDepositsDB(db *gorm.DB) QuarantinedDepositsDB {
+	return &quarantinedDepositsDB{gorm: db}
+}
+
+// StoreQuarantinedDeposits 区块回扫时同一笔充值可能重复出现，冲突时不更新
+func (db *quarantinedDepositsDB) StoreQuarantinedDeposits(requestId string, chainName string, depositList []*QuarantinedDeposits) error {
+	if len(depositList) == 0 {
+		return nil
+	}
+	tableName := utils.GetTableName("quarantined_deposits", requestId, chainName)
+	return db.gorm.Table(tableName).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "hash"}, {Name: "to_address"}, {Name: "token_address"}},
+			DoNothing: true,
+		}).
+		CreateInBatches(depositList, len(depositList)).Error
+}
+
+func (db *quarantinedDepositsDB) QueryQuarantinedDeposits(requestId string, chainName string, tokenAddress string) ([]*QuarantinedDeposits, error) {
+	var depositList []*QuarantinedDeposits
+	tableName := utils.GetTableName("quarantined_deposits", requestId, chainName)
+	query := db.gorm.Table(tableName)
+	if tokenAddress != "" {
+		query = query.Where("token_address = ?", tokenAddress)
+	}
+	if err := query.Order("timestamp DESC").Find(&depositList).Error; err != nil {
+		return nil, fmt.Errorf("query quarantined deposits failed: %w", err)
+	}
+	return depositList, nil
+}
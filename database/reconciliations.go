@@ -0,0 +1,122 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReconcileStatus string
+
+const (
+	ReconcileStatusCorrected ReconcileStatus = "corrected"
+	ReconcileStatusMismatch  ReconcileStatus = "mismatch"
+	// ReconcileStatusResolved 差异已消失或被新的差异取代的 mismatch 记录
+	ReconcileStatusResolved ReconcileStatus = "resolved"
+)
+
+type ReconcileNotifyStatus string
+
+const (
+	ReconcileNotifyNone     ReconcileNotifyStatus = "none"
+	ReconcileNotifyPending  ReconcileNotifyStatus = "pending"
+	ReconcileNotifyNotified ReconcileNotifyStatus = "notified"
+)
+
+// Reconciliations 记录数据库余额与链上余额不一致的对账结果，Difference = ChainBalance - StoredBalance
+type Reconciliations struct {
+	GUID          uuid.UUID             `gorm:"primaryKey" json:"guid"`
+	BusinessUid   string                `gorm:"type:varchar;not null" json:"business_uid"`
+	ChainName     string                `gorm:"type:varchar;not null" json:"chain_name"`
+	Address       string                `gorm:"type:varchar;not null" json:"address"`
+	TokenAddress  string                `gorm:"type:varchar;not null" json:"token_address"`
+	AddressType   AddressType           `gorm:"type:varchar(10);not null;default:'eoa'" json:"address_type"`
+	StoredBalance *big.Int              `gorm:"type:numeric;not null;default:0;serializer:u256" json:"stored_balance"`
+	ChainBalance  *big.Int              `gorm:"type:numeric;not null;default:0;serializer:u256" json:"chain_balance"`
+	Difference    *big.Int              `gorm:"type:numeric;not null;default:0;serializer:u256" json:"difference"`
+	Status        ReconcileStatus       `gorm:"type:varchar(10);not null" json:"status"`
+	NotifyStatus  ReconcileNotifyStatus `gorm:"type:varchar(10);not null;default:'none'" json:"notify_status"`
+	Timestamp     uint64                `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+}
+
+type ReconciliationsView interface {
+	QueryNotifyReconciliations(businessUid string, chainName string) ([]*Reconciliations, error)
+	QueryOpenMismatches(businessUid string, chainName string) ([]*Reconciliations, error)
+}
+
+type ReconciliationsDB interface {
+	ReconciliationsView
+
+	StoreReconciliations(reconciliations []*Reconciliations) error
+	UpdateReconciliationsNotifyStatus(reconciliations []*Reconciliations, status ReconcileNotifyStatus) error
+	ResolveReconciliations(guids []uuid.UUID) error
+}
+
+type reconciliationsDB struct {
+	gorm *gorm.DB
+}
+
+func NewReconciliationsDB(db *gorm.DB) ReconciliationsDB {
+	return &reconciliationsDB{gorm: db}
+}
+
+func (db *reconciliationsDB) StoreReconciliations(reconciliations []*Reconciliations) error {
+	if len(reconciliations) == 0 {
+		return nil
+	}
+	return db.gorm.Table("reconciliations").CreateInBatches(reconciliations, len(reconciliations)).Error
+}
+
+func (db *reconciliationsDB) QueryNotifyReconciliations(businessUid string, chainName string) ([]*Reconciliations, error) {
+	var reconciliations []*Reconciliations
+	err := db.gorm.Table("reconciliations").
+		Where("business_uid = ? AND chain_name = ? AND notify_status = ?", businessUid, chainName, ReconcileNotifyPending).
+		Order("timestamp ASC").
+		Find(&reconciliations).Error
+	if err != nil {
+		return nil, fmt.Errorf("query notify reconciliations failed: %w", err)
+	}
+	return reconciliations, nil
+}
+
+func (db *reconciliationsDB) UpdateReconciliationsNotifyStatus(reconciliations []*Reconciliations, status ReconcileNotifyStatus) error {
+	if len(reconciliations) == 0 {
+		return nil
+	}
+	guids := make([]uuid.UUID, 0, len(reconciliations))
+	for _, reconciliation := range reconciliations {
+		guids = append(guids, reconciliation.GUID)
+	}
+	result := db.gorm.Table("reconciliations").Where("guid IN ?", guids).Update("notify_status", status)
+	if result.Error != nil {
+		return fmt.Errorf("update reconciliations notify status failed: %w", result.Error)
+	}
+	return nil
+}
+
+// QueryOpenMismatches 查询尚未解决的 mismatch 记录，同一地址和代币只会有一条
+func (db *reconciliationsDB) QueryOpenMismatches(businessUid string, chainName string) ([]*Reconciliations, error) {
+	var reconciliations []*Reconciliations
+	err := db.gorm.Table("reconciliations").
+		Where("business_uid = ? AND chain_name = ? AND status = ?", businessUid, chainName, ReconcileStatusMismatch).
+		Find(&reconciliations).Error
+	if err != nil {
+		return nil, fmt.Errorf("query open mismatches failed: %w", err)
+	}
+	return reconciliations, nil
+}
+
+func (db *reconciliationsDB) ResolveReconciliations(guids []uuid.UUID) error {
+	if len(guids) == 0 {
+		return nil
+	}
+	result := db.gorm.Table("reconciliations").
+		Where("guid IN ? AND status = ?", guids, ReconcileStatusMismatch).
+		Update("status", ReconcileStatusResolved)
+	if result.Error != nil {
+		return fmt.Errorf("resolve reconciliations failed: %w", result.Error)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 
 	"gorm.io/gorm"
@@ -18,11 +19,19 @@ type Tokens struct {
 	TokenName     string    `json:"tokens_name"`
 	CollectAmount *big.Int  `gorm:"serializer:u256" json:"collect_amount"`
 	ColdAmount    *big.Int  `gorm:"serializer:u256" json:"cold_amount"`
-	Timestamp     uint64    `json:"timestamp"`
+	// MinDeposit 低于该金额的充值记为 below_minimum，AggregateDust 为 true 时同一地址累计达到该金额后一并入账
+	MinDeposit    *big.Int `gorm:"serializer:u256;not null;default:0" json:"min_deposit"`
+	AggregateDust bool     `gorm:"not null;default:false" json:"aggregate_dust"`
+	// HotMinBalance 热钱包预计余额低于该金额时自动创建冷钱包补充交易，补充到 HotTargetBalance，为 0 时不监控
+	HotMinBalance    *big.Int `gorm:"serializer:u256;not null;default:0" json:"hot_min_balance"`
+	HotTargetBalance *big.Int `gorm:"serializer:u256;not null;default:0" json:"hot_target_balance"`
+	Timestamp        uint64   `json:"timestamp"`
 }
 
 type TokensView interface {
 	TokensInfoByAddress(requestId string, chainName string, address string) (*Tokens, error)
+	QueryListedToken(requestId string, chainName string, address string) (*Tokens, bool, error)
+	QueryLiquidityTokens(requestId string, chainName string) ([]*Tokens, error)
 }
 
 type TokensDB interface {
@@ -41,13 +50,25 @@ func NewTokensDB(db *gorm.DB) TokensDB {
 
 func (db *tokensDB) StoreTokens(requestId string, chainName string, tokenList []Tokens) error {
 	tableName := utils.GetTableName("tokens", requestId, chainName)
+	for i := range tokenList {
+		tokenList[i].TokenAddress = NormalizeAddress(chainName, tokenList[i].TokenAddress)
+		if tokenList[i].MinDeposit == nil {
+			tokenList[i].MinDeposit = big.NewInt(0)
+		}
+		if tokenList[i].HotMinBalance == nil {
+			tokenList[i].HotMinBalance = big.NewInt(0)
+		}
+		if tokenList[i].HotTargetBalance == nil {
+			tokenList[i].HotTargetBalance = big.NewInt(0)
+		}
+	}
 	return db.gorm.Table(tableName).CreateInBatches(&tokenList, len(tokenList)).Error
 }
 
 func (db *tokensDB) TokensInfoByAddress(requestId string, chainName string, address string) (*Tokens, error) {
 	var tokensEntry Tokens
 	tableName := utils.GetTableName("tokens", requestId, chainName)
-	err := db.gorm.Table(tableName).Where("token_address", address).Take(&tokensEntry).Error
+	err := db.gorm.Table(tableName).Where("token_address IN ?", addressLookupForms(chainName, address)).Take(&tokensEntry).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -56,3 +77,71 @@ func (db *tokensDB) TokensInfoByAddress(requestId string, chainName string, addr
 	}
 	return &tokensEntry, nil
 }
+
+// TokenMeta 金额格式化使用的代币符号和精度
+type TokenMeta struct {
+	Symbol   string
+	Decimals uint8
+}
+
+// QueryListedToken 合约地址按链的地址规则匹配，重复登记时取最新的配置；原生代币未登记时也视为已登记，返回的配置为 nil
+func (db *tokensDB) QueryListedToken(requestId string, chainName string, address string) (*Tokens, bool, error) {
+	var tokensEntry Tokens
+	tableName := utils.GetTableName("tokens", requestId, chainName)
+	err := db.gorm.Table(tableName).
+		Where("token_address IN ?", addressLookupForms(chainName, address)).
+		Order("timestamp DESC").
+		Take(&tokensEntry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, IsNativeToken(chainName, address), nil
+		}
+		return nil, false, fmt.Errorf("query listed token failed: %w", err)
+	}
+	return &tokensEntry, true, nil
+}
+
+// QueryLiquidityTokens 配置了热钱包余额下限的代币，重复登记时取最新的配置
+func (db *tokensDB) QueryLiquidityTokens(requestId string, chainName string) ([]*Tokens, error) {
+	var tokenList []*Tokens
+	tableName := utils.GetTableName("tokens", requestId, chainName)
+	if err := db.gorm.Table(tableName).Order("timestamp ASC").Find(&tokenList).Error; err != nil {
+		return nil, fmt.Errorf("query liquidity tokens failed: %w", err)
+	}
+	latest := make(map[string]int)
+	var configured []*Tokens
+	for _, token := range tokenList {
+		key := NormalizeAddress(chainName, token.TokenAddress)
+		if IsNativeToken(chainName, token.TokenAddress) {
+			key = ""
+		}
+		if index, ok := latest[key]; ok {
+			configured[index] = token
+			continue
+		}
+		latest[key] = len(configured)
+		configured = append(configured, token)
+	}
+	result := configured[:0]
+	for _, token := range configured {
+		if token.HotMinBalance != nil && token.HotMinBalance.Sign() > 0 {
+			result = append(result, token)
+		}
+	}
+	return result, nil
+}
+
+// ResolveTokenMeta 主币使用链配置，其他代币使用业务登记的 tokens 表，未登记时返回 nil
+func ResolveTokenMeta(tokens TokensView, requestId string, chainName string, tokenAddress string) (*TokenMeta, error) {
+	if IsNativeToken(chainName, tokenAddress) {
+		return &TokenMeta{
+			Symbol:   GetTokenType(chainName, true).String(),
+			Decimals: GetNativeDecimals(chainName),
+		}, nil
+	}
+	token, err := tokens.TokensInfoByAddress(requestId, chainName, tokenAddress)
+	if err != nil || token == nil {
+		return nil, err
+	}
+	return &TokenMeta{Symbol: token.TokenName, Decimals: token.Decimals}, nil
+}
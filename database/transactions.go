@@ -31,6 +31,7 @@ type Transactions struct {
 
 type TransactionsView interface {
 	QueryTransactionByHash(requestId string, chainName string, hash common.Hash) (*Transactions, error)
+	AddressHasTransactions(requestId string, chainName string, address string) (bool, error)
 }
 
 type TransactionsDB interface {
@@ -58,6 +59,23 @@ func (db *transactionsDB) QueryTransactionByHash(requestId string, chainName str
 	return &transactionEntry, nil
 }
 
+// AddressHasTransactions 地址是否出现在已扫描的交易中
+func (db *transactionsDB) AddressHasTransactions(requestId string, chainName string, address string) (bool, error) {
+	tableName := utils.GetTableName("transactions", requestId, chainName)
+	lookupForms := addressLookupForms(chainName, address)
+	var transactionEntry Transactions
+	result := db.gorm.Table(tableName).
+		Where("from_address IN ? OR to_address IN ?", lookupForms, lookupForms).
+		Take(&transactionEntry)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, result.Error
+	}
+	return true, nil
+}
+
 func (db *transactionsDB) UpdateTransactionsStatus(requestId string, chainName string, blockNumber *big.Int) error {
 	tableName := utils.GetTableName("transactions", requestId, chainName)
 	result := db.gorm.Table(tableName).Where("status = ? and block_number = ?", 0, blockNumber).Updates(map[string]interface{}{"status": gorm.Expr("GREATEST(1)")})
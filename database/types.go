@@ -10,4 +10,10 @@ type TokenBalance struct {
 	TokenAddress string          `json:"to_ken_address"`
 	Balance      *big.Int        `json:"balance"`
 	TxType       TransactionType `json:"tx_type"`
+	TxHash       string          `json:"tx_hash"`
+	BlockNumber  *big.Int        `json:"block_number"`
+	Fee          *big.Int        `json:"fee"`
+	// FromRole/ToRole 区块扫描时双方的地址角色，为空时按交易类型的默认规则记账
+	FromRole AddressType `json:"from_role"`
+	ToRole   AddressType `json:"to_role"`
 }
@@ -0,0 +1,163 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/dapplink-labs/multichain-sync-account/database/utils"
+)
+
+type UtxoStatus string
+
+const (
+	UtxoUnspent UtxoStatus = "unspent"
+	// UtxoLocked 已被待签名交易选中，SpendGuid 为对应的提现或内部交易
+	UtxoLocked UtxoStatus = "locked"
+	UtxoSpent  UtxoStatus = "spent"
+)
+
+// Utxos 业务地址上的未花费输出，扫块时记录，交易广播后标记为已花费
+type Utxos struct {
+	GUID        uuid.UUID  `gorm:"primaryKey" json:"guid"`
+	Address     string     `gorm:"type:varchar;not null" json:"address"`
+	TxHash      string     `gorm:"type:varchar;not null" json:"tx_hash"`
+	Vout        uint32     `gorm:"not null" json:"vout"`
+	Amount      *big.Int   `gorm:"not null;serializer:u256" json:"amount"`
+	BlockNumber *big.Int   `gorm:"not null;serializer:u256" json:"block_number"`
+	Status      UtxoStatus `gorm:"type:varchar(10);not null;default:'unspent'" json:"status"`
+	SpendGuid   string     `gorm:"type:varchar;not null;default:''" json:"spend_guid"`
+	SpentTxHash string     `gorm:"type:varchar;not null;default:''" json:"spent_tx_hash"`
+	Timestamp   uint64     `gorm:"not null;check:timestamp > 0" json:"timestamp"`
+}
+
+type UtxosView interface {
+	QueryUnspentUtxos(requestId string, chainName string, address string) ([]*Utxos, error)
+	QueryUtxosBySpendGuid(requestId string, chainName string, spendGuid string) ([]*Utxos, error)
+}
+
+type UtxosDB interface {
+	UtxosView
+
+	StoreUtxos(requestId string, chainName string, utxoList []*Utxos) error
+	LockUtxos(requestId string, chainName string, spendGuid string, utxoList []*Utxos) error
+	MarkUtxosSpent(requestId string, chainName string, spendGuid string, spentTxHash string) error
+	MarkAddressUtxosSpent(requestId string, chainName string, address string, beforeBlock *big.Int, spentTxHash string) error
+	UnlockUtxos(requestId string, chainName string, spendGuids []string) error
+}
+
+type utxosDB struct {
+	gorm *gorm.DB
+}
+
+func NewUtxosDB(db *gorm.DB) UtxosDB {
+	return &utxosDB{gorm: db}
+}
+
+// StoreUtxos 区块回扫时同一个输出可能重复出现，冲突时不更新
+func (db *utxosDB) StoreUtxos(requestId string, chainName string, utxoList []*Utxos) error {
+	if len(utxoList) == 0 {
+		return nil
+	}
+	tableName := utils.GetTableName("utxos", requestId, chainName)
+	for _, utxo := range utxoList {
+		utxo.Address = NormalizeAddress(chainName, utxo.Address)
+	}
+	return db.gorm.Table(tableName).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "vout"}},
+			DoNothing: true,
+		}).
+		CreateInBatches(utxoList, len(utxoList)).Error
+}
+
+func (db *utxosDB) QueryUnspentUtxos(requestId string, chainName string, address string) ([]*Utxos, error) {
+	var utxoList []*Utxos
+	tableName := utils.GetTableName("utxos", requestId, chainName)
+	err := db.gorm.Table(tableName).
+		Where("address IN ? AND status = ?", addressLookupForms(chainName, address), UtxoUnspent).
+		Order("block_number ASC").
+		Find(&utxoList).Error
+	if err != nil {
+		return nil, fmt.Errorf("query unspent utxos failed: %w", err)
+	}
+	return utxoList, nil
+}
+
+// QueryUtxosBySpendGuid 按输出点排序，保证重建的待签名交易输入顺序一致
+func (db *utxosDB) QueryUtxosBySpendGuid(requestId string, chainName string, spendGuid string) ([]*Utxos, error) {
+	var utxoList []*Utxos
+	tableName := utils.GetTableName("utxos", requestId, chainName)
+	err := db.gorm.Table(tableName).
+		Where("spend_guid = ?", spendGuid).
+		Order("tx_hash ASC, vout ASC").
+		Find(&utxoList).Error
+	if err != nil {
+		return nil, fmt.Errorf("query utxos by spend guid failed: %w", err)
+	}
+	return utxoList, nil
+}
+
+// LockUtxos 只锁定仍未花费的输出，锁定数量不一致说明输出已被其他交易选中
+func (db *utxosDB) LockUtxos(requestId string, chainName string, spendGuid string, utxoList []*Utxos) error {
+	if len(utxoList) == 0 {
+		return nil
+	}
+	guids := make([]uuid.UUID, 0, len(utxoList))
+	for _, utxo := range utxoList {
+		guids = append(guids, utxo.GUID)
+	}
+	tableName := utils.GetTableName("utxos", requestId, chainName)
+	result := db.gorm.Table(tableName).
+		Where("guid IN ? AND status = ?", guids, UtxoUnspent).
+		Updates(map[string]interface{}{"status": UtxoLocked, "spend_guid": spendGuid})
+	if result.Error != nil {
+		return fmt.Errorf("lock utxos failed: %w", result.Error)
+	}
+	if result.RowsAffected != int64(len(utxoList)) {
+		return fmt.Errorf("lock utxos failed: %d of %d outputs are no longer unspent", int64(len(utxoList))-result.RowsAffected, len(utxoList))
+	}
+	return nil
+}
+
+func (db *utxosDB) MarkUtxosSpent(requestId string, chainName string, spendGuid string, spentTxHash string) error {
+	tableName := utils.GetTableName("utxos", requestId, chainName)
+	result := db.gorm.Table(tableName).
+		Where("spend_guid = ? AND status = ?", spendGuid, UtxoLocked).
+		Updates(map[string]interface{}{"status": UtxoSpent, "spent_tx_hash": spentTxHash})
+	if result.Error != nil {
+		return fmt.Errorf("mark utxos spent failed: %w", result.Error)
+	}
+	return nil
+}
+
+// MarkAddressUtxosSpent 扫描到不是系统创建的交易花费了地址上的输出时，交易数据不包含输入的输出点，
+// 地址上 beforeBlock 之前的输出全部标记为已花费，同一区块中的找零不受影响
+func (db *utxosDB) MarkAddressUtxosSpent(requestId string, chainName string, address string, beforeBlock *big.Int, spentTxHash string) error {
+	tableName := utils.GetTableName("utxos", requestId, chainName)
+	result := db.gorm.Table(tableName).
+		Where("address IN ? AND status IN ? AND block_number < ?", addressLookupForms(chainName, address), []UtxoStatus{UtxoUnspent, UtxoLocked}, beforeBlock.String()).
+		Updates(map[string]interface{}{"status": UtxoSpent, "spent_tx_hash": spentTxHash})
+	if result.Error != nil {
+		return fmt.Errorf("mark address utxos spent failed: %w", result.Error)
+	}
+	return nil
+}
+
+// UnlockUtxos 交易过期时释放其锁定的输出，已花费的输出不受影响
+func (db *utxosDB) UnlockUtxos(requestId string, chainName string, spendGuids []string) error {
+	if len(spendGuids) == 0 {
+		return nil
+	}
+	tableName := utils.GetTableName("utxos", requestId, chainName)
+	result := db.gorm.Table(tableName).
+		Where("spend_guid IN ? AND status = ?", spendGuids, UtxoLocked).
+		Updates(map[string]interface{}{"status": UtxoUnspent, "spend_guid": ""})
+	if result.Error != nil {
+		return fmt.Errorf("unlock utxos failed: %w", result.Error)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"math/big"
@@ -9,6 +10,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/dapplink-labs/multichain-sync-account/database/utils"
 )
@@ -34,6 +36,10 @@ type Withdraws struct {
 	GasLimit             uint64 `json:"gas_limit"`
 	MaxFeePerGas         string `json:"max_fee_per_gas"`
 	MaxPriorityFeePerGas string `json:"max_priority_fee_per_gas"`
+	// GasPrice legacy 交易的 gasPrice，此时 MaxFeePerGas 和 MaxPriorityFeePerGas 为空
+	GasPrice string `json:"gas_price" gorm:"column:gas_price"`
+	// FeeStrategy 创建交易时使用的手续费策略档位，被上限截断时带 capped 后缀
+	FeeStrategy string `json:"fee_strategy" gorm:"column:fee_strategy"`
 
 	// Token 相关信息
 	TokenType    TokenType `json:"token_type" gorm:"column:token_type"` // ETH, ERC20, ERC721, ERC1155
@@ -43,6 +49,30 @@ type Withdraws struct {
 
 	// 交易签名
 	TxSignHex string `json:"tx_sign_hex" gorm:"column:tx_sign_hex"`
+	// UnsignedTx 创建时交给签名方的 base64 交易数据，签名时原样使用，SignHash 为 chain-account 返回的待签名哈希
+	UnsignedTx string `json:"unsigned_tx" gorm:"column:unsigned_tx"`
+	SignHash   string `json:"sign_hash" gorm:"column:sign_hash"`
+
+	// Memo 写入待签名交易的 memo / destination tag
+	Memo string `json:"memo" gorm:"column:memo"`
+	// UTXO 链的找零地址和找零金额，没有找零时 ChangeAmount 为 nil
+	ChangeAddress string   `json:"change_address" gorm:"column:change_address"`
+	ChangeAmount  *big.Int `json:"change_amount" gorm:"serializer:u256;column:change_amount"`
+
+	// 批量提现：BatchIndex 为请求中的序号，MultiSend 的条目共用一笔链上交易，交易 id 为 BatchId，
+	// 逐笔发送的条目各自一笔交易，Nonce 为创建时分配的连续 nonce
+	BatchId    string  `json:"batch_id" gorm:"column:batch_id"`
+	BatchIndex int     `json:"batch_index" gorm:"column:batch_index"`
+	MultiSend  bool    `json:"multi_send" gorm:"column:multi_send"`
+	Nonce      *uint64 `json:"nonce" gorm:"column:nonce"`
+}
+
+// SpendGuid UTXO 链锁定输出时使用的交易 id
+func (w *Withdraws) SpendGuid() string {
+	if w.MultiSend {
+		return w.BatchId
+	}
+	return w.GUID.String()
 }
 
 type WithdrawsView interface {
@@ -50,18 +80,24 @@ type WithdrawsView interface {
 	QueryWithdrawsByHash(requestId string, chainName string, txHash common.Hash) (*Withdraws, error)
 	QueryWithdrawsById(requestId string, chainName string, guid string) (*Withdraws, error)
 	UnSendWithdrawsList(requestId string, chainName string) ([]*Withdraws, error)
+	QueryWithdrawsByBatchId(requestId string, chainName string, batchId string) ([]*Withdraws, error)
+	QueryPendingWithdraws(requestId string, chainName string) ([]*Withdraws, error)
 }
 
 type WithdrawsDB interface {
 	WithdrawsView
 
 	StoreWithdraw(requestId string, chainName string, withdraw *Withdraws) error
+	StoreWithdraws(requestId string, chainName string, withdrawList []*Withdraws) error
+	UpdateWithdrawsByBatchId(requestId string, chainName string, batchId string, signedTx string, status TxStatus) error
 	UpdateWithdrawByTxHash(requestId string, chainName string, txHash common.Hash, signedTx string, status TxStatus) error
 	UpdateWithdrawById(requestId string, chainName string, guid string, signedTx string, status TxStatus) error
 	UpdateWithdrawStatusById(requestId string, chainName string, status TxStatus, withdrawsList []*Withdraws) error
 	UpdateWithdrawStatusByTxHash(requestId string, chainName string, status TxStatus, withdrawsList []*Withdraws) error
 	UpdateWithdrawListByTxHash(requestId string, chainName string, withdrawsList []*Withdraws) error
 	UpdateWithdrawListById(requestId string, chainName string, withdrawsList []*Withdraws) error
+	ExpireWithdraws(requestId string, chainName string, createdBefore uint64) ([]*Withdraws, error)
+	LockPendingNonce(requestId string, chainName string, fromAddress string) (*uint64, error)
 }
 
 type withdrawsDB struct {
@@ -96,6 +132,42 @@ func (db *withdrawsDB) UnSendWithdrawsList(requestId string, chainName string) (
 	return withdrawsList, nil
 }
 
+// QueryPendingWithdraws 已创建但链上尚未确认的提现，用于统计各出款地址的在途金额和排队交易数；
+// 超过 tx-expiry 仍未签名广播的记录由 Expiry worker 标记为 expired，不再计入
+func (db *withdrawsDB) QueryPendingWithdraws(requestId string, chainName string) ([]*Withdraws, error) {
+	tableName := utils.GetTableName("withdraws", requestId, chainName)
+	var withdrawsList []*Withdraws
+	err := db.gorm.Table(tableName).
+		Where("status IN ?", []TxStatus{TxStatusCreateUnsigned, TxStatusSigned, TxStatusBroadcasted}).
+		Find(&withdrawsList).Error
+	if err != nil {
+		return nil, fmt.Errorf("query pending withdraws failed: %w", err)
+	}
+	return withdrawsList, nil
+}
+
+// LockPendingNonce 必须在事务中调用，对出款地址加事务级锁后返回在途提现已分配的最大 nonce，没有时返回 nil，
+// 锁在事务提交前阻止其他请求为同一地址分配 nonce，提现和内部交易共用这把锁
+func (db *withdrawsDB) LockPendingNonce(requestId string, chainName string, fromAddress string) (*uint64, error) {
+	tableName := utils.GetTableName("withdraws", requestId, chainName)
+	if err := db.gorm.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", tableName+"/nonce/"+fromAddress).Error; err != nil {
+		return nil, fmt.Errorf("lock withdraw nonce failed: %w", err)
+	}
+	var maxNonce sql.NullInt64
+	err := db.gorm.Table(tableName).
+		Select("MAX(nonce)").
+		Where("from_address = ? AND nonce IS NOT NULL AND status IN ?", fromAddress, []TxStatus{TxStatusCreateUnsigned, TxStatusSigned, TxStatusBroadcasted}).
+		Scan(&maxNonce).Error
+	if err != nil {
+		return nil, fmt.Errorf("query pending nonce failed: %w", err)
+	}
+	if !maxNonce.Valid {
+		return nil, nil
+	}
+	nonce := uint64(maxNonce.Int64)
+	return &nonce, nil
+}
+
 func (db *withdrawsDB) QueryWithdrawsById(requestId string, chainName string, guid string) (*Withdraws, error) {
 	tableName := utils.GetTableName("withdraws", requestId, chainName)
 	var withdrawsEntity Withdraws
@@ -109,6 +181,20 @@ func (db *withdrawsDB) QueryWithdrawsById(requestId string, chainName string, gu
 	return &withdrawsEntity, nil
 }
 
+// QueryWithdrawsByBatchId 按请求中的序号返回批量提现的全部条目
+func (db *withdrawsDB) QueryWithdrawsByBatchId(requestId string, chainName string, batchId string) ([]*Withdraws, error) {
+	tableName := utils.GetTableName("withdraws", requestId, chainName)
+	var withdrawsList []*Withdraws
+	err := db.gorm.Table(tableName).
+		Where("batch_id = ?", batchId).
+		Order("batch_index ASC").
+		Find(&withdrawsList).Error
+	if err != nil {
+		return nil, fmt.Errorf("query batch withdraws failed: %w", err)
+	}
+	return withdrawsList, nil
+}
+
 func (db *withdrawsDB) QueryWithdrawsByHash(requestId string, chainName string, txHash common.Hash) (*Withdraws, error) {
 	tableName := utils.GetTableName("withdraws", requestId, chainName)
 	var withdrawsEntity Withdraws
@@ -193,6 +279,36 @@ func (db *withdrawsDB) StoreWithdraw(requestId string, chainName string, withdra
 	return db.gorm.Table(tableName).Create(withdraw).Error
 }
 
+func (db *withdrawsDB) StoreWithdraws(requestId string, chainName string, withdrawList []*Withdraws) error {
+	if len(withdrawList) == 0 {
+		return nil
+	}
+	tableName := utils.GetTableName("withdraws", requestId, chainName)
+	return db.gorm.Table(tableName).CreateInBatches(withdrawList, len(withdrawList)).Error
+}
+
+// UpdateWithdrawsByBatchId 多收款地址交易签名后，批次内所有条目共用同一笔签名交易
+func (db *withdrawsDB) UpdateWithdrawsByBatchId(requestId string, chainName string, batchId string, signedTx string, status TxStatus) error {
+	tableName := utils.GetTableName("withdraws", requestId, chainName)
+	updates := map[string]interface{}{
+		"status": status,
+	}
+	if signedTx != "" {
+		updates["tx_sign_hex"] = signedTx
+	}
+	result := db.gorm.Table(tableName).
+		Where("batch_id = ? AND multi_send = ?", batchId, true).
+		Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("update batch withdraws failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("batch withdraw not found: %s", batchId)
+	}
+	log.Info("Update batch withdraws success", "requestId", requestId, "batchId", batchId, "count", result.RowsAffected, "status", status)
+	return nil
+}
+
 func (db *withdrawsDB) UpdateWithdrawStatusById(requestId string, chainName string, status TxStatus, withdrawsList []*Withdraws) error {
 	if len(withdrawsList) == 0 {
 		return nil
@@ -375,3 +491,27 @@ func (db *withdrawsDB) CheckWithdrawExistsById(tableName string, id string) erro
 
 	return nil
 }
+
+// ExpireWithdraws 将 createdBefore 之前创建、仍未签名或未广播成功的提现标记为 expired，返回被标记的提现
+func (db *withdrawsDB) ExpireWithdraws(requestId string, chainName string, createdBefore uint64) ([]*Withdraws, error) {
+	tableName := utils.GetTableName("withdraws", requestId, chainName)
+	var expired []*Withdraws
+	err := db.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Table(tableName).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("status IN ? AND timestamp < ?", []TxStatus{TxStatusCreateUnsigned, TxStatusSigned}, createdBefore).
+			Find(&expired).Error
+		if err != nil || len(expired) == 0 {
+			return err
+		}
+		guids := make([]uuid.UUID, 0, len(expired))
+		for _, withdraw := range expired {
+			guids = append(guids, withdraw.GUID)
+		}
+		return tx.Table(tableName).Where("guid IN ?", guids).Update("status", TxStatusExpired).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("expire withdraws failed: %w", err)
+	}
+	return expired, nil
+}
@@ -27,25 +27,25 @@ func TestQueryNotifyWithdraws(t *testing.T) {
 		BlockHash:            common.HexToHash("0x1"),
 		BlockNumber:          big.NewInt(1),
 		TxHash:               common.HexToHash("0x2"),
-		FromAddress:          common.HexToAddress("0x3"),
-		ToAddress:            common.HexToAddress("0x4"),
+		FromAddress:          common.HexToAddress("0x3").String(),
+		ToAddress:            common.HexToAddress("0x4").String(),
 		Amount:               big.NewInt(1000),
 		GasLimit:             21000,
 		MaxFeePerGas:         "100",
 		MaxPriorityFeePerGas: "2",
-		TokenType:            TokenTypeERC20,
-		TokenAddress:         common.HexToAddress("0x5"),
+		TokenType:            TokenType("ERC20"),
+		TokenAddress:         common.HexToAddress("0x5").String(),
 		TokenId:              "1",
 		TokenMeta:            "meta",
 		TxSignHex:            "0x6",
 	}
 
-	err := withdrawsDB.StoreWithdraw(requestId, withdraw)
+	err := withdrawsDB.StoreWithdraw(requestId, CurrentChain, withdraw)
 	if err != nil {
 		t.Fatalf("failed to store withdraw: %v", err)
 	}
 
-	notifyWithdraws, err := withdrawsDB.QueryNotifyWithdraws(requestId)
+	notifyWithdraws, err := withdrawsDB.QueryNotifyWithdraws(requestId, CurrentChain)
 	if err != nil {
 		t.Fatalf("failed to query notify withdraws: %v", err)
 	}
@@ -68,25 +68,25 @@ func TestUnSendWithdrawsList(t *testing.T) {
 		BlockHash:            common.HexToHash("0x1"),
 		BlockNumber:          big.NewInt(1),
 		TxHash:               common.HexToHash("0x2"),
-		FromAddress:          common.HexToAddress("0x3"),
-		ToAddress:            common.HexToAddress("0x4"),
+		FromAddress:          common.HexToAddress("0x3").String(),
+		ToAddress:            common.HexToAddress("0x4").String(),
 		Amount:               big.NewInt(1000),
 		GasLimit:             21000,
 		MaxFeePerGas:         "100",
 		MaxPriorityFeePerGas: "2",
-		TokenType:            TokenTypeERC20,
-		TokenAddress:         common.HexToAddress("0x5"),
+		TokenType:            TokenType("ERC20"),
+		TokenAddress:         common.HexToAddress("0x5").String(),
 		TokenId:              "1",
 		TokenMeta:            "meta",
 		TxSignHex:            "0x6",
 	}
 
-	err := withdrawsDB.StoreWithdraw(requestId, withdraw)
+	err := withdrawsDB.StoreWithdraw(requestId, CurrentChain, withdraw)
 	if err != nil {
 		t.Fatalf("failed to store withdraw: %v", err)
 	}
 
-	unSendWithdraws, err := withdrawsDB.UnSendWithdrawsList(requestId)
+	unSendWithdraws, err := withdrawsDB.UnSendWithdrawsList(requestId, CurrentChain)
 	if err != nil {
 		t.Fatalf("failed to query unsend withdraws list: %v", err)
 	}
@@ -109,25 +109,25 @@ func TestQueryWithdrawsByHash(t *testing.T) {
 		BlockHash:            common.HexToHash("0x1"),
 		BlockNumber:          big.NewInt(1),
 		TxHash:               common.HexToHash("0x2"),
-		FromAddress:          common.HexToAddress("0x3"),
-		ToAddress:            common.HexToAddress("0x4"),
+		FromAddress:          common.HexToAddress("0x3").String(),
+		ToAddress:            common.HexToAddress("0x4").String(),
 		Amount:               big.NewInt(1000),
 		GasLimit:             21000,
 		MaxFeePerGas:         "100",
 		MaxPriorityFeePerGas: "2",
-		TokenType:            TokenTypeERC20,
-		TokenAddress:         common.HexToAddress("0x5"),
+		TokenType:            TokenType("ERC20"),
+		TokenAddress:         common.HexToAddress("0x5").String(),
 		TokenId:              "1",
 		TokenMeta:            "meta",
 		TxSignHex:            "0x6",
 	}
 
-	err := withdrawsDB.StoreWithdraw(requestId, withdraw)
+	err := withdrawsDB.StoreWithdraw(requestId, CurrentChain, withdraw)
 	if err != nil {
 		t.Fatalf("failed to store withdraw: %v", err)
 	}
 
-	retrievedWithdraw, err := withdrawsDB.QueryWithdrawsByHash(requestId, withdraw.TxHash)
+	retrievedWithdraw, err := withdrawsDB.QueryWithdrawsByHash(requestId, CurrentChain, withdraw.TxHash)
 	if err != nil {
 		t.Fatalf("failed to query withdraw by hash: %v", err)
 	}
@@ -150,23 +150,23 @@ func TestUpdateWithdrawTx(t *testing.T) {
 		BlockHash:            common.HexToHash("0x1"),
 		BlockNumber:          big.NewInt(1),
 		TxHash:               common.HexToHash("0x2"),
-		FromAddress:          common.HexToAddress("0x3"),
-		ToAddress:            common.HexToAddress("0x4"),
+		FromAddress:          common.HexToAddress("0x3").String(),
+		ToAddress:            common.HexToAddress("0x4").String(),
 		Amount:               big.NewInt(1000),
 		GasLimit:             21000,
 		MaxFeePerGas:         "100",
 		MaxPriorityFeePerGas: "2",
-		TokenType:            TokenTypeERC20,
-		TokenAddress:         common.HexToAddress("0x5"),
+		TokenType:            TokenType("ERC20"),
+		TokenAddress:         common.HexToAddress("0x5").String(),
 		TokenId:              "1",
 		TokenMeta:            "meta",
 		TxSignHex:            "0x6",
 	}
-	err := withdrawsDB.StoreWithdraw(requestId, withdraw)
+	err := withdrawsDB.StoreWithdraw(requestId, CurrentChain, withdraw)
 	if err != nil {
 		t.Fatalf("failed to store withdraw: %v", err)
 	}
-	updatedWithdraw, err := withdrawsDB.QueryWithdrawsByHash(requestId, withdraw.TxHash)
+	updatedWithdraw, err := withdrawsDB.QueryWithdrawsByHash(requestId, CurrentChain, withdraw.TxHash)
 	if err != nil {
 		t.Fatalf("failed to query updated withdraw: %v", err)
 	}
@@ -174,12 +174,12 @@ func TestUpdateWithdrawTx(t *testing.T) {
 
 	newStatus := TxStatusSigned
 	signedTx := "0x7"
-	err = withdrawsDB.UpdateWithdrawByTxHash(requestId, withdraw.TxHash, signedTx, newStatus)
+	err = withdrawsDB.UpdateWithdrawByTxHash(requestId, CurrentChain, withdraw.TxHash, signedTx, newStatus)
 	if err != nil {
 		t.Fatalf("failed to update withdraw tx: %v", err)
 	}
 
-	updatedWithdrawV2, err := withdrawsDB.QueryWithdrawsByHash(requestId, withdraw.TxHash)
+	updatedWithdrawV2, err := withdrawsDB.QueryWithdrawsByHash(requestId, CurrentChain, withdraw.TxHash)
 	if err != nil {
 		t.Fatalf("failed to query updated withdraw: %v", err)
 	}
@@ -202,24 +202,24 @@ func TestUpdateWithdrawStatus(t *testing.T) {
 		BlockHash:            common.HexToHash("0x1"),
 		BlockNumber:          big.NewInt(1),
 		TxHash:               common.HexToHash("0x2"),
-		FromAddress:          common.HexToAddress("0x3"),
-		ToAddress:            common.HexToAddress("0x4"),
+		FromAddress:          common.HexToAddress("0x3").String(),
+		ToAddress:            common.HexToAddress("0x4").String(),
 		Amount:               big.NewInt(1000),
 		GasLimit:             21000,
 		MaxFeePerGas:         "100",
 		MaxPriorityFeePerGas: "2",
-		TokenType:            TokenTypeERC20,
-		TokenAddress:         common.HexToAddress("0x5"),
+		TokenType:            TokenType("ERC20"),
+		TokenAddress:         common.HexToAddress("0x5").String(),
 		TokenId:              "1",
 		TokenMeta:            "meta",
 		TxSignHex:            "0x6",
 	}
-	err := withdrawsDB.StoreWithdraw(requestId, withdraw)
+	err := withdrawsDB.StoreWithdraw(requestId, CurrentChain, withdraw)
 	if err != nil {
 		t.Fatalf("failed to store withdraw: %v", err)
 	}
 
-	updatedWithdraw, err := withdrawsDB.QueryWithdrawsByHash(requestId, withdraw.TxHash)
+	updatedWithdraw, err := withdrawsDB.QueryWithdrawsByHash(requestId, CurrentChain, withdraw.TxHash)
 	if err != nil {
 		t.Fatalf("failed to query updated withdraw: %v", err)
 	}
@@ -227,12 +227,12 @@ func TestUpdateWithdrawStatus(t *testing.T) {
 
 	newStatus := TxStatusSigned
 	signedTx := "0x7"
-	err = withdrawsDB.UpdateWithdrawByTxHash(requestId, withdraw.TxHash, signedTx, newStatus)
+	err = withdrawsDB.UpdateWithdrawByTxHash(requestId, CurrentChain, withdraw.TxHash, signedTx, newStatus)
 	if err != nil {
 		t.Fatalf("failed to update withdraw tx: %v", err)
 	}
 
-	updatedWithdrawV2, err := withdrawsDB.QueryWithdrawsByHash(requestId, withdraw.TxHash)
+	updatedWithdrawV2, err := withdrawsDB.QueryWithdrawsByHash(requestId, CurrentChain, withdraw.TxHash)
 	if err != nil {
 		t.Fatalf("failed to query updated withdraw: %v", err)
 	}
@@ -257,14 +257,14 @@ func TestUpdateWithdrawList(t *testing.T) {
 			BlockHash:            common.HexToHash("0x1"),
 			BlockNumber:          big.NewInt(1),
 			TxHash:               common.HexToHash("0x2"),
-			FromAddress:          common.HexToAddress("0x3"),
-			ToAddress:            common.HexToAddress("0x4"),
+			FromAddress:          common.HexToAddress("0x3").String(),
+			ToAddress:            common.HexToAddress("0x4").String(),
 			Amount:               big.NewInt(1000),
 			GasLimit:             21000,
 			MaxFeePerGas:         "100",
 			MaxPriorityFeePerGas: "2",
-			TokenType:            TokenTypeERC20,
-			TokenAddress:         common.HexToAddress("0x5"),
+			TokenType:            TokenType("ERC20"),
+			TokenAddress:         common.HexToAddress("0x5").String(),
 			TokenId:              "1",
 			TokenMeta:            "meta",
 			TxSignHex:            "0x6",
@@ -276,14 +276,14 @@ func TestUpdateWithdrawList(t *testing.T) {
 			BlockHash:            common.HexToHash("0x1"),
 			BlockNumber:          big.NewInt(2),
 			TxHash:               common.HexToHash("0x3"),
-			FromAddress:          common.HexToAddress("0x3"),
-			ToAddress:            common.HexToAddress("0x4"),
+			FromAddress:          common.HexToAddress("0x3").String(),
+			ToAddress:            common.HexToAddress("0x4").String(),
 			Amount:               big.NewInt(2000),
 			GasLimit:             21000,
 			MaxFeePerGas:         "100",
 			MaxPriorityFeePerGas: "2",
-			TokenType:            TokenTypeERC20,
-			TokenAddress:         common.HexToAddress("0x5"),
+			TokenType:            TokenType("ERC20"),
+			TokenAddress:         common.HexToAddress("0x5").String(),
 			TokenId:              "2",
 			TokenMeta:            "meta",
 			TxSignHex:            "0x7",
@@ -292,7 +292,7 @@ func TestUpdateWithdrawList(t *testing.T) {
 
 	// Store initial withdraws
 	for _, withdraw := range withdrawsList {
-		err := withdrawsDB.StoreWithdraw(requestId, withdraw)
+		err := withdrawsDB.StoreWithdraw(requestId, CurrentChain, withdraw)
 		if err != nil {
 			t.Fatalf("failed to store withdraw: %v", err)
 		}
@@ -300,7 +300,7 @@ func TestUpdateWithdrawList(t *testing.T) {
 
 	// Verify updates
 	for _, withdraw := range withdrawsList {
-		updatedWithdraw, err := withdrawsDB.QueryWithdrawsByHash(requestId, withdraw.TxHash)
+		updatedWithdraw, err := withdrawsDB.QueryWithdrawsByHash(requestId, CurrentChain, withdraw.TxHash)
 		if err != nil {
 			t.Fatalf("failed to query updated withdraw: %v", err)
 		}
@@ -313,14 +313,14 @@ func TestUpdateWithdrawList(t *testing.T) {
 		withdraw.Status = newStatus
 	}
 
-	err := withdrawsDB.UpdateWithdrawListByTxHash(requestId, withdrawsList)
+	err := withdrawsDB.UpdateWithdrawListByTxHash(requestId, CurrentChain, withdrawsList)
 	if err != nil {
 		t.Fatalf("failed to update withdraw list: %v", err)
 	}
 
 	// Verify updates
 	for _, withdraw := range withdrawsList {
-		updatedWithdraw, err := withdrawsDB.QueryWithdrawsByHash(requestId, withdraw.TxHash)
+		updatedWithdraw, err := withdrawsDB.QueryWithdrawsByHash(requestId, CurrentChain, withdraw.TxHash)
 		if err != nil {
 			t.Fatalf("failed to query updated withdraw: %v", err)
 		}
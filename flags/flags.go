@@ -19,6 +19,22 @@ var (
 		Usage:   "path for database migrations",
 		EnvVars: prefixEnvVars("MIGRATIONS_DIR"),
 	}
+	ChainsConfigFlag = &cli.StringFlag{
+		Name:    "chains-config",
+		Usage:   "path of the JSON chain registry merged over the built-in chains",
+		EnvVars: prefixEnvVars("CHAINS_CONFIG"),
+	}
+	FeeStrategyConfigFlag = &cli.StringFlag{
+		Name:    "fee-strategy-config",
+		Usage:   "path of the JSON per-chain / per-token fee strategies",
+		EnvVars: prefixEnvVars("FEE_STRATEGY_CONFIG"),
+	}
+	ArchiveDirFlag = &cli.StringFlag{
+		Name:    "archive-dir",
+		Value:   "./archive",
+		Usage:   "path for archived business tables",
+		EnvVars: prefixEnvVars("ARCHIVE_DIR"),
+	}
 
 	ChainIdFlag = &cli.StringFlag{
 		Name:     "chain-id",
@@ -65,6 +81,116 @@ var (
 		EnvVars: prefixEnvVars("WORKER_INTERVAL"),
 		Value:   time.Second * 5,
 	}
+	TxExpiryFlag = &cli.DurationFlag{
+		Name:    "tx-expiry",
+		Usage:   "Expire withdraws and internal transactions not signed or broadcast within this duration and release their locked utxos",
+		EnvVars: prefixEnvVars("TX_EXPIRY"),
+		Value:   time.Hour * 24,
+	}
+	ReconcileEnableFlag = &cli.BoolFlag{
+		Name:    "reconcile-enable",
+		Usage:   "Enable on-chain balance reconciliation worker",
+		EnvVars: prefixEnvVars("RECONCILE_ENABLE"),
+	}
+	ReconcileIntervalFlag = &cli.DurationFlag{
+		Name:    "reconcile-interval",
+		Usage:   "The interval of on-chain balance reconciliation",
+		EnvVars: prefixEnvVars("RECONCILE_INTERVAL"),
+		Value:   time.Minute * 10,
+	}
+	ReconcileAutoCorrectFlag = &cli.BoolFlag{
+		Name:    "reconcile-auto-correct",
+		Usage:   "Overwrite stored balance with on-chain balance when the difference is within tolerance",
+		EnvVars: prefixEnvVars("RECONCILE_AUTO_CORRECT"),
+	}
+	ReconcileToleranceFlag = &cli.StringFlag{
+		Name:    "reconcile-tolerance",
+		Usage:   "Max balance difference in smallest unit that can be auto corrected",
+		EnvVars: prefixEnvVars("RECONCILE_TOLERANCE"),
+		Value:   "0",
+	}
+	ReconcileNotifyThresholdFlag = &cli.StringFlag{
+		Name:    "reconcile-notify-threshold",
+		Usage:   "Min balance difference in smallest unit that is notified to business",
+		EnvVars: prefixEnvVars("RECONCILE_NOTIFY_THRESHOLD"),
+		Value:   "1",
+	}
+	AlertEnableFlag = &cli.BoolFlag{
+		Name:    "alert-enable",
+		Usage:   "Enable alerting on sync lag, rpc outage, broadcast failures, notify backlog and balance problems",
+		EnvVars: prefixEnvVars("ALERT_ENABLE"),
+	}
+	AlertSyncLagBlocksFlag = &cli.Uint64Flag{
+		Name:    "alert-sync-lag-blocks",
+		Usage:   "Alert when sync falls behind chain head by more than this many blocks",
+		EnvVars: prefixEnvVars("ALERT_SYNC_LAG_BLOCKS"),
+		Value:   100,
+	}
+	AlertBroadcastFailureRateFlag = &cli.Float64Flag{
+		Name:    "alert-broadcast-failure-rate",
+		Usage:   "Alert when the failure rate of recent broadcasts exceeds this ratio",
+		EnvVars: prefixEnvVars("ALERT_BROADCAST_FAILURE_RATE"),
+		Value:   0.5,
+	}
+	AlertBroadcastWindowFlag = &cli.IntFlag{
+		Name:    "alert-broadcast-window",
+		Usage:   "Number of recent broadcasts used to compute the failure rate",
+		EnvVars: prefixEnvVars("ALERT_BROADCAST_WINDOW"),
+		Value:   20,
+	}
+	AlertNotifyBacklogAgeFlag = &cli.DurationFlag{
+		Name:    "alert-notify-backlog-age",
+		Usage:   "Alert when the oldest pending notification is older than this",
+		EnvVars: prefixEnvVars("ALERT_NOTIFY_BACKLOG_AGE"),
+		Value:   time.Minute * 10,
+	}
+	AlertCoolDownFlag = &cli.DurationFlag{
+		Name:    "alert-cool-down",
+		Usage:   "Min interval between two sends of the same firing alert",
+		EnvVars: prefixEnvVars("ALERT_COOL_DOWN"),
+		Value:   time.Minute * 30,
+	}
+	AlertWebhookUrlFlag = &cli.StringFlag{
+		Name:    "alert-webhook-url",
+		Usage:   "Generic webhook receiving alerts as json",
+		EnvVars: prefixEnvVars("ALERT_WEBHOOK_URL"),
+	}
+	AlertSlackWebhookUrlFlag = &cli.StringFlag{
+		Name:    "alert-slack-webhook-url",
+		Usage:   "Slack compatible incoming webhook receiving alerts",
+		EnvVars: prefixEnvVars("ALERT_SLACK_WEBHOOK_URL"),
+	}
+	AlertSmtpHostFlag = &cli.StringFlag{
+		Name:    "alert-smtp-host",
+		Usage:   "The host of the smtp server sending alert mails",
+		EnvVars: prefixEnvVars("ALERT_SMTP_HOST"),
+	}
+	AlertSmtpPortFlag = &cli.IntFlag{
+		Name:    "alert-smtp-port",
+		Usage:   "The port of the smtp server sending alert mails",
+		EnvVars: prefixEnvVars("ALERT_SMTP_PORT"),
+		Value:   25,
+	}
+	AlertSmtpUserFlag = &cli.StringFlag{
+		Name:    "alert-smtp-user",
+		Usage:   "The user of the smtp server, empty to skip authentication",
+		EnvVars: prefixEnvVars("ALERT_SMTP_USER"),
+	}
+	AlertSmtpPasswordFlag = &cli.StringFlag{
+		Name:    "alert-smtp-password",
+		Usage:   "The password of the smtp server",
+		EnvVars: prefixEnvVars("ALERT_SMTP_PASSWORD"),
+	}
+	AlertSmtpFromFlag = &cli.StringFlag{
+		Name:    "alert-smtp-from",
+		Usage:   "The sender of alert mails",
+		EnvVars: prefixEnvVars("ALERT_SMTP_FROM"),
+	}
+	AlertSmtpToFlag = &cli.StringSliceFlag{
+		Name:    "alert-smtp-to",
+		Usage:   "The recipients of alert mails",
+		EnvVars: prefixEnvVars("ALERT_SMTP_TO"),
+	}
 	BlocksStepFlag = &cli.UintFlag{
 		Name:    "blocks-step",
 		Usage:   "Scanner blocks step",
@@ -93,6 +219,66 @@ var (
 		Required: true,
 	}
 
+	// RpcTLSEnableFlag rpc tls flags
+	RpcTLSEnableFlag = &cli.BoolFlag{
+		Name:    "rpc-tls-enable",
+		Usage:   "Enable tls for the rpc server",
+		EnvVars: prefixEnvVars("RPC_TLS_ENABLE"),
+	}
+	RpcTLSCertFlag = &cli.StringFlag{
+		Name:    "rpc-tls-cert",
+		Usage:   "The certificate file of the rpc server",
+		EnvVars: prefixEnvVars("RPC_TLS_CERT"),
+	}
+	RpcTLSKeyFlag = &cli.StringFlag{
+		Name:    "rpc-tls-key",
+		Usage:   "The private key file of the rpc server",
+		EnvVars: prefixEnvVars("RPC_TLS_KEY"),
+	}
+	RpcTLSClientCAFlag = &cli.StringFlag{
+		Name:    "rpc-tls-client-ca",
+		Usage:   "The ca file used to verify rpc client certificates",
+		EnvVars: prefixEnvVars("RPC_TLS_CLIENT_CA"),
+	}
+	RpcTLSClientAuthFlag = &cli.StringFlag{
+		Name:    "rpc-tls-client-auth",
+		Usage:   "Client certificate verification mode: none, request or require",
+		EnvVars: prefixEnvVars("RPC_TLS_CLIENT_AUTH"),
+		Value:   "none",
+	}
+	RpcTLSAllowedClientsFlag = &cli.StringSliceFlag{
+		Name:    "rpc-tls-allowed-clients",
+		Usage:   "Client certificate names (CN, DNS or URI SAN) allowed to call the rpc server, requires --rpc-tls-client-auth=require",
+		EnvVars: prefixEnvVars("RPC_TLS_ALLOWED_CLIENTS"),
+	}
+
+	// ChainAccountTLSEnableFlag chain account rpc tls flags
+	ChainAccountTLSEnableFlag = &cli.BoolFlag{
+		Name:    "chain-account-tls-enable",
+		Usage:   "Enable tls for the chain account rpc connection",
+		EnvVars: prefixEnvVars("CHAIN_ACCOUNT_TLS_ENABLE"),
+	}
+	ChainAccountTLSCAFlag = &cli.StringFlag{
+		Name:    "chain-account-tls-ca",
+		Usage:   "The ca file pinned for the chain account rpc server",
+		EnvVars: prefixEnvVars("CHAIN_ACCOUNT_TLS_CA"),
+	}
+	ChainAccountTLSCertFlag = &cli.StringFlag{
+		Name:    "chain-account-tls-cert",
+		Usage:   "The client certificate file for the chain account rpc",
+		EnvVars: prefixEnvVars("CHAIN_ACCOUNT_TLS_CERT"),
+	}
+	ChainAccountTLSKeyFlag = &cli.StringFlag{
+		Name:    "chain-account-tls-key",
+		Usage:   "The client private key file for the chain account rpc",
+		EnvVars: prefixEnvVars("CHAIN_ACCOUNT_TLS_KEY"),
+	}
+	ChainAccountTLSServerNameFlag = &cli.StringFlag{
+		Name:    "chain-account-tls-server-name",
+		Usage:   "Override the server name used to verify the chain account rpc certificate",
+		EnvVars: prefixEnvVars("CHAIN_ACCOUNT_TLS_SERVER_NAME"),
+	}
+
 	// MetricsHostFlag Metrics flags
 	MetricsHostFlag = &cli.StringFlag{
 		Name:     "metrics-host",
@@ -236,6 +422,40 @@ var optionalFlags = []cli.Flag{
 	ApiCacheDetailSizeFlag,
 	ApiCacheListExpireTimeFlag,
 	ApiCacheDetailExpireTimeFlag,
+	RpcTLSEnableFlag,
+	RpcTLSCertFlag,
+	RpcTLSKeyFlag,
+	RpcTLSClientCAFlag,
+	RpcTLSClientAuthFlag,
+	RpcTLSAllowedClientsFlag,
+	ChainAccountTLSEnableFlag,
+	ChainAccountTLSCAFlag,
+	ChainAccountTLSCertFlag,
+	ChainAccountTLSKeyFlag,
+	ChainAccountTLSServerNameFlag,
+	ArchiveDirFlag,
+	ChainsConfigFlag,
+	FeeStrategyConfigFlag,
+	TxExpiryFlag,
+	ReconcileEnableFlag,
+	ReconcileIntervalFlag,
+	ReconcileAutoCorrectFlag,
+	ReconcileToleranceFlag,
+	ReconcileNotifyThresholdFlag,
+	AlertEnableFlag,
+	AlertSyncLagBlocksFlag,
+	AlertBroadcastFailureRateFlag,
+	AlertBroadcastWindowFlag,
+	AlertNotifyBacklogAgeFlag,
+	AlertCoolDownFlag,
+	AlertWebhookUrlFlag,
+	AlertSlackWebhookUrlFlag,
+	AlertSmtpHostFlag,
+	AlertSmtpPortFlag,
+	AlertSmtpUserFlag,
+	AlertSmtpPasswordFlag,
+	AlertSmtpFromFlag,
+	AlertSmtpToFlag,
 }
 
 var Flags []cli.Flag
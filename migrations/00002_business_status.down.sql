@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS business_status;
+ALTER TABLE business
+    DROP CONSTRAINT IF EXISTS check_business_status;
+ALTER TABLE business
+    DROP COLUMN IF EXISTS status;
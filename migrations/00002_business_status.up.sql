@@ -0,0 +1,7 @@
+ALTER TABLE business
+    ADD COLUMN IF NOT EXISTS status VARCHAR(10) NOT NULL DEFAULT 'active';
+ALTER TABLE business
+    DROP CONSTRAINT IF EXISTS check_business_status;
+ALTER TABLE business
+    ADD CONSTRAINT check_business_status CHECK (status IN ('active', 'suspended', 'archived'));
+CREATE INDEX IF NOT EXISTS business_status ON business (status);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS reconciliations
+(
+    guid           VARCHAR PRIMARY KEY,
+    business_uid   VARCHAR     NOT NULL,
+    chain_name     VARCHAR     NOT NULL,
+    address        VARCHAR     NOT NULL,
+    token_address  VARCHAR     NOT NULL,
+    address_type   VARCHAR(10) NOT NULL DEFAULT 'eoa',
+    stored_balance UINT256     NOT NULL DEFAULT 0,
+    chain_balance  UINT256     NOT NULL DEFAULT 0,
+    difference     NUMERIC     NOT NULL DEFAULT 0,
+    status         VARCHAR(10) NOT NULL,
+    notify_status  VARCHAR(10) NOT NULL DEFAULT 'none',
+    timestamp      BIGINT      NOT NULL,
+    CONSTRAINT check_timestamp CHECK (timestamp > 0),
+    CONSTRAINT check_reconciliation_status CHECK (status IN ('corrected', 'mismatch', 'resolved')),
+    CONSTRAINT check_reconciliation_notify_status CHECK (notify_status IN ('none', 'pending', 'notified'))
+);
+CREATE INDEX IF NOT EXISTS idx_reconciliations_business ON reconciliations (business_uid, chain_name);
+CREATE INDEX IF NOT EXISTS idx_reconciliations_address ON reconciliations (address);
+CREATE INDEX IF NOT EXISTS idx_reconciliations_notify_status ON reconciliations (notify_status);
+CREATE INDEX IF NOT EXISTS idx_reconciliations_status ON reconciliations (business_uid, chain_name, status);
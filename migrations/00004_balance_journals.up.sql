@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS balance_journals
+(
+    guid               VARCHAR PRIMARY KEY,
+    seq                BIGSERIAL   NOT NULL,
+    entry_group        VARCHAR     NOT NULL,
+    address            VARCHAR     NOT NULL,
+    token_address      VARCHAR     NOT NULL,
+    address_type       VARCHAR(10) NOT NULL DEFAULT '',
+    bucket             VARCHAR(10) NOT NULL,
+    direction          VARCHAR(6)  NOT NULL,
+    entry_type         VARCHAR(16) NOT NULL,
+    amount             UINT256     NOT NULL,
+    balance_after      UINT256     NOT NULL DEFAULT 0,
+    lock_balance_after UINT256     NOT NULL DEFAULT 0,
+    tx_hash            VARCHAR     NOT NULL DEFAULT '',
+    block_number       UINT256,
+    timestamp          BIGINT      NOT NULL,
+    CONSTRAINT check_timestamp CHECK (timestamp > 0),
+    CONSTRAINT check_journal_bucket CHECK (bucket IN ('available', 'locked', 'external')),
+    CONSTRAINT check_journal_direction CHECK (direction IN ('credit', 'debit'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_balance_journals_seq ON balance_journals (seq);
+CREATE INDEX IF NOT EXISTS idx_balance_journals_account ON balance_journals (address, token_address, seq);
+CREATE INDEX IF NOT EXISTS idx_balance_journals_entry_group ON balance_journals (entry_group);
+CREATE INDEX IF NOT EXISTS idx_balance_journals_tx_hash ON balance_journals (tx_hash);
+CREATE INDEX IF NOT EXISTS idx_balance_journals_block_number ON balance_journals (block_number);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS quarantined_deposits;
+ALTER TABLE deposits
+    DROP COLUMN IF EXISTS unlisted_token;
+ALTER TABLE business
+    DROP CONSTRAINT IF EXISTS check_business_unlisted_token_policy;
+ALTER TABLE business
+    DROP COLUMN IF EXISTS unlisted_token_policy;
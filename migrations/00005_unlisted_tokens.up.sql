@@ -0,0 +1,25 @@
+ALTER TABLE business
+    ADD COLUMN IF NOT EXISTS unlisted_token_policy VARCHAR(10) NOT NULL DEFAULT 'quarantine';
+ALTER TABLE business
+    DROP CONSTRAINT IF EXISTS check_business_unlisted_token_policy;
+ALTER TABLE business
+    ADD CONSTRAINT check_business_unlisted_token_policy CHECK (unlisted_token_policy IN ('ignore', 'quarantine', 'notify'));
+
+ALTER TABLE deposits
+    ADD COLUMN IF NOT EXISTS unlisted_token BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS quarantined_deposits
+(
+    guid          VARCHAR PRIMARY KEY,
+    block_number  UINT256 NOT NULL CHECK (block_number > 0),
+    hash          VARCHAR NOT NULL,
+    from_address  VARCHAR NOT NULL,
+    to_address    VARCHAR NOT NULL,
+    token_address VARCHAR NOT NULL,
+    amount        UINT256 NOT NULL,
+    fee           VARCHAR NOT NULL DEFAULT '',
+    timestamp     BIGINT  NOT NULL CHECK (timestamp > 0)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS quarantined_deposits_hash_to ON quarantined_deposits (hash, to_address, token_address);
+CREATE INDEX IF NOT EXISTS quarantined_deposits_token_address ON quarantined_deposits (token_address);
+CREATE INDEX IF NOT EXISTS quarantined_deposits_to_address ON quarantined_deposits (to_address);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS deposits_to_address_status;
+ALTER TABLE deposits
+    DROP COLUMN IF EXISTS dust_aggregated;
+ALTER TABLE deposits
+    DROP COLUMN IF EXISTS min_deposit;
+
+ALTER TABLE tokens
+    DROP COLUMN IF EXISTS aggregate_dust;
+ALTER TABLE tokens
+    DROP COLUMN IF EXISTS min_deposit;
@@ -0,0 +1,10 @@
+ALTER TABLE tokens
+    ADD COLUMN IF NOT EXISTS min_deposit UINT256 NOT NULL DEFAULT 0;
+ALTER TABLE tokens
+    ADD COLUMN IF NOT EXISTS aggregate_dust BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE deposits
+    ADD COLUMN IF NOT EXISTS min_deposit UINT256 NOT NULL DEFAULT 0;
+ALTER TABLE deposits
+    ADD COLUMN IF NOT EXISTS dust_aggregated BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS deposits_to_address_status ON deposits (to_address, token_address, status);
@@ -0,0 +1,6 @@
+ALTER TABLE withdraws
+    DROP COLUMN IF EXISTS memo;
+ALTER TABLE deposits
+    DROP COLUMN IF EXISTS memo;
+
+DROP TABLE IF EXISTS memos;
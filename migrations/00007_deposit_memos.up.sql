@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS memos
+(
+    guid      VARCHAR PRIMARY KEY,
+    address   VARCHAR NOT NULL,
+    memo      VARCHAR NOT NULL,
+    timestamp BIGINT  NOT NULL CHECK (timestamp > 0)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS memos_address_memo ON memos (address, memo);
+
+ALTER TABLE deposits
+    ADD COLUMN IF NOT EXISTS memo VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE withdraws
+    ADD COLUMN IF NOT EXISTS memo VARCHAR NOT NULL DEFAULT '';
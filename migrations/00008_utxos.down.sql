@@ -0,0 +1,10 @@
+ALTER TABLE internals
+    DROP COLUMN IF EXISTS change_amount;
+ALTER TABLE internals
+    DROP COLUMN IF EXISTS change_address;
+ALTER TABLE withdraws
+    DROP COLUMN IF EXISTS change_amount;
+ALTER TABLE withdraws
+    DROP COLUMN IF EXISTS change_address;
+
+DROP TABLE IF EXISTS utxos;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS utxos
+(
+    guid          VARCHAR PRIMARY KEY,
+    address       VARCHAR     NOT NULL,
+    tx_hash       VARCHAR     NOT NULL,
+    vout          INTEGER     NOT NULL CHECK (vout >= 0),
+    amount        UINT256     NOT NULL,
+    block_number  UINT256     NOT NULL,
+    status        VARCHAR(10) NOT NULL DEFAULT 'unspent',
+    spend_guid    VARCHAR     NOT NULL DEFAULT '',
+    spent_tx_hash VARCHAR     NOT NULL DEFAULT '',
+    timestamp     BIGINT      NOT NULL CHECK (timestamp > 0),
+    CONSTRAINT check_utxos_status CHECK (status IN ('unspent', 'locked', 'spent'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS utxos_outpoint ON utxos (tx_hash, vout);
+CREATE INDEX IF NOT EXISTS utxos_address_status ON utxos (address, status);
+CREATE INDEX IF NOT EXISTS utxos_spend_guid ON utxos (spend_guid);
+
+ALTER TABLE withdraws
+    ADD COLUMN IF NOT EXISTS change_address VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE withdraws
+    ADD COLUMN IF NOT EXISTS change_amount UINT256;
+ALTER TABLE internals
+    ADD COLUMN IF NOT EXISTS change_address VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE internals
+    ADD COLUMN IF NOT EXISTS change_amount UINT256;
@@ -0,0 +1,4 @@
+ALTER TABLE internals
+    DROP COLUMN IF EXISTS fee_strategy;
+ALTER TABLE withdraws
+    DROP COLUMN IF EXISTS fee_strategy;
@@ -0,0 +1,4 @@
+ALTER TABLE withdraws
+    ADD COLUMN IF NOT EXISTS fee_strategy VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE internals
+    ADD COLUMN IF NOT EXISTS fee_strategy VARCHAR NOT NULL DEFAULT '';
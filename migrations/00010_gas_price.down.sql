@@ -0,0 +1,6 @@
+ALTER TABLE internals
+    DROP COLUMN IF EXISTS gas_price;
+ALTER TABLE withdraws
+    DROP COLUMN IF EXISTS gas_price;
+ALTER TABLE deposits
+    DROP COLUMN IF EXISTS gas_price;
@@ -0,0 +1,6 @@
+ALTER TABLE deposits
+    ADD COLUMN IF NOT EXISTS gas_price VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE withdraws
+    ADD COLUMN IF NOT EXISTS gas_price VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE internals
+    ADD COLUMN IF NOT EXISTS gas_price VARCHAR NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS withdraws_batch_id;
+ALTER TABLE internals
+    DROP COLUMN IF EXISTS nonce;
+ALTER TABLE withdraws
+    DROP COLUMN IF EXISTS nonce;
+ALTER TABLE withdraws
+    DROP COLUMN IF EXISTS multi_send;
+ALTER TABLE withdraws
+    DROP COLUMN IF EXISTS batch_index;
+ALTER TABLE withdraws
+    DROP COLUMN IF EXISTS batch_id;
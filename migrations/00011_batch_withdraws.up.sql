@@ -0,0 +1,11 @@
+ALTER TABLE withdraws
+    ADD COLUMN IF NOT EXISTS batch_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE withdraws
+    ADD COLUMN IF NOT EXISTS batch_index INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE withdraws
+    ADD COLUMN IF NOT EXISTS multi_send BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE withdraws
+    ADD COLUMN IF NOT EXISTS nonce BIGINT;
+ALTER TABLE internals
+    ADD COLUMN IF NOT EXISTS nonce BIGINT;
+CREATE INDEX IF NOT EXISTS withdraws_batch_id ON withdraws (batch_id);
@@ -0,0 +1,7 @@
+ALTER TABLE business
+    DROP COLUMN IF EXISTS address_pool_watermark;
+ALTER TABLE deposits
+    DROP COLUMN IF EXISTS user_id;
+ALTER TABLE addresses
+    DROP COLUMN IF EXISTS user_id;
+DROP TABLE IF EXISTS address_pool;
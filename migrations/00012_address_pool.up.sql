@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS address_pool
+(
+    guid        VARCHAR PRIMARY KEY,
+    public_key  VARCHAR     NOT NULL,
+    address     VARCHAR     NOT NULL DEFAULT '',
+    status      VARCHAR(10) NOT NULL DEFAULT 'pending',
+    user_id     VARCHAR     NOT NULL DEFAULT '',
+    assigned_at BIGINT      NOT NULL DEFAULT 0,
+    timestamp   BIGINT      NOT NULL CHECK (timestamp > 0),
+    CONSTRAINT check_address_pool_status CHECK (status IN ('pending', 'available', 'assigned', 'failed'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS address_pool_public_key ON address_pool (public_key);
+CREATE INDEX IF NOT EXISTS address_pool_status ON address_pool (status, timestamp);
+CREATE UNIQUE INDEX IF NOT EXISTS address_pool_user_id ON address_pool (user_id) WHERE status = 'assigned';
+
+ALTER TABLE addresses
+    ADD COLUMN IF NOT EXISTS user_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE deposits
+    ADD COLUMN IF NOT EXISTS user_id VARCHAR NOT NULL DEFAULT '';
+
+ALTER TABLE business
+    ADD COLUMN IF NOT EXISTS address_pool_watermark INTEGER NOT NULL DEFAULT 100 CHECK (address_pool_watermark >= 0);
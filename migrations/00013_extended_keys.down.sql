@@ -0,0 +1,8 @@
+ALTER TABLE address_pool
+    DROP COLUMN IF EXISTS address_format;
+DROP INDEX IF EXISTS addresses_extended_key_id;
+ALTER TABLE addresses
+    DROP COLUMN IF EXISTS derivation_index;
+ALTER TABLE addresses
+    DROP COLUMN IF EXISTS extended_key_id;
+DROP TABLE IF EXISTS extended_keys;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS extended_keys
+(
+    guid           VARCHAR PRIMARY KEY,
+    extended_key   VARCHAR     NOT NULL,
+    address_format VARCHAR     NOT NULL DEFAULT '',
+    address_type   VARCHAR(10) NOT NULL DEFAULT 'eoa',
+    next_index     BIGINT      NOT NULL DEFAULT 0 CHECK (next_index >= 0),
+    timestamp      BIGINT      NOT NULL CHECK (timestamp > 0)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS extended_keys_key_format ON extended_keys (extended_key, address_format);
+
+ALTER TABLE addresses
+    ADD COLUMN IF NOT EXISTS extended_key_id VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE addresses
+    ADD COLUMN IF NOT EXISTS derivation_index BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS addresses_extended_key_id ON addresses (extended_key_id, derivation_index);
+
+ALTER TABLE address_pool
+    ADD COLUMN IF NOT EXISTS address_format VARCHAR NOT NULL DEFAULT '';
@@ -0,0 +1,12 @@
+ALTER TABLE internals
+    DROP COLUMN IF EXISTS sign_hash;
+ALTER TABLE internals
+    DROP COLUMN IF EXISTS unsigned_tx;
+ALTER TABLE withdraws
+    DROP COLUMN IF EXISTS sign_hash;
+ALTER TABLE withdraws
+    DROP COLUMN IF EXISTS unsigned_tx;
+ALTER TABLE deposits
+    DROP COLUMN IF EXISTS sign_hash;
+ALTER TABLE deposits
+    DROP COLUMN IF EXISTS unsigned_tx;
@@ -0,0 +1,12 @@
+ALTER TABLE deposits
+    ADD COLUMN IF NOT EXISTS unsigned_tx TEXT NOT NULL DEFAULT '';
+ALTER TABLE deposits
+    ADD COLUMN IF NOT EXISTS sign_hash VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE withdraws
+    ADD COLUMN IF NOT EXISTS unsigned_tx TEXT NOT NULL DEFAULT '';
+ALTER TABLE withdraws
+    ADD COLUMN IF NOT EXISTS sign_hash VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE internals
+    ADD COLUMN IF NOT EXISTS unsigned_tx TEXT NOT NULL DEFAULT '';
+ALTER TABLE internals
+    ADD COLUMN IF NOT EXISTS sign_hash VARCHAR NOT NULL DEFAULT '';
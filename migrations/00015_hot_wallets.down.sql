@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS withdraws_from_status;
+ALTER TABLE business
+    DROP COLUMN IF EXISTS collection_target;
+ALTER TABLE business
+    DROP COLUMN IF EXISTS hot_wallet_strategy;
@@ -0,0 +1,5 @@
+ALTER TABLE business
+    ADD COLUMN IF NOT EXISTS hot_wallet_strategy VARCHAR(20) NOT NULL DEFAULT 'round_robin';
+ALTER TABLE business
+    ADD COLUMN IF NOT EXISTS collection_target VARCHAR NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS withdraws_from_status ON withdraws (from_address, status);
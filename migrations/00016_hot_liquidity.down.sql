@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS liquidity_top_ups;
+ALTER TABLE tokens
+    DROP COLUMN IF EXISTS hot_target_balance;
+ALTER TABLE tokens
+    DROP COLUMN IF EXISTS hot_min_balance;
@@ -0,0 +1,27 @@
+ALTER TABLE tokens
+    ADD COLUMN IF NOT EXISTS hot_min_balance UINT256 NOT NULL DEFAULT 0;
+ALTER TABLE tokens
+    ADD COLUMN IF NOT EXISTS hot_target_balance UINT256 NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS liquidity_top_ups
+(
+    guid            VARCHAR PRIMARY KEY,
+    business_uid    VARCHAR     NOT NULL,
+    chain_name      VARCHAR     NOT NULL,
+    transaction_id  VARCHAR     NOT NULL,
+    hot_address     VARCHAR     NOT NULL,
+    cold_address    VARCHAR     NOT NULL,
+    token_address   VARCHAR     NOT NULL,
+    balance         UINT256     NOT NULL DEFAULT 0,
+    pending_outflow UINT256     NOT NULL DEFAULT 0,
+    pending_inflow  UINT256     NOT NULL DEFAULT 0,
+    min_balance     UINT256     NOT NULL DEFAULT 0,
+    target_balance  UINT256     NOT NULL DEFAULT 0,
+    amount          UINT256     NOT NULL,
+    notify_status   VARCHAR(10) NOT NULL DEFAULT 'pending',
+    timestamp       BIGINT      NOT NULL,
+    CONSTRAINT check_timestamp CHECK (timestamp > 0),
+    CONSTRAINT check_liquidity_top_up_notify_status CHECK (notify_status IN ('pending', 'notified'))
+);
+CREATE INDEX IF NOT EXISTS idx_liquidity_top_ups_business ON liquidity_top_ups (business_uid, chain_name);
+CREATE INDEX IF NOT EXISTS idx_liquidity_top_ups_notify_status ON liquidity_top_ups (notify_status);
@@ -0,0 +1,16 @@
+DO
+$$
+    DECLARE
+        item RECORD;
+    BEGIN
+        FOR item IN SELECT conrelid::regclass AS table_name
+                    FROM pg_constraint
+                    WHERE conname = 'check_address_type'
+                      AND contype = 'c'
+            LOOP
+                EXECUTE format('ALTER TABLE %s DROP CONSTRAINT check_address_type', item.table_name);
+                EXECUTE format('ALTER TABLE %s ADD CONSTRAINT check_address_type CHECK (address_type IN (''eoa'', ''hot'', ''cold''))',
+                               item.table_name);
+            END LOOP;
+    END
+$$;
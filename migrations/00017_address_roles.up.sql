@@ -0,0 +1,17 @@
+-- 模板表和已创建的业务表(LIKE INCLUDING ALL 复制了同名约束)都放开 fee、treasury 地址类型
+DO
+$$
+    DECLARE
+        item RECORD;
+    BEGIN
+        FOR item IN SELECT conrelid::regclass AS table_name
+                    FROM pg_constraint
+                    WHERE conname = 'check_address_type'
+                      AND contype = 'c'
+            LOOP
+                EXECUTE format('ALTER TABLE %s DROP CONSTRAINT check_address_type', item.table_name);
+                EXECUTE format('ALTER TABLE %s ADD CONSTRAINT check_address_type CHECK (address_type IN (''eoa'', ''hot'', ''cold'', ''fee'', ''treasury''))',
+                               item.table_name);
+            END LOOP;
+    END
+$$;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS outflow_incidents;
+ALTER TABLE business
+    DROP COLUMN IF EXISTS withdraw_paused;
+ALTER TABLE business
+    DROP CONSTRAINT IF EXISTS check_business_outflow_policy;
+ALTER TABLE business
+    DROP COLUMN IF EXISTS outflow_policy;
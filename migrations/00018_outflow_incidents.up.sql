@@ -0,0 +1,31 @@
+ALTER TABLE business
+    ADD COLUMN IF NOT EXISTS outflow_policy VARCHAR(10) NOT NULL DEFAULT 'alert';
+ALTER TABLE business
+    DROP CONSTRAINT IF EXISTS check_business_outflow_policy;
+ALTER TABLE business
+    ADD CONSTRAINT check_business_outflow_policy CHECK (outflow_policy IN ('alert', 'pause'));
+ALTER TABLE business
+    ADD COLUMN IF NOT EXISTS withdraw_paused BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS outflow_incidents
+(
+    guid            VARCHAR PRIMARY KEY,
+    business_uid    VARCHAR     NOT NULL,
+    chain_name      VARCHAR     NOT NULL,
+    tx_hash         VARCHAR     NOT NULL,
+    block_number    UINT256     NOT NULL,
+    from_address    VARCHAR     NOT NULL,
+    from_role       VARCHAR(10) NOT NULL,
+    to_address      VARCHAR     NOT NULL,
+    to_role         VARCHAR(10) NOT NULL,
+    token_address   VARCHAR     NOT NULL,
+    amount          UINT256     NOT NULL,
+    tx_type         VARCHAR     NOT NULL,
+    withdraw_paused BOOLEAN     NOT NULL DEFAULT FALSE,
+    notify_status   VARCHAR(10) NOT NULL DEFAULT 'pending',
+    timestamp       BIGINT      NOT NULL,
+    CONSTRAINT check_timestamp CHECK (timestamp > 0),
+    CONSTRAINT check_outflow_incident_notify_status CHECK (notify_status IN ('pending', 'notified'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outflow_incidents_tx ON outflow_incidents (business_uid, chain_name, tx_hash, from_address, token_address);
+CREATE INDEX IF NOT EXISTS idx_outflow_incidents_notify_status ON outflow_incidents (notify_status);
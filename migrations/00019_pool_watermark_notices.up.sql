@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS pool_watermark_notices
+(
+    business_uid VARCHAR NOT NULL,
+    chain_name   VARCHAR NOT NULL,
+    timestamp    BIGINT  NOT NULL,
+    PRIMARY KEY (business_uid, chain_name),
+    CONSTRAINT check_timestamp CHECK (timestamp > 0)
+);
@@ -5,9 +5,8 @@ import (
 	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/log"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/dapplink-labs/multichain-sync-account/alerting"
 	"github.com/dapplink-labs/multichain-sync-account/config"
 	"github.com/dapplink-labs/multichain-sync-account/database"
 	"github.com/dapplink-labs/multichain-sync-account/rpcclient"
@@ -20,6 +19,11 @@ type MultiChainSync struct {
 	Deposit      *worker.Deposit
 	Withdraw     *worker.Withdraw
 	Internal     *worker.Internal
+	Reconcile    *worker.Reconcile
+	AddressPool  *worker.AddressPool
+	Liquidity    *worker.Liquidity
+	Expiry       *worker.Expiry
+	Alerter      *alerting.Alerter
 
 	shutdown context.CancelCauseFunc
 	stopped  atomic.Bool
@@ -33,33 +37,57 @@ func NewMultiChainSync(ctx context.Context, cfg *config.Config, shutdown context
 	}
 
 	log.Info("New deposit", "ChainAccountRpc", cfg.ChainAccountRpc)
-	conn, err := grpc.NewClient(cfg.ChainAccountRpc, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := rpcclient.DialChainAccount(cfg.ChainAccountRpc, cfg.ChainAccountTLS)
 	if err != nil {
 		log.Error("Connect to da retriever fail", "err", err)
 		return nil, err
 	}
 	client := account.NewWalletAccountServiceClient(conn)
-	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, cfg.ChainNode.ChainName)
+	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, cfg.ChainNode.ChainName, database.GetNetwork(cfg.ChainNode.ChainName))
 	if err != nil {
 		log.Error("new wallet account client fail", "err", err)
 		return nil, err
 	}
 
-	deposit, _ := worker.NewDeposit(cfg, db, accountClient, shutdown)
-	withdraw, _ := worker.NewWithdraw(cfg, db, accountClient, shutdown)
-	internal, _ := worker.NewInternal(cfg, db, accountClient, shutdown)
+	alerter, err := alerting.NewAlerterFromConfig(cfg.Alert)
+	if err != nil {
+		log.Error("new alerter fail", "err", err)
+		return nil, err
+	}
+
+	deposit, _ := worker.NewDeposit(cfg, db, accountClient, alerter, shutdown)
+	withdraw, _ := worker.NewWithdraw(cfg, db, accountClient, alerter, shutdown)
+	internal, _ := worker.NewInternal(cfg, db, accountClient, alerter, shutdown)
+	addressPool, _ := worker.NewAddressPool(cfg, db, accountClient, shutdown)
+	liquidity, _ := worker.NewLiquidity(cfg, db, accountClient, alerter, shutdown)
+	expiry, _ := worker.NewExpiry(cfg, db, accountClient, shutdown)
 
 	out := &MultiChainSync{
-		Deposit:  deposit,
-		Withdraw: withdraw,
-		Internal: internal,
-		shutdown: shutdown,
+		Deposit:     deposit,
+		Withdraw:    withdraw,
+		Internal:    internal,
+		AddressPool: addressPool,
+		Liquidity:   liquidity,
+		Expiry:      expiry,
+		Alerter:     alerter,
+		shutdown:    shutdown,
+	}
+	if cfg.Reconcile.Enable {
+		out.Reconcile, err = worker.NewReconcile(cfg, db, accountClient, alerter, shutdown)
+		if err != nil {
+			log.Error("new reconcile worker fail", "err", err)
+			return nil, err
+		}
 	}
 	return out, nil
 }
 
 func (mcs *MultiChainSync) Start(ctx context.Context) error {
-	err := mcs.Deposit.Start()
+	err := mcs.Alerter.Start()
+	if err != nil {
+		return err
+	}
+	err = mcs.Deposit.Start()
 	if err != nil {
 		return err
 	}
@@ -71,6 +99,24 @@ func (mcs *MultiChainSync) Start(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	err = mcs.AddressPool.Start()
+	if err != nil {
+		return err
+	}
+	err = mcs.Liquidity.Start()
+	if err != nil {
+		return err
+	}
+	err = mcs.Expiry.Start()
+	if err != nil {
+		return err
+	}
+	if mcs.Reconcile != nil {
+		err = mcs.Reconcile.Start()
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -87,7 +133,25 @@ func (mcs *MultiChainSync) Stop(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	return nil
+	err = mcs.AddressPool.Close()
+	if err != nil {
+		return err
+	}
+	err = mcs.Liquidity.Close()
+	if err != nil {
+		return err
+	}
+	err = mcs.Expiry.Close()
+	if err != nil {
+		return err
+	}
+	if mcs.Reconcile != nil {
+		err = mcs.Reconcile.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return mcs.Alerter.Close()
 }
 
 func (mcs *MultiChainSync) Stopped() bool {
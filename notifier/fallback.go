@@ -54,3 +54,88 @@ func (nc *NotifyClient) BusinessNotify(notifyData *NotifyRequest) (bool, error)
 	}
 	return spt.Success, nil
 }
+
+func (nc *NotifyClient) ReconcileNotify(notifyData *ReconcileNotifyRequest) (bool, error) {
+	body, err := json.Marshal(notifyData)
+	if err != nil {
+		log.Error("failed to marshal reconcile notify data", "err", err)
+		return false, err
+	}
+	res, err := nc.client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(body).
+		SetResult(&NotifyResponse{}).Post("/dapplink/reconcile")
+	if err != nil {
+		log.Error("reconcile notify fail", "err", err)
+		return false, err
+	}
+	spt, ok := res.Result().(*NotifyResponse)
+	if !ok {
+		return false, errors.New("reconcile notify fail, ok is false")
+	}
+	return spt.Success, nil
+}
+
+func (nc *NotifyClient) AddressPoolNotify(notifyData *AddressPoolNotifyRequest) (bool, error) {
+	body, err := json.Marshal(notifyData)
+	if err != nil {
+		log.Error("failed to marshal address pool notify data", "err", err)
+		return false, err
+	}
+	res, err := nc.client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(body).
+		SetResult(&NotifyResponse{}).Post("/dapplink/address-pool")
+	if err != nil {
+		log.Error("address pool notify fail", "err", err)
+		return false, err
+	}
+	spt, ok := res.Result().(*NotifyResponse)
+	if !ok {
+		return false, errors.New("address pool notify fail, ok is false")
+	}
+	return spt.Success, nil
+}
+
+func (nc *NotifyClient) LiquidityNotify(notifyData *LiquidityNotifyRequest) (bool, error) {
+	body, err := json.Marshal(notifyData)
+	if err != nil {
+		log.Error("failed to marshal liquidity notify data", "err", err)
+		return false, err
+	}
+	res, err := nc.client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(body).
+		SetResult(&NotifyResponse{}).Post("/dapplink/liquidity")
+	if err != nil {
+		log.Error("liquidity notify fail", "err", err)
+		return false, err
+	}
+	spt, ok := res.Result().(*NotifyResponse)
+	if !ok {
+		return false, errors.New("liquidity notify fail, ok is false")
+	}
+	return spt.Success, nil
+}
+
+func (nc *NotifyClient) SecurityNotify(notifyData *SecurityNotifyRequest) (bool, error) {
+	body, err := json.Marshal(notifyData)
+	if err != nil {
+		log.Error("failed to marshal security notify data", "err", err)
+		return false, err
+	}
+	res, err := nc.client.R().
+		SetHeader("Content-Type", "application/json").
+		SetHeader("X-Notify-Priority", SecurityPriorityHigh).
+		SetBody(body).
+		SetResult(&NotifyResponse{}).Post("/dapplink/security")
+	if err != nil {
+		log.Error("security notify fail", "err", err)
+		return false, err
+	}
+	spt, ok := res.Result().(*NotifyResponse)
+	if !ok {
+		return false, errors.New("security notify fail, ok is false")
+	}
+	return spt.Success, nil
+}
@@ -9,52 +9,39 @@ import (
 
 	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/dapplink-labs/multichain-sync-account/alerting"
+	"github.com/dapplink-labs/multichain-sync-account/common/bigint"
 	"github.com/dapplink-labs/multichain-sync-account/common/retry"
 	"github.com/dapplink-labs/multichain-sync-account/common/tasks"
 	"github.com/dapplink-labs/multichain-sync-account/database"
 )
 
 type Notifier struct {
-	db             *database.DB
-	businessIds    []string
-	notifyClient   map[string]*NotifyClient
+	db           *database.DB
+	businessIds  []string
+	notifyClient map[string]*NotifyClient
+	tokenMetas   map[string]*database.TokenMeta
+	// poolWatermarks 业务的地址池水位
+	poolWatermarks map[string]uint64
 	resourceCtx    context.Context
 	resourceCancel context.CancelFunc
 	tasks          tasks.Group
 	ticker         *time.Ticker
 	chainName      string
+	alerter        *alerting.Alerter
 
 	shutdown context.CancelCauseFunc
 	stopped  atomic.Bool
 }
 
-func NewNotifier(db *database.DB, shutdown context.CancelCauseFunc, chainName string) (*Notifier, error) {
-	businessList, err := db.Business.QueryBusinessList()
-	if err != nil {
-		log.Error("query business list fail", "err", err)
-		return nil, err
-	}
-
-	var businessIds []string
-	notifyClient := make(map[string]*NotifyClient)
-
-	for _, business := range businessList {
-		log.Info("handle business id", "business", business.BusinessUid)
-		businessIds = append(businessIds, business.BusinessUid)
-		client, err := NewNotifierClient(business.NotifyUrl)
-		if err != nil {
-			log.Error("new notify client fail", "err", err)
-			return nil, err
-		}
-		notifyClient[business.BusinessUid] = client
-	}
-
+func NewNotifier(db *database.DB, shutdown context.CancelCauseFunc, chainName string, alerter *alerting.Alerter) (*Notifier, error) {
 	resCtx, resCancel := context.WithCancel(context.Background())
 
-	return &Notifier{
+	nf := &Notifier{
 		db:             db,
-		notifyClient:   notifyClient,
-		businessIds:    businessIds,
+		notifyClient:   make(map[string]*NotifyClient),
+		tokenMetas:     make(map[string]*database.TokenMeta),
+		poolWatermarks: make(map[string]uint64),
 		resourceCtx:    resCtx,
 		resourceCancel: resCancel,
 		tasks: tasks.Group{HandleCrit: func(err error) {
@@ -62,17 +49,61 @@ func NewNotifier(db *database.DB, shutdown context.CancelCauseFunc, chainName st
 		}},
 		ticker:    time.NewTicker(time.Second * 5),
 		chainName: chainName,
-	}, nil
+		alerter:   alerter,
+	}
+	if err := nf.refreshBusiness(); err != nil {
+		resCancel()
+		return nil, err
+	}
+	return nf, nil
+}
+
+// refreshBusiness 重新加载 active 状态的业务，暂停或归档的业务不再通知
+func (nf *Notifier) refreshBusiness() error {
+	businessList, err := nf.db.Business.QueryActiveBusinessList()
+	if err != nil {
+		log.Error("query business list fail", "err", err)
+		return err
+	}
+
+	var businessIds []string
+	for _, business := range businessList {
+		if _, ok := nf.notifyClient[business.BusinessUid]; !ok {
+			log.Info("handle business id", "business", business.BusinessUid)
+			client, err := NewNotifierClient(business.NotifyUrl)
+			if err != nil {
+				log.Error("new notify client fail", "err", err)
+				return err
+			}
+			nf.notifyClient[business.BusinessUid] = client
+		}
+		businessIds = append(businessIds, business.BusinessUid)
+		nf.poolWatermarks[business.BusinessUid] = business.AddressPoolWatermark
+	}
+	nf.businessIds = businessIds
+	return nil
 }
 
 func (nf *Notifier) Start(ctx context.Context) error {
 	log.Info("start internals......")
+	if err := nf.alerter.Start(); err != nil {
+		return err
+	}
 	nf.tasks.Go(func() error {
 		for {
 			select {
 			case <-nf.ticker.C:
+				if err := nf.refreshBusiness(); err != nil {
+					log.Error("refresh business list fail", "err", err)
+					continue
+				}
 				var txn []Transaction
 				for _, businessId := range nf.businessIds {
+					nf.notifySecurity(businessId)
+					nf.notifyReconciliations(businessId)
+					nf.notifyAddressPool(businessId)
+					nf.notifyLiquidity(businessId)
+
 					log.Info("txn and businessId", "txn", txn, "businessId", businessId)
 
 					needNotifyDeposits, err := nf.db.Deposits.QueryNotifyDeposits(businessId, nf.chainName)
@@ -92,7 +123,8 @@ func (nf *Notifier) Start(ctx context.Context) error {
 						log.Error("Query notify deposits fail", "err", err)
 						return err
 					}
-					notifyRequest, err := nf.BuildNotifyTransaction(needNotifyDeposits, needNotifyWithdraws, needNotifyInternals)
+					nf.alerter.NotifyBacklog(nf.chainName, businessId, oldestPending(needNotifyDeposits, needNotifyWithdraws, needNotifyInternals))
+					notifyRequest, err := nf.BuildNotifyTransaction(businessId, needNotifyDeposits, needNotifyWithdraws, needNotifyInternals)
 
 					// BeforeRequest
 					err = nf.BeforeAfterNotify(businessId, true, false, needNotifyDeposits, needNotifyWithdraws, needNotifyInternals)
@@ -123,12 +155,240 @@ func (nf *Notifier) Start(ctx context.Context) error {
 	return nil
 }
 
+// oldestPending 待通知交易中最早的创建时间，没有待通知交易时为零值
+func oldestPending(deposits []*database.Deposits, withdraws []*database.Withdraws, internals []*database.Internals) time.Time {
+	var oldest uint64
+	observe := func(timestamp uint64) {
+		if timestamp > 0 && (oldest == 0 || timestamp < oldest) {
+			oldest = timestamp
+		}
+	}
+	for _, deposit := range deposits {
+		observe(deposit.Timestamp)
+	}
+	for _, withdraw := range withdraws {
+		observe(withdraw.Timestamp)
+	}
+	for _, internal := range internals {
+		observe(internal.Timestamp)
+	}
+	if oldest == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(oldest), 0)
+}
+
+// notifyReconciliations 通知业务方超过阈值的余额对账差异，失败时下一轮重试
+func (nf *Notifier) notifyReconciliations(businessId string) {
+	reconciliations, err := nf.db.Reconciliations.QueryNotifyReconciliations(businessId, nf.chainName)
+	if err != nil {
+		log.Error("Query notify reconciliations fail", "err", err)
+		return
+	}
+	if len(reconciliations) == 0 {
+		return
+	}
+	notifyRequest := &ReconcileNotifyRequest{}
+	for _, reconciliation := range reconciliations {
+		item := &Reconciliation{
+			Address:       reconciliation.Address,
+			TokenAddress:  reconciliation.TokenAddress,
+			AddressType:   string(reconciliation.AddressType),
+			StoredBalance: reconciliation.StoredBalance.String(),
+			ChainBalance:  reconciliation.ChainBalance.String(),
+			Difference:    reconciliation.Difference.String(),
+			Status:        string(reconciliation.Status),
+			Timestamp:     reconciliation.Timestamp,
+		}
+		if meta := nf.tokenMeta(businessId, reconciliation.TokenAddress); meta != nil {
+			item.Symbol = meta.Symbol
+			item.Decimals = meta.Decimals
+			item.DifferenceAmount = bigint.FormatUnits(reconciliation.Difference, meta.Decimals)
+		}
+		notifyRequest.Reconciliations = append(notifyRequest.Reconciliations, item)
+	}
+	notify, err := nf.notifyClient[businessId].ReconcileNotify(notifyRequest)
+	if err != nil || !notify {
+		log.Error("notify reconciliations fail", "businessId", businessId, "err", err)
+		return
+	}
+	if err := nf.db.Reconciliations.UpdateReconciliationsNotifyStatus(reconciliations, database.ReconcileNotifyNotified); err != nil {
+		log.Error("update reconciliations notify status fail", "err", err)
+	}
+}
+
+// notifyAddressPool 地址池未分配地址低于水位时通知一次，失败时下一轮重试；没有上传过公钥或水位为 0 的业务不使用地址池，不通知
+func (nf *Notifier) notifyAddressPool(businessId string) {
+	watermark := nf.poolWatermarks[businessId]
+	if watermark == 0 {
+		return
+	}
+	stats, err := nf.db.AddressPool.QueryAddressPoolStats(businessId, nf.chainName)
+	if err != nil {
+		log.Error("Query address pool stats fail", "err", err)
+		return
+	}
+	if stats.Total() == 0 {
+		return
+	}
+	notified, err := nf.db.AddressPool.QueryPoolLowNotified(businessId, nf.chainName)
+	if err != nil {
+		log.Error("Query address pool notice fail", "err", err)
+		return
+	}
+	if uint64(stats.Available+stats.Pending) >= watermark {
+		if notified {
+			if err := nf.db.AddressPool.UpdatePoolLowNotified(businessId, nf.chainName, false); err != nil {
+				log.Error("clear address pool notice fail", "businessId", businessId, "err", err)
+			}
+		}
+		return
+	}
+	if notified {
+		return
+	}
+	notify, err := nf.notifyClient[businessId].AddressPoolNotify(&AddressPoolNotifyRequest{
+		ChainName: nf.chainName,
+		Pending:   stats.Pending,
+		Available: stats.Available,
+		Assigned:  stats.Assigned,
+		Watermark: watermark,
+	})
+	if err != nil || !notify {
+		log.Error("notify address pool low watermark fail", "businessId", businessId, "err", err)
+		return
+	}
+	log.Info("notify address pool low watermark", "businessId", businessId, "available", stats.Available, "watermark", watermark)
+	if err := nf.db.AddressPool.UpdatePoolLowNotified(businessId, nf.chainName, true); err != nil {
+		log.Error("record address pool notice fail", "businessId", businessId, "err", err)
+	}
+}
+
+// notifyLiquidity 通知业务方为热钱包自动创建的 cold2hot 补充交易签名，失败时下一轮重试
+func (nf *Notifier) notifyLiquidity(businessId string) {
+	topUps, err := nf.db.LiquidityTopUps.QueryNotifyTopUps(businessId, nf.chainName)
+	if err != nil {
+		log.Error("Query notify liquidity top ups fail", "err", err)
+		return
+	}
+	if len(topUps) == 0 {
+		return
+	}
+	notifyRequest := &LiquidityNotifyRequest{ChainName: nf.chainName}
+	for _, topUp := range topUps {
+		item := &LiquidityTopUp{
+			TransactionId:  topUp.TransactionId,
+			HotAddress:     topUp.HotAddress,
+			ColdAddress:    topUp.ColdAddress,
+			TokenAddress:   topUp.TokenAddress,
+			Balance:        topUp.Balance.String(),
+			PendingOutflow: topUp.PendingOutflow.String(),
+			PendingInflow:  topUp.PendingInflow.String(),
+			MinBalance:     topUp.MinBalance.String(),
+			TargetBalance:  topUp.TargetBalance.String(),
+			Value:          topUp.Amount.String(),
+			Timestamp:      topUp.Timestamp,
+		}
+		if meta := nf.tokenMeta(businessId, topUp.TokenAddress); meta != nil {
+			item.Symbol = meta.Symbol
+			item.Decimals = meta.Decimals
+			item.Amount = bigint.FormatUnits(topUp.Amount, meta.Decimals)
+		}
+		notifyRequest.TopUps = append(notifyRequest.TopUps, item)
+	}
+	notify, err := nf.notifyClient[businessId].LiquidityNotify(notifyRequest)
+	if err != nil || !notify {
+		log.Error("notify liquidity top ups fail", "businessId", businessId, "err", err)
+		return
+	}
+	if err := nf.db.LiquidityTopUps.UpdateTopUpsNotifyStatus(topUps, database.LiquidityNotifyNotified); err != nil {
+		log.Error("update liquidity top ups notify status fail", "err", err)
+	}
+}
+
+// notifySecurity 通知业务方未授权转出事件，每轮最先发送，失败时下一轮重试
+func (nf *Notifier) notifySecurity(businessId string) {
+	incidents, err := nf.db.Incidents.QueryNotifyIncidents(businessId, nf.chainName)
+	if err != nil {
+		log.Error("Query notify outflow incidents fail", "err", err)
+		return
+	}
+	if len(incidents) == 0 {
+		return
+	}
+	notifyRequest := &SecurityNotifyRequest{ChainName: nf.chainName, Priority: SecurityPriorityHigh}
+	for _, incident := range incidents {
+		item := &OutflowIncident{
+			Id:           incident.GUID.String(),
+			TxHash:       incident.TxHash,
+			BlockNumber:  incident.BlockNumber.String(),
+			FromAddress:  incident.FromAddress,
+			FromRole:     string(incident.FromRole),
+			ToAddress:    incident.ToAddress,
+			ToRole:       string(incident.ToRole),
+			TokenAddress: incident.TokenAddress,
+			TxType:       string(incident.TxType),
+			Value:        incident.Amount.String(),
+			Timestamp:    incident.Timestamp,
+		}
+		if meta := nf.tokenMeta(businessId, incident.TokenAddress); meta != nil {
+			item.Symbol = meta.Symbol
+			item.Decimals = meta.Decimals
+			item.Amount = bigint.FormatUnits(incident.Amount, meta.Decimals)
+		}
+		notifyRequest.WithdrawPaused = notifyRequest.WithdrawPaused || incident.WithdrawPaused
+		notifyRequest.Incidents = append(notifyRequest.Incidents, item)
+	}
+	notify, err := nf.notifyClient[businessId].SecurityNotify(notifyRequest)
+	if err != nil || !notify {
+		log.Error("notify outflow incidents fail", "businessId", businessId, "count", len(incidents), "err", err)
+		return
+	}
+	if err := nf.db.Incidents.UpdateIncidentsNotifyStatus(incidents, database.IncidentNotifyNotified); err != nil {
+		log.Error("update outflow incidents notify status fail", "err", err)
+	}
+}
+
+// tokenMeta 查询并缓存代币精度，未登记的代币不缓存，登记后下一轮即可生效
+func (nf *Notifier) tokenMeta(businessId string, tokenAddress string) *database.TokenMeta {
+	key := businessId + ":" + tokenAddress
+	if meta, ok := nf.tokenMetas[key]; ok {
+		return meta
+	}
+	meta, err := database.ResolveTokenMeta(nf.db.Tokens, businessId, nf.chainName, tokenAddress)
+	if err != nil {
+		log.Warn("resolve token meta fail", "businessId", businessId, "token", tokenAddress, "err", err)
+		return nil
+	}
+	if meta != nil {
+		nf.tokenMetas[key] = meta
+	}
+	return meta
+}
+
+func (nf *Notifier) formatAmounts(businessId string, txItem *Transaction) {
+	if meta := nf.tokenMeta(businessId, txItem.TokenAddress); meta != nil {
+		txItem.Symbol = meta.Symbol
+		txItem.Decimals = meta.Decimals
+		txItem.Amount = bigint.FormatUnits(bigint.StringToBigInt(txItem.Value), meta.Decimals)
+	}
+	if fee := bigint.StringToBigInt(txItem.Fee); fee != nil {
+		txItem.FeeSymbol = database.GetTokenType(nf.chainName, true).String()
+		txItem.FeeAmount = bigint.FormatUnits(fee, database.GetNativeDecimals(nf.chainName))
+	}
+}
+
 func (nf *Notifier) Stop(ctx context.Context) error {
 	var result error
 	nf.resourceCancel()
 	nf.ticker.Stop()
 	if err := nf.tasks.Wait(); err != nil {
 		result = errors.Join(result, fmt.Errorf("failed to await notify %w", err))
+	}
+	if err := nf.alerter.Close(); err != nil {
+		result = errors.Join(result, err)
+	}
+	if result != nil {
 		return result
 	}
 	log.Info("stop notify success")
@@ -196,22 +456,37 @@ func (nf *Notifier) BeforeAfterNotify(businessId string, isBefore bool, notifySu
 	return nil
 }
 
-func (nf *Notifier) BuildNotifyTransaction(deposits []*database.Deposits, withdraws []*database.Withdraws, internals []*database.Internals) (*NotifyRequest, error) {
+// notifyFee legacy 交易通知 gasPrice，EIP-1559 交易通知 maxFeePerGas
+func notifyFee(maxFeePerGas, gasPrice string) string {
+	if gasPrice != "" {
+		return gasPrice
+	}
+	return maxFeePerGas
+}
+
+func (nf *Notifier) BuildNotifyTransaction(businessId string, deposits []*database.Deposits, withdraws []*database.Withdraws, internals []*database.Internals) (*NotifyRequest, error) {
 	var notifyTransactions []*Transaction
 	for _, deposit := range deposits {
 		txItem := &Transaction{
-			BlockHash:    deposit.BlockHash.String(),
-			BlockNumber:  deposit.BlockNumber.Uint64(),
-			Hash:         deposit.TxHash.String(),
-			FromAddress:  deposit.FromAddress,
-			ToAddress:    deposit.ToAddress,
-			Value:        deposit.Amount.String(),
-			Fee:          deposit.MaxFeePerGas,
-			TxType:       deposit.TxType,
-			Confirms:     deposit.Confirms,
-			TokenAddress: deposit.TokenAddress,
-			TokenId:      deposit.TokenId,
-			TokenMeta:    deposit.TokenMeta,
+			BlockHash:      deposit.BlockHash.String(),
+			BlockNumber:    deposit.BlockNumber.Uint64(),
+			Hash:           deposit.TxHash.String(),
+			FromAddress:    deposit.FromAddress,
+			ToAddress:      deposit.ToAddress,
+			Value:          deposit.Amount.String(),
+			Fee:            notifyFee(deposit.MaxFeePerGas, deposit.GasPrice),
+			TxType:         deposit.TxType,
+			Confirms:       deposit.Confirms,
+			TokenAddress:   deposit.TokenAddress,
+			TokenId:        deposit.TokenId,
+			TokenMeta:      deposit.TokenMeta,
+			UnlistedToken:  deposit.UnlistedToken,
+			DustAggregated: deposit.DustAggregated,
+			Memo:           deposit.Memo,
+			UserId:         deposit.UserId,
+		}
+		if deposit.MinDeposit != nil && deposit.MinDeposit.Sign() > 0 {
+			txItem.MinDeposit = deposit.MinDeposit.String()
 		}
 		notifyTransactions = append(notifyTransactions, txItem)
 	}
@@ -224,12 +499,14 @@ func (nf *Notifier) BuildNotifyTransaction(deposits []*database.Deposits, withdr
 			FromAddress:  withdraw.FromAddress,
 			ToAddress:    withdraw.ToAddress,
 			Value:        withdraw.Amount.String(),
-			Fee:          withdraw.MaxFeePerGas,
+			Fee:          notifyFee(withdraw.MaxFeePerGas, withdraw.GasPrice),
 			TxType:       withdraw.TxType,
 			Confirms:     0,
 			TokenAddress: withdraw.TokenAddress,
 			TokenId:      withdraw.TokenId,
 			TokenMeta:    withdraw.TokenMeta,
+			Memo:         withdraw.Memo,
+			BatchId:      withdraw.BatchId,
 		}
 		notifyTransactions = append(notifyTransactions, txItem)
 	}
@@ -242,7 +519,7 @@ func (nf *Notifier) BuildNotifyTransaction(deposits []*database.Deposits, withdr
 			FromAddress:  internal.FromAddress,
 			ToAddress:    internal.ToAddress,
 			Value:        internal.Amount.String(),
-			Fee:          internal.MaxFeePerGas,
+			Fee:          notifyFee(internal.MaxFeePerGas, internal.GasPrice),
 			TxType:       internal.TxType,
 			Confirms:     0,
 			TokenAddress: internal.TokenAddress,
@@ -251,6 +528,9 @@ func (nf *Notifier) BuildNotifyTransaction(deposits []*database.Deposits, withdr
 		}
 		notifyTransactions = append(notifyTransactions, txItem)
 	}
+	for _, txItem := range notifyTransactions {
+		nf.formatAmounts(businessId, txItem)
+	}
 	notifyReq := &NotifyRequest{
 		Txn: notifyTransactions,
 	}
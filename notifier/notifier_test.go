@@ -16,7 +16,7 @@ func setupNotifier(t *testing.T) *Notifier {
 	ctx := context.Background()
 	_, cancelCauseFunc := context.WithCancelCause(ctx)
 
-	newNotifier, _ := NewNotifier(db, cancelCauseFunc)
+	newNotifier, _ := NewNotifier(db, cancelCauseFunc, "ethereum", nil)
 
 	return newNotifier
 }
@@ -19,8 +19,107 @@ type Transaction struct {
 	TokenAddress string                   `json:"token_address"`
 	TokenId      string                   `json:"token_id"`
 	TokenMeta    string                   `json:"token_meta"`
+	// Amount 和 FeeAmount 是按代币精度格式化的 Value 和 Fee，代币未登记时为空
+	Amount    string `json:"amount"`
+	Symbol    string `json:"symbol"`
+	Decimals  uint8  `json:"decimals"`
+	FeeAmount string `json:"fee_amount"`
+	FeeSymbol string `json:"fee_symbol"`
+	// UnlistedToken 充值代币未在业务方登记，钱包不入账
+	UnlistedToken bool `json:"unlisted_token"`
+	// MinDeposit 入账时生效的最小充值额，DustAggregated 表示该笔是累计达到最小充值额后入账的零散充值
+	MinDeposit     string `json:"min_deposit"`
+	DustAggregated bool   `json:"dust_aggregated"`
+	// Memo 共用充值地址上区分用户的 memo / destination tag
+	Memo string `json:"memo"`
+	// UserId 充值地址通过 assignAddress 分配时绑定的用户 id，其他地址为空
+	UserId string `json:"user_id"`
+	// BatchId 批量提现的批次 id，multi-send 批次内的条目共用同一个 Hash
+	BatchId string `json:"batch_id,omitempty"`
 }
 
 type NotifyResponse struct {
 	Success bool `json:"success"`
 }
+
+// AddressPoolNotifyRequest 地址池未分配地址（含待生成）低于水位时通知业务方上传公钥
+type AddressPoolNotifyRequest struct {
+	ChainName string `json:"chain_name"`
+	Pending   int64  `json:"pending"`
+	Available int64  `json:"available"`
+	Assigned  int64  `json:"assigned"`
+	Watermark uint64 `json:"watermark"`
+}
+
+// ReconcileNotifyRequest 余额对账差异通知，Difference = ChainBalance - StoredBalance
+type ReconcileNotifyRequest struct {
+	Reconciliations []*Reconciliation `json:"reconciliations"`
+}
+
+type Reconciliation struct {
+	Address       string `json:"address"`
+	TokenAddress  string `json:"token_address"`
+	AddressType   string `json:"address_type"`
+	StoredBalance string `json:"stored_balance"`
+	ChainBalance  string `json:"chain_balance"`
+	Difference    string `json:"difference"`
+	// DifferenceAmount 是按代币精度格式化的 Difference，代币未登记时为空
+	DifferenceAmount string `json:"difference_amount"`
+	Symbol           string `json:"symbol"`
+	Decimals         uint8  `json:"decimals"`
+	Status           string `json:"status"`
+	Timestamp        uint64 `json:"timestamp"`
+}
+
+// LiquidityNotifyRequest 热钱包预计余额低于下限时创建的 cold2hot 补充交易，业务方通过 prepareUnSignTransaction 取得待签名数据后用冷钱包签名
+type LiquidityNotifyRequest struct {
+	ChainName string            `json:"chain_name"`
+	TopUps    []*LiquidityTopUp `json:"top_ups"`
+}
+
+type LiquidityTopUp struct {
+	TransactionId  string `json:"transaction_id"`
+	HotAddress     string `json:"hot_address"`
+	ColdAddress    string `json:"cold_address"`
+	TokenAddress   string `json:"token_address"`
+	Balance        string `json:"balance"`
+	PendingOutflow string `json:"pending_outflow"`
+	PendingInflow  string `json:"pending_inflow"`
+	MinBalance     string `json:"min_balance"`
+	TargetBalance  string `json:"target_balance"`
+	Value          string `json:"value"`
+	// Amount 是按代币精度格式化的 Value，代币未登记时为空
+	Amount    string `json:"amount"`
+	Symbol    string `json:"symbol"`
+	Decimals  uint8  `json:"decimals"`
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// SecurityPriorityHigh 安全通知的优先级，同时放在请求头 X-Notify-Priority 中
+const SecurityPriorityHigh = "high"
+
+// SecurityNotifyRequest 登记地址上出现不是系统创建的转出交易，WithdrawPaused 为 true 时该业务的提现已暂停，需调用 resumeWithdraw 恢复
+type SecurityNotifyRequest struct {
+	ChainName      string             `json:"chain_name"`
+	Priority       string             `json:"priority"`
+	WithdrawPaused bool               `json:"withdraw_paused"`
+	Incidents      []*OutflowIncident `json:"incidents"`
+}
+
+type OutflowIncident struct {
+	Id           string `json:"id"`
+	TxHash       string `json:"tx_hash"`
+	BlockNumber  string `json:"block_number"`
+	FromAddress  string `json:"from_address"`
+	FromRole     string `json:"from_role"`
+	ToAddress    string `json:"to_address"`
+	ToRole       string `json:"to_role"`
+	TokenAddress string `json:"token_address"`
+	TxType       string `json:"tx_type"`
+	Value        string `json:"value"`
+	// Amount 是按代币精度格式化的 Value，代币未登记时为空
+	Amount    string `json:"amount"`
+	Symbol    string `json:"symbol"`
+	Decimals  uint8  `json:"decimals"`
+	Timestamp uint64 `json:"timestamp"`
+}
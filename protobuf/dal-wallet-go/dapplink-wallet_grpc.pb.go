@@ -24,6 +24,24 @@ const (
 	BusinessMiddleWireServices_CreateUnSignTransaction_FullMethodName     = "/syncs.BusinessMiddleWireServices/createUnSignTransaction"
 	BusinessMiddleWireServices_BuildSignedTransaction_FullMethodName      = "/syncs.BusinessMiddleWireServices/buildSignedTransaction"
 	BusinessMiddleWireServices_SetTokenAddress_FullMethodName             = "/syncs.BusinessMiddleWireServices/setTokenAddress"
+	BusinessMiddleWireServices_SuspendBusiness_FullMethodName             = "/syncs.BusinessMiddleWireServices/suspendBusiness"
+	BusinessMiddleWireServices_ActivateBusiness_FullMethodName            = "/syncs.BusinessMiddleWireServices/activateBusiness"
+	BusinessMiddleWireServices_ArchiveBusiness_FullMethodName             = "/syncs.BusinessMiddleWireServices/archiveBusiness"
+	BusinessMiddleWireServices_GetBalanceHistory_FullMethodName           = "/syncs.BusinessMiddleWireServices/getBalanceHistory"
+	BusinessMiddleWireServices_GetBalanceAt_FullMethodName                = "/syncs.BusinessMiddleWireServices/getBalanceAt"
+	BusinessMiddleWireServices_RegisterDepositMemos_FullMethodName        = "/syncs.BusinessMiddleWireServices/registerDepositMemos"
+	BusinessMiddleWireServices_ListSupportedChains_FullMethodName         = "/syncs.BusinessMiddleWireServices/listSupportedChains"
+	BusinessMiddleWireServices_CreateBatchWithdraw_FullMethodName         = "/syncs.BusinessMiddleWireServices/createBatchWithdraw"
+	BusinessMiddleWireServices_GetBatchWithdraw_FullMethodName            = "/syncs.BusinessMiddleWireServices/getBatchWithdraw"
+	BusinessMiddleWireServices_UploadPoolPublicKeys_FullMethodName        = "/syncs.BusinessMiddleWireServices/uploadPoolPublicKeys"
+	BusinessMiddleWireServices_AssignAddress_FullMethodName               = "/syncs.BusinessMiddleWireServices/assignAddress"
+	BusinessMiddleWireServices_RegisterExtendedKey_FullMethodName         = "/syncs.BusinessMiddleWireServices/registerExtendedKey"
+	BusinessMiddleWireServices_DeriveAddresses_FullMethodName             = "/syncs.BusinessMiddleWireServices/deriveAddresses"
+	BusinessMiddleWireServices_RescanExtendedKey_FullMethodName           = "/syncs.BusinessMiddleWireServices/rescanExtendedKey"
+	BusinessMiddleWireServices_GetHotWallets_FullMethodName               = "/syncs.BusinessMiddleWireServices/getHotWallets"
+	BusinessMiddleWireServices_PrepareUnSignTransaction_FullMethodName    = "/syncs.BusinessMiddleWireServices/prepareUnSignTransaction"
+	BusinessMiddleWireServices_PauseWithdraw_FullMethodName               = "/syncs.BusinessMiddleWireServices/pauseWithdraw"
+	BusinessMiddleWireServices_ResumeWithdraw_FullMethodName              = "/syncs.BusinessMiddleWireServices/resumeWithdraw"
 )
 
 // BusinessMiddleWireServicesClient is the client API for BusinessMiddleWireServices service.
@@ -35,6 +53,24 @@ type BusinessMiddleWireServicesClient interface {
 	CreateUnSignTransaction(ctx context.Context, in *UnSignTransactionRequest, opts ...grpc.CallOption) (*UnSignTransactionResponse, error)
 	BuildSignedTransaction(ctx context.Context, in *SignedTransactionRequest, opts ...grpc.CallOption) (*SignedTransactionResponse, error)
 	SetTokenAddress(ctx context.Context, in *SetTokenAddressRequest, opts ...grpc.CallOption) (*SetTokenAddressResponse, error)
+	SuspendBusiness(ctx context.Context, in *BusinessStatusRequest, opts ...grpc.CallOption) (*BusinessStatusResponse, error)
+	ActivateBusiness(ctx context.Context, in *BusinessStatusRequest, opts ...grpc.CallOption) (*BusinessStatusResponse, error)
+	ArchiveBusiness(ctx context.Context, in *BusinessStatusRequest, opts ...grpc.CallOption) (*BusinessStatusResponse, error)
+	GetBalanceHistory(ctx context.Context, in *BalanceHistoryRequest, opts ...grpc.CallOption) (*BalanceHistoryResponse, error)
+	GetBalanceAt(ctx context.Context, in *BalanceAtRequest, opts ...grpc.CallOption) (*BalanceAtResponse, error)
+	RegisterDepositMemos(ctx context.Context, in *RegisterDepositMemosRequest, opts ...grpc.CallOption) (*RegisterDepositMemosResponse, error)
+	ListSupportedChains(ctx context.Context, in *SupportedChainsRequest, opts ...grpc.CallOption) (*SupportedChainsResponse, error)
+	CreateBatchWithdraw(ctx context.Context, in *BatchWithdrawRequest, opts ...grpc.CallOption) (*BatchWithdrawResponse, error)
+	GetBatchWithdraw(ctx context.Context, in *BatchWithdrawStatusRequest, opts ...grpc.CallOption) (*BatchWithdrawStatusResponse, error)
+	UploadPoolPublicKeys(ctx context.Context, in *UploadPoolPublicKeysRequest, opts ...grpc.CallOption) (*UploadPoolPublicKeysResponse, error)
+	AssignAddress(ctx context.Context, in *AssignAddressRequest, opts ...grpc.CallOption) (*AssignAddressResponse, error)
+	RegisterExtendedKey(ctx context.Context, in *RegisterExtendedKeyRequest, opts ...grpc.CallOption) (*RegisterExtendedKeyResponse, error)
+	DeriveAddresses(ctx context.Context, in *DeriveAddressesRequest, opts ...grpc.CallOption) (*DeriveAddressesResponse, error)
+	RescanExtendedKey(ctx context.Context, in *RescanExtendedKeyRequest, opts ...grpc.CallOption) (*RescanExtendedKeyResponse, error)
+	GetHotWallets(ctx context.Context, in *HotWalletsRequest, opts ...grpc.CallOption) (*HotWalletsResponse, error)
+	PrepareUnSignTransaction(ctx context.Context, in *PrepareUnSignTransactionRequest, opts ...grpc.CallOption) (*UnSignTransactionResponse, error)
+	PauseWithdraw(ctx context.Context, in *BusinessStatusRequest, opts ...grpc.CallOption) (*WithdrawPauseResponse, error)
+	ResumeWithdraw(ctx context.Context, in *BusinessStatusRequest, opts ...grpc.CallOption) (*WithdrawPauseResponse, error)
 }
 
 type businessMiddleWireServicesClient struct {
@@ -95,6 +131,186 @@ func (c *businessMiddleWireServicesClient) SetTokenAddress(ctx context.Context,
 	return out, nil
 }
 
+func (c *businessMiddleWireServicesClient) SuspendBusiness(ctx context.Context, in *BusinessStatusRequest, opts ...grpc.CallOption) (*BusinessStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BusinessStatusResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_SuspendBusiness_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) ActivateBusiness(ctx context.Context, in *BusinessStatusRequest, opts ...grpc.CallOption) (*BusinessStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BusinessStatusResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_ActivateBusiness_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) ArchiveBusiness(ctx context.Context, in *BusinessStatusRequest, opts ...grpc.CallOption) (*BusinessStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BusinessStatusResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_ArchiveBusiness_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) GetBalanceHistory(ctx context.Context, in *BalanceHistoryRequest, opts ...grpc.CallOption) (*BalanceHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BalanceHistoryResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_GetBalanceHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) GetBalanceAt(ctx context.Context, in *BalanceAtRequest, opts ...grpc.CallOption) (*BalanceAtResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BalanceAtResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_GetBalanceAt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) RegisterDepositMemos(ctx context.Context, in *RegisterDepositMemosRequest, opts ...grpc.CallOption) (*RegisterDepositMemosResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterDepositMemosResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_RegisterDepositMemos_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) ListSupportedChains(ctx context.Context, in *SupportedChainsRequest, opts ...grpc.CallOption) (*SupportedChainsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SupportedChainsResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_ListSupportedChains_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) CreateBatchWithdraw(ctx context.Context, in *BatchWithdrawRequest, opts ...grpc.CallOption) (*BatchWithdrawResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchWithdrawResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_CreateBatchWithdraw_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) GetBatchWithdraw(ctx context.Context, in *BatchWithdrawStatusRequest, opts ...grpc.CallOption) (*BatchWithdrawStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchWithdrawStatusResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_GetBatchWithdraw_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) UploadPoolPublicKeys(ctx context.Context, in *UploadPoolPublicKeysRequest, opts ...grpc.CallOption) (*UploadPoolPublicKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadPoolPublicKeysResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_UploadPoolPublicKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) AssignAddress(ctx context.Context, in *AssignAddressRequest, opts ...grpc.CallOption) (*AssignAddressResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignAddressResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_AssignAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) RegisterExtendedKey(ctx context.Context, in *RegisterExtendedKeyRequest, opts ...grpc.CallOption) (*RegisterExtendedKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterExtendedKeyResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_RegisterExtendedKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) DeriveAddresses(ctx context.Context, in *DeriveAddressesRequest, opts ...grpc.CallOption) (*DeriveAddressesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeriveAddressesResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_DeriveAddresses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) RescanExtendedKey(ctx context.Context, in *RescanExtendedKeyRequest, opts ...grpc.CallOption) (*RescanExtendedKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RescanExtendedKeyResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_RescanExtendedKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) GetHotWallets(ctx context.Context, in *HotWalletsRequest, opts ...grpc.CallOption) (*HotWalletsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HotWalletsResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_GetHotWallets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) PrepareUnSignTransaction(ctx context.Context, in *PrepareUnSignTransactionRequest, opts ...grpc.CallOption) (*UnSignTransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnSignTransactionResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_PrepareUnSignTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) PauseWithdraw(ctx context.Context, in *BusinessStatusRequest, opts ...grpc.CallOption) (*WithdrawPauseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WithdrawPauseResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_PauseWithdraw_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServicesClient) ResumeWithdraw(ctx context.Context, in *BusinessStatusRequest, opts ...grpc.CallOption) (*WithdrawPauseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WithdrawPauseResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireServices_ResumeWithdraw_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BusinessMiddleWireServicesServer is the server API for BusinessMiddleWireServices service.
 // All implementations should embed UnimplementedBusinessMiddleWireServicesServer
 // for forward compatibility.
@@ -104,6 +320,24 @@ type BusinessMiddleWireServicesServer interface {
 	CreateUnSignTransaction(context.Context, *UnSignTransactionRequest) (*UnSignTransactionResponse, error)
 	BuildSignedTransaction(context.Context, *SignedTransactionRequest) (*SignedTransactionResponse, error)
 	SetTokenAddress(context.Context, *SetTokenAddressRequest) (*SetTokenAddressResponse, error)
+	SuspendBusiness(context.Context, *BusinessStatusRequest) (*BusinessStatusResponse, error)
+	ActivateBusiness(context.Context, *BusinessStatusRequest) (*BusinessStatusResponse, error)
+	ArchiveBusiness(context.Context, *BusinessStatusRequest) (*BusinessStatusResponse, error)
+	GetBalanceHistory(context.Context, *BalanceHistoryRequest) (*BalanceHistoryResponse, error)
+	GetBalanceAt(context.Context, *BalanceAtRequest) (*BalanceAtResponse, error)
+	RegisterDepositMemos(context.Context, *RegisterDepositMemosRequest) (*RegisterDepositMemosResponse, error)
+	ListSupportedChains(context.Context, *SupportedChainsRequest) (*SupportedChainsResponse, error)
+	CreateBatchWithdraw(context.Context, *BatchWithdrawRequest) (*BatchWithdrawResponse, error)
+	GetBatchWithdraw(context.Context, *BatchWithdrawStatusRequest) (*BatchWithdrawStatusResponse, error)
+	UploadPoolPublicKeys(context.Context, *UploadPoolPublicKeysRequest) (*UploadPoolPublicKeysResponse, error)
+	AssignAddress(context.Context, *AssignAddressRequest) (*AssignAddressResponse, error)
+	RegisterExtendedKey(context.Context, *RegisterExtendedKeyRequest) (*RegisterExtendedKeyResponse, error)
+	DeriveAddresses(context.Context, *DeriveAddressesRequest) (*DeriveAddressesResponse, error)
+	RescanExtendedKey(context.Context, *RescanExtendedKeyRequest) (*RescanExtendedKeyResponse, error)
+	GetHotWallets(context.Context, *HotWalletsRequest) (*HotWalletsResponse, error)
+	PrepareUnSignTransaction(context.Context, *PrepareUnSignTransactionRequest) (*UnSignTransactionResponse, error)
+	PauseWithdraw(context.Context, *BusinessStatusRequest) (*WithdrawPauseResponse, error)
+	ResumeWithdraw(context.Context, *BusinessStatusRequest) (*WithdrawPauseResponse, error)
 }
 
 // UnimplementedBusinessMiddleWireServicesServer should be embedded to have
@@ -128,6 +362,60 @@ func (UnimplementedBusinessMiddleWireServicesServer) BuildSignedTransaction(cont
 func (UnimplementedBusinessMiddleWireServicesServer) SetTokenAddress(context.Context, *SetTokenAddressRequest) (*SetTokenAddressResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetTokenAddress not implemented")
 }
+func (UnimplementedBusinessMiddleWireServicesServer) SuspendBusiness(context.Context, *BusinessStatusRequest) (*BusinessStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuspendBusiness not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) ActivateBusiness(context.Context, *BusinessStatusRequest) (*BusinessStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ActivateBusiness not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) ArchiveBusiness(context.Context, *BusinessStatusRequest) (*BusinessStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveBusiness not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) GetBalanceHistory(context.Context, *BalanceHistoryRequest) (*BalanceHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalanceHistory not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) GetBalanceAt(context.Context, *BalanceAtRequest) (*BalanceAtResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalanceAt not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) RegisterDepositMemos(context.Context, *RegisterDepositMemosRequest) (*RegisterDepositMemosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterDepositMemos not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) ListSupportedChains(context.Context, *SupportedChainsRequest) (*SupportedChainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSupportedChains not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) CreateBatchWithdraw(context.Context, *BatchWithdrawRequest) (*BatchWithdrawResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBatchWithdraw not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) GetBatchWithdraw(context.Context, *BatchWithdrawStatusRequest) (*BatchWithdrawStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBatchWithdraw not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) UploadPoolPublicKeys(context.Context, *UploadPoolPublicKeysRequest) (*UploadPoolPublicKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadPoolPublicKeys not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) AssignAddress(context.Context, *AssignAddressRequest) (*AssignAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignAddress not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) RegisterExtendedKey(context.Context, *RegisterExtendedKeyRequest) (*RegisterExtendedKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterExtendedKey not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) DeriveAddresses(context.Context, *DeriveAddressesRequest) (*DeriveAddressesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeriveAddresses not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) RescanExtendedKey(context.Context, *RescanExtendedKeyRequest) (*RescanExtendedKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RescanExtendedKey not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) GetHotWallets(context.Context, *HotWalletsRequest) (*HotWalletsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHotWallets not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) PrepareUnSignTransaction(context.Context, *PrepareUnSignTransactionRequest) (*UnSignTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrepareUnSignTransaction not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) PauseWithdraw(context.Context, *BusinessStatusRequest) (*WithdrawPauseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseWithdraw not implemented")
+}
+func (UnimplementedBusinessMiddleWireServicesServer) ResumeWithdraw(context.Context, *BusinessStatusRequest) (*WithdrawPauseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeWithdraw not implemented")
+}
 func (UnimplementedBusinessMiddleWireServicesServer) testEmbeddedByValue() {}
 
 // UnsafeBusinessMiddleWireServicesServer may be embedded to opt out of forward compatibility for this service.
@@ -238,6 +526,330 @@ func _BusinessMiddleWireServices_SetTokenAddress_Handler(srv interface{}, ctx co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BusinessMiddleWireServices_SuspendBusiness_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BusinessStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).SuspendBusiness(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_SuspendBusiness_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).SuspendBusiness(ctx, req.(*BusinessStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_ActivateBusiness_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BusinessStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).ActivateBusiness(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_ActivateBusiness_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).ActivateBusiness(ctx, req.(*BusinessStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_ArchiveBusiness_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BusinessStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).ArchiveBusiness(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_ArchiveBusiness_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).ArchiveBusiness(ctx, req.(*BusinessStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_GetBalanceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).GetBalanceHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_GetBalanceHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).GetBalanceHistory(ctx, req.(*BalanceHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_GetBalanceAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).GetBalanceAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_GetBalanceAt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).GetBalanceAt(ctx, req.(*BalanceAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_RegisterDepositMemos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterDepositMemosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).RegisterDepositMemos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_RegisterDepositMemos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).RegisterDepositMemos(ctx, req.(*RegisterDepositMemosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_ListSupportedChains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SupportedChainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).ListSupportedChains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_ListSupportedChains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).ListSupportedChains(ctx, req.(*SupportedChainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_CreateBatchWithdraw_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchWithdrawRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).CreateBatchWithdraw(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_CreateBatchWithdraw_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).CreateBatchWithdraw(ctx, req.(*BatchWithdrawRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_GetBatchWithdraw_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchWithdrawStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).GetBatchWithdraw(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_GetBatchWithdraw_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).GetBatchWithdraw(ctx, req.(*BatchWithdrawStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_UploadPoolPublicKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadPoolPublicKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).UploadPoolPublicKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_UploadPoolPublicKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).UploadPoolPublicKeys(ctx, req.(*UploadPoolPublicKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_AssignAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).AssignAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_AssignAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).AssignAddress(ctx, req.(*AssignAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_RegisterExtendedKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterExtendedKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).RegisterExtendedKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_RegisterExtendedKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).RegisterExtendedKey(ctx, req.(*RegisterExtendedKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_DeriveAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeriveAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).DeriveAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_DeriveAddresses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).DeriveAddresses(ctx, req.(*DeriveAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_RescanExtendedKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RescanExtendedKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).RescanExtendedKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_RescanExtendedKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).RescanExtendedKey(ctx, req.(*RescanExtendedKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_GetHotWallets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HotWalletsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).GetHotWallets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_GetHotWallets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).GetHotWallets(ctx, req.(*HotWalletsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_PrepareUnSignTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrepareUnSignTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).PrepareUnSignTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_PrepareUnSignTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).PrepareUnSignTransaction(ctx, req.(*PrepareUnSignTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_PauseWithdraw_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BusinessStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).PauseWithdraw(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_PauseWithdraw_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).PauseWithdraw(ctx, req.(*BusinessStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireServices_ResumeWithdraw_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BusinessStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServicesServer).ResumeWithdraw(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireServices_ResumeWithdraw_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServicesServer).ResumeWithdraw(ctx, req.(*BusinessStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BusinessMiddleWireServices_ServiceDesc is the grpc.ServiceDesc for BusinessMiddleWireServices service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -265,6 +877,78 @@ var BusinessMiddleWireServices_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "setTokenAddress",
 			Handler:    _BusinessMiddleWireServices_SetTokenAddress_Handler,
 		},
+		{
+			MethodName: "suspendBusiness",
+			Handler:    _BusinessMiddleWireServices_SuspendBusiness_Handler,
+		},
+		{
+			MethodName: "activateBusiness",
+			Handler:    _BusinessMiddleWireServices_ActivateBusiness_Handler,
+		},
+		{
+			MethodName: "archiveBusiness",
+			Handler:    _BusinessMiddleWireServices_ArchiveBusiness_Handler,
+		},
+		{
+			MethodName: "getBalanceHistory",
+			Handler:    _BusinessMiddleWireServices_GetBalanceHistory_Handler,
+		},
+		{
+			MethodName: "getBalanceAt",
+			Handler:    _BusinessMiddleWireServices_GetBalanceAt_Handler,
+		},
+		{
+			MethodName: "registerDepositMemos",
+			Handler:    _BusinessMiddleWireServices_RegisterDepositMemos_Handler,
+		},
+		{
+			MethodName: "listSupportedChains",
+			Handler:    _BusinessMiddleWireServices_ListSupportedChains_Handler,
+		},
+		{
+			MethodName: "createBatchWithdraw",
+			Handler:    _BusinessMiddleWireServices_CreateBatchWithdraw_Handler,
+		},
+		{
+			MethodName: "getBatchWithdraw",
+			Handler:    _BusinessMiddleWireServices_GetBatchWithdraw_Handler,
+		},
+		{
+			MethodName: "uploadPoolPublicKeys",
+			Handler:    _BusinessMiddleWireServices_UploadPoolPublicKeys_Handler,
+		},
+		{
+			MethodName: "assignAddress",
+			Handler:    _BusinessMiddleWireServices_AssignAddress_Handler,
+		},
+		{
+			MethodName: "registerExtendedKey",
+			Handler:    _BusinessMiddleWireServices_RegisterExtendedKey_Handler,
+		},
+		{
+			MethodName: "deriveAddresses",
+			Handler:    _BusinessMiddleWireServices_DeriveAddresses_Handler,
+		},
+		{
+			MethodName: "rescanExtendedKey",
+			Handler:    _BusinessMiddleWireServices_RescanExtendedKey_Handler,
+		},
+		{
+			MethodName: "getHotWallets",
+			Handler:    _BusinessMiddleWireServices_GetHotWallets_Handler,
+		},
+		{
+			MethodName: "prepareUnSignTransaction",
+			Handler:    _BusinessMiddleWireServices_PrepareUnSignTransaction_Handler,
+		},
+		{
+			MethodName: "pauseWithdraw",
+			Handler:    _BusinessMiddleWireServices_PauseWithdraw_Handler,
+		},
+		{
+			MethodName: "resumeWithdraw",
+			Handler:    _BusinessMiddleWireServices_ResumeWithdraw_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "dapplink-wallet.proto",
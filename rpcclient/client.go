@@ -2,6 +2,7 @@ package rpcclient
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"strconv"
 
@@ -15,12 +16,13 @@ import (
 type WalletChainAccountClient struct {
 	Ctx             context.Context
 	ChainName       string
+	Network         string
 	AccountRpClient account.WalletAccountServiceClient
 }
 
-func NewWalletChainAccountClient(ctx context.Context, rpc account.WalletAccountServiceClient, chainName string) (*WalletChainAccountClient, error) {
-	log.Info("New account chain rpc client", "chainName", chainName)
-	return &WalletChainAccountClient{Ctx: ctx, AccountRpClient: rpc, ChainName: chainName}, nil
+func NewWalletChainAccountClient(ctx context.Context, rpc account.WalletAccountServiceClient, chainName, network string) (*WalletChainAccountClient, error) {
+	log.Info("New account chain rpc client", "chainName", chainName, "network", network)
+	return &WalletChainAccountClient{Ctx: ctx, AccountRpClient: rpc, ChainName: chainName, Network: network}, nil
 }
 
 func (wac *WalletChainAccountClient) ExportAddressByPubKey(typeOrVersion, publicKey string) string {
@@ -50,7 +52,7 @@ func (wac *WalletChainAccountClient) GetBlockHeader(number *big.Int) (*BlockHead
 	}
 	req := &account.BlockHeaderNumberRequest{
 		Chain:   wac.ChainName,
-		Network: "mainnet",
+		Network: wac.Network,
 		Height:  height,
 	}
 	blockHeader, err := wac.AccountRpClient.GetBlockHeaderByNumber(wac.Ctx, req)
@@ -93,7 +95,7 @@ func (wac *WalletChainAccountClient) GetBlockInfo(blockNumber *big.Int) ([]*acco
 func (wac *WalletChainAccountClient) GetTransactionByHash(hash string) (*account.TxMessage, error) {
 	req := &account.TxHashRequest{
 		Chain:   wac.ChainName,
-		Network: "mainnet",
+		Network: wac.Network,
 		Hash:    hash,
 	}
 	txInfo, err := wac.AccountRpClient.GetTxByHash(wac.Ctx, req)
@@ -111,7 +113,7 @@ func (wac *WalletChainAccountClient) GetTransactionByHash(hash string) (*account
 func (wac *WalletChainAccountClient) GetAccountAccountNumber(address string) (int, error) {
 	req := &account.AccountRequest{
 		Chain:   wac.ChainName,
-		Network: "mainnet",
+		Network: wac.Network,
 		Address: address,
 	}
 	accountInfo, err := wac.AccountRpClient.GetAccount(wac.Ctx, req)
@@ -125,7 +127,7 @@ func (wac *WalletChainAccountClient) GetAccountAccountNumber(address string) (in
 func (wac *WalletChainAccountClient) GetAccount(address string) (int, int, int) {
 	req := &account.AccountRequest{
 		Chain:           wac.ChainName,
-		Network:         "mainnet",
+		Network:         wac.Network,
 		Address:         address,
 		ContractAddress: "0x00",
 	}
@@ -162,11 +164,91 @@ func (wac *WalletChainAccountClient) GetAccount(address string) (int, int, int)
 	return accountNumber, sequence, balance
 }
 
+// GetAccountBalance 查询地址在链上的余额，contractAddress 为空或零地址时查询主币余额
+func (wac *WalletChainAccountClient) GetAccountBalance(address, contractAddress string) (*big.Int, error) {
+	if contractAddress == "" || contractAddress == (common2.Address{}).String() {
+		contractAddress = "0x00"
+	}
+	req := &account.AccountRequest{
+		Chain:           wac.ChainName,
+		Network:         wac.Network,
+		Address:         address,
+		ContractAddress: contractAddress,
+	}
+	accountInfo, err := wac.AccountRpClient.GetAccount(wac.Ctx, req)
+	if err != nil {
+		log.Error("get account balance fail", "address", address, "err", err)
+		return nil, err
+	}
+	if accountInfo.Code == common.ReturnCode_ERROR {
+		log.Error("get account balance fail", "address", address, "msg", accountInfo.Msg)
+		return nil, fmt.Errorf("get account balance fail: %s", accountInfo.Msg)
+	}
+	balance, ok := new(big.Int).SetString(accountInfo.Balance, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid account balance: %q", accountInfo.Balance)
+	}
+	return balance, nil
+}
+
+// HasTxHistory 地址在链上是否有过交易，余额被归集为 0 的地址仍然算作使用过
+func (wac *WalletChainAccountClient) HasTxHistory(address string) (bool, error) {
+	req := &account.TxAddressRequest{
+		Chain:    wac.ChainName,
+		Network:  wac.Network,
+		Address:  address,
+		Page:     1,
+		Pagesize: 1,
+	}
+	txInfo, err := wac.AccountRpClient.GetTxByAddress(wac.Ctx, req)
+	if err != nil {
+		log.Error("get tx by address fail", "address", address, "err", err)
+		return false, err
+	}
+	if txInfo.Code == common.ReturnCode_ERROR {
+		log.Error("get tx by address fail", "address", address, "msg", txInfo.Msg)
+		return false, fmt.Errorf("get tx by address fail: %s", txInfo.Msg)
+	}
+	return len(txInfo.Tx) > 0, nil
+}
+
+// LatestTxHeight 地址最近一页链上交易中最大的区块高度，没有交易时返回 nil
+func (wac *WalletChainAccountClient) LatestTxHeight(address, contractAddress string) (*big.Int, error) {
+	req := &account.TxAddressRequest{
+		Chain:           wac.ChainName,
+		Network:         wac.Network,
+		Address:         address,
+		ContractAddress: contractAddress,
+		Page:            1,
+		Pagesize:        10,
+	}
+	txInfo, err := wac.AccountRpClient.GetTxByAddress(wac.Ctx, req)
+	if err != nil {
+		log.Error("get tx by address fail", "address", address, "err", err)
+		return nil, err
+	}
+	if txInfo.Code == common.ReturnCode_ERROR {
+		log.Error("get tx by address fail", "address", address, "msg", txInfo.Msg)
+		return nil, fmt.Errorf("get tx by address fail: %s", txInfo.Msg)
+	}
+	var latest *big.Int
+	for _, tx := range txInfo.Tx {
+		height, ok := new(big.Int).SetString(tx.Height, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid tx height: %q", tx.Height)
+		}
+		if latest == nil || height.Cmp(latest) > 0 {
+			latest = height
+		}
+	}
+	return latest, nil
+}
+
 func (wac *WalletChainAccountClient) SendTx(rawTx string) (string, error) {
 	log.Info("Send transaction", "rawTx", rawTx, "ChainName", wac.ChainName)
 	req := &account.SendTxRequest{
 		Chain:   wac.ChainName,
-		Network: "mainnet",
+		Network: wac.Network,
 		RawTx:   rawTx,
 	}
 	txInfo, err := wac.AccountRpClient.SendTx(wac.Ctx, req)
@@ -184,7 +266,7 @@ func (wac *WalletChainAccountClient) SendTx(rawTx string) (string, error) {
 func (wac *WalletChainAccountClient) ValidAddress(address string) bool {
 	req := &account.ValidAddressRequest{
 		Chain:   wac.ChainName,
-		Network: "mainnet",
+		Network: wac.Network,
 		Address: address,
 	}
 	response, err := wac.AccountRpClient.ValidAddress(wac.Ctx, req)
@@ -0,0 +1,51 @@
+package rpcclient
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/dapplink-labs/multichain-sync-account/common/tlsutil"
+	"github.com/dapplink-labs/multichain-sync-account/config"
+)
+
+// DialChainAccount 连接 wallet-chain-account 服务，开启 TLS 时使用固定的 CA 校验服务端证书
+func DialChainAccount(target string, tlsConfig config.TLSConfig) (*grpc.ClientConn, error) {
+	transportCredentials := insecure.NewCredentials()
+	if tlsConfig.Enable {
+		serverName := tlsConfig.ServerName
+		if serverName == "" {
+			serverName = targetHost(target)
+		}
+		tlsCfg, err := tlsutil.NewClientTLSConfig(tlsutil.ClientConfig{
+			CAFile:     tlsConfig.CAFile,
+			CertFile:   tlsConfig.CertFile,
+			KeyFile:    tlsConfig.KeyFile,
+			ServerName: serverName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("build chain account tls config fail: %w", err)
+		}
+		transportCredentials = credentials.NewTLS(tlsCfg)
+	} else {
+		log.Warn("chain account rpc connection is not encrypted", "target", target)
+	}
+	return grpc.NewClient(target, grpc.WithTransportCredentials(transportCredentials))
+}
+
+// targetHost 从 host:port 形式的 target 中取出主机名，用于校验服务端证书
+func targetHost(target string) string {
+	if i := strings.LastIndex(target, "/"); i >= 0 {
+		target = target[i+1:]
+	}
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		return target
+	}
+	return host
+}
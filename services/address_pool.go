@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	dal_wallet_go "github.com/dapplink-labs/multichain-sync-account/protobuf/dal-wallet-go"
+)
+
+const maxPoolKeysPerUpload = 5000
+
+// UploadPoolPublicKeys 批量上传公钥到地址池，地址由 address pool worker 异步生成，重复的公钥忽略
+func (bws *BusinessMiddleWireServices) UploadPoolPublicKeys(ctx context.Context, request *dal_wallet_go.UploadPoolPublicKeysRequest) (*dal_wallet_go.UploadPoolPublicKeysResponse, error) {
+	response := &dal_wallet_go.UploadPoolPublicKeysResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" || len(request.PublicKeys) == 0 {
+		response.Msg = "invalid params"
+		return response, nil
+	}
+	if len(request.PublicKeys) > maxPoolKeysPerUpload {
+		response.Msg = fmt.Sprintf("too many public keys, upload at most %d at a time", maxPoolKeysPerUpload)
+		return response, nil
+	}
+	if err := bws.checkBusinessActive(request.RequestId); err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
+
+	poolList := make([]*database.AddressPool, 0, len(request.PublicKeys))
+	seen := make(map[string]bool, len(request.PublicKeys))
+	for _, publicKey := range request.PublicKeys {
+		publicKey = strings.TrimSpace(publicKey)
+		if publicKey == "" {
+			response.Msg = "public key cannot be empty"
+			return response, nil
+		}
+		if seen[publicKey] {
+			continue
+		}
+		seen[publicKey] = true
+		poolList = append(poolList, &database.AddressPool{
+			GUID:          uuid.New(),
+			PublicKey:     publicKey,
+			AddressFormat: strings.ToLower(strings.TrimSpace(request.AddressFormat)),
+			Status:        database.AddressPoolPending,
+			Timestamp:     uint64(time.Now().Unix()),
+		})
+	}
+	accepted, err := bws.db.AddressPool.StorePoolKeys(request.RequestId, bws.chainName, poolList)
+	if err != nil {
+		log.Error("store address pool keys fail", "err", err)
+		response.Msg = "store public keys fail"
+		return response, nil
+	}
+	stats, err := bws.addressPoolStats(request.RequestId)
+	if err != nil {
+		log.Error("query address pool stats fail", "err", err)
+	}
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "upload pool public keys success"
+	response.Accepted = uint64(accepted)
+	response.Stats = stats
+	return response, nil
+}
+
+// AssignAddress 从地址池分配一个地址给业务方的用户，同一个用户重复请求返回已分配的地址
+func (bws *BusinessMiddleWireServices) AssignAddress(ctx context.Context, request *dal_wallet_go.AssignAddressRequest) (*dal_wallet_go.AssignAddressResponse, error) {
+	response := &dal_wallet_go.AssignAddressResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	userId := strings.TrimSpace(request.UserId)
+	if request.RequestId == "" || userId == "" {
+		response.Msg = "invalid params"
+		return response, nil
+	}
+	if err := bws.checkBusinessActive(request.RequestId); err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
+
+	pool, err := bws.db.AddressPool.QueryPoolAddressByUserId(request.RequestId, bws.chainName, userId)
+	if err != nil {
+		return nil, err
+	}
+	if pool == nil {
+		if err := bws.db.Transaction(func(tx *database.DB) error {
+			pool, err = tx.AddressPool.AssignPoolAddress(request.RequestId, bws.chainName, userId)
+			if err != nil || pool == nil {
+				return err
+			}
+			return tx.Addresses.UpdateAddressUserId(request.RequestId, bws.chainName, pool.Address, userId)
+		}); err != nil {
+			// 同一用户的并发请求违反唯一索引时，返回另一个请求分配的地址
+			existing, queryErr := bws.db.AddressPool.QueryPoolAddressByUserId(request.RequestId, bws.chainName, userId)
+			if queryErr != nil || existing == nil {
+				log.Error("assign pool address fail", "userId", userId, "err", err)
+				return nil, fmt.Errorf("assign address failed: %w", err)
+			}
+			pool = existing
+		}
+	}
+
+	stats, err := bws.addressPoolStats(request.RequestId)
+	if err != nil {
+		log.Error("query address pool stats fail", "err", err)
+	}
+	response.Stats = stats
+	if pool == nil {
+		response.Msg = "address pool is empty, upload more public keys"
+		return response, nil
+	}
+	log.Info("assign pool address", "businessId", request.RequestId, "userId", userId, "address", pool.Address)
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "assign address success"
+	response.Address = pool.Address
+	response.UserId = userId
+	return response, nil
+}
+
+func (bws *BusinessMiddleWireServices) addressPoolStats(requestId string) (*dal_wallet_go.AddressPoolStats, error) {
+	stats, err := bws.db.AddressPool.QueryAddressPoolStats(requestId, bws.chainName)
+	if err != nil {
+		return nil, err
+	}
+	business, err := bws.db.Business.QueryBusinessByUuid(requestId)
+	if err != nil {
+		return nil, err
+	}
+	return &dal_wallet_go.AddressPoolStats{
+		Pending:   uint64(stats.Pending),
+		Available: uint64(stats.Available),
+		Assigned:  uint64(stats.Assigned),
+		Failed:    uint64(stats.Failed),
+		Watermark: business.AddressPoolWatermark,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/dapplink-labs/multichain-sync-account/common/tlsutil"
+)
+
+type identityKey struct{}
+
+// IdentityFromContext 返回调用方客户端证书中的身份，未使用客户端证书时返回 nil
+func IdentityFromContext(ctx context.Context) *tlsutil.Identity {
+	identity, _ := ctx.Value(identityKey{}).(*tlsutil.Identity)
+	return identity
+}
+
+func peerIdentity(ctx context.Context) *tlsutil.Identity {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return tlsutil.IdentityFromCertificate(tlsInfo.State.VerifiedChains[0][0])
+}
+
+// identityInterceptor 将客户端证书身份写入 context，配置了 allowedClients 时拒绝不在列表中的调用方
+func identityInterceptor(allowedClients []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		identity := peerIdentity(ctx)
+		if len(allowedClients) > 0 && !identity.Matches(allowedClients) {
+			log.Warn("reject rpc call from unauthorized client", "method", info.FullMethod, "identity", identity.Names())
+			return nil, status.Error(codes.PermissionDenied, "client certificate is not allowed")
+		}
+		if identity != nil {
+			ctx = context.WithValue(ctx, identityKey{}, identity)
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/dapplink-labs/multichain-sync-account/common/bigint"
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	dal_wallet_go "github.com/dapplink-labs/multichain-sync-account/protobuf/dal-wallet-go"
+)
+
+const (
+	defaultHistoryPageSize = 20
+	maxHistoryPageSize     = 1000
+)
+
+func (bws *BusinessMiddleWireServices) GetBalanceHistory(ctx context.Context, request *dal_wallet_go.BalanceHistoryRequest) (*dal_wallet_go.BalanceHistoryResponse, error) {
+	response := &dal_wallet_go.BalanceHistoryResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" || request.Address == "" || request.TokenAddress == "" {
+		response.Msg = "invalid params"
+		return response, nil
+	}
+	page, pageSize := int(request.Page), int(request.PageSize)
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultHistoryPageSize
+	}
+	if pageSize > maxHistoryPageSize {
+		pageSize = maxHistoryPageSize
+	}
+
+	journals, total, err := bws.db.BalanceJournals.QueryBalanceHistory(request.RequestId, bws.chainName, request.Address, request.TokenAddress, page, pageSize)
+	if err != nil {
+		log.Error("query balance history fail", "address", request.Address, "err", err)
+		response.Msg = "query balance history fail"
+		return response, nil
+	}
+	meta := bws.tokenMeta(request.RequestId, request.TokenAddress)
+	if meta != nil {
+		response.Symbol = meta.Symbol
+		response.Decimals = uint32(meta.Decimals)
+	}
+	for _, journal := range journals {
+		response.Journals = append(response.Journals, toBalanceJournal(journal, meta))
+	}
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "query balance history success"
+	response.Total = uint64(total)
+	return response, nil
+}
+
+func (bws *BusinessMiddleWireServices) GetBalanceAt(ctx context.Context, request *dal_wallet_go.BalanceAtRequest) (*dal_wallet_go.BalanceAtResponse, error) {
+	response := &dal_wallet_go.BalanceAtResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" || request.Address == "" || request.TokenAddress == "" {
+		response.Msg = "invalid params"
+		return response, nil
+	}
+
+	var (
+		balance *database.PointInTimeBalance
+		err     error
+	)
+	switch {
+	case request.BlockNumber != "":
+		blockNumber, ok := new(big.Int).SetString(request.BlockNumber, 10)
+		if !ok || blockNumber.Sign() < 0 {
+			response.Msg = "invalid block number"
+			return response, nil
+		}
+		balance, err = bws.db.BalanceJournals.QueryBalanceAtBlock(request.RequestId, bws.chainName, request.Address, request.TokenAddress, blockNumber)
+	case request.Timestamp > 0:
+		balance, err = bws.db.BalanceJournals.QueryBalanceAtTime(request.RequestId, bws.chainName, request.Address, request.TokenAddress, request.Timestamp)
+	default:
+		response.Msg = "block number or timestamp is required"
+		return response, nil
+	}
+	if err != nil {
+		log.Error("query balance at fail", "address", request.Address, "err", err)
+		response.Msg = "query balance fail"
+		return response, nil
+	}
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "query balance success"
+	response.Balance = balance.Balance.String()
+	response.LockBalance = balance.LockBalance.String()
+	response.Found = balance.Found
+	if meta := bws.tokenMeta(request.RequestId, request.TokenAddress); meta != nil {
+		response.Symbol = meta.Symbol
+		response.Decimals = uint32(meta.Decimals)
+		response.FormattedBalance = bigint.FormatUnits(balance.Balance, meta.Decimals)
+		response.FormattedLockBalance = bigint.FormatUnits(balance.LockBalance, meta.Decimals)
+	}
+	return response, nil
+}
+
+// tokenMeta 查询失败或代币未登记时返回 nil，响应中只返回原始金额
+func (bws *BusinessMiddleWireServices) tokenMeta(requestId string, tokenAddress string) *database.TokenMeta {
+	meta, err := database.ResolveTokenMeta(bws.db.Tokens, requestId, bws.chainName, tokenAddress)
+	if err != nil {
+		log.Warn("resolve token meta fail", "token", tokenAddress, "err", err)
+		return nil
+	}
+	return meta
+}
+
+func toBalanceJournal(journal *database.BalanceJournals, meta *database.TokenMeta) *dal_wallet_go.BalanceJournal {
+	item := &dal_wallet_go.BalanceJournal{
+		Guid:             journal.GUID.String(),
+		EntryGroup:       journal.EntryGroup.String(),
+		Bucket:           string(journal.Bucket),
+		Direction:        string(journal.Direction),
+		EntryType:        string(journal.EntryType),
+		Amount:           journal.Amount.String(),
+		BalanceAfter:     journal.BalanceAfter.String(),
+		LockBalanceAfter: journal.LockBalanceAfter.String(),
+		TxHash:           journal.TxHash,
+		Timestamp:        journal.Timestamp,
+	}
+	if journal.BlockNumber != nil {
+		item.BlockNumber = journal.BlockNumber.String()
+	}
+	if meta != nil {
+		item.FormattedAmount = bigint.FormatUnits(journal.Amount, meta.Decimals)
+		item.FormattedBalanceAfter = bigint.FormatUnits(journal.BalanceAfter, meta.Decimals)
+		item.FormattedLockBalanceAfter = bigint.FormatUnits(journal.LockBalanceAfter, meta.Decimals)
+	}
+	return item
+}
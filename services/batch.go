@@ -0,0 +1,610 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	"github.com/dapplink-labs/multichain-sync-account/common/json2"
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	dal_wallet_go "github.com/dapplink-labs/multichain-sync-account/protobuf/dal-wallet-go"
+	"github.com/dapplink-labs/multichain-sync-account/rpcclient/chain-account/account"
+	common2 "github.com/dapplink-labs/multichain-sync-account/rpcclient/chain-account/common"
+	"github.com/dapplink-labs/multichain-sync-account/utxo"
+)
+
+const maxBatchWithdrawItems = 500
+
+// batchItem 批量提现的单个条目，request 复用单笔提现的校验和入库逻辑
+type batchItem struct {
+	request *dal_wallet_go.UnSignTransactionRequest
+	amount  *big.Int
+}
+
+// CreateBatchWithdraw 批量提现，每个条目单独落库，multi_send 时所有条目共用一笔交易，否则逐笔生成连续 nonce 的交易
+func (bws *BusinessMiddleWireServices) CreateBatchWithdraw(ctx context.Context, request *dal_wallet_go.BatchWithdrawRequest) (*dal_wallet_go.BatchWithdrawResponse, error) {
+	response := &dal_wallet_go.BatchWithdrawResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" || len(request.Items) == 0 {
+		response.Msg = "invalid params"
+		return response, nil
+	}
+	if len(request.Items) > maxBatchWithdrawItems {
+		response.Msg = fmt.Sprintf("too many items, a batch holds at most %d", maxBatchWithdrawItems)
+		return response, nil
+	}
+	if err := bws.checkWithdrawAllowed(request.RequestId); err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
+	if request.From != "" {
+		from, err := database.ValidateAddress(bws.chainName, request.From)
+		if err != nil {
+			response.Msg = fmt.Sprintf("invalid from address: %v", err)
+			return response, nil
+		}
+		request.From = from
+	}
+
+	items := make([]*batchItem, 0, len(request.Items))
+	for i, item := range request.Items {
+		itemRequest := &dal_wallet_go.UnSignTransactionRequest{
+			ConsumerToken:   request.ConsumerToken,
+			RequestId:       request.RequestId,
+			ChainId:         request.ChainId,
+			Chain:           request.Chain,
+			From:            request.From,
+			To:              item.To,
+			Value:           item.Value,
+			Amount:          item.Amount,
+			ContractAddress: item.ContractAddress,
+			TxType:          string(database.TxTypeWithdraw),
+			Memo:            item.Memo,
+			FeeRate:         request.FeeRate,
+			ChangeAddress:   request.ChangeAddress,
+		}
+		if err := validateRequest(itemRequest); err != nil {
+			response.Msg = fmt.Sprintf("item %d: %v", i, err)
+			return response, nil
+		}
+		if err := bws.normalizeAddresses(itemRequest); err != nil {
+			response.Msg = fmt.Sprintf("item %d: %v", i, err)
+			return response, nil
+		}
+		amount, err := bws.resolveAmount(itemRequest)
+		if err != nil {
+			response.Msg = fmt.Sprintf("item %d: %v", i, err)
+			return response, nil
+		}
+		if amount.Sign() <= 0 {
+			response.Msg = fmt.Sprintf("item %d: amount must be positive", i)
+			return response, nil
+		}
+		itemRequest.Value = amount.String()
+		items = append(items, &batchItem{request: itemRequest, amount: amount})
+	}
+	// 未指定出款地址时按所有条目各代币的总额选择热钱包
+	if request.From == "" {
+		from, release, err := bws.selectHotWallet(request.RequestId, batchDemand(bws.chainName, items))
+		if err != nil {
+			if errors.Is(err, errHotWalletUnavailable) {
+				response.Msg = err.Error()
+				return response, nil
+			}
+			return nil, err
+		}
+		defer release()
+		request.From = from
+		for _, item := range items {
+			item.request.From = from
+		}
+	}
+
+	batchId := uuid.New().String()
+	var (
+		transactions []*dal_wallet_go.BatchWithdrawTransaction
+		err          error
+	)
+	switch {
+	case request.MultiSend && database.IsUTXOChain(bws.chainName):
+		transactions, err = bws.createUtxoMultiSend(ctx, request, batchId, items)
+	case request.MultiSend:
+		transactions, err = bws.createEvmMultiSend(ctx, request, batchId, items)
+	case database.IsUTXOChain(bws.chainName):
+		err = errors.New("utxo chains pay a batch in one transaction, set multi_send")
+	default:
+		transactions, err = bws.createSequentialBatch(ctx, request, batchId, items)
+	}
+	if err != nil {
+		var rejected *requestRejectedError
+		if errors.As(err, &rejected) || errors.Is(err, utxo.ErrInsufficientFunds) {
+			response.Msg = err.Error()
+			return response, nil
+		}
+		log.Error("create batch withdraw fail", "batchId", batchId, "err", err)
+		return nil, fmt.Errorf("create batch withdraw failed: %w", err)
+	}
+
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "submit batch withdraw and build un sign transactions success"
+	response.BatchId = batchId
+	response.Transactions = transactions
+	response.From = request.From
+	return response, nil
+}
+
+// batchDemand 按代币汇总批量提现的出款金额，顺序与代币在条目中首次出现的顺序一致
+func batchDemand(chainName string, items []*batchItem) []tokenDemand {
+	var demand []tokenDemand
+	index := make(map[string]int)
+	for _, item := range items {
+		token := hotWalletToken(chainName, item.request.ContractAddress)
+		if i, ok := index[token]; ok {
+			demand[i].amount.Add(demand[i].amount, item.amount)
+			continue
+		}
+		index[token] = len(demand)
+		demand = append(demand, tokenDemand{token: token, amount: new(big.Int).Set(item.amount)})
+	}
+	return demand
+}
+
+// requestRejectedError 请求本身不满足创建交易的条件，作为业务错误返回而不是 rpc 错误
+type requestRejectedError struct {
+	msg string
+}
+
+func (e *requestRejectedError) Error() string {
+	return e.msg
+}
+
+func rejectRequest(format string, args ...interface{}) error {
+	return &requestRejectedError{msg: fmt.Sprintf(format, args...)}
+}
+
+// newBatchWithdraw 批量提现条目的 withdraws 记录
+func newBatchWithdraw(batchId string, index int, item *batchItem, fee *TxFee, multiSend bool) *database.Withdraws {
+	withdraw := newWithdraw(item.request, uuid.New(), item.amount, fee, database.TxTypeWithdraw)
+	withdraw.BatchId = batchId
+	withdraw.BatchIndex = index
+	withdraw.MultiSend = multiSend
+	return withdraw
+}
+
+func batchTotal(items []*batchItem) *big.Int {
+	total := big.NewInt(0)
+	for _, item := range items {
+		total.Add(total, item.amount)
+	}
+	return total
+}
+
+func (bws *BusinessMiddleWireServices) createUnSignTx(ctx context.Context, chain string, base64Str string) (string, error) {
+	returnTx, err := bws.accountClient.AccountRpClient.CreateUnSignTransaction(ctx, &account.UnSignTransactionRequest{
+		Chain:    chain,
+		Network:  database.GetNetwork(chain),
+		Base64Tx: base64Str,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create unsigned transaction failed: %w", err)
+	}
+	if returnTx.Code == common2.ReturnCode_ERROR {
+		return "", fmt.Errorf("create unsigned transaction failed: %s", returnTx.Msg)
+	}
+	return returnTx.UnSignTx, nil
+}
+
+func allItemIndexes(items []*batchItem) []uint32 {
+	indexes := make([]uint32, 0, len(items))
+	for i := range items {
+		indexes = append(indexes, uint32(i))
+	}
+	return indexes
+}
+
+// createEvmMultiSend 通过链注册表中配置的 multisend 合约一笔交易支付所有条目，条目必须是同一代币；
+// ERC-20 由合约 transferFrom 出款地址，出款地址需要事先对 multisend 合约 approve 不少于批次合计的额度，这里不检查也不发起 approve
+func (bws *BusinessMiddleWireServices) createEvmMultiSend(ctx context.Context, request *dal_wallet_go.BatchWithdrawRequest,
+	batchId string, items []*batchItem) ([]*dal_wallet_go.BatchWithdrawTransaction, error) {
+	chainConfig, _ := database.GetChainConfig(request.Chain)
+	if !chainConfig.IsEVM {
+		return nil, rejectRequest("multi-send is not supported on chain %s, send the batch without multi_send", request.Chain)
+	}
+	if chainConfig.MultiSendContract == "" {
+		return nil, rejectRequest("no multisend contract configured for chain %s", request.Chain)
+	}
+	token := items[0].request.ContractAddress
+	recipients := make([]*MultiSendRecipient, 0, len(items))
+	for i, item := range items {
+		if item.request.ContractAddress != token {
+			return nil, rejectRequest("item %d: multi-send items must transfer the same token", i)
+		}
+		if item.request.Memo != "" {
+			return nil, rejectRequest("item %d: memo is not supported by multi-send", i)
+		}
+		recipients = append(recipients, &MultiSendRecipient{ToAddress: item.request.To, Amount: item.amount.String()})
+	}
+
+	builder, err := bws.txBuilder(request.Chain)
+	if err != nil {
+		return nil, rejectRequest("%v", err)
+	}
+	params := &TxParams{
+		Chain:           request.Chain,
+		ChainId:         chainIdOf(request.Chain, request.ChainId),
+		From:            request.From,
+		To:              chainConfig.MultiSendContract,
+		Amount:          batchTotal(items).String(),
+		ContractAddress: token,
+		Strategy:        bws.FeeStrategies.Resolve(request.Chain, token),
+		Recipients:      recipients,
+	}
+	fee, err := builder.Fee(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("get fee info failed: %w", err)
+	}
+	params.Fee = *fee
+	var unSignTx string
+	if err := bws.db.Transaction(func(tx *database.DB) error {
+		nonce, err := bws.nextNonce(ctx, tx, request.RequestId, request.Chain, request.From)
+		if err != nil {
+			return err
+		}
+		params.Nonce = &nonce
+		base64Str, err := builder.Build(ctx, params)
+		if err != nil {
+			return fmt.Errorf("build transaction failed: %w", err)
+		}
+		unSignTx, err = bws.createUnSignTx(ctx, request.Chain, base64Str)
+		if err != nil {
+			return err
+		}
+		withdrawList := make([]*database.Withdraws, 0, len(items))
+		for i, item := range items {
+			withdraw := newBatchWithdraw(batchId, i, item, fee, true)
+			withdraw.Nonce = params.Nonce
+			withdraw.UnsignedTx, withdraw.SignHash = base64Str, unSignTx
+			withdrawList = append(withdrawList, withdraw)
+		}
+		if err := tx.Withdraws.StoreWithdraws(request.RequestId, bws.chainName, withdrawList); err != nil {
+			return fmt.Errorf("store batch withdraw failed: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return []*dal_wallet_go.BatchWithdrawTransaction{{
+		TransactionId: batchId,
+		UnSignTx:      unSignTx,
+		ItemIndexes:   allItemIndexes(items),
+	}}, nil
+}
+
+// createUtxoMultiSend 一笔交易为每个条目生成一个输出，锁定的输出以批次 id 作为交易 id
+func (bws *BusinessMiddleWireServices) createUtxoMultiSend(ctx context.Context, request *dal_wallet_go.BatchWithdrawRequest,
+	batchId string, items []*batchItem) ([]*dal_wallet_go.BatchWithdrawTransaction, error) {
+	outputs := make([]*UtxoTxOutput, 0, len(items))
+	for i, item := range items {
+		if !database.IsNativeToken(bws.chainName, item.request.ContractAddress) {
+			return nil, rejectRequest("item %d: utxo chain only supports native token transfer", i)
+		}
+		if item.request.Memo != "" {
+			return nil, rejectRequest("item %d: memo is not supported by multi-send", i)
+		}
+		outputs = append(outputs, &UtxoTxOutput{Address: item.request.To, Amount: item.amount.String()})
+	}
+	first := items[0].request
+	feeRate, strategy, err := bws.utxoFeeRate(ctx, first)
+	if err != nil {
+		return nil, rejectRequest("%v", err)
+	}
+	changeAddress, err := bws.utxoChangeAddress(first)
+	if err != nil {
+		return nil, rejectRequest("%v", err)
+	}
+	selection, selected, err := bws.selectUtxos(request.RequestId, request.From, batchTotal(items), len(items), feeRate)
+	if err != nil {
+		return nil, err
+	}
+	fee, changeAmount := utxoFee(selection, feeRate, strategy)
+	base64Str := buildUtxoTx(request.Chain, selected, outputs, changeAddress, changeAmount, selection.Fee, feeRate)
+	unSignTx, err := bws.createUnSignTx(ctx, request.Chain, base64Str)
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawList := make([]*database.Withdraws, 0, len(items))
+	for i, item := range items {
+		withdraw := newBatchWithdraw(batchId, i, item, fee, true)
+		withdraw.ChangeAddress, withdraw.ChangeAmount = changeAddress, changeAmount
+		withdraw.UnsignedTx, withdraw.SignHash = base64Str, unSignTx
+		withdrawList = append(withdrawList, withdraw)
+	}
+	if err := bws.db.Transaction(func(tx *database.DB) error {
+		if err := tx.Withdraws.StoreWithdraws(request.RequestId, bws.chainName, withdrawList); err != nil {
+			return err
+		}
+		return tx.Utxos.LockUtxos(request.RequestId, bws.chainName, batchId, selected)
+	}); err != nil {
+		return nil, fmt.Errorf("store batch withdraw failed: %w", err)
+	}
+	return []*dal_wallet_go.BatchWithdrawTransaction{{
+		TransactionId: batchId,
+		UnSignTx:      unSignTx,
+		ItemIndexes:   allItemIndexes(items),
+	}}, nil
+}
+
+// usesNonce 链族的交易是否带账户序号，逐笔发送时需要在创建阶段分配连续的值
+func usesNonce(family database.TxFamily) bool {
+	switch family {
+	case database.TxFamilyEVM1559, database.TxFamilyEVMLegacy, database.TxFamilyCosmos, database.TxFamilyTon, database.TxFamilyXrp:
+		return true
+	}
+	return false
+}
+
+// createSequentialBatch 每个条目一笔交易，nonce 从账户当前值开始连续分配，手续费按代币只查询一次
+func (bws *BusinessMiddleWireServices) createSequentialBatch(ctx context.Context, request *dal_wallet_go.BatchWithdrawRequest,
+	batchId string, items []*batchItem) ([]*dal_wallet_go.BatchWithdrawTransaction, error) {
+	builder, err := bws.txBuilder(request.Chain)
+	if err != nil {
+		return nil, rejectRequest("%v", err)
+	}
+
+	withdrawList := make([]*database.Withdraws, 0, len(items))
+	// 分配 nonce 到落库在同一个事务中完成，出款地址的锁保证并发请求不会拿到相同的 nonce
+	if err := bws.db.Transaction(func(tx *database.DB) error {
+		var baseNonce *uint64
+		if usesNonce(database.GetTxFamily(request.Chain)) {
+			nonce, err := bws.nextNonce(ctx, tx, request.RequestId, request.Chain, request.From)
+			if err != nil {
+				return err
+			}
+			baseNonce = &nonce
+		}
+
+		feeByToken := make(map[string]*TxFee)
+		for i, item := range items {
+			params := &TxParams{
+				Chain:           request.Chain,
+				ChainId:         chainIdOf(request.Chain, request.ChainId),
+				From:            request.From,
+				To:              item.request.To,
+				Amount:          item.request.Value,
+				ContractAddress: item.request.ContractAddress,
+				Memo:            item.request.Memo,
+				Strategy:        bws.FeeStrategies.Resolve(request.Chain, item.request.ContractAddress),
+			}
+			tokenKey := item.request.ContractAddress
+			fee, ok := feeByToken[tokenKey]
+			if !ok {
+				fee, err = builder.Fee(ctx, params)
+				if err != nil {
+					return fmt.Errorf("item %d: get fee info failed: %w", i, err)
+				}
+				feeByToken[tokenKey] = fee
+			}
+			params.Fee = *fee
+			if baseNonce != nil {
+				nonce := *baseNonce + uint64(i)
+				params.Nonce = &nonce
+			}
+			base64Str, err := builder.Build(ctx, params)
+			if err != nil {
+				return rejectRequest("item %d: %v", i, err)
+			}
+			unSignTx, err := bws.createUnSignTx(ctx, request.Chain, base64Str)
+			if err != nil {
+				return err
+			}
+			withdraw := newBatchWithdraw(batchId, i, item, fee, false)
+			withdraw.Nonce = params.Nonce
+			withdraw.UnsignedTx, withdraw.SignHash = base64Str, unSignTx
+			withdrawList = append(withdrawList, withdraw)
+		}
+		if err := tx.Withdraws.StoreWithdraws(request.RequestId, bws.chainName, withdrawList); err != nil {
+			return fmt.Errorf("store batch withdraw failed: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*dal_wallet_go.BatchWithdrawTransaction, 0, len(items))
+	for i, withdraw := range withdrawList {
+		transactions = append(transactions, &dal_wallet_go.BatchWithdrawTransaction{
+			TransactionId: withdraw.GUID.String(),
+			UnSignTx:      withdraw.SignHash,
+			ItemIndexes:   []uint32{uint32(i)},
+		})
+	}
+	return transactions, nil
+}
+
+// nextNonce 出款地址下一个可用的 nonce：链上 nonce 和在途提现、内部交易已分配的最大 nonce + 1 中较大的值，
+// 必须在事务中调用，事务提交前同一地址的其他请求会等待
+func (bws *BusinessMiddleWireServices) nextNonce(ctx context.Context, tx *database.DB, requestId, chain, from string) (uint64, error) {
+	pendingNonce, err := tx.Withdraws.LockPendingNonce(requestId, bws.chainName, from)
+	if err != nil {
+		return 0, err
+	}
+	internalNonce, err := tx.Internals.QueryPendingNonce(requestId, bws.chainName, from)
+	if err != nil {
+		return 0, err
+	}
+	if internalNonce != nil && (pendingNonce == nil || *internalNonce > *pendingNonce) {
+		pendingNonce = internalNonce
+	}
+	accountInfo, err := queryAccount(ctx, bws.accountClient.AccountRpClient, chain, from)
+	if err != nil {
+		return 0, err
+	}
+	nonce, err := strconv.ParseUint(accountInfo.Sequence, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid nonce value: %w", err)
+	}
+	if pendingNonce != nil && *pendingNonce+1 > nonce {
+		nonce = *pendingNonce + 1
+	}
+	return nonce, nil
+}
+
+// buildMultiSendSignedTransaction 按批次条目重建 multi-send 交易，签名后批次内所有条目共用签名交易
+func (bws *BusinessMiddleWireServices) buildMultiSendSignedTransaction(ctx context.Context, request *dal_wallet_go.SignedTransactionRequest,
+	withdrawList []*database.Withdraws) (*dal_wallet_go.SignedTransactionResponse, error) {
+	response := &dal_wallet_go.SignedTransactionResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	first := withdrawList[0]
+	if first.Status == database.TxStatusExpired {
+		response.Msg = errTxExpired.Error()
+		return response, nil
+	}
+
+	fee := TxFee{
+		GasLimit:             first.GasLimit,
+		MaxFeePerGas:         first.MaxFeePerGas,
+		MaxPriorityFeePerGas: first.MaxPriorityFeePerGas,
+		GasPrice:             first.GasPrice,
+	}
+	total := big.NewInt(0)
+	for _, withdraw := range withdrawList {
+		total.Add(total, withdraw.Amount)
+	}
+
+	var (
+		record    *txSummary
+		base64Str = first.UnsignedTx
+		err       error
+	)
+	if database.IsUTXOChain(bws.chainName) {
+		outputs := make([]*UtxoTxOutput, 0, len(withdrawList))
+		for _, withdraw := range withdrawList {
+			outputs = append(outputs, &UtxoTxOutput{Address: withdraw.ToAddress, Amount: withdraw.Amount.String()})
+		}
+		record = recordSummary(request.Chain, first.FromAddress, "", total, first.TokenAddress, nil, fee)
+		record.Outputs = append(record.Outputs, outputs...)
+		record.withChange(first.ChangeAddress, first.ChangeAmount)
+		if base64Str == "" {
+			feeRate, _ := strconv.ParseUint(first.MaxPriorityFeePerGas, 10, 64)
+			base64Str, err = bws.buildUtxoSignedTx(request.RequestId, request.Chain, first.BatchId, outputs, first.ChangeAddress, first.ChangeAmount, feeRate)
+			if err != nil {
+				return nil, fmt.Errorf("build utxo transaction failed: %w", err)
+			}
+		}
+	} else {
+		chainConfig, _ := database.GetChainConfig(request.Chain)
+		if chainConfig.MultiSendContract == "" {
+			response.Msg = "no multisend contract configured for chain " + request.Chain
+			return response, nil
+		}
+		recipients := make([]*MultiSendRecipient, 0, len(withdrawList))
+		for _, withdraw := range withdrawList {
+			recipients = append(recipients, &MultiSendRecipient{ToAddress: withdraw.ToAddress, Amount: withdraw.Amount.String()})
+		}
+		record = recordSummary(request.Chain, first.FromAddress, chainConfig.MultiSendContract, total, first.TokenAddress, first.Nonce, fee)
+		record.Recipients = recipients
+		if base64Str == "" {
+			builder, err := bws.signedTxBuilder(request.Chain, fee)
+			if err != nil {
+				response.Msg = err.Error()
+				return response, nil
+			}
+			base64Str, err = builder.Build(ctx, &TxParams{
+				Chain:           request.Chain,
+				ChainId:         chainIdOf(request.Chain, request.ChainId),
+				From:            first.FromAddress,
+				To:              chainConfig.MultiSendContract,
+				Amount:          total.String(),
+				ContractAddress: first.TokenAddress,
+				Fee:             fee,
+				Nonce:           first.Nonce,
+				Recipients:      recipients,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("build transaction failed: %w", err)
+			}
+		}
+	}
+	expected, err := expectedSummary(request.Chain, record, base64Str)
+	if err != nil {
+		return signedTxFailure(response, first.BatchId, err)
+	}
+
+	returnTx, err := bws.accountClient.AccountRpClient.BuildSignedTransaction(ctx, &account.SignedTransactionRequest{
+		Chain:     request.Chain,
+		Network:   database.GetNetwork(request.Chain),
+		Signature: request.Signature,
+		Base64Tx:  base64Str,
+	})
+	log.Info("BuildSignedTransaction multi-send response", "batchId", first.BatchId, "returnTx", json2.ToJSONString(returnTx))
+	if err != nil {
+		return nil, fmt.Errorf("build signed transaction failed: %w", err)
+	}
+	if returnTx.Code == common2.ReturnCode_ERROR {
+		response.Msg = "build signed transaction failed: " + returnTx.Msg
+		return response, nil
+	}
+	if err := bws.verifySignedTx(ctx, request.RequestId, request.Chain, expected, first.FromAddress, returnTx.SignedTx); err != nil {
+		return signedTxFailure(response, first.BatchId, err)
+	}
+	if err := bws.db.Withdraws.UpdateWithdrawsByBatchId(request.RequestId, bws.chainName, first.BatchId, returnTx.SignedTx, database.TxStatusSigned); err != nil {
+		return nil, fmt.Errorf("update transaction status failed: %w", err)
+	}
+	response.SignedTx = returnTx.SignedTx
+	response.Msg = "build signed tx success"
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	return response, nil
+}
+
+// GetBatchWithdraw 查询批量提现每个条目的状态
+func (bws *BusinessMiddleWireServices) GetBatchWithdraw(ctx context.Context, request *dal_wallet_go.BatchWithdrawStatusRequest) (*dal_wallet_go.BatchWithdrawStatusResponse, error) {
+	response := &dal_wallet_go.BatchWithdrawStatusResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" || request.BatchId == "" {
+		response.Msg = "invalid params"
+		return response, nil
+	}
+	withdrawList, err := bws.db.Withdraws.QueryWithdrawsByBatchId(request.RequestId, bws.chainName, request.BatchId)
+	if err != nil {
+		log.Error("query batch withdraw fail", "batchId", request.BatchId, "err", err)
+		response.Msg = "query batch withdraw fail"
+		return response, nil
+	}
+	if len(withdrawList) == 0 {
+		response.Msg = "batch withdraw not found"
+		return response, nil
+	}
+	for _, withdraw := range withdrawList {
+		item := &dal_wallet_go.BatchWithdrawItemStatus{
+			Index:           uint32(withdraw.BatchIndex),
+			TransactionId:   withdraw.GUID.String(),
+			To:              withdraw.ToAddress,
+			Value:           withdraw.Amount.String(),
+			ContractAddress: withdraw.TokenAddress,
+			Status:          string(withdraw.Status),
+			Memo:            withdraw.Memo,
+		}
+		if withdraw.MultiSend {
+			item.TransactionId = withdraw.BatchId
+		}
+		if withdraw.TxHash != (common.Hash{}) {
+			item.Hash = withdraw.TxHash.String()
+		}
+		response.Items = append(response.Items, item)
+	}
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "query batch withdraw success"
+	response.BatchId = request.BatchId
+	response.MultiSend = withdrawList[0].MultiSend
+	return response, nil
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	"github.com/dapplink-labs/multichain-sync-account/database/dynamic"
+	dal_wallet_go "github.com/dapplink-labs/multichain-sync-account/protobuf/dal-wallet-go"
+)
+
+var (
+	errBusinessNotActive = errors.New("business is not active")
+	errWithdrawPaused    = errors.New("withdraws are paused")
+	errTxExpired         = errors.New("transaction expired, create a new one")
+)
+
+// checkBusinessActive 暂停或归档的业务不允许再发起地址和交易相关的请求
+func (bws *BusinessMiddleWireServices) checkBusinessActive(requestId string) error {
+	business, err := bws.db.Business.QueryBusinessByUuid(requestId)
+	if err != nil {
+		return fmt.Errorf("query business fail: %w", err)
+	}
+	if !business.IsActive() {
+		return fmt.Errorf("%w: %s is %s", errBusinessNotActive, requestId, business.Status)
+	}
+	return nil
+}
+
+// checkWithdrawAllowed 发现未授权转出后暂停了提现的业务不允许再创建和签名提现
+func (bws *BusinessMiddleWireServices) checkWithdrawAllowed(requestId string) error {
+	business, err := bws.db.Business.QueryBusinessByUuid(requestId)
+	if err != nil {
+		return fmt.Errorf("query business fail: %w", err)
+	}
+	if !business.IsActive() {
+		return fmt.Errorf("%w: %s is %s", errBusinessNotActive, requestId, business.Status)
+	}
+	if business.WithdrawPaused {
+		return fmt.Errorf("%w: %s, resume withdraws after reviewing the outflow incidents", errWithdrawPaused, requestId)
+	}
+	return nil
+}
+
+func (bws *BusinessMiddleWireServices) SuspendBusiness(ctx context.Context, request *dal_wallet_go.BusinessStatusRequest) (*dal_wallet_go.BusinessStatusResponse, error) {
+	return bws.changeBusinessStatus(request, database.BusinessStatusSuspended, database.BusinessStatusActive)
+}
+
+func (bws *BusinessMiddleWireServices) ActivateBusiness(ctx context.Context, request *dal_wallet_go.BusinessStatusRequest) (*dal_wallet_go.BusinessStatusResponse, error) {
+	return bws.changeBusinessStatus(request, database.BusinessStatusActive, database.BusinessStatusSuspended)
+}
+
+func (bws *BusinessMiddleWireServices) changeBusinessStatus(request *dal_wallet_go.BusinessStatusRequest, to database.BusinessStatus, from database.BusinessStatus) (*dal_wallet_go.BusinessStatusResponse, error) {
+	response := &dal_wallet_go.BusinessStatusResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	business, err := bws.db.Business.QueryBusinessByUuid(request.RequestId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Msg = "business not found"
+			return response, nil
+		}
+		return nil, fmt.Errorf("query business fail: %w", err)
+	}
+	response.Status = string(business.Status)
+	if business.Status == to {
+		response.Code = dal_wallet_go.ReturnCode_SUCCESS
+		response.Msg = fmt.Sprintf("business already %s", to)
+		return response, nil
+	}
+	if business.Status != from {
+		response.Msg = fmt.Sprintf("can not change business from %s to %s", business.Status, to)
+		return response, nil
+	}
+	if err := bws.db.Business.UpdateBusinessStatus(request.RequestId, to); err != nil {
+		return nil, err
+	}
+	log.Info("business status changed", "businessUid", request.RequestId, "from", from, "to", to)
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = fmt.Sprintf("business %s success", to)
+	response.Status = string(to)
+	return response, nil
+}
+
+func (bws *BusinessMiddleWireServices) PauseWithdraw(ctx context.Context, request *dal_wallet_go.BusinessStatusRequest) (*dal_wallet_go.WithdrawPauseResponse, error) {
+	return bws.changeWithdrawPaused(request, true)
+}
+
+// ResumeWithdraw 业务方处理完未授权转出事件后恢复提现
+func (bws *BusinessMiddleWireServices) ResumeWithdraw(ctx context.Context, request *dal_wallet_go.BusinessStatusRequest) (*dal_wallet_go.WithdrawPauseResponse, error) {
+	return bws.changeWithdrawPaused(request, false)
+}
+
+func (bws *BusinessMiddleWireServices) changeWithdrawPaused(request *dal_wallet_go.BusinessStatusRequest, paused bool) (*dal_wallet_go.WithdrawPauseResponse, error) {
+	response := &dal_wallet_go.WithdrawPauseResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	business, err := bws.db.Business.QueryBusinessByUuid(request.RequestId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Msg = "business not found"
+			return response, nil
+		}
+		return nil, fmt.Errorf("query business fail: %w", err)
+	}
+	response.WithdrawPaused = business.WithdrawPaused
+	if business.WithdrawPaused != paused {
+		if err := bws.db.Business.UpdateWithdrawPaused(request.RequestId, paused); err != nil {
+			return nil, err
+		}
+		response.WithdrawPaused = paused
+	}
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	if paused {
+		response.Msg = "withdraws paused"
+	} else {
+		response.Msg = "withdraws resumed"
+	}
+	return response, nil
+}
+
+// ArchiveBusiness 业务必须先暂停，导出所有动态表并校验后删除
+func (bws *BusinessMiddleWireServices) ArchiveBusiness(ctx context.Context, request *dal_wallet_go.BusinessStatusRequest) (*dal_wallet_go.BusinessStatusResponse, error) {
+	response := &dal_wallet_go.BusinessStatusResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	business, err := bws.db.Business.QueryBusinessByUuid(request.RequestId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Msg = "business not found"
+			return response, nil
+		}
+		return nil, fmt.Errorf("query business fail: %w", err)
+	}
+	response.Status = string(business.Status)
+	if business.Status != database.BusinessStatusSuspended {
+		response.Msg = "business must be suspended before archive"
+		return response, nil
+	}
+	archiveFile, err := dynamic.ArchiveBusinessTables(bws.db, request.RequestId, bws.ArchiveDir)
+	if err != nil {
+		log.Error("archive business fail", "businessUid", request.RequestId, "err", err)
+		response.Msg = "archive business fail"
+		response.ArchiveFile = archiveFile
+		return response, nil
+	}
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "archive business success"
+	response.Status = string(database.BusinessStatusArchived)
+	response.ArchiveFile = archiveFile
+	return response, nil
+}
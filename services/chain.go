@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	dal_wallet_go "github.com/dapplink-labs/multichain-sync-account/protobuf/dal-wallet-go"
+)
+
+// ListSupportedChains 返回启动时加载的链注册表，按链名排序
+func (bws *BusinessMiddleWireServices) ListSupportedChains(ctx context.Context, request *dal_wallet_go.SupportedChainsRequest) (*dal_wallet_go.SupportedChainsResponse, error) {
+	chainList := make([]*dal_wallet_go.SupportedChain, 0, len(database.ChainTokenTypes))
+	for _, name := range database.SupportedChains() {
+		config, _ := database.GetChainConfig(name)
+		chainList = append(chainList, &dal_wallet_go.SupportedChain{
+			ChainName:         name,
+			NativeSymbol:      config.Native.String(),
+			DefaultToken:      config.Default.String(),
+			NativeAddress:     config.NativeAddress,
+			IsEvm:             config.IsEVM,
+			ChainId:           config.ChainId,
+			Network:           config.Network,
+			Decimals:          uint32(config.NativeDecimals),
+			Confirmations:     config.Confirmations,
+			SupportsMemo:      config.SupportsMemo,
+			IsUtxo:            config.IsUTXO,
+			TxFamily:          string(config.TxFamily),
+			MultisendContract: config.MultiSendContract,
+		})
+	}
+	return &dal_wallet_go.SupportedChainsResponse{
+		Code:   dal_wallet_go.ReturnCode_SUCCESS,
+		Msg:    "list supported chains success",
+		Chains: chainList,
+	}, nil
+}
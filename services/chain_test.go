@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	dal_wallet_go "github.com/dapplink-labs/multichain-sync-account/protobuf/dal-wallet-go"
+)
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func TestRegisterChain(t *testing.T) {
+	decimals := uint8(18)
+	err := database.RegisterChain(database.ChainSpec{
+		Name:          "Base",
+		NativeSymbol:  "ETH",
+		DefaultToken:  "ERC20",
+		NativeAddress: "0x0000000000000000000000000000000000000000",
+		IsEVM:         boolPtr(true),
+		ChainId:       "8453",
+		Decimals:      &decimals,
+		Confirmations: 20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer delete(database.ChainTokenTypes, "base")
+
+	config, ok := database.GetChainConfig("base")
+	if !ok {
+		t.Fatal("base should be registered")
+	}
+	if config.TxFamily != database.TxFamilyEVM1559 || config.Network != database.NetworkMainnet {
+		t.Fatalf("unexpected defaults %+v", config)
+	}
+	if chainIdOf("base", "") != "8453" {
+		t.Fatal("chain id should come from the registry")
+	}
+
+	response, err := (&BusinessMiddleWireServices{}).ListSupportedChains(context.Background(), &dal_wallet_go.SupportedChainsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found *dal_wallet_go.SupportedChain
+	for _, chain := range response.Chains {
+		if chain.ChainName == "base" {
+			found = chain
+		}
+	}
+	if found == nil || found.ChainId != "8453" || found.Confirmations != 20 || found.Decimals != 18 {
+		t.Fatalf("base missing from supported chains: %v", found)
+	}
+}
+
+func TestRegisterChainValidation(t *testing.T) {
+	cases := []database.ChainSpec{
+		{Name: "", NativeSymbol: "ETH", DefaultToken: "ERC20"},
+		{Name: "linea", NativeSymbol: "ETH", DefaultToken: "ERC20", IsEVM: boolPtr(true)},
+		{Name: "linea", NativeSymbol: "ETH", DefaultToken: "ERC20", IsEVM: boolPtr(true), ChainId: "59144", Network: "devnet"},
+		{Name: "aptos", NativeSymbol: "APT", DefaultToken: "Coin"},
+		{Name: "aptos", NativeSymbol: "APT", DefaultToken: "Coin", TxFamily: database.TxFamilyEVM1559},
+	}
+	for _, spec := range cases {
+		if err := database.RegisterChain(spec); err == nil {
+			delete(database.ChainTokenTypes, spec.Name)
+			t.Errorf("spec %+v should be rejected", spec)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	"github.com/dapplink-labs/multichain-sync-account/rpcclient/chain-account/account"
+)
+
+type FeeSpeed string
+
+const (
+	FeeSpeedSlow   FeeSpeed = "slow"
+	FeeSpeedNormal FeeSpeed = "normal"
+	FeeSpeedFast   FeeSpeed = "fast"
+)
+
+const defaultTipBuffer int64 = 2
+
+// FeeStrategy 手续费策略，Token 为空时对整条链生效，上限为链上最小单位的整数
+type FeeStrategy struct {
+	Chain             string   `json:"chain"`
+	Token             string   `json:"token"`
+	Speed             FeeSpeed `json:"speed"`
+	TipBuffer         int64    `json:"tip_buffer"`           // maxFeePerGas = baseFee + 小费 * TipBuffer
+	GasLimit          uint64   `json:"gas_limit"`            // 每笔转账的 gas limit，EVM 代币策略必填
+	MaxFeeCap         string   `json:"max_fee_cap"`          // maxFeePerGas / gasPrice / 手续费 / 费率的上限
+	MaxPriorityFeeCap string   `json:"max_priority_fee_cap"` // maxPriorityFeePerGas 的上限
+}
+
+// DefaultFeeStrategy 未配置策略的链和代币使用快速档位
+var DefaultFeeStrategy = FeeStrategy{
+	Speed:     FeeSpeedFast,
+	TipBuffer: defaultTipBuffer,
+}
+
+// FeeStrategies 按链和代币查找手续费策略，代币策略优先于链策略
+type FeeStrategies struct {
+	strategies map[string]FeeStrategy
+}
+
+func feeStrategyKey(chain, token string) string {
+	return strings.ToLower(chain) + ":" + strings.ToLower(token)
+}
+
+// LoadFeeStrategies 读取 JSON 格式的手续费策略文件，path 为空时全部使用默认策略
+func LoadFeeStrategies(path string) (*FeeStrategies, error) {
+	fs := &FeeStrategies{strategies: make(map[string]FeeStrategy)}
+	if path == "" {
+		return fs, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fee strategy config %s: %w", path, err)
+	}
+	var strategyList []FeeStrategy
+	if err := json.Unmarshal(data, &strategyList); err != nil {
+		return nil, fmt.Errorf("parse fee strategy config %s: %w", path, err)
+	}
+	for _, strategy := range strategyList {
+		if err := fs.Add(strategy); err != nil {
+			return nil, err
+		}
+	}
+	return fs, nil
+}
+
+// Add 校验并登记策略，未填写的字段使用默认值
+func (fs *FeeStrategies) Add(strategy FeeStrategy) error {
+	if strategy.Chain == "" {
+		return fmt.Errorf("fee strategy chain is required")
+	}
+	switch strategy.Speed {
+	case "":
+		strategy.Speed = DefaultFeeStrategy.Speed
+	case FeeSpeedSlow, FeeSpeedNormal, FeeSpeedFast:
+	default:
+		return fmt.Errorf("fee strategy %s: invalid speed %s", strategy.Chain, strategy.Speed)
+	}
+	if strategy.TipBuffer == 0 {
+		strategy.TipBuffer = DefaultFeeStrategy.TipBuffer
+	}
+	if strategy.TipBuffer < 0 {
+		return fmt.Errorf("fee strategy %s: tip buffer cannot be negative", strategy.Chain)
+	}
+	for _, feeCap := range []string{strategy.MaxFeeCap, strategy.MaxPriorityFeeCap} {
+		if feeCap == "" {
+			continue
+		}
+		if value, ok := new(big.Int).SetString(feeCap, 10); !ok || value.Sign() <= 0 {
+			return fmt.Errorf("fee strategy %s: invalid fee cap %s", strategy.Chain, feeCap)
+		}
+	}
+	fs.strategies[feeStrategyKey(strategy.Chain, strategy.Token)] = strategy
+	return nil
+}
+
+// Resolve 返回代币策略，其次是链策略，都没有时返回默认策略
+func (fs *FeeStrategies) Resolve(chain, token string) FeeStrategy {
+	if fs != nil {
+		if strategy, ok := fs.strategies[feeStrategyKey(chain, token)]; ok && token != "" {
+			return strategy
+		}
+		if strategy, ok := fs.strategies[feeStrategyKey(chain, "")]; ok {
+			return strategy
+		}
+	}
+	return DefaultFeeStrategy
+}
+
+// pick 按档位选择 chain-account 返回的费用，档位缺失时回退到快速档位
+func (s FeeStrategy) pick(feeResponse *account.FeeResponse) string {
+	var fee string
+	switch s.Speed {
+	case FeeSpeedSlow:
+		fee = feeResponse.SlowFee
+	case FeeSpeedNormal:
+		fee = feeResponse.NormalFee
+	}
+	if strings.TrimSpace(fee) == "" {
+		fee = feeResponse.FastFee
+	}
+	return strings.TrimSpace(fee)
+}
+
+// gasLimit 使用策略或默认的 gas limit，multisend 按收款地址数累加
+func (s FeeStrategy) gasLimit(params *TxParams) uint64 {
+	gasLimit := s.GasLimit
+	if gasLimit == 0 {
+		gasLimit = gasLimitOf(params.Chain, params.ContractAddress)
+	}
+	if len(params.Recipients) > 1 {
+		gasLimit *= uint64(len(params.Recipients))
+	}
+	return gasLimit
+}
+
+// evmGasLimit chain-account 不预估 gas，代币转账的 gas 随合约实现变化，默认值不可靠，必须在代币策略中配置 gas_limit
+func (s FeeStrategy) evmGasLimit(params *TxParams) (uint64, error) {
+	if !database.IsNativeToken(params.Chain, params.ContractAddress) && (s.Token == "" || s.GasLimit == 0) {
+		return 0, fmt.Errorf("fee strategy with gas_limit is required for token %s on chain %s", params.ContractAddress, params.Chain)
+	}
+	return s.gasLimit(params), nil
+}
+
+// capFee 超过上限时按上限取值，返回是否被截断
+func capFee(value *big.Int, feeCap string) (*big.Int, bool) {
+	if feeCap == "" {
+		return value, false
+	}
+	capValue, ok := new(big.Int).SetString(feeCap, 10)
+	if !ok || value.Cmp(capValue) <= 0 {
+		return value, false
+	}
+	return capValue, true
+}
+
+// label 记录在交易上的策略名称，费用被上限截断时带 capped 后缀
+func (s FeeStrategy) label(capped bool) string {
+	if capped {
+		return string(s.Speed) + ":capped"
+	}
+	return string(s.Speed)
+}
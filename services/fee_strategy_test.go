@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"github.com/dapplink-labs/multichain-sync-account/rpcclient/chain-account/account"
+)
+
+func TestParseFee(t *testing.T) {
+	feeInfo, err := ParseFee("100|10|*2", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feeInfo.MaxPriorityFee.String() != "160" || feeInfo.MultipliedTip.String() != "20" {
+		t.Fatalf("unexpected fee info %+v", feeInfo)
+	}
+	if _, err := ParseFee("100|10|*2|50000", 2); err == nil {
+		t.Fatal("fee with extra fields should be rejected")
+	}
+	feeInfo, err = ParseFastFee("100|10|*2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feeInfo.MaxPriorityFee.String() != "140" {
+		t.Fatalf("unexpected fast fee info %+v", feeInfo)
+	}
+}
+
+func TestFeeStrategiesResolve(t *testing.T) {
+	fs, err := LoadFeeStrategies("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Add(FeeStrategy{Chain: "Ethereum", Speed: FeeSpeedNormal}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Add(FeeStrategy{Chain: "ethereum", Token: "0xToken", Speed: FeeSpeedSlow, GasLimit: 200000}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Add(FeeStrategy{Chain: "ethereum", Speed: "turbo"}); err == nil {
+		t.Fatal("invalid speed should be rejected")
+	}
+	if err := fs.Add(FeeStrategy{Chain: "ethereum", MaxFeeCap: "-1"}); err == nil {
+		t.Fatal("invalid cap should be rejected")
+	}
+
+	if s := fs.Resolve("ethereum", "0xtoken"); s.Speed != FeeSpeedSlow || s.GasLimit != 200000 || s.TipBuffer != defaultTipBuffer {
+		t.Fatalf("token strategy expected, got %+v", s)
+	}
+	if s := fs.Resolve("ethereum", "0xother"); s.Speed != FeeSpeedNormal {
+		t.Fatalf("chain strategy expected, got %+v", s)
+	}
+	if s := fs.Resolve("tron", ""); s.Speed != FeeSpeedFast {
+		t.Fatalf("default strategy expected, got %+v", s)
+	}
+	var nilStrategies *FeeStrategies
+	if s := nilStrategies.Resolve("ethereum", ""); s.Speed != FeeSpeedFast {
+		t.Fatalf("default strategy expected, got %+v", s)
+	}
+}
+
+type feeTierClient struct {
+	account.WalletAccountServiceClient
+	feeResponse *account.FeeResponse
+}
+
+func (c *feeTierClient) GetFee(ctx context.Context, in *account.FeeRequest, opts ...grpc.CallOption) (*account.FeeResponse, error) {
+	return c.feeResponse, nil
+}
+
+func TestChainFeeStrategy(t *testing.T) {
+	client := &feeTierClient{feeResponse: &account.FeeResponse{SlowFee: "5", NormalFee: "8", FastFee: "12"}}
+	params := &TxParams{Chain: "tron", Strategy: FeeStrategy{Speed: FeeSpeedNormal, MaxFeeCap: "7"}}
+	fee, err := (&tronTxBuilder{client: client}).Fee(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee.MaxFeePerGas != "7" || fee.Strategy != "normal:capped" {
+		t.Fatalf("unexpected tron fee %+v", fee)
+	}
+
+	client.feeResponse.SlowFee = ""
+	params.Strategy = FeeStrategy{Speed: FeeSpeedSlow}
+	fee, err = (&tronTxBuilder{client: client}).Fee(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee.MaxFeePerGas != "12" || fee.Strategy != "slow" {
+		t.Fatalf("missing tier should fall back to fast fee, got %+v", fee)
+	}
+}
+
+func TestEvmFeeStrategy(t *testing.T) {
+	client := &fakeAccountClient{fastFee: "100|10|*2"}
+	params := &TxParams{
+		Chain:           "ethereum",
+		ContractAddress: "0xtoken",
+		Strategy:        FeeStrategy{Token: "0xtoken", Speed: FeeSpeedFast, TipBuffer: 2, GasLimit: 90000, MaxFeeCap: "130", MaxPriorityFeeCap: "15"},
+	}
+	fee, err := (&evm1559TxBuilder{client: client}).Fee(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee.GasLimit != 90000 || fee.MaxFeePerGas != "130" || fee.MaxPriorityFeePerGas != "15" || fee.Strategy != "fast:capped" {
+		t.Fatalf("unexpected capped fee %+v", fee)
+	}
+
+	// 代币没有配置 gas_limit 时拒绝创建，链策略的 gas_limit 不用于代币
+	for _, strategy := range []FeeStrategy{
+		{Speed: FeeSpeedFast, TipBuffer: 2},
+		{Speed: FeeSpeedFast, TipBuffer: 2, GasLimit: 90000},
+		{Token: "0xtoken", Speed: FeeSpeedFast, TipBuffer: 2},
+	} {
+		params.Strategy = strategy
+		if _, err := (&evm1559TxBuilder{client: client}).Fee(context.Background(), params); err == nil {
+			t.Fatalf("token without gas_limit should be rejected, strategy %+v", strategy)
+		}
+		if _, err := (&evmLegacyTxBuilder{client: client}).Fee(context.Background(), params); err == nil {
+			t.Fatalf("legacy token without gas_limit should be rejected, strategy %+v", strategy)
+		}
+	}
+}
+
+// TestEvmFeeUpstreamFormat 上游 chain-account 的 GetFee 只返回三段，没有预估 gas，原生币按默认 gas limit，代币按代币策略的 gas_limit
+func TestEvmFeeUpstreamFormat(t *testing.T) {
+	client := &feeTierClient{feeResponse: &account.FeeResponse{SlowFee: "100|1|*1", NormalFee: "100|2|*1", FastFee: "100|3|*2"}}
+	tests := []struct {
+		speed       FeeSpeed
+		params      *TxParams
+		gasLimit    uint64
+		maxFee      string
+		priorityFee string
+	}{
+		{FeeSpeedSlow, &TxParams{Chain: "ethereum"}, EthGasLimit, "102", "1"},
+		{FeeSpeedNormal, &TxParams{Chain: "ethereum", ContractAddress: "0xtoken"}, 2 * TokenGasLimit, "104", "2"},
+		{FeeSpeedFast, &TxParams{Chain: "ethereum", Recipients: []*MultiSendRecipient{{}, {}, {}}}, 3 * EthGasLimit, "112", "6"},
+	}
+	for _, tt := range tests {
+		tt.params.Strategy = FeeStrategy{Speed: tt.speed, TipBuffer: 2}
+		if tt.params.ContractAddress != "" {
+			tt.params.Strategy.Token, tt.params.Strategy.GasLimit = tt.params.ContractAddress, 2*TokenGasLimit
+		}
+		fee, err := (&evm1559TxBuilder{client: client}).Fee(context.Background(), tt.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fee.GasLimit != tt.gasLimit || fee.MaxFeePerGas != tt.maxFee || fee.MaxPriorityFeePerGas != tt.priorityFee || fee.Strategy != string(tt.speed) {
+			t.Fatalf("%s: unexpected fee %+v", tt.speed, fee)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"encoding/base64"
 	"errors"
 	"fmt"
 	"math/big"
@@ -14,18 +13,16 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/google/uuid"
 
+	"github.com/dapplink-labs/multichain-sync-account/common/bigint"
 	"github.com/dapplink-labs/multichain-sync-account/common/json2"
 	"github.com/dapplink-labs/multichain-sync-account/database"
 	"github.com/dapplink-labs/multichain-sync-account/database/dynamic"
 	dal_wallet_go "github.com/dapplink-labs/multichain-sync-account/protobuf/dal-wallet-go"
 	"github.com/dapplink-labs/multichain-sync-account/rpcclient/chain-account/account"
+	common2 "github.com/dapplink-labs/multichain-sync-account/rpcclient/chain-account/common"
 	"gorm.io/gorm"
 )
 
-const (
-	Network = "mainnet"
-)
-
 var (
 	EthGasLimit   uint64 = 60000
 	TokenGasLimit uint64 = 120000
@@ -52,13 +49,68 @@ func (bws *BusinessMiddleWireServices) BusinessRegister(ctx context.Context, req
 		}, nil
 	}
 
-	// 2. 如果业务不存在，创建新业务
+	if existingBusiness != nil && !existingBusiness.IsActive() {
+		return &dal_wallet_go.BusinessRegisterResponse{
+			Code: dal_wallet_go.ReturnCode_ERROR,
+			Msg:  fmt.Sprintf("business is %s", existingBusiness.Status),
+		}, nil
+	}
+
+	var policy database.UnlistedTokenPolicy
+	if request.UnlistedTokenPolicy != "" {
+		policy, err = database.ParseUnlistedTokenPolicy(request.UnlistedTokenPolicy)
+		if err != nil {
+			return &dal_wallet_go.BusinessRegisterResponse{
+				Code: dal_wallet_go.ReturnCode_ERROR,
+				Msg:  "invalid unlisted token policy",
+			}, nil
+		}
+	}
+
+	var strategy database.HotWalletStrategy
+	if request.HotWalletStrategy != "" {
+		strategy, err = database.ParseHotWalletStrategy(request.HotWalletStrategy)
+		if err != nil {
+			return &dal_wallet_go.BusinessRegisterResponse{
+				Code: dal_wallet_go.ReturnCode_ERROR,
+				Msg:  "invalid hot wallet strategy",
+			}, nil
+		}
+	}
+	collectionTarget := request.CollectionTarget
+	if collectionTarget != "" {
+		collectionTarget, err = database.ValidateAddress(bws.chainName, collectionTarget)
+		if err != nil {
+			return &dal_wallet_go.BusinessRegisterResponse{
+				Code: dal_wallet_go.ReturnCode_ERROR,
+				Msg:  fmt.Sprintf("invalid collection target: %v", err),
+			}, nil
+		}
+	}
+
+	var outflowPolicy database.OutflowPolicy
+	if request.OutflowPolicy != "" {
+		outflowPolicy, err = database.ParseOutflowPolicy(request.OutflowPolicy)
+		if err != nil {
+			return &dal_wallet_go.BusinessRegisterResponse{
+				Code: dal_wallet_go.ReturnCode_ERROR,
+				Msg:  "invalid outflow policy",
+			}, nil
+		}
+	}
+
+	// 2. 如果业务不存在，创建新业务，已存在时按请求更新未登记代币策略
 	if existingBusiness == nil {
 		business := &database.Business{
-			GUID:        uuid.New(),
-			BusinessUid: request.RequestId,
-			NotifyUrl:   request.NotifyUrl,
-			Timestamp:   uint64(time.Now().Unix()),
+			GUID:                 uuid.New(),
+			BusinessUid:          request.RequestId,
+			NotifyUrl:            request.NotifyUrl,
+			UnlistedTokenPolicy:  policy,
+			AddressPoolWatermark: uint64(request.AddressPoolWatermark),
+			HotWalletStrategy:    strategy,
+			CollectionTarget:     collectionTarget,
+			OutflowPolicy:        outflowPolicy,
+			Timestamp:            uint64(time.Now().Unix()),
 		}
 		if err := bws.db.Business.StoreBusiness(business); err != nil {
 			log.Error("store business fail", "err", err)
@@ -67,6 +119,52 @@ func (bws *BusinessMiddleWireServices) BusinessRegister(ctx context.Context, req
 				Msg:  "store db fail",
 			}, nil
 		}
+	} else if policy != "" && policy != existingBusiness.TokenPolicy() {
+		if err := bws.db.Business.UpdateUnlistedTokenPolicy(request.RequestId, policy); err != nil {
+			log.Error("update unlisted token policy fail", "err", err)
+			return &dal_wallet_go.BusinessRegisterResponse{
+				Code: dal_wallet_go.ReturnCode_ERROR,
+				Msg:  "update db fail",
+			}, nil
+		}
+	}
+	if existingBusiness != nil && request.AddressPoolWatermark != 0 && uint64(request.AddressPoolWatermark) != existingBusiness.AddressPoolWatermark {
+		if err := bws.db.Business.UpdateAddressPoolWatermark(request.RequestId, uint64(request.AddressPoolWatermark)); err != nil {
+			log.Error("update address pool watermark fail", "err", err)
+			return &dal_wallet_go.BusinessRegisterResponse{
+				Code: dal_wallet_go.ReturnCode_ERROR,
+				Msg:  "update db fail",
+			}, nil
+		}
+	}
+
+	if existingBusiness != nil && strategy != "" && strategy != existingBusiness.WalletStrategy() {
+		if err := bws.db.Business.UpdateHotWalletStrategy(request.RequestId, strategy); err != nil {
+			log.Error("update hot wallet strategy fail", "err", err)
+			return &dal_wallet_go.BusinessRegisterResponse{
+				Code: dal_wallet_go.ReturnCode_ERROR,
+				Msg:  "update db fail",
+			}, nil
+		}
+	}
+	if existingBusiness != nil && collectionTarget != "" && collectionTarget != existingBusiness.CollectionTarget {
+		if err := bws.db.Business.UpdateCollectionTarget(request.RequestId, collectionTarget); err != nil {
+			log.Error("update collection target fail", "err", err)
+			return &dal_wallet_go.BusinessRegisterResponse{
+				Code: dal_wallet_go.ReturnCode_ERROR,
+				Msg:  "update db fail",
+			}, nil
+		}
+	}
+
+	if existingBusiness != nil && outflowPolicy != "" && outflowPolicy != existingBusiness.IncidentPolicy() {
+		if err := bws.db.Business.UpdateOutflowPolicy(request.RequestId, outflowPolicy); err != nil {
+			log.Error("update outflow policy fail", "err", err)
+			return &dal_wallet_go.BusinessRegisterResponse{
+				Code: dal_wallet_go.ReturnCode_ERROR,
+				Msg:  "update db fail",
+			}, nil
+		}
 	}
 
 	// 3. 创建或更新业务链关系和相关表
@@ -92,9 +190,15 @@ func (bws *BusinessMiddleWireServices) ExportAddressesByPublicKeys(ctx context.C
 		dbAddresses   []*database.Addresses
 		balances      []*database.Balances
 	)
+	if err := bws.checkBusinessActive(request.RequestId); err != nil {
+		return &dal_wallet_go.ExportAddressesResponse{
+			Code: dal_wallet_go.ReturnCode_ERROR,
+			Msg:  err.Error(),
+		}, nil
+	}
 
 	for _, value := range request.PublicKeys {
-		address := bws.accountClient.ExportAddressByPubKey("", value.PublicKey)
+		address := database.NormalizeAddress(bws.chainName, bws.accountClient.ExportAddressByPubKey(value.AddressFormat, value.PublicKey))
 		item := &dal_wallet_go.Address{
 			Type:    value.Type,
 			Address: address,
@@ -104,7 +208,11 @@ func (bws *BusinessMiddleWireServices) ExportAddressesByPublicKeys(ctx context.C
 			log.Error("handle ParseAddressType fail", "type", value.Type, "err", err)
 			return nil, err
 		}
-		_, _, balance := bws.accountClient.GetAccount(address)
+		balance, err := bws.accountClient.GetAccountBalance(address, "")
+		if err != nil {
+			log.Warn("get address balance fail, init balance with zero", "address", address, "err", err)
+			balance = big.NewInt(0)
+		}
 
 		dbAddress := &database.Addresses{
 			GUID:        uuid.New(),
@@ -120,7 +228,7 @@ func (bws *BusinessMiddleWireServices) ExportAddressesByPublicKeys(ctx context.C
 			Address:      address,
 			TokenAddress: common.Address{}.String(),
 			AddressType:  parseAddressType,
-			Balance:      big.NewInt(int64(balance)),
+			Balance:      balance,
 			LockBalance:  big.NewInt(0),
 			Timestamp:    uint64(time.Now().Unix()),
 		}
@@ -158,104 +266,142 @@ func (bws *BusinessMiddleWireServices) CreateUnSignTransaction(ctx context.Conte
 	if err := validateRequest(request); err != nil {
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
+	if err := bws.checkBusinessActive(request.RequestId); err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
+	if request.To == "" {
+		target, err := bws.collectionTarget(request.RequestId)
+		if err != nil {
+			if errors.Is(err, errHotWalletUnavailable) {
+				response.Msg = err.Error()
+				return response, nil
+			}
+			return nil, err
+		}
+		request.To = target
+	}
+	if err := bws.normalizeAddresses(request); err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
 
 	transactionType, err := database.ParseTransactionType(request.TxType)
 	if err != nil {
 		return nil, fmt.Errorf("invalid request TxType: %w", err)
 	}
+	if request.Memo != "" && transactionType != database.TxTypeWithdraw {
+		response.Msg = "memo is only supported for withdrawals"
+		return response, nil
+	}
+	if transactionType == database.TxTypeWithdraw {
+		if err := bws.checkWithdrawAllowed(request.RequestId); err != nil {
+			response.Msg = err.Error()
+			return response, nil
+		}
+	}
 
-	amountBig, ok := new(big.Int).SetString(request.Value, 10)
-	if !ok {
-		return nil, fmt.Errorf("invalid amount value: %s", request.Value)
+	amountBig, err := bws.resolveAmount(request)
+	if err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
+	request.Value = amountBig.String()
+	if request.From == "" {
+		demand := []tokenDemand{{token: hotWalletToken(bws.chainName, request.ContractAddress), amount: amountBig}}
+		var release func()
+		request.From, release, err = bws.selectHotWallet(request.RequestId, demand)
+		if err != nil {
+			if errors.Is(err, errHotWalletUnavailable) {
+				response.Msg = err.Error()
+				return response, nil
+			}
+			return nil, err
+		}
+		defer release()
 	}
+	response.From, response.To = request.From, request.To
 	guid := uuid.New()
 
-	nonceStr, err := bws.getAccountNonce(ctx, request.Chain, request.From)
-	if err != nil {
-		return nil, fmt.Errorf("get account nonce failed: %w", err)
+	if database.IsUTXOChain(bws.chainName) {
+		return bws.createUtxoUnSignTransaction(ctx, request, guid, amountBig, transactionType)
 	}
 
-	feeInfo, err := bws.getFeeInfo(ctx, request.Chain, request.From)
+	builder, err := bws.txBuilder(request.Chain)
+	if err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
+	params := &TxParams{
+		Chain:           request.Chain,
+		ChainId:         chainIdOf(request.Chain, request.ChainId),
+		From:            request.From,
+		To:              request.To,
+		Amount:          request.Value,
+		ContractAddress: request.ContractAddress,
+		Memo:            request.Memo,
+		Strategy:        bws.FeeStrategies.Resolve(request.Chain, request.ContractAddress),
+	}
+	fee, err := builder.Fee(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("get fee info failed: %w", err)
 	}
-	gasLimit, contractAddress := bws.getGasAndContractInfo(request.ContractAddress)
+	params.Fee = *fee
 
 	switch transactionType {
-	case database.TxTypeDeposit:
-		err := bws.StoreDeposits(ctx, request, guid, amountBig, gasLimit, feeInfo, transactionType)
-		if err != nil {
-			return nil, fmt.Errorf("store deposit failed: %w", err)
-		}
-	case database.TxTypeWithdraw:
-		if err := bws.storeWithdraw(request, guid, amountBig, gasLimit, feeInfo, transactionType); err != nil {
-			return nil, fmt.Errorf("store withdraw failed: %w", err)
-		}
-	case database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot:
-		if err := bws.storeInternal(request, guid, amountBig, gasLimit, feeInfo, transactionType); err != nil {
-			return nil, fmt.Errorf("store internal failed: %w", err)
-		}
+	case database.TxTypeDeposit, database.TxTypeWithdraw, database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot:
 	default:
 		response.Msg = "Unsupported transaction type"
 		response.UnSignTx = "0x00"
 		return response, nil
 	}
 
-	// 构建交易请求
-	var base64Str string
-	if config, ok := database.ChainTokenTypes[strings.ToLower(request.Chain)]; ok && config.IsEVM {
-		// EVM 链使用 EIP-1559 交易格式
-		nonce, err := strconv.ParseUint(nonceStr, 10, 64)
+	// 分配 nonce 到落库在同一个事务中完成，与批量提现共用出款地址的锁，并发请求不会拿到相同的 nonce；
+	// 先取得待签名哈希，交易数据和哈希随记录一起落库，签名时按落库的数据组装和校验
+	var signHash string
+	if err := bws.db.Transaction(func(tx *database.DB) error {
+		if usesNonce(database.GetTxFamily(request.Chain)) {
+			nonce, err := bws.nextNonce(ctx, tx, request.RequestId, request.Chain, request.From)
+			if err != nil {
+				return err
+			}
+			params.Nonce = &nonce
+		}
+		base64Str, err := builder.Build(ctx, params)
 		if err != nil {
-			return nil, fmt.Errorf("invalid nonce value: %w", err)
-		}
-
-		dynamicFeeTxReq := Eip1559DynamicFeeTx{
-			ChainId:              request.ChainId,
-			Nonce:                nonce,
-			FromAddress:          request.From,
-			ToAddress:            request.To,
-			GasLimit:             gasLimit,
-			MaxFeePerGas:         feeInfo.MaxPriorityFee.String(),
-			MaxPriorityFeePerGas: feeInfo.MultipliedTip.String(),
-			Amount:               request.Value,
-			ContractAddress:      contractAddress,
-		}
-		data := json2.ToJSON(dynamicFeeTxReq)
-		base64Str = base64.StdEncoding.EncodeToString(data)
-	} else {
-		// 非 EVM 链使用各自的交易格式
-		txReq := map[string]interface{}{
-			"chain":           request.Chain,
-			"from":            request.From,
-			"to":              request.To,
-			"amount":          request.Value,
-			"nonce":           nonceStr, // 使用原始 nonce 字符串
-			"contractAddress": contractAddress,
-			"gasLimit":        gasLimit,
-		}
-		data := json2.ToJSON(txReq)
-		base64Str = base64.StdEncoding.EncodeToString(data)
-	}
-
-	unsignTx := &account.UnSignTransactionRequest{
-		Chain:    request.Chain,
-		Network:  Network,
-		Base64Tx: base64Str,
-	}
-
-	log.Info("BusinessMiddleWireServices CreateUnSignTransaction unsignTx", json2.ToJSONString(unsignTx))
-	returnTx, err := bws.accountClient.AccountRpClient.CreateUnSignTransaction(ctx, unsignTx)
-	log.Info("BusinessMiddleWireServices CreateUnSignTransaction returnTx", json2.ToJSONString(returnTx))
-	if err != nil {
-		log.Error("create un sign transaction fail", "err", err)
-		return nil, fmt.Errorf("create unsigned transaction failed: %w", err)
+			return fmt.Errorf("build transaction failed: %w", err)
+		}
+		log.Info("BusinessMiddleWireServices CreateUnSignTransaction unsignTx", "base64Tx", base64Str)
+		signHash, err = bws.createUnSignTx(ctx, request.Chain, base64Str)
+		if err != nil {
+			log.Error("create un sign transaction fail", "err", err)
+			return err
+		}
+		unsigned := &unsignedPayload{base64Tx: base64Str, signHash: signHash, nonce: params.Nonce}
+
+		switch transactionType {
+		case database.TxTypeDeposit:
+			if err := bws.StoreDeposits(tx, request, guid, amountBig, fee, transactionType, unsigned); err != nil {
+				return fmt.Errorf("store deposit failed: %w", err)
+			}
+		case database.TxTypeWithdraw:
+			if err := bws.storeWithdraw(tx, request, guid, amountBig, fee, transactionType, unsigned); err != nil {
+				return fmt.Errorf("store withdraw failed: %w", err)
+			}
+		default:
+			if err := bws.storeInternal(tx, request, guid, amountBig, fee, transactionType, unsigned); err != nil {
+				return fmt.Errorf("store internal failed: %w", err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
 	response.Code = dal_wallet_go.ReturnCode_SUCCESS
 	response.Msg = "submit withdraw and build un sign tranaction success"
 	response.TransactionId = guid.String()
-	response.UnSignTx = returnTx.UnSignTx
+	response.UnSignTx = signHash
 	return response, nil
 }
 
@@ -263,6 +409,10 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 	response := &dal_wallet_go.SignedTransactionResponse{
 		Code: dal_wallet_go.ReturnCode_ERROR,
 	}
+	if err := bws.checkBusinessActive(request.RequestId); err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
 	// 1. Get transaction from database based on type
 	var (
 		fromAddress          string
@@ -272,12 +422,24 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 		gasLimit             uint64
 		maxFeePerGas         string
 		maxPriorityFeePerGas string
+		gasPrice             string
+		memo                 string
+		changeAddress        string
+		changeAmount         *big.Int
+		nonce                *uint64
+		unsignedTx           string
 	)
 
 	transactionType, err := database.ParseTransactionType(request.TxType)
 	if err != nil {
 		return nil, fmt.Errorf("invalid request TxType: %w", err)
 	}
+	if transactionType == database.TxTypeWithdraw {
+		if err := bws.checkWithdrawAllowed(request.RequestId); err != nil {
+			response.Msg = err.Error()
+			return response, nil
+		}
+	}
 
 	switch transactionType {
 	case database.TxTypeDeposit:
@@ -296,6 +458,8 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 		gasLimit = tx.GasLimit
 		maxFeePerGas = tx.MaxFeePerGas
 		maxPriorityFeePerGas = tx.MaxPriorityFeePerGas
+		gasPrice = tx.GasPrice
+		unsignedTx = tx.UnsignedTx
 
 	case database.TxTypeWithdraw:
 		tx, err := bws.db.Withdraws.QueryWithdrawsById(request.RequestId, bws.accountClient.ChainName, request.TransactionId)
@@ -303,9 +467,24 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 			return nil, fmt.Errorf("query withdraw failed: %w", err)
 		}
 		if tx == nil {
+			batchList, err := bws.db.Withdraws.QueryWithdrawsByBatchId(request.RequestId, bws.accountClient.ChainName, request.TransactionId)
+			if err != nil {
+				return nil, fmt.Errorf("query batch withdraw failed: %w", err)
+			}
+			if len(batchList) > 0 && batchList[0].MultiSend {
+				return bws.buildMultiSendSignedTransaction(ctx, request, batchList)
+			}
 			response.Msg = "Withdraw transaction not found"
 			return response, nil
 		}
+		if tx.MultiSend {
+			response.Msg = "multi-send withdraw is signed by its batch id " + tx.BatchId
+			return response, nil
+		}
+		if tx.Status == database.TxStatusExpired {
+			response.Msg = errTxExpired.Error()
+			return response, nil
+		}
 		fromAddress = tx.FromAddress
 		toAddress = tx.ToAddress
 		amount = tx.Amount.String()
@@ -313,6 +492,11 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 		gasLimit = tx.GasLimit
 		maxFeePerGas = tx.MaxFeePerGas
 		maxPriorityFeePerGas = tx.MaxPriorityFeePerGas
+		gasPrice = tx.GasPrice
+		unsignedTx = tx.UnsignedTx
+		memo = tx.Memo
+		changeAddress, changeAmount = tx.ChangeAddress, tx.ChangeAmount
+		nonce = tx.Nonce
 
 	case database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot:
 		tx, err := bws.db.Internals.QueryInternalsById(request.RequestId, bws.accountClient.ChainName, request.TransactionId)
@@ -323,6 +507,10 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 			response.Msg = "Internal transaction not found"
 			return response, nil
 		}
+		if tx.Status == database.TxStatusExpired {
+			response.Msg = errTxExpired.Error()
+			return response, nil
+		}
 		fromAddress = tx.FromAddress
 		toAddress = tx.ToAddress
 		amount = tx.Amount.String()
@@ -330,6 +518,10 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 		gasLimit = tx.GasLimit
 		maxFeePerGas = tx.MaxFeePerGas
 		maxPriorityFeePerGas = tx.MaxPriorityFeePerGas
+		gasPrice = tx.GasPrice
+		unsignedTx = tx.UnsignedTx
+		changeAddress, changeAmount = tx.ChangeAddress, tx.ChangeAmount
+		nonce = tx.Nonce
 
 	default:
 		response.Msg = "Unsupported transaction type"
@@ -337,54 +529,58 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 		return response, nil
 	}
 
-	// 2. Get current nonce
-	nonceStr, err := bws.getAccountNonce(ctx, request.Chain, fromAddress)
+	// 2. Use the payload stored when the unsigned tx was created, records created before it was stored are rebuilt,
+	// utxo chains rebuild the inputs locked when the unsigned tx was created
+	fee := TxFee{
+		GasLimit:             gasLimit,
+		MaxFeePerGas:         maxFeePerGas,
+		MaxPriorityFeePerGas: maxPriorityFeePerGas,
+		GasPrice:             gasPrice,
+	}
+	amountBig, _ := new(big.Int).SetString(amount, 10)
+	if database.IsUTXOChain(bws.chainName) && transactionType == database.TxTypeDeposit {
+		response.Msg = "Unsupported transaction type"
+		return response, nil
+	}
+	base64Str := unsignedTx
+	if base64Str == "" && database.IsUTXOChain(bws.chainName) {
+		feeRate, _ := strconv.ParseUint(maxPriorityFeePerGas, 10, 64)
+		outputs := []*UtxoTxOutput{{Address: toAddress, Amount: amountBig.String()}}
+		base64Str, err = bws.buildUtxoSignedTx(request.RequestId, request.Chain, request.TransactionId, outputs, changeAddress, changeAmount, feeRate)
+		if err != nil {
+			return nil, fmt.Errorf("build utxo transaction failed: %w", err)
+		}
+	} else if base64Str == "" {
+		builder, err := bws.signedTxBuilder(request.Chain, fee)
+		if err != nil {
+			response.Msg = err.Error()
+			return response, nil
+		}
+		base64Str, err = builder.Build(ctx, &TxParams{
+			Chain:           request.Chain,
+			ChainId:         chainIdOf(request.Chain, request.ChainId),
+			From:            fromAddress,
+			To:              toAddress,
+			Amount:          amount,
+			ContractAddress: tokenAddress,
+			Memo:            memo,
+			Fee:             fee,
+			Nonce:           nonce,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("build transaction failed: %w", err)
+		}
+	}
+	record := recordSummary(request.Chain, fromAddress, toAddress, amountBig, tokenAddress, nonce, fee).withChange(changeAddress, changeAmount)
+	expected, err := expectedSummary(request.Chain, record, base64Str)
 	if err != nil {
-		return nil, fmt.Errorf("get account nonce failed: %w", err)
+		return signedTxFailure(response, request.TransactionId, err)
 	}
 
-	// 3. Build transaction data
-	var base64Str string
-	if config, ok := database.ChainTokenTypes[strings.ToLower(request.Chain)]; ok && config.IsEVM {
-		// Convert nonce for EVM chains
-		nonce, err := strconv.ParseUint(nonceStr, 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("invalid nonce value: %w", err)
-		}
-
-		// Build EIP-1559 transaction
-		dynamicFeeTx := Eip1559DynamicFeeTx{
-			ChainId:              request.ChainId,
-			Nonce:                nonce,
-			FromAddress:          fromAddress,
-			ToAddress:            toAddress,
-			GasLimit:             gasLimit,
-			MaxFeePerGas:         maxFeePerGas,
-			MaxPriorityFeePerGas: maxPriorityFeePerGas,
-			Amount:               amount,
-			ContractAddress:      tokenAddress,
-		}
-		data := json2.ToJSON(dynamicFeeTx)
-		base64Str = base64.StdEncoding.EncodeToString(data)
-	} else {
-		// Non-EVM chains use their own transaction format
-		txReq := map[string]interface{}{
-			"chain":           request.Chain,
-			"from":            fromAddress,
-			"to":              toAddress,
-			"amount":          amount,
-			"nonce":           nonceStr, // Use original nonce string
-			"contractAddress": tokenAddress,
-			"gasLimit":        gasLimit,
-		}
-		data := json2.ToJSON(txReq)
-		base64Str = base64.StdEncoding.EncodeToString(data)
-	}
-
-	// 4. Build signed transaction
+	// 3. Build signed transaction, then decode and verify it against the record before it is stored
 	signedTxReq := &account.SignedTransactionRequest{
 		Chain:     request.Chain,
-		Network:   Network,
+		Network:   database.GetNetwork(request.Chain),
 		Signature: request.Signature,
 		Base64Tx:  base64Str,
 	}
@@ -395,8 +591,15 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 	if err != nil {
 		return nil, fmt.Errorf("build signed transaction failed: %w", err)
 	}
+	if returnTx.Code == common2.ReturnCode_ERROR {
+		response.Msg = "build signed transaction failed: " + returnTx.Msg
+		return response, nil
+	}
+	if err := bws.verifySignedTx(ctx, request.RequestId, request.Chain, expected, fromAddress, returnTx.SignedTx); err != nil {
+		return signedTxFailure(response, request.TransactionId, err)
+	}
 
-	// 5. Update transaction status in database
+	// 4. Update transaction status in database
 	var updateErr error
 	switch transactionType {
 	case database.TxTypeDeposit:
@@ -425,17 +628,45 @@ func (bws *BusinessMiddleWireServices) SetTokenAddress(ctx context.Context, requ
 	var (
 		tokenList []database.Tokens
 	)
+	if err := bws.checkBusinessActive(request.RequestId); err != nil {
+		return &dal_wallet_go.SetTokenAddressResponse{
+			Code: dal_wallet_go.ReturnCode_ERROR,
+			Msg:  err.Error(),
+		}, nil
+	}
 	for _, value := range request.TokenList {
 		CollectAmountBigInt, _ := new(big.Int).SetString(value.CollectAmount, 10)
 		ColdAmountBigInt, _ := new(big.Int).SetString(value.ColdAmount, 10)
+		minDeposit := big.NewInt(0)
+		if value.MinDeposit != "" {
+			var ok bool
+			minDeposit, ok = new(big.Int).SetString(value.MinDeposit, 10)
+			if !ok || minDeposit.Sign() < 0 {
+				return &dal_wallet_go.SetTokenAddressResponse{
+					Code: dal_wallet_go.ReturnCode_ERROR,
+					Msg:  fmt.Sprintf("invalid min deposit for token %s", value.Address),
+				}, nil
+			}
+		}
+		hotMinBalance, hotTargetBalance, ok := parseHotBalances(value.HotMinBalance, value.HotTargetBalance)
+		if !ok {
+			return &dal_wallet_go.SetTokenAddressResponse{
+				Code: dal_wallet_go.ReturnCode_ERROR,
+				Msg:  fmt.Sprintf("invalid hot wallet balance for token %s", value.Address),
+			}, nil
+		}
 		token := database.Tokens{
-			GUID:          uuid.New(),
-			TokenAddress:  value.Address,
-			Decimals:      uint8(value.Decimals),
-			TokenName:     value.TokenName,
-			CollectAmount: CollectAmountBigInt,
-			ColdAmount:    ColdAmountBigInt,
-			Timestamp:     uint64(time.Now().Unix()),
+			GUID:             uuid.New(),
+			TokenAddress:     value.Address,
+			Decimals:         uint8(value.Decimals),
+			TokenName:        value.TokenName,
+			CollectAmount:    CollectAmountBigInt,
+			ColdAmount:       ColdAmountBigInt,
+			MinDeposit:       minDeposit,
+			AggregateDust:    value.AggregateDust,
+			HotMinBalance:    hotMinBalance,
+			HotTargetBalance: hotTargetBalance,
+			Timestamp:        uint64(time.Now().Unix()),
 		}
 		tokenList = append(tokenList, token)
 	}
@@ -450,21 +681,46 @@ func (bws *BusinessMiddleWireServices) SetTokenAddress(ctx context.Context, requ
 	}, nil
 }
 
+// parseHotBalances 热钱包余额下限和目标余额为空时按 0 处理，目标余额不能低于下限
+func parseHotBalances(minValue, targetValue string) (*big.Int, *big.Int, bool) {
+	parse := func(value string) (*big.Int, bool) {
+		if value == "" {
+			return big.NewInt(0), true
+		}
+		parsed, ok := new(big.Int).SetString(value, 10)
+		return parsed, ok && parsed.Sign() >= 0
+	}
+	minBalance, ok := parse(minValue)
+	if !ok {
+		return nil, nil, false
+	}
+	targetBalance, ok := parse(targetValue)
+	if !ok || targetBalance.Sign() > 0 && targetBalance.Cmp(minBalance) < 0 {
+		return nil, nil, false
+	}
+	return minBalance, targetBalance, true
+}
+
 // FeeInfo 结构体用于存储解析后的费用信息
 type FeeInfo struct {
 	GasPrice       *big.Int // 基础 gas 价格
 	GasTipCap      *big.Int // 小费上限
 	Multiplier     int64    // 倍数
 	MultipliedTip  *big.Int // 小费 * 倍数
-	MaxPriorityFee *big.Int // 小费 * 倍数 * 2 (最大上限)
+	MaxPriorityFee *big.Int // baseFee + 小费 * 倍数 * tipBuffer (最大上限)
 }
 
 // ParseFastFee 解析 FastFee 字符串并计算相关费用
 func ParseFastFee(fastFee string) (*FeeInfo, error) {
+	return ParseFee(fastFee, defaultTipBuffer)
+}
+
+// ParseFee 解析 "baseFee|tip|*倍数" 格式的费用，maxFeePerGas 为 baseFee 加上 tipBuffer 倍的小费
+func ParseFee(fee string, tipBuffer int64) (*FeeInfo, error) {
 	// 1. 按 "|" 分割字符串
-	parts := strings.Split(fastFee, "|")
+	parts := strings.Split(fee, "|")
 	if len(parts) != 3 {
-		return nil, fmt.Errorf("invalid fast fee format: %s", fastFee)
+		return nil, fmt.Errorf("invalid fast fee format: %s", fee)
 	}
 
 	// 2. 解析 GasPrice (baseFee)
@@ -491,16 +747,11 @@ func ParseFastFee(fastFee string) (*FeeInfo, error) {
 		gasTipCap,
 		big.NewInt(multiplier),
 	)
-	// 设置最小小费阈值 (1 Gwei)
-	//minTipCap := big.NewInt(int64(Min1Gwei))
-	//if multipliedTip.Cmp(minTipCap) < 0 {
-	//	multipliedTip = minTipCap
-	//}
 
-	// 6. 计算 MaxPriorityFee (baseFee + 小费*倍数*2)
+	// 6. 计算 MaxPriorityFee (baseFee + 小费*倍数*tipBuffer)
 	maxPriorityFee := new(big.Int).Mul(
 		multipliedTip,
-		big.NewInt(2),
+		big.NewInt(tipBuffer),
 	)
 	// 加上 baseFee
 	maxPriorityFee.Add(maxPriorityFee, gasPrice)
@@ -518,70 +769,115 @@ func validateRequest(request *dal_wallet_go.UnSignTransactionRequest) error {
 	if request == nil {
 		return errors.New("request cannot be nil")
 	}
-	if request.From == "" {
+	// 提现可以不指定出款地址，由业务的热钱包策略选择；归集可以不指定接收地址，发往归集地址
+	transactionType, _ := database.ParseTransactionType(request.TxType)
+	if request.From == "" && transactionType != database.TxTypeWithdraw {
 		return errors.New("from address cannot be empty")
 	}
-	if request.To == "" {
+	if request.To == "" && transactionType != database.TxTypeCollection {
 		return errors.New("to address cannot be empty")
 	}
-	if request.Value == "" {
-		return errors.New("value cannot be empty")
+	if request.Value == "" && request.Amount == "" {
+		return errors.New("value and amount cannot both be empty")
 	}
-	return nil
-}
-
-func determineTokenType(chainName, contractAddress string) database.TokenType {
-	if contractAddress == "0x00" {
-		contractAddress = common.Address{}.String()
+	if request.Memo != "" && !database.SupportsMemo(request.Chain) {
+		return fmt.Errorf("memo is not supported on chain %s", request.Chain)
 	}
-	isNative := contractAddress == database.GetNativeAddress(chainName)
-	return database.GetTokenType(chainName, isNative)
+	return nil
 }
 
-func (bws *BusinessMiddleWireServices) getAccountNonce(ctx context.Context, chain, address string) (string, error) {
-	accountReq := &account.AccountRequest{
-		Chain:           chain,
-		Network:         Network,
-		Address:         address,
-		ContractAddress: "0x00",
+// normalizeAddresses 按链的地址规则校验并规范化请求中的地址，目标地址再经 chain-account 校验，
+// 不通过时在写入任何记录之前拒绝
+func (bws *BusinessMiddleWireServices) normalizeAddresses(request *dal_wallet_go.UnSignTransactionRequest) error {
+	from := request.From
+	if from != "" {
+		normalized, err := database.ValidateAddress(bws.chainName, from)
+		if err != nil {
+			return fmt.Errorf("invalid from address: %w", err)
+		}
+		from = normalized
 	}
-
-	accountInfo, err := bws.accountClient.AccountRpClient.GetAccount(ctx, accountReq)
+	to, err := database.ValidateAddress(bws.chainName, request.To)
 	if err != nil {
-		return "", fmt.Errorf("get account info failed: %w", err)
+		return fmt.Errorf("invalid to address: %w", err)
 	}
-
-	return accountInfo.Sequence, nil
+	if request.ContractAddress != "" && !database.IsNativeToken(bws.chainName, request.ContractAddress) {
+		contract, err := database.ValidateAddress(bws.chainName, request.ContractAddress)
+		if err != nil {
+			return fmt.Errorf("invalid contract address: %w", err)
+		}
+		request.ContractAddress = contract
+	}
+	if request.ChangeAddress != "" {
+		change, err := database.ValidateAddress(bws.chainName, request.ChangeAddress)
+		if err != nil {
+			return fmt.Errorf("invalid change address: %w", err)
+		}
+		request.ChangeAddress = change
+	}
+	if !bws.accountClient.ValidAddress(to) {
+		return fmt.Errorf("invalid to address: %s rejected by chain-account", to)
+	}
+	request.From, request.To = from, to
+	return nil
 }
 
-// TODO Solana链需要传入构建后的交易，才能获取交易费用。需要在上游服务（wallet-chain-account）进行处理
-func (bws *BusinessMiddleWireServices) getFeeInfo(ctx context.Context, chain, address string) (*FeeInfo, error) {
-	accountFeeReq := &account.FeeRequest{
-		Chain:   chain,
-		Network: Network,
-		RawTx:   "",
-		Address: address,
+// resolveAmount value 是最小单位的整数，amount 按代币精度换算，精度超出代币小数位的金额直接拒绝
+func (bws *BusinessMiddleWireServices) resolveAmount(request *dal_wallet_go.UnSignTransactionRequest) (*big.Int, error) {
+	var value *big.Int
+	if request.Value != "" {
+		if strings.Contains(request.Value, ".") {
+			return nil, fmt.Errorf("value %s must be an integer in the smallest unit of the token, use amount for decimal values", request.Value)
+		}
+		parsed, ok := new(big.Int).SetString(request.Value, 10)
+		if !ok || parsed.Sign() < 0 {
+			return nil, fmt.Errorf("invalid amount value: %s", request.Value)
+		}
+		value = parsed
+	}
+	if request.Amount == "" {
+		return value, nil
 	}
 
-	feeResponse, err := bws.accountClient.AccountRpClient.GetFee(ctx, accountFeeReq)
+	meta := bws.tokenMeta(request.RequestId, request.ContractAddress)
+	if meta == nil {
+		return nil, fmt.Errorf("token %s is not registered, decimals unknown", request.ContractAddress)
+	}
+	amount, err := bigint.ParseUnits(request.Amount, meta.Decimals)
 	if err != nil {
-		return nil, fmt.Errorf("get fee failed: %w", err)
+		return nil, err
 	}
-
-	return ParseFastFee(feeResponse.FastFee)
+	if value != nil && value.Cmp(amount) != 0 {
+		return nil, fmt.Errorf("value %s does not match amount %s %s", request.Value, request.Amount, meta.Symbol)
+	}
+	return amount, nil
 }
 
-func (bws *BusinessMiddleWireServices) getGasAndContractInfo(contractAddress string) (uint64, string) {
+func determineTokenType(chainName, contractAddress string) database.TokenType {
 	if contractAddress == "0x00" {
-		return EthGasLimit, "0x00"
+		contractAddress = common.Address{}.String()
 	}
-	return TokenGasLimit, contractAddress
+	isNative := contractAddress == database.GetNativeAddress(chainName)
+	return database.GetTokenType(chainName, isNative)
 }
 
-func (bws *BusinessMiddleWireServices) storeWithdraw(request *dal_wallet_go.UnSignTransactionRequest,
-	transactionId uuid.UUID, amountBig *big.Int, gasLimit uint64, feeInfo *FeeInfo, transactionType database.TransactionType) error {
+// unsignedPayload 交给签名方的交易数据、chain-account 返回的待签名哈希和创建时分配的 nonce
+type unsignedPayload struct {
+	base64Tx string
+	signHash string
+	nonce    *uint64
+}
+
+func (bws *BusinessMiddleWireServices) storeWithdraw(tx *database.DB, request *dal_wallet_go.UnSignTransactionRequest,
+	transactionId uuid.UUID, amountBig *big.Int, fee *TxFee, transactionType database.TransactionType, unsigned *unsignedPayload) error {
+	withdraw := newWithdraw(request, transactionId, amountBig, fee, transactionType)
+	withdraw.UnsignedTx, withdraw.SignHash, withdraw.Nonce = unsigned.base64Tx, unsigned.signHash, unsigned.nonce
+	return tx.Withdraws.StoreWithdraw(request.RequestId, bws.accountClient.ChainName, withdraw)
+}
 
-	withdraw := &database.Withdraws{
+func newWithdraw(request *dal_wallet_go.UnSignTransactionRequest,
+	transactionId uuid.UUID, amountBig *big.Int, fee *TxFee, transactionType database.TransactionType) *database.Withdraws {
+	return &database.Withdraws{
 		GUID:                 transactionId,
 		Timestamp:            uint64(time.Now().Unix()),
 		Status:               database.TxStatusCreateUnsigned,
@@ -592,24 +888,31 @@ func (bws *BusinessMiddleWireServices) storeWithdraw(request *dal_wallet_go.UnSi
 		FromAddress:          request.From,
 		ToAddress:            request.To,
 		Amount:               amountBig,
-		GasLimit:             gasLimit,
-		MaxFeePerGas:         feeInfo.MaxPriorityFee.String(),
-		MaxPriorityFeePerGas: feeInfo.MultipliedTip.String(),
+		GasLimit:             fee.GasLimit,
+		MaxFeePerGas:         fee.MaxFeePerGas,
+		MaxPriorityFeePerGas: fee.MaxPriorityFeePerGas,
+		GasPrice:             fee.GasPrice,
+		FeeStrategy:          fee.Strategy,
 		TokenType:            determineTokenType(request.Chain, request.ContractAddress),
 		TokenAddress:         request.ContractAddress,
 		TokenId:              request.TokenId,
 		TokenMeta:            request.TokenMeta,
 		TxSignHex:            "",
+		Memo:                 request.Memo,
 	}
-
-	return bws.db.Withdraws.StoreWithdraw(request.RequestId, bws.accountClient.ChainName, withdraw)
 }
 
 // 辅助方法：存储内部交易
-func (bws *BusinessMiddleWireServices) storeInternal(request *dal_wallet_go.UnSignTransactionRequest,
-	transactionId uuid.UUID, amountBig *big.Int, gasLimit uint64, feeInfo *FeeInfo, transactionType database.TransactionType) error {
+func (bws *BusinessMiddleWireServices) storeInternal(tx *database.DB, request *dal_wallet_go.UnSignTransactionRequest,
+	transactionId uuid.UUID, amountBig *big.Int, fee *TxFee, transactionType database.TransactionType, unsigned *unsignedPayload) error {
+	internal := newInternal(request, transactionId, amountBig, fee, transactionType)
+	internal.UnsignedTx, internal.SignHash, internal.Nonce = unsigned.base64Tx, unsigned.signHash, unsigned.nonce
+	return tx.Internals.StoreInternal(request.RequestId, bws.accountClient.ChainName, internal)
+}
 
-	internal := &database.Internals{
+func newInternal(request *dal_wallet_go.UnSignTransactionRequest,
+	transactionId uuid.UUID, amountBig *big.Int, fee *TxFee, transactionType database.TransactionType) *database.Internals {
+	return &database.Internals{
 		GUID:                 transactionId,
 		Timestamp:            uint64(time.Now().Unix()),
 		Status:               database.TxStatusCreateUnsigned,
@@ -620,22 +923,22 @@ func (bws *BusinessMiddleWireServices) storeInternal(request *dal_wallet_go.UnSi
 		FromAddress:          request.From,
 		ToAddress:            request.To,
 		Amount:               amountBig,
-		GasLimit:             gasLimit,
-		MaxFeePerGas:         feeInfo.MaxPriorityFee.String(),
-		MaxPriorityFeePerGas: feeInfo.MultipliedTip.String(),
+		GasLimit:             fee.GasLimit,
+		MaxFeePerGas:         fee.MaxFeePerGas,
+		MaxPriorityFeePerGas: fee.MaxPriorityFeePerGas,
+		GasPrice:             fee.GasPrice,
+		FeeStrategy:          fee.Strategy,
 		TokenType:            determineTokenType(request.Chain, request.ContractAddress),
 		TokenAddress:         request.ContractAddress,
 		TokenId:              request.TokenId,
 		TokenMeta:            request.TokenMeta,
 		TxSignHex:            "",
 	}
-
-	return bws.db.Internals.StoreInternal(request.RequestId, bws.accountClient.ChainName, internal)
 }
 
-func (bws *BusinessMiddleWireServices) StoreDeposits(ctx context.Context,
+func (bws *BusinessMiddleWireServices) StoreDeposits(tx *database.DB,
 	depositsRequest *dal_wallet_go.UnSignTransactionRequest, transactionId uuid.UUID, amountBig *big.Int,
-	gasLimit uint64, feeInfo *FeeInfo, transactionType database.TransactionType) error {
+	fee *TxFee, transactionType database.TransactionType, unsigned *unsignedPayload) error {
 	fmt.Printf("StoreDeposits - Chain: %s, ContractAddress: %s\n",
 		depositsRequest.Chain, depositsRequest.ContractAddress)
 	dbDeposit := &database.Deposits{
@@ -650,15 +953,18 @@ func (bws *BusinessMiddleWireServices) StoreDeposits(ctx context.Context,
 		FromAddress:          depositsRequest.From,
 		ToAddress:            depositsRequest.To,
 		Amount:               amountBig,
-		GasLimit:             gasLimit,
-		MaxFeePerGas:         feeInfo.MaxPriorityFee.String(),
-		MaxPriorityFeePerGas: feeInfo.MultipliedTip.String(),
+		GasLimit:             fee.GasLimit,
+		MaxFeePerGas:         fee.MaxFeePerGas,
+		MaxPriorityFeePerGas: fee.MaxPriorityFeePerGas,
+		GasPrice:             fee.GasPrice,
 		TokenType:            determineTokenType(depositsRequest.Chain, depositsRequest.ContractAddress),
 		TokenAddress:         depositsRequest.ContractAddress,
 		TokenId:              depositsRequest.TokenId,
 		TokenMeta:            depositsRequest.TokenMeta,
 		TxSignHex:            "",
+		UnsignedTx:           unsigned.base64Tx,
+		SignHash:             unsigned.signHash,
 	}
 
-	return bws.db.Deposits.StoreDeposits(depositsRequest.RequestId, bws.accountClient.ChainName, []*database.Deposits{dbDeposit})
+	return tx.Deposits.StoreDeposits(depositsRequest.RequestId, bws.accountClient.ChainName, []*database.Deposits{dbDeposit})
 }
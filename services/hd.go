@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	"github.com/dapplink-labs/multichain-sync-account/common/hdkey"
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	dal_wallet_go "github.com/dapplink-labs/multichain-sync-account/protobuf/dal-wallet-go"
+)
+
+const (
+	maxDeriveCount      = 1000
+	defaultGapLimit     = 20
+	maxGapLimit         = 1000
+	maxRescanIndexLimit = 100_000
+)
+
+// hdDerivedAddress 派生出的一个地址，exist 表示地址已登记过
+type hdDerivedAddress struct {
+	index     uint32
+	publicKey string
+	address   string
+	exist     bool
+	balance   *big.Int
+}
+
+// supportsHDDerivation ed25519 链只能强化派生，扩展公钥无法派生子公钥
+func supportsHDDerivation(chain string) bool {
+	switch database.GetTxFamily(chain) {
+	case database.TxFamilySolana, database.TxFamilyTon:
+		return false
+	}
+	return true
+}
+
+// defaultAddressFormat utxo 链按扩展公钥前缀确定地址格式，其他链使用 chain-account 的默认格式
+func defaultAddressFormat(chain string, key *hdkey.ExtendedKey, requested string) string {
+	if requested = strings.ToLower(strings.TrimSpace(requested)); requested != "" {
+		return requested
+	}
+	if !database.IsUTXOChain(chain) {
+		return ""
+	}
+	switch key.Prefix() {
+	case "ypub", "upub":
+		return "p2sh-p2wpkh"
+	case "zpub", "vpub":
+		return "p2wpkh"
+	default:
+		return "p2pkh"
+	}
+}
+
+// hdPublicKeyHex evm 和 tron 地址由非压缩公钥生成，其他链使用压缩公钥
+func hdPublicKeyHex(chain string, key *hdkey.ExtendedKey) string {
+	config, _ := database.GetChainConfig(chain)
+	if config.IsEVM || config.TxFamily == database.TxFamilyTron {
+		return hex.EncodeToString(key.UncompressedPublicKey())
+	}
+	return hex.EncodeToString(key.PublicKey())
+}
+
+func (bws *BusinessMiddleWireServices) deriveAddress(key *hdkey.ExtendedKey, addressFormat string, index uint32) (*hdDerivedAddress, error) {
+	child, err := key.Derive(0, index)
+	if err != nil {
+		return nil, fmt.Errorf("derive index %d failed: %w", index, err)
+	}
+	publicKey := hdPublicKeyHex(bws.chainName, child)
+	address := database.NormalizeAddress(bws.chainName, bws.accountClient.ExportAddressByPubKey(addressFormat, publicKey))
+	if address == "" {
+		return nil, fmt.Errorf("convert address of index %d failed", index)
+	}
+	return &hdDerivedAddress{index: index, publicKey: publicKey, address: address}, nil
+}
+
+func derivedAddressRecords(keyRecord *database.ExtendedKeys, derivedList []*hdDerivedAddress) ([]*database.Addresses, []*database.Balances) {
+	addressList := make([]*database.Addresses, 0, len(derivedList))
+	balanceList := make([]*database.Balances, 0, len(derivedList))
+	for _, derived := range derivedList {
+		balance := derived.balance
+		if balance == nil {
+			balance = big.NewInt(0)
+		}
+		addressList = append(addressList, &database.Addresses{
+			GUID:            uuid.New(),
+			Address:         derived.address,
+			AddressType:     keyRecord.AddressType,
+			PublicKey:       derived.publicKey,
+			ExtendedKeyId:   keyRecord.GUID.String(),
+			DerivationIndex: derived.index,
+			Timestamp:       uint64(time.Now().Unix()),
+		})
+		balanceList = append(balanceList, &database.Balances{
+			GUID:         uuid.New(),
+			Address:      derived.address,
+			TokenAddress: common.Address{}.String(),
+			AddressType:  keyRecord.AddressType,
+			Balance:      balance,
+			LockBalance:  big.NewInt(0),
+			Timestamp:    uint64(time.Now().Unix()),
+		})
+	}
+	return addressList, balanceList
+}
+
+func toDerivedAddresses(keyRecord *database.ExtendedKeys, derivedList []*hdDerivedAddress) []*dal_wallet_go.DerivedAddress {
+	retList := make([]*dal_wallet_go.DerivedAddress, 0, len(derivedList))
+	for _, derived := range derivedList {
+		retList = append(retList, &dal_wallet_go.DerivedAddress{
+			Index:       derived.index,
+			Address:     derived.address,
+			PublicKey:   derived.publicKey,
+			AddressType: string(keyRecord.AddressType),
+		})
+	}
+	return retList
+}
+
+// RegisterExtendedKey 登记账户级扩展公钥，之后由服务端按序号派生地址
+func (bws *BusinessMiddleWireServices) RegisterExtendedKey(ctx context.Context, request *dal_wallet_go.RegisterExtendedKeyRequest) (*dal_wallet_go.RegisterExtendedKeyResponse, error) {
+	response := &dal_wallet_go.RegisterExtendedKeyResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" || request.ExtendedKey == "" {
+		response.Msg = "invalid params"
+		return response, nil
+	}
+	if !supportsHDDerivation(bws.chainName) {
+		response.Msg = "extended public key derivation is not supported on chain " + bws.chainName
+		return response, nil
+	}
+	if err := bws.checkBusinessActive(request.RequestId); err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
+	key, err := hdkey.ParseExtendedKey(request.ExtendedKey)
+	if err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
+	addressType := database.AddressTypeEOA
+	if request.AddressType != "" {
+		addressType, err = database.ParseAddressType(request.AddressType)
+		if err != nil {
+			response.Msg = err.Error()
+			return response, nil
+		}
+	}
+
+	keyRecord, err := bws.db.ExtendedKeys.StoreExtendedKey(request.RequestId, bws.chainName, &database.ExtendedKeys{
+		GUID:          uuid.New(),
+		ExtendedKey:   key.String(),
+		AddressFormat: defaultAddressFormat(bws.chainName, key, request.AddressFormat),
+		AddressType:   addressType,
+		Timestamp:     uint64(time.Now().Unix()),
+	})
+	if err != nil {
+		log.Error("store extended key fail", "err", err)
+		response.Msg = "store extended key fail"
+		return response, nil
+	}
+	if keyRecord.AddressType != addressType {
+		response.Msg = fmt.Sprintf("extended key already registered for address type %s", keyRecord.AddressType)
+		return response, nil
+	}
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "register extended key success"
+	response.KeyId = keyRecord.GUID.String()
+	response.AddressFormat = keyRecord.AddressFormat
+	response.NextIndex = keyRecord.NextIndex
+	return response, nil
+}
+
+// DeriveAddresses 从扩展公钥的下一个序号开始派生并登记地址，派生失败时序号不前进
+func (bws *BusinessMiddleWireServices) DeriveAddresses(ctx context.Context, request *dal_wallet_go.DeriveAddressesRequest) (*dal_wallet_go.DeriveAddressesResponse, error) {
+	response := &dal_wallet_go.DeriveAddressesResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" || request.KeyId == "" || request.Count == 0 {
+		response.Msg = "invalid params"
+		return response, nil
+	}
+	if request.Count > maxDeriveCount {
+		response.Msg = fmt.Sprintf("too many addresses, derive at most %d at a time", maxDeriveCount)
+		return response, nil
+	}
+	if err := bws.checkBusinessActive(request.RequestId); err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
+
+	var (
+		keyRecord   *database.ExtendedKeys
+		derivedList []*hdDerivedAddress
+		nextIndex   uint32
+	)
+	errKeyNotFound := errors.New("extended key not found")
+	err := bws.db.Transaction(func(tx *database.DB) error {
+		var err error
+		keyRecord, err = tx.ExtendedKeys.LockExtendedKey(request.RequestId, bws.chainName, request.KeyId)
+		if err != nil {
+			return err
+		}
+		if keyRecord == nil {
+			return errKeyNotFound
+		}
+		key, err := hdkey.ParseExtendedKey(keyRecord.ExtendedKey)
+		if err != nil {
+			return err
+		}
+		nextIndex = keyRecord.NextIndex
+		for len(derivedList) < int(request.Count) {
+			if nextIndex >= hdkey.HardenedOffset {
+				return errors.New("extended key has no unused non-hardened index left")
+			}
+			derived, err := bws.deriveAddress(key, keyRecord.AddressFormat, nextIndex)
+			nextIndex++
+			if errors.Is(err, hdkey.ErrInvalidChild) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if exist, _ := tx.Addresses.AddressExist(request.RequestId, bws.chainName, derived.address); exist {
+				log.Warn("derived address already registered", "keyId", request.KeyId, "index", derived.index, "address", derived.address)
+				continue
+			}
+			derivedList = append(derivedList, derived)
+		}
+		addressList, balanceList := derivedAddressRecords(keyRecord, derivedList)
+		if err := tx.Addresses.StoreAddresses(request.RequestId, bws.chainName, addressList); err != nil {
+			return err
+		}
+		if err := tx.Balances.StoreBalances(request.RequestId, bws.chainName, balanceList); err != nil {
+			return err
+		}
+		return tx.ExtendedKeys.UpdateNextIndex(request.RequestId, bws.chainName, request.KeyId, nextIndex)
+	})
+	if err != nil {
+		if errors.Is(err, errKeyNotFound) {
+			response.Msg = err.Error()
+			return response, nil
+		}
+		log.Error("derive addresses fail", "keyId", request.KeyId, "err", err)
+		response.Msg = "derive addresses fail: " + err.Error()
+		return response, nil
+	}
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "derive addresses success"
+	response.Addresses = toDerivedAddresses(keyRecord, derivedList)
+	response.NextIndex = nextIndex
+	return response, nil
+}
+
+// RescanExtendedKey 从序号 0 开始检查派生地址是否使用过，连续 gap_limit 个未使用时停止，
+// 登记最后一个已使用地址及之前所有未登记的地址，用于数据丢失后的恢复。
+// 有过交易的地址即为使用过，归集后余额为 0 的充值地址同样需要恢复；扫描期间锁定扩展公钥，与 DeriveAddresses 互斥
+func (bws *BusinessMiddleWireServices) RescanExtendedKey(ctx context.Context, request *dal_wallet_go.RescanExtendedKeyRequest) (*dal_wallet_go.RescanExtendedKeyResponse, error) {
+	response := &dal_wallet_go.RescanExtendedKeyResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" || request.KeyId == "" {
+		response.Msg = "invalid params"
+		return response, nil
+	}
+	gapLimit := request.GapLimit
+	if gapLimit == 0 {
+		gapLimit = defaultGapLimit
+	}
+	if gapLimit > maxGapLimit {
+		response.Msg = fmt.Sprintf("gap limit cannot exceed %d", maxGapLimit)
+		return response, nil
+	}
+	if err := bws.checkBusinessActive(request.RequestId); err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
+
+	var (
+		keyRecord *database.ExtendedKeys
+		recovered []*hdDerivedAddress
+		index     uint32
+		nextIndex uint32
+	)
+	errKeyNotFound := errors.New("extended key not found")
+	err := bws.db.Transaction(func(tx *database.DB) error {
+		var err error
+		keyRecord, err = tx.ExtendedKeys.LockExtendedKey(request.RequestId, bws.chainName, request.KeyId)
+		if err != nil {
+			return err
+		}
+		if keyRecord == nil {
+			return errKeyNotFound
+		}
+		key, err := hdkey.ParseExtendedKey(keyRecord.ExtendedKey)
+		if err != nil {
+			return err
+		}
+
+		var (
+			candidates []*hdDerivedAddress
+			lastUsed   = -1
+			gap        uint32
+		)
+		for ; gap < gapLimit && index < maxRescanIndexLimit; index++ {
+			derived, err := bws.deriveAddress(key, keyRecord.AddressFormat, index)
+			if errors.Is(err, hdkey.ErrInvalidChild) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			derived.exist, _ = tx.Addresses.AddressExist(request.RequestId, bws.chainName, derived.address)
+			used := derived.exist
+			if !derived.exist {
+				used, err = bws.addressUsed(tx, request.RequestId, derived.address)
+				if err != nil {
+					return fmt.Errorf("check history of index %d failed: %w", index, err)
+				}
+				derived.balance, err = bws.accountClient.GetAccountBalance(derived.address, "")
+				if err != nil {
+					return fmt.Errorf("query balance of index %d failed: %w", index, err)
+				}
+				used = used || (derived.balance != nil && derived.balance.Sign() > 0)
+				candidates = append(candidates, derived)
+			}
+			if used {
+				lastUsed = int(index)
+				gap = 0
+			} else {
+				gap++
+			}
+		}
+
+		for _, derived := range candidates {
+			if int(derived.index) <= lastUsed {
+				recovered = append(recovered, derived)
+			}
+		}
+		nextIndex = uint32(lastUsed + 1)
+		if len(recovered) > 0 {
+			addressList, balanceList := derivedAddressRecords(keyRecord, recovered)
+			if err := tx.Addresses.StoreAddresses(request.RequestId, bws.chainName, addressList); err != nil {
+				return err
+			}
+			if err := tx.Balances.StoreBalances(request.RequestId, bws.chainName, balanceList); err != nil {
+				return err
+			}
+		}
+		return tx.ExtendedKeys.UpdateNextIndex(request.RequestId, bws.chainName, request.KeyId, nextIndex)
+	})
+	if err != nil {
+		if errors.Is(err, errKeyNotFound) {
+			response.Msg = err.Error()
+			return response, nil
+		}
+		log.Error("rescan extended key fail", "keyId", request.KeyId, "err", err)
+		response.Msg = "rescan extended key fail: " + err.Error()
+		return response, nil
+	}
+	if nextIndex < keyRecord.NextIndex {
+		nextIndex = keyRecord.NextIndex
+	}
+	log.Info("rescan extended key", "keyId", request.KeyId, "scanned", index, "recovered", len(recovered), "nextIndex", nextIndex)
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "rescan extended key success"
+	response.Scanned = index
+	response.Recovered = toDerivedAddresses(keyRecord, recovered)
+	response.NextIndex = nextIndex
+	return response, nil
+}
+
+// addressUsed 已扫描的交易中出现过或链上有交易记录的地址视为使用过
+func (bws *BusinessMiddleWireServices) addressUsed(tx *database.DB, requestId, address string) (bool, error) {
+	scanned, err := tx.Transactions.AddressHasTransactions(requestId, bws.chainName, address)
+	if err != nil {
+		return false, err
+	}
+	if scanned {
+		return true, nil
+	}
+	return bws.accountClient.HasTxHistory(address)
+}
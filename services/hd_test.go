@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/dapplink-labs/multichain-sync-account/common/hdkey"
+)
+
+const testXpub = "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw"
+
+func TestHDAddressFormatAndPublicKey(t *testing.T) {
+	key, err := hdkey.ParseExtendedKey(testXpub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format := defaultAddressFormat("bitcoin", key, ""); format != "p2pkh" {
+		t.Fatalf("xpub on bitcoin should default to p2pkh, got %q", format)
+	}
+	if format := defaultAddressFormat("bitcoin", key, "P2TR"); format != "p2tr" {
+		t.Fatalf("requested format should win, got %q", format)
+	}
+	if format := defaultAddressFormat("ethereum", key, ""); format != "" {
+		t.Fatalf("account chains use the chain default format, got %q", format)
+	}
+
+	child, err := key.Derive(0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub := hdPublicKeyHex("ethereum", child); len(pub) != 130 || pub[:2] != "04" {
+		t.Fatalf("evm needs the uncompressed public key, got %s", pub)
+	}
+	if pub := hdPublicKeyHex("bitcoin", child); len(pub) != 66 {
+		t.Fatalf("bitcoin needs the compressed public key, got %s", pub)
+	}
+	if supportsHDDerivation("solana") || !supportsHDDerivation("bitcoin") {
+		t.Fatal("ed25519 chains cannot derive from an extended public key")
+	}
+}
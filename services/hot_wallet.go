@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	"github.com/dapplink-labs/multichain-sync-account/protobuf/dal-wallet-go"
+)
+
+// errHotWalletUnavailable 没有登记热钱包或所有热钱包余额都不足，作为业务错误返回
+var errHotWalletUnavailable = errors.New("no hot wallet available")
+
+// tokenDemand 一次出款需要的某个代币的金额
+type tokenDemand struct {
+	token  string
+	amount *big.Int
+}
+
+// hotWallet 热钱包的出款能力，available 为余额表中的可用余额，pending 为已创建但未广播(尚未锁定)的出款金额
+type hotWallet struct {
+	address    string
+	balances   []*database.Balances
+	available  map[string]*big.Int
+	pending    map[string]*big.Int
+	pendingTxs map[string]struct{}
+}
+
+func newHotWallet(address string) *hotWallet {
+	return &hotWallet{
+		address:    address,
+		available:  make(map[string]*big.Int),
+		pending:    make(map[string]*big.Int),
+		pendingTxs: make(map[string]struct{}),
+	}
+}
+
+// hotWalletToken 代币的统一键，主币的各种写法都归为空串
+func hotWalletToken(chainName string, tokenAddress string) string {
+	if database.IsNativeToken(chainName, tokenAddress) {
+		return ""
+	}
+	return database.NormalizeAddress(chainName, tokenAddress)
+}
+
+func (w *hotWallet) addBalance(chainName string, balance *database.Balances) {
+	w.balances = append(w.balances, balance)
+	token := hotWalletToken(chainName, balance.TokenAddress)
+	if w.available[token] == nil {
+		w.available[token] = new(big.Int)
+	}
+	w.available[token].Add(w.available[token], balance.Balance)
+}
+
+// addPending 记录一笔未确认的出款，已广播的金额已经转入锁定余额，只计入排队交易数
+func (w *hotWallet) addPending(chainName string, txId string, tokenAddress string, amount *big.Int, status database.TxStatus) {
+	w.pendingTxs[txId] = struct{}{}
+	if status == database.TxStatusBroadcasted || amount == nil {
+		return
+	}
+	token := hotWalletToken(chainName, tokenAddress)
+	if w.pending[token] == nil {
+		w.pending[token] = new(big.Int)
+	}
+	w.pending[token].Add(w.pending[token], amount)
+}
+
+// reserve 把已选中但尚未落库的出款计入未广播金额
+func (w *hotWallet) reserve(demand []tokenDemand) {
+	for _, d := range demand {
+		if w.pending[d.token] == nil {
+			w.pending[d.token] = new(big.Int)
+		}
+		w.pending[d.token].Add(w.pending[d.token], d.amount)
+	}
+}
+
+// hotWalletReservation 已选出热钱包、提现还没落库的出款，落库或失败后释放；只在本进程内生效
+type hotWalletReservation struct {
+	requestId string
+	address   string
+	demand    []tokenDemand
+}
+
+func (w *hotWallet) pendingAmount(token string) *big.Int {
+	if pending := w.pending[token]; pending != nil {
+		return pending
+	}
+	return new(big.Int)
+}
+
+// spendable 可用余额扣除未广播的出款金额
+func (w *hotWallet) spendable(token string) *big.Int {
+	available := w.available[token]
+	if available == nil {
+		return new(big.Int)
+	}
+	spendable := new(big.Int).Sub(available, w.pendingAmount(token))
+	if spendable.Sign() < 0 {
+		return new(big.Int)
+	}
+	return spendable
+}
+
+func (w *hotWallet) covers(demand []tokenDemand) bool {
+	for _, d := range demand {
+		if w.spendable(d.token).Cmp(d.amount) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// pickHotWallet 在余额足够的热钱包中按策略选择出款地址，wallets 按登记时间排序，
+// cursor 为轮询的起始位置，返回下一次轮询的起始位置
+func pickHotWallet(strategy database.HotWalletStrategy, wallets []*hotWallet, demand []tokenDemand, cursor int) (*hotWallet, int, error) {
+	if len(wallets) == 0 {
+		return nil, cursor, fmt.Errorf("%w: business has no hot wallet", errHotWalletUnavailable)
+	}
+	var selected *hotWallet
+	switch strategy {
+	case database.HotWalletMostFunded:
+		// 多个代币时按第一个代币的可用余额比较
+		for _, w := range wallets {
+			if !w.covers(demand) {
+				continue
+			}
+			if selected == nil || len(demand) > 0 && w.spendable(demand[0].token).Cmp(selected.spendable(demand[0].token)) > 0 {
+				selected = w
+			}
+		}
+	case database.HotWalletLeastPendingNonce:
+		for _, w := range wallets {
+			if w.covers(demand) && (selected == nil || len(w.pendingTxs) < len(selected.pendingTxs)) {
+				selected = w
+			}
+		}
+	default:
+		for i := range wallets {
+			index := (cursor + i) % len(wallets)
+			if wallets[index].covers(demand) {
+				return wallets[index], index + 1, nil
+			}
+		}
+	}
+	if selected == nil {
+		return nil, cursor, fmt.Errorf("%w: no hot wallet has enough spendable balance", errHotWalletUnavailable)
+	}
+	return selected, cursor, nil
+}
+
+// loadHotWallets 汇总业务全部热钱包的余额和未确认出款
+func (bws *BusinessMiddleWireServices) loadHotWallets(requestId string) ([]*hotWallet, error) {
+	addresses, err := bws.db.Addresses.QueryHotWallets(requestId, bws.chainName)
+	if err != nil {
+		return nil, err
+	}
+	wallets := make([]*hotWallet, 0, len(addresses))
+	byAddress := make(map[string]*hotWallet, len(addresses))
+	for _, address := range addresses {
+		key := database.NormalizeAddress(bws.chainName, address.Address)
+		if _, ok := byAddress[key]; ok {
+			continue
+		}
+		wallet := newHotWallet(key)
+		wallets = append(wallets, wallet)
+		byAddress[key] = wallet
+	}
+	if len(wallets) == 0 {
+		return wallets, nil
+	}
+
+	balances, err := bws.db.Balances.QueryBalancesByAddressType(requestId, bws.chainName, database.AddressTypeHot)
+	if err != nil {
+		return nil, err
+	}
+	for _, balance := range balances {
+		if wallet := byAddress[database.NormalizeAddress(bws.chainName, balance.Address)]; wallet != nil {
+			wallet.addBalance(bws.chainName, balance)
+		}
+	}
+
+	withdraws, err := bws.db.Withdraws.QueryPendingWithdraws(requestId, bws.chainName)
+	if err != nil {
+		return nil, err
+	}
+	for _, withdraw := range withdraws {
+		if wallet := byAddress[database.NormalizeAddress(bws.chainName, withdraw.FromAddress)]; wallet != nil {
+			wallet.addPending(bws.chainName, withdraw.SpendGuid(), withdraw.TokenAddress, withdraw.Amount, withdraw.Status)
+		}
+	}
+	internals, err := bws.db.Internals.QueryPendingInternals(requestId, bws.chainName)
+	if err != nil {
+		return nil, err
+	}
+	for _, internal := range internals {
+		if wallet := byAddress[database.NormalizeAddress(bws.chainName, internal.FromAddress)]; wallet != nil {
+			wallet.addPending(bws.chainName, internal.GUID.String(), internal.TokenAddress, internal.Amount, internal.Status)
+		}
+	}
+	return wallets, nil
+}
+
+// selectHotWallet 按业务配置的策略为未指定出款地址的提现选择热钱包，选中的金额在调用 release 之前计入该热钱包的在途出款，
+// 调用方在提现落库或创建失败后调用 release，避免并发请求选中只够支付其中一笔的热钱包
+func (bws *BusinessMiddleWireServices) selectHotWallet(requestId string, demand []tokenDemand) (string, func(), error) {
+	business, err := bws.db.Business.QueryBusinessByUuid(requestId)
+	if err != nil {
+		return "", nil, fmt.Errorf("query business fail: %w", err)
+	}
+
+	bws.hotWalletMu.Lock()
+	defer bws.hotWalletMu.Unlock()
+	wallets, err := bws.loadHotWallets(requestId)
+	if err != nil {
+		return "", nil, fmt.Errorf("load hot wallets fail: %w", err)
+	}
+	for reservation := range bws.hotWalletReserved {
+		if reservation.requestId != requestId {
+			continue
+		}
+		for _, wallet := range wallets {
+			if wallet.address == reservation.address {
+				wallet.reserve(reservation.demand)
+			}
+		}
+	}
+	selected, cursor, err := pickHotWallet(business.WalletStrategy(), wallets, demand, bws.hotWalletCursor[requestId])
+	if err != nil {
+		return "", nil, err
+	}
+	bws.hotWalletCursor[requestId] = cursor
+	reservation := &hotWalletReservation{requestId: requestId, address: selected.address, demand: demand}
+	bws.hotWalletReserved[reservation] = struct{}{}
+	log.Info("select hot wallet", "requestId", requestId, "strategy", business.WalletStrategy(), "address", selected.address)
+	release := func() {
+		bws.hotWalletMu.Lock()
+		defer bws.hotWalletMu.Unlock()
+		delete(bws.hotWalletReserved, reservation)
+	}
+	return selected.address, release, nil
+}
+
+// collectionTarget 未指定接收地址的归集发往业务配置的归集地址，没有配置时发往最早登记的热钱包
+func (bws *BusinessMiddleWireServices) collectionTarget(requestId string) (string, error) {
+	business, err := bws.db.Business.QueryBusinessByUuid(requestId)
+	if err != nil {
+		return "", fmt.Errorf("query business fail: %w", err)
+	}
+	if business.CollectionTarget != "" {
+		return business.CollectionTarget, nil
+	}
+	hotWallet, err := bws.db.Addresses.QueryHotWalletInfo(requestId, bws.chainName)
+	if err != nil {
+		return "", fmt.Errorf("query hot wallet failed: %w", err)
+	}
+	if hotWallet == nil {
+		return "", fmt.Errorf("%w: no collection target configured", errHotWalletUnavailable)
+	}
+	return hotWallet.Address, nil
+}
+
+// GetHotWallets 查询业务每个热钱包的余额、在途出款和排队交易数
+func (bws *BusinessMiddleWireServices) GetHotWallets(ctx context.Context, request *dal_wallet_go.HotWalletsRequest) (*dal_wallet_go.HotWalletsResponse, error) {
+	response := &dal_wallet_go.HotWalletsResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" {
+		response.Msg = "invalid params"
+		return response, nil
+	}
+	business, err := bws.db.Business.QueryBusinessByUuid(request.RequestId)
+	if err != nil {
+		log.Error("query business fail", "requestId", request.RequestId, "err", err)
+		response.Msg = "query business fail"
+		return response, nil
+	}
+	wallets, err := bws.loadHotWallets(request.RequestId)
+	if err != nil {
+		log.Error("load hot wallets fail", "requestId", request.RequestId, "err", err)
+		response.Msg = "query hot wallets fail"
+		return response, nil
+	}
+
+	target := business.CollectionTarget
+	if target == "" && len(wallets) > 0 {
+		target = wallets[0].address
+	}
+	for _, wallet := range wallets {
+		item := &dal_wallet_go.HotWallet{
+			Address:          wallet.address,
+			PendingTxCount:   uint32(len(wallet.pendingTxs)),
+			CollectionTarget: wallet.address == database.NormalizeAddress(bws.chainName, target),
+		}
+		for _, balance := range wallet.balances {
+			token := hotWalletToken(bws.chainName, balance.TokenAddress)
+			item.Balances = append(item.Balances, &dal_wallet_go.HotWalletBalance{
+				TokenAddress:  balance.TokenAddress,
+				Balance:       balance.Balance.String(),
+				LockBalance:   balance.LockBalance.String(),
+				PendingAmount: wallet.pendingAmount(token).String(),
+				Spendable:     wallet.spendable(token).String(),
+			})
+		}
+		response.Wallets = append(response.Wallets, item)
+	}
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "query hot wallets success"
+	response.Strategy = string(business.WalletStrategy())
+	response.CollectionTarget = target
+	return response, nil
+}
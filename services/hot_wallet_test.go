@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	dal_wallet_go "github.com/dapplink-labs/multichain-sync-account/protobuf/dal-wallet-go"
+)
+
+func testHotWallet(address string, available int64, pending int64, pendingTxs int) *hotWallet {
+	w := newHotWallet(address)
+	w.addBalance("ethereum", &database.Balances{Address: address, TokenAddress: "0x0000000000000000000000000000000000000000", Balance: big.NewInt(available)})
+	if pending > 0 {
+		w.addPending("ethereum", "pending", "0x00", big.NewInt(pending), database.TxStatusSigned)
+	}
+	for i := 0; i < pendingTxs; i++ {
+		w.addPending("ethereum", address+string(rune('a'+i)), "", big.NewInt(1), database.TxStatusBroadcasted)
+	}
+	return w
+}
+
+func TestHotWalletSpendable(t *testing.T) {
+	w := testHotWallet("a", 100, 30, 0)
+	// 已广播的出款已经锁定，不再从可用余额中扣除
+	w.addPending("ethereum", "broadcasted", "", big.NewInt(50), database.TxStatusBroadcasted)
+	if got := w.spendable(""); got.Cmp(big.NewInt(70)) != 0 {
+		t.Fatalf("spendable = %s, want 70", got)
+	}
+	if got := len(w.pendingTxs); got != 2 {
+		t.Fatalf("pending txs = %d, want 2", got)
+	}
+	if got := w.spendable("0xdAC17F958D2ee523a2206206994597C13D831ec7"); got.Sign() != 0 {
+		t.Fatalf("token without balance spendable = %s", got)
+	}
+}
+
+func TestHotWalletReserve(t *testing.T) {
+	wallets := []*hotWallet{testHotWallet("a", 100, 0, 0), testHotWallet("b", 60, 0, 0)}
+	demand := []tokenDemand{{token: "", amount: big.NewInt(80)}}
+	// a 已被另一笔尚未落库的提现选中，剩余可用余额不够第二笔
+	wallets[0].reserve(demand)
+	if got := wallets[0].spendable(""); got.Cmp(big.NewInt(20)) != 0 {
+		t.Fatalf("spendable = %s, want 20", got)
+	}
+	if _, _, err := pickHotWallet(database.HotWalletMostFunded, wallets, demand, 0); !errors.Is(err, errHotWalletUnavailable) {
+		t.Fatalf("reserved wallet should not be picked again, err = %v", err)
+	}
+	selected, _, err := pickHotWallet(database.HotWalletMostFunded, wallets, []tokenDemand{{token: "", amount: big.NewInt(50)}}, 0)
+	if err != nil || selected.address != "b" {
+		t.Fatalf("selected = %+v, err = %v", selected, err)
+	}
+}
+
+func TestHotWalletStrategies(t *testing.T) {
+	wallets := []*hotWallet{
+		testHotWallet("a", 100, 0, 3),
+		testHotWallet("b", 500, 0, 2),
+		testHotWallet("c", 300, 250, 0),
+		testHotWallet("d", 200, 0, 1),
+	}
+	demand := []tokenDemand{{token: "", amount: big.NewInt(100)}}
+
+	selected, _, err := pickHotWallet(database.HotWalletMostFunded, wallets, demand, 0)
+	if err != nil || selected.address != "b" {
+		t.Fatalf("most funded selected %v, err %v", selected, err)
+	}
+	// c 排队最少但扣除未广播金额后余额不足
+	selected, _, err = pickHotWallet(database.HotWalletLeastPendingNonce, wallets, demand, 0)
+	if err != nil || selected.address != "d" {
+		t.Fatalf("least pending nonce selected %v, err %v", selected, err)
+	}
+
+	var got []string
+	cursor := 0
+	for i := 0; i < 4; i++ {
+		selected, cursor, err = pickHotWallet(database.HotWalletRoundRobin, wallets, demand, cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, selected.address)
+	}
+	if want := []string{"a", "b", "d", "a"}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] || got[3] != want[3] {
+		t.Fatalf("round robin order %v, want %v", got, want)
+	}
+
+	_, _, err = pickHotWallet(database.HotWalletRoundRobin, wallets, []tokenDemand{{token: "", amount: big.NewInt(600)}}, 0)
+	if !errors.Is(err, errHotWalletUnavailable) {
+		t.Fatalf("insufficient balance err = %v", err)
+	}
+	_, _, err = pickHotWallet(database.HotWalletMostFunded, nil, demand, 0)
+	if !errors.Is(err, errHotWalletUnavailable) {
+		t.Fatalf("no hot wallet err = %v", err)
+	}
+}
+
+func TestHotWalletBatchDemand(t *testing.T) {
+	const usdt = "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+	items := []*batchItem{
+		{request: &dal_wallet_go.UnSignTransactionRequest{ContractAddress: "0x00"}, amount: big.NewInt(10)},
+		{request: &dal_wallet_go.UnSignTransactionRequest{ContractAddress: "0xdac17f958d2ee523a2206206994597c13d831ec7"}, amount: big.NewInt(5)},
+		{request: &dal_wallet_go.UnSignTransactionRequest{ContractAddress: ""}, amount: big.NewInt(7)},
+		{request: &dal_wallet_go.UnSignTransactionRequest{ContractAddress: usdt}, amount: big.NewInt(1)},
+	}
+	demand := batchDemand("ethereum", items)
+	if len(demand) != 2 {
+		t.Fatalf("demand %v, want native and usdt", demand)
+	}
+	if demand[0].token != "" || demand[0].amount.Cmp(big.NewInt(17)) != 0 {
+		t.Errorf("native demand = %s %s", demand[0].token, demand[0].amount)
+	}
+	if demand[1].token != usdt || demand[1].amount.Cmp(big.NewInt(6)) != 0 {
+		t.Errorf("token demand = %s %s", demand[1].token, demand[1].amount)
+	}
+	if items[0].amount.Cmp(big.NewInt(10)) != 0 {
+		t.Error("batch demand modified the item amount")
+	}
+}
+
+func TestHotWalletBalanceLimits(t *testing.T) {
+	minBalance, targetBalance, ok := parseHotBalances("100", "")
+	if !ok || minBalance.Cmp(big.NewInt(100)) != 0 || targetBalance.Sign() != 0 {
+		t.Fatalf("parse min only = %v %v %v", minBalance, targetBalance, ok)
+	}
+	if _, _, ok := parseHotBalances("100", "500"); !ok {
+		t.Error("target above min rejected")
+	}
+	for _, c := range [][2]string{{"100", "50"}, {"-1", ""}, {"1.5", ""}, {"", "abc"}} {
+		if _, _, ok := parseHotBalances(c[0], c[1]); ok {
+			t.Errorf("invalid hot balances %v accepted", c)
+		}
+	}
+}
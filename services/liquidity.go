@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	"github.com/dapplink-labs/multichain-sync-account/protobuf/dal-wallet-go"
+)
+
+// PrepareUnSignTransaction 为 worker 创建的内部交易(如热钱包补充的 cold2hot)生成待签名数据并落库，重复调用返回已生成的待签名哈希
+func (bws *BusinessMiddleWireServices) PrepareUnSignTransaction(ctx context.Context, request *dal_wallet_go.PrepareUnSignTransactionRequest) (*dal_wallet_go.UnSignTransactionResponse, error) {
+	response := &dal_wallet_go.UnSignTransactionResponse{
+		Code:     dal_wallet_go.ReturnCode_ERROR,
+		UnSignTx: "0x00",
+	}
+	if request.RequestId == "" || request.TransactionId == "" {
+		response.Msg = "invalid params"
+		return response, nil
+	}
+	if err := bws.checkBusinessActive(request.RequestId); err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
+	internal, err := bws.db.Internals.QueryInternalsById(request.RequestId, bws.chainName, request.TransactionId)
+	if err != nil {
+		return nil, fmt.Errorf("query internal failed: %w", err)
+	}
+	if internal == nil {
+		response.Msg = "Internal transaction not found"
+		return response, nil
+	}
+	response.TransactionId = internal.GUID.String()
+	response.From, response.To = internal.FromAddress, internal.ToAddress
+	if internal.UnsignedTx != "" {
+		response.Code = dal_wallet_go.ReturnCode_SUCCESS
+		response.Msg = "un sign transaction already prepared"
+		response.UnSignTx = internal.SignHash
+		return response, nil
+	}
+	if internal.Status != database.TxStatusCreateUnsigned {
+		response.Msg = fmt.Sprintf("internal transaction is %s", internal.Status)
+		return response, nil
+	}
+
+	txRequest := &dal_wallet_go.UnSignTransactionRequest{
+		ConsumerToken:   request.ConsumerToken,
+		RequestId:       request.RequestId,
+		ChainId:         request.ChainId,
+		Chain:           request.Chain,
+		From:            internal.FromAddress,
+		To:              internal.ToAddress,
+		Value:           internal.Amount.String(),
+		ContractAddress: internal.TokenAddress,
+		TxType:          string(internal.TxType),
+		FeeRate:         request.FeeRate,
+	}
+	if database.IsUTXOChain(bws.chainName) {
+		return bws.prepareUtxoInternal(ctx, response, txRequest, internal)
+	}
+
+	builder, err := bws.txBuilder(request.Chain)
+	if err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
+	params := &TxParams{
+		Chain:           request.Chain,
+		ChainId:         chainIdOf(request.Chain, request.ChainId),
+		From:            internal.FromAddress,
+		To:              internal.ToAddress,
+		Amount:          internal.Amount.String(),
+		ContractAddress: internal.TokenAddress,
+		Strategy:        bws.FeeStrategies.Resolve(request.Chain, internal.TokenAddress),
+	}
+	fee, err := builder.Fee(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("get fee info failed: %w", err)
+	}
+	params.Fee = *fee
+	internal.GasLimit, internal.MaxFeePerGas, internal.MaxPriorityFeePerGas = fee.GasLimit, fee.MaxFeePerGas, fee.MaxPriorityFeePerGas
+	internal.GasPrice, internal.FeeStrategy = fee.GasPrice, fee.Strategy
+
+	// nonce 与提现共用出款地址的锁，分配和写入在同一个事务中完成
+	var signHash string
+	if err := bws.db.Transaction(func(tx *database.DB) error {
+		if usesNonce(database.GetTxFamily(request.Chain)) {
+			nonce, err := bws.nextNonce(ctx, tx, request.RequestId, request.Chain, internal.FromAddress)
+			if err != nil {
+				return err
+			}
+			params.Nonce = &nonce
+		}
+		base64Str, err := builder.Build(ctx, params)
+		if err != nil {
+			return fmt.Errorf("build transaction failed: %w", err)
+		}
+		signHash, err = bws.createUnSignTx(ctx, request.Chain, base64Str)
+		if err != nil {
+			log.Error("create un sign transaction fail", "err", err)
+			return err
+		}
+		internal.UnsignedTx, internal.SignHash, internal.Nonce = base64Str, signHash, params.Nonce
+		return tx.Internals.UpdateInternalUnsignedTx(request.RequestId, bws.chainName, internal)
+	}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Msg = "un sign transaction is being prepared by another request"
+			return response, nil
+		}
+		return nil, err
+	}
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "prepare un sign transaction success"
+	response.UnSignTx = signHash
+	return response, nil
+}
+
+// prepareUtxoInternal UTXO 链选币生成待签名数据，写入内部交易和锁定输出在同一个事务中完成
+func (bws *BusinessMiddleWireServices) prepareUtxoInternal(ctx context.Context, response *dal_wallet_go.UnSignTransactionResponse,
+	request *dal_wallet_go.UnSignTransactionRequest, internal *database.Internals) (*dal_wallet_go.UnSignTransactionResponse, error) {
+	if !database.IsNativeToken(bws.chainName, request.ContractAddress) {
+		response.Msg = "utxo chain only supports native token transfer"
+		return response, nil
+	}
+	unsigned, err := bws.buildUtxoUnSignTx(ctx, request, internal.Amount)
+	if err != nil {
+		var rejected *requestRejectedError
+		if errors.As(err, &rejected) {
+			response.Msg = err.Error()
+			return response, nil
+		}
+		return nil, err
+	}
+	signHash, err := bws.createUnSignTx(ctx, request.Chain, unsigned.base64Tx)
+	if err != nil {
+		log.Error("create un sign utxo transaction fail", "err", err)
+		return nil, err
+	}
+
+	internal.MaxFeePerGas, internal.MaxPriorityFeePerGas, internal.FeeStrategy = unsigned.fee.MaxFeePerGas, unsigned.fee.MaxPriorityFeePerGas, unsigned.fee.Strategy
+	internal.ChangeAddress, internal.ChangeAmount = unsigned.changeAddress, unsigned.changeAmount
+	internal.UnsignedTx, internal.SignHash = unsigned.base64Tx, signHash
+	if err := bws.db.Transaction(func(tx *database.DB) error {
+		if err := tx.Internals.UpdateInternalUnsignedTx(request.RequestId, bws.chainName, internal); err != nil {
+			return err
+		}
+		return tx.Utxos.LockUtxos(request.RequestId, bws.chainName, internal.GUID.String(), unsigned.selected)
+	}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Msg = "un sign transaction is being prepared by another request"
+			return response, nil
+		}
+		log.Error("store utxo transaction fail", "err", err)
+		return nil, fmt.Errorf("store utxo transaction failed: %w", err)
+	}
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "prepare un sign transaction success"
+	response.UnSignTx = signHash
+	return response, nil
+}
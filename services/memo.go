@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	dal_wallet_go "github.com/dapplink-labs/multichain-sync-account/protobuf/dal-wallet-go"
+)
+
+// RegisterDepositMemos 登记共用充值地址的 memo，地址必须是业务已导出的用户地址
+func (bws *BusinessMiddleWireServices) RegisterDepositMemos(ctx context.Context, request *dal_wallet_go.RegisterDepositMemosRequest) (*dal_wallet_go.RegisterDepositMemosResponse, error) {
+	response := &dal_wallet_go.RegisterDepositMemosResponse{
+		Code: dal_wallet_go.ReturnCode_ERROR,
+	}
+	if request.RequestId == "" || request.Address == "" || len(request.Memos) == 0 {
+		response.Msg = "invalid params"
+		return response, nil
+	}
+	if !database.SupportsMemo(bws.chainName) {
+		response.Msg = "memo is not supported on chain " + bws.chainName
+		return response, nil
+	}
+	if err := bws.checkBusinessActive(request.RequestId); err != nil {
+		response.Msg = err.Error()
+		return response, nil
+	}
+	exist, addressType := bws.db.Addresses.AddressExist(request.RequestId, bws.chainName, request.Address)
+	if !exist || addressType != database.AddressTypeEOA {
+		response.Msg = "address is not a deposit address of the business"
+		return response, nil
+	}
+
+	memoList := make([]*database.Memos, 0, len(request.Memos))
+	for _, memo := range request.Memos {
+		memo = strings.TrimSpace(memo)
+		if memo == "" {
+			response.Msg = "memo cannot be empty"
+			return response, nil
+		}
+		memoList = append(memoList, &database.Memos{
+			GUID:      uuid.New(),
+			Address:   request.Address,
+			Memo:      memo,
+			Timestamp: uint64(time.Now().Unix()),
+		})
+	}
+	if err := bws.db.Memos.StoreMemos(request.RequestId, bws.chainName, memoList); err != nil {
+		log.Error("store deposit memos fail", "address", request.Address, "err", err)
+		response.Msg = "store memos fail"
+		return response, nil
+	}
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "register deposit memos success"
+	return response, nil
+}
@@ -2,11 +2,14 @@ package services
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
+	"sync"
 	"sync/atomic"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/ethereum/go-ethereum/log"
@@ -19,8 +22,12 @@ import (
 const MaxRecvMessageSize = 1024 * 1024 * 300
 
 type BusinessMiddleConfig struct {
-	GrpcHostname string
-	GrpcPort     int
+	GrpcHostname   string
+	GrpcPort       int
+	TLS            *tls.Config
+	AllowedClients []string
+	ArchiveDir     string
+	FeeStrategies  *FeeStrategies
 }
 
 type BusinessMiddleWireServices struct {
@@ -29,6 +36,11 @@ type BusinessMiddleWireServices struct {
 	db            *database.DB
 	stopped       atomic.Bool
 	chainName     string
+
+	// hotWalletCursor 每个业务 round_robin 选择热钱包的轮询位置，hotWalletReserved 已选出热钱包但还没落库的出款
+	hotWalletMu       sync.Mutex
+	hotWalletCursor   map[string]int
+	hotWalletReserved map[*hotWalletReservation]struct{}
 }
 
 func (bws *BusinessMiddleWireServices) Stop(ctx context.Context) error {
@@ -46,6 +58,8 @@ func NewBusinessMiddleWireServices(db *database.DB, config *BusinessMiddleConfig
 		accountClient:        accountClient,
 		db:                   db,
 		chainName:            accountClient.ChainName,
+		hotWalletCursor:      make(map[string]int),
+		hotWalletReserved:    make(map[*hotWalletReservation]struct{}),
 	}, nil
 }
 
@@ -57,12 +71,18 @@ func (bws *BusinessMiddleWireServices) Start(ctx context.Context) error {
 		if err != nil {
 			log.Error("Could not start tcp listener. ")
 		}
-		gs := grpc.NewServer(
+		opts := []grpc.ServerOption{
 			grpc.MaxRecvMsgSize(MaxRecvMessageSize),
 			grpc.ChainUnaryInterceptor(
-				nil,
+				identityInterceptor(bws.AllowedClients),
 			),
-		)
+		}
+		if bws.TLS != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(bws.TLS)))
+		} else {
+			log.Warn("rpc server is running without tls", "addr", addr)
+		}
+		gs := grpc.NewServer(opts...)
 		reflection.Register(gs)
 
 		dal_wallet_go.RegisterBusinessMiddleWireServicesServer(gs, bws)
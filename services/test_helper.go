@@ -144,7 +144,7 @@ func setupService(t *testing.T, chainConfig ChainConfig) (*BusinessMiddleWireSer
 	assert.NoError(t, err)
 
 	client := account.NewWalletAccountServiceClient(conn)
-	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, chainConfig.ChainName)
+	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, chainConfig.ChainName, database.GetNetwork(chainConfig.ChainName))
 	assert.NoError(t, err)
 
 	bws, err := NewBusinessMiddleWireServices(db, bConfig, accountClient)
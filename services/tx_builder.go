@@ -0,0 +1,451 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/dapplink-labs/multichain-sync-account/common/json2"
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	"github.com/dapplink-labs/multichain-sync-account/rpcclient/chain-account/account"
+)
+
+// TxParams 构造交易的参数，创建待签名交易时来自请求，组装签名交易时从交易记录还原
+type TxParams struct {
+	Chain           string
+	ChainId         string
+	From            string
+	To              string
+	Amount          string
+	ContractAddress string
+	Memo            string
+	Strategy        FeeStrategy
+	Fee             TxFee
+	// Nonce 批量逐笔发送时创建阶段分配的 nonce，为空时查询账户当前 nonce
+	Nonce *uint64
+	// Recipients 不为空时为 multisend 交易，To 为 multisend 合约，Amount 为合计金额
+	Recipients []*MultiSendRecipient
+}
+
+// feeStrategy 未指定策略时使用默认策略
+func (p *TxParams) feeStrategy() FeeStrategy {
+	if p.Strategy.Speed == "" {
+		return DefaultFeeStrategy
+	}
+	return p.Strategy
+}
+
+// TxFee 创建待签名交易时确定并随交易落库，组装签名交易时原样使用，
+// EIP-1559 交易使用 MaxFeePerGas / MaxPriorityFeePerGas，legacy 交易只使用 GasPrice
+type TxFee struct {
+	GasLimit             uint64
+	MaxFeePerGas         string
+	MaxPriorityFeePerGas string
+	GasPrice             string
+	Strategy             string
+}
+
+// TxBuilder 按链族构造交易数据，负责手续费、gas 和 nonce 的处理
+type TxBuilder interface {
+	// Fee 查询链上费用，确定 gas limit 和手续费
+	Fee(ctx context.Context, params *TxParams) (*TxFee, error)
+	// Build 查询 nonce 等账户状态并生成 base64 编码的交易数据，同一笔交易的待签名和签名阶段必须生成相同的数据
+	Build(ctx context.Context, params *TxParams) (string, error)
+}
+
+var txBuilders = map[database.TxFamily]func(client account.WalletAccountServiceClient) TxBuilder{
+	database.TxFamilyEVM1559:   func(client account.WalletAccountServiceClient) TxBuilder { return &evm1559TxBuilder{client: client} },
+	database.TxFamilyEVMLegacy: func(client account.WalletAccountServiceClient) TxBuilder { return &evmLegacyTxBuilder{client: client} },
+	database.TxFamilyTron:      func(client account.WalletAccountServiceClient) TxBuilder { return &tronTxBuilder{client: client} },
+	database.TxFamilySolana:    func(client account.WalletAccountServiceClient) TxBuilder { return &solanaTxBuilder{client: client} },
+	database.TxFamilyCosmos:    func(client account.WalletAccountServiceClient) TxBuilder { return &cosmosTxBuilder{client: client} },
+	database.TxFamilyTon:       func(client account.WalletAccountServiceClient) TxBuilder { return &tonTxBuilder{client: client} },
+	database.TxFamilyXrp:       func(client account.WalletAccountServiceClient) TxBuilder { return &xrpTxBuilder{client: client} },
+}
+
+// txBuilder 按链注册表中的链族选择交易构造器
+func (bws *BusinessMiddleWireServices) txBuilder(chain string) (TxBuilder, error) {
+	return bws.txBuilderOf(chain, database.GetTxFamily(chain))
+}
+
+// signedTxBuilder 按交易记录的定价方式选择 EVM 交易格式，链配置在创建和签名之间切换时仍与待签名交易一致
+func (bws *BusinessMiddleWireServices) signedTxBuilder(chain string, fee TxFee) (TxBuilder, error) {
+	family := database.GetTxFamily(chain)
+	switch {
+	case family == database.TxFamilyEVM1559 && fee.GasPrice != "":
+		family = database.TxFamilyEVMLegacy
+	case family == database.TxFamilyEVMLegacy && fee.GasPrice == "":
+		family = database.TxFamilyEVM1559
+	}
+	return bws.txBuilderOf(chain, family)
+}
+
+func (bws *BusinessMiddleWireServices) txBuilderOf(chain string, family database.TxFamily) (TxBuilder, error) {
+	newBuilder, ok := txBuilders[family]
+	if !ok {
+		return nil, fmt.Errorf("no transaction builder for chain %s", chain)
+	}
+	return newBuilder(bws.accountClient.AccountRpClient), nil
+}
+
+// chainIdOf 请求未带 chain id 时使用链注册表中的配置
+func chainIdOf(chain, requested string) string {
+	if requested != "" {
+		return requested
+	}
+	if config, ok := database.GetChainConfig(chain); ok {
+		return config.ChainId
+	}
+	return ""
+}
+
+func queryAccount(ctx context.Context, client account.WalletAccountServiceClient, chain, address string) (*account.AccountResponse, error) {
+	accountInfo, err := client.GetAccount(ctx, &account.AccountRequest{
+		Chain:           chain,
+		Network:         database.GetNetwork(chain),
+		Address:         address,
+		ContractAddress: "0x00",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get account info failed: %w", err)
+	}
+	return accountInfo, nil
+}
+
+// queryFee wallet-chain-account 的 GetFee 不读取 raw_tx，EVM 链只返回 "baseFee|tip|*倍数"，没有 gas 预估
+func queryFee(ctx context.Context, client account.WalletAccountServiceClient, params *TxParams) (*account.FeeResponse, error) {
+	feeResponse, err := client.GetFee(ctx, &account.FeeRequest{
+		Chain:   params.Chain,
+		Network: database.GetNetwork(params.Chain),
+		RawTx:   "",
+		Address: params.From,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get fee failed: %w", err)
+	}
+	return feeResponse, nil
+}
+
+func gasLimitOf(chain, contractAddress string) uint64 {
+	if database.IsNativeToken(chain, contractAddress) {
+		return EthGasLimit
+	}
+	return TokenGasLimit
+}
+
+func encodeTx(tx interface{}) string {
+	return base64.StdEncoding.EncodeToString(json2.ToJSON(tx))
+}
+
+// evm1559TxBuilder EIP-1559 交易，nonce 为账户交易序号
+type evm1559TxBuilder struct {
+	client account.WalletAccountServiceClient
+}
+
+func (b *evm1559TxBuilder) Fee(ctx context.Context, params *TxParams) (*TxFee, error) {
+	feeInfo, err := evmFeeInfo(ctx, b.client, params)
+	if err != nil {
+		return nil, err
+	}
+	strategy := params.feeStrategy()
+	gasLimit, err := strategy.evmGasLimit(params)
+	if err != nil {
+		return nil, err
+	}
+	maxFee, maxFeeCapped := capFee(feeInfo.MaxPriorityFee, strategy.MaxFeeCap)
+	priorityFee, priorityFeeCapped := capFee(feeInfo.MultipliedTip, strategy.MaxPriorityFeeCap)
+	if priorityFee.Cmp(maxFee) > 0 {
+		priorityFee, priorityFeeCapped = maxFee, true
+	}
+	return &TxFee{
+		GasLimit:             gasLimit,
+		MaxFeePerGas:         maxFee.String(),
+		MaxPriorityFeePerGas: priorityFee.String(),
+		Strategy:             strategy.label(maxFeeCapped || priorityFeeCapped),
+	}, nil
+}
+
+func (b *evm1559TxBuilder) Build(ctx context.Context, params *TxParams) (string, error) {
+	nonce, err := evmNonce(ctx, b.client, params)
+	if err != nil {
+		return "", err
+	}
+	if len(params.Recipients) > 0 {
+		return encodeTx(newEvmMultiSendTx(params, nonce)), nil
+	}
+	return encodeTx(&Eip1559DynamicFeeTx{
+		ChainId:              params.ChainId,
+		Nonce:                nonce,
+		FromAddress:          params.From,
+		ToAddress:            params.To,
+		GasLimit:             params.Fee.GasLimit,
+		MaxFeePerGas:         params.Fee.MaxFeePerGas,
+		MaxPriorityFeePerGas: params.Fee.MaxPriorityFeePerGas,
+		Amount:               params.Amount,
+		ContractAddress:      params.ContractAddress,
+	}), nil
+}
+
+// evmLegacyTxBuilder gasPrice 定价的 legacy 交易，gasPrice 为基础费用加小费
+type evmLegacyTxBuilder struct {
+	client account.WalletAccountServiceClient
+}
+
+func (b *evmLegacyTxBuilder) Fee(ctx context.Context, params *TxParams) (*TxFee, error) {
+	feeInfo, err := evmFeeInfo(ctx, b.client, params)
+	if err != nil {
+		return nil, err
+	}
+	strategy := params.feeStrategy()
+	gasLimit, err := strategy.evmGasLimit(params)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, capped := capFee(new(big.Int).Add(feeInfo.GasPrice, feeInfo.MultipliedTip), strategy.MaxFeeCap)
+	return &TxFee{
+		GasLimit: gasLimit,
+		GasPrice: gasPrice.String(),
+		Strategy: strategy.label(capped),
+	}, nil
+}
+
+func (b *evmLegacyTxBuilder) Build(ctx context.Context, params *TxParams) (string, error) {
+	nonce, err := evmNonce(ctx, b.client, params)
+	if err != nil {
+		return "", err
+	}
+	if len(params.Recipients) > 0 {
+		return encodeTx(newEvmMultiSendTx(params, nonce)), nil
+	}
+	return encodeTx(&LegacyTx{
+		ChainId:         params.ChainId,
+		Nonce:           nonce,
+		FromAddress:     params.From,
+		ToAddress:       params.To,
+		GasLimit:        params.Fee.GasLimit,
+		GasPrice:        params.Fee.GasPrice,
+		Amount:          params.Amount,
+		ContractAddress: params.ContractAddress,
+	}), nil
+}
+
+// evmFeeInfo 按策略档位查询费用和预估 gas
+func evmFeeInfo(ctx context.Context, client account.WalletAccountServiceClient, params *TxParams) (*FeeInfo, error) {
+	feeResponse, err := queryFee(ctx, client, params)
+	if err != nil {
+		return nil, err
+	}
+	strategy := params.feeStrategy()
+	return ParseFee(strategy.pick(feeResponse), strategy.TipBuffer)
+}
+
+// newEvmMultiSendTx multisend 交易沿用交易记录中的定价方式
+func newEvmMultiSendTx(params *TxParams, nonce uint64) *EvmMultiSendTx {
+	return &EvmMultiSendTx{
+		ChainId:              params.ChainId,
+		Nonce:                nonce,
+		FromAddress:          params.From,
+		MultiSendAddress:     params.To,
+		GasLimit:             params.Fee.GasLimit,
+		MaxFeePerGas:         params.Fee.MaxFeePerGas,
+		MaxPriorityFeePerGas: params.Fee.MaxPriorityFeePerGas,
+		GasPrice:             params.Fee.GasPrice,
+		Amount:               params.Amount,
+		ContractAddress:      params.ContractAddress,
+		Recipients:           params.Recipients,
+	}
+}
+
+func evmNonce(ctx context.Context, client account.WalletAccountServiceClient, params *TxParams) (uint64, error) {
+	if params.Nonce != nil {
+		return *params.Nonce, nil
+	}
+	accountInfo, err := queryAccount(ctx, client, params.Chain, params.From)
+	if err != nil {
+		return 0, err
+	}
+	nonce, err := strconv.ParseUint(accountInfo.Sequence, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid nonce value: %w", err)
+	}
+	return nonce, nil
+}
+
+// accountTx 非 EVM 账户模型链的交易数据，各链只使用自己需要的字段
+type accountTx struct {
+	Chain           string `json:"chain"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Amount          string `json:"amount"`
+	Nonce           string `json:"nonce,omitempty"`
+	AccountNumber   string `json:"accountNumber,omitempty"`
+	ContractAddress string `json:"contractAddress"`
+	GasLimit        uint64 `json:"gasLimit"`
+	Fee             string `json:"fee,omitempty"`
+	Memo            string `json:"memo,omitempty"`
+}
+
+func newAccountTx(params *TxParams) *accountTx {
+	return &accountTx{
+		Chain:           params.Chain,
+		From:            params.From,
+		To:              params.To,
+		Amount:          params.Amount,
+		ContractAddress: params.ContractAddress,
+		GasLimit:        params.Fee.GasLimit,
+		Fee:             params.Fee.MaxFeePerGas,
+	}
+}
+
+// chainFee 非 EVM 链按策略档位记录链上返回的费用，由 chain-account 按链解释，整数费用受上限约束
+func chainFee(ctx context.Context, client account.WalletAccountServiceClient, params *TxParams) (*TxFee, error) {
+	feeResponse, err := queryFee(ctx, client, params)
+	if err != nil {
+		return nil, err
+	}
+	strategy := params.feeStrategy()
+	fee, capped := strategy.pick(feeResponse), false
+	if value, ok := new(big.Int).SetString(fee, 10); ok {
+		value, capped = capFee(value, strategy.MaxFeeCap)
+		fee = value.String()
+	}
+	return &TxFee{
+		GasLimit:     strategy.gasLimit(params),
+		MaxFeePerGas: fee,
+		Strategy:     strategy.label(capped),
+	}, nil
+}
+
+// accountSequence 返回批量创建时分配的 sequence，未分配时使用账户当前 sequence
+func accountSequence(params *TxParams, accountInfo *account.AccountResponse) string {
+	if params.Nonce != nil {
+		return strconv.FormatUint(*params.Nonce, 10)
+	}
+	return accountInfo.Sequence
+}
+
+// rejectMultiSend 非 EVM 账户模型链没有 multisend 合约
+func rejectMultiSend(params *TxParams) error {
+	if len(params.Recipients) > 0 {
+		return fmt.Errorf("multi-send is not supported on chain %s", params.Chain)
+	}
+	return nil
+}
+
+func rejectMemo(params *TxParams) error {
+	if params.Memo != "" {
+		return fmt.Errorf("memo is not supported on chain %s", params.Chain)
+	}
+	return nil
+}
+
+// tronTxBuilder tron 交易由 chain-account 引用最新区块，没有 nonce
+type tronTxBuilder struct {
+	client account.WalletAccountServiceClient
+}
+
+func (b *tronTxBuilder) Fee(ctx context.Context, params *TxParams) (*TxFee, error) {
+	return chainFee(ctx, b.client, params)
+}
+
+func (b *tronTxBuilder) Build(ctx context.Context, params *TxParams) (string, error) {
+	if err := rejectMultiSend(params); err != nil {
+		return "", err
+	}
+	if err := rejectMemo(params); err != nil {
+		return "", err
+	}
+	return encodeTx(newAccountTx(params)), nil
+}
+
+// solanaTxBuilder solana 交易使用最新 blockhash，没有 nonce，手续费要在交易构造后才能确定
+type solanaTxBuilder struct {
+	client account.WalletAccountServiceClient
+}
+
+func (b *solanaTxBuilder) Fee(ctx context.Context, params *TxParams) (*TxFee, error) {
+	strategy := params.feeStrategy()
+	return &TxFee{GasLimit: strategy.gasLimit(params), Strategy: strategy.label(false)}, nil
+}
+
+func (b *solanaTxBuilder) Build(ctx context.Context, params *TxParams) (string, error) {
+	if err := rejectMultiSend(params); err != nil {
+		return "", err
+	}
+	if err := rejectMemo(params); err != nil {
+		return "", err
+	}
+	return encodeTx(newAccountTx(params)), nil
+}
+
+// cosmosTxBuilder cosmos 签名需要 account number 和 sequence
+type cosmosTxBuilder struct {
+	client account.WalletAccountServiceClient
+}
+
+func (b *cosmosTxBuilder) Fee(ctx context.Context, params *TxParams) (*TxFee, error) {
+	return chainFee(ctx, b.client, params)
+}
+
+func (b *cosmosTxBuilder) Build(ctx context.Context, params *TxParams) (string, error) {
+	if err := rejectMultiSend(params); err != nil {
+		return "", err
+	}
+	accountInfo, err := queryAccount(ctx, b.client, params.Chain, params.From)
+	if err != nil {
+		return "", err
+	}
+	tx := newAccountTx(params)
+	tx.Nonce = accountSequence(params, accountInfo)
+	tx.AccountNumber = accountInfo.AccountNumber
+	tx.Memo = params.Memo
+	return encodeTx(tx), nil
+}
+
+// tonTxBuilder ton 钱包合约的 seqno 作为 nonce
+type tonTxBuilder struct {
+	client account.WalletAccountServiceClient
+}
+
+func (b *tonTxBuilder) Fee(ctx context.Context, params *TxParams) (*TxFee, error) {
+	return chainFee(ctx, b.client, params)
+}
+
+func (b *tonTxBuilder) Build(ctx context.Context, params *TxParams) (string, error) {
+	if err := rejectMultiSend(params); err != nil {
+		return "", err
+	}
+	accountInfo, err := queryAccount(ctx, b.client, params.Chain, params.From)
+	if err != nil {
+		return "", err
+	}
+	tx := newAccountTx(params)
+	tx.Nonce = accountSequence(params, accountInfo)
+	tx.Memo = params.Memo
+	return encodeTx(tx), nil
+}
+
+// xrpTxBuilder xrp 账户的 Sequence 作为 nonce，memo 写入 destination tag
+type xrpTxBuilder struct {
+	client account.WalletAccountServiceClient
+}
+
+func (b *xrpTxBuilder) Fee(ctx context.Context, params *TxParams) (*TxFee, error) {
+	return chainFee(ctx, b.client, params)
+}
+
+func (b *xrpTxBuilder) Build(ctx context.Context, params *TxParams) (string, error) {
+	if err := rejectMultiSend(params); err != nil {
+		return "", err
+	}
+	accountInfo, err := queryAccount(ctx, b.client, params.Chain, params.From)
+	if err != nil {
+		return "", err
+	}
+	tx := newAccountTx(params)
+	tx.Nonce = accountSequence(params, accountInfo)
+	tx.Memo = params.Memo
+	return encodeTx(tx), nil
+}
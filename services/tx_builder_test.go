@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	"github.com/dapplink-labs/multichain-sync-account/rpcclient"
+	"github.com/dapplink-labs/multichain-sync-account/rpcclient/chain-account/account"
+)
+
+type fakeAccountClient struct {
+	account.WalletAccountServiceClient
+	fastFee string
+}
+
+func (c *fakeAccountClient) GetAccount(ctx context.Context, in *account.AccountRequest, opts ...grpc.CallOption) (*account.AccountResponse, error) {
+	return &account.AccountResponse{AccountNumber: "42", Sequence: "7"}, nil
+}
+
+func (c *fakeAccountClient) GetFee(ctx context.Context, in *account.FeeRequest, opts ...grpc.CallOption) (*account.FeeResponse, error) {
+	return &account.FeeResponse{FastFee: c.fastFee}, nil
+}
+
+func decodeTx(t *testing.T, base64Str string) map[string]interface{} {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(base64Str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := make(map[string]interface{})
+	if err := json.Unmarshal(data, &tx); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestTxBuilderRegistry(t *testing.T) {
+	for chain, config := range database.ChainTokenTypes {
+		if config.IsUTXO {
+			continue
+		}
+		if _, ok := txBuilders[config.TxFamily]; !ok {
+			t.Errorf("chain %s has no transaction builder for family %q", chain, config.TxFamily)
+		}
+	}
+}
+
+func TestEvmTxBuilders(t *testing.T) {
+	client := &fakeAccountClient{fastFee: "100|10|*2"}
+	params := &TxParams{Chain: "ethereum", ChainId: "1", From: "0xfrom", To: "0xto", Amount: "1000", ContractAddress: "0x00"}
+
+	fee, err := (&evm1559TxBuilder{client: client}).Fee(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee.GasLimit != EthGasLimit || fee.MaxFeePerGas != "140" || fee.MaxPriorityFeePerGas != "20" {
+		t.Fatalf("unexpected 1559 fee %+v", fee)
+	}
+	params.Fee = *fee
+	base64Str, err := (&evm1559TxBuilder{client: client}).Build(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := decodeTx(t, base64Str)
+	if tx["nonce"] != float64(7) || tx["max_fee_per_gas"] != "140" {
+		t.Fatalf("unexpected 1559 tx %v", tx)
+	}
+
+	fee, err = (&evmLegacyTxBuilder{client: client}).Fee(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params.Fee = *fee
+	base64Str, err = (&evmLegacyTxBuilder{client: client}).Build(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx = decodeTx(t, base64Str)
+	if fee.GasPrice != "120" || fee.MaxFeePerGas != "" || fee.MaxPriorityFeePerGas != "" {
+		t.Fatalf("legacy fee must only carry gas price: %+v", fee)
+	}
+	if tx["gas_price"] != "120" {
+		t.Fatalf("unexpected legacy tx %v", tx)
+	}
+	if _, ok := tx["max_fee_per_gas"]; ok {
+		t.Fatalf("legacy tx must not carry 1559 fields: %v", tx)
+	}
+}
+
+func TestSignedTxBuilderFollowsRecordedPricing(t *testing.T) {
+	bws := &BusinessMiddleWireServices{accountClient: &rpcclient.WalletChainAccountClient{AccountRpClient: &fakeAccountClient{}}}
+
+	builder, err := bws.signedTxBuilder("bsc", TxFee{GasPrice: "5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := builder.(*evmLegacyTxBuilder); !ok {
+		t.Fatalf("bsc gas price record should use the legacy builder, got %T", builder)
+	}
+	builder, _ = bws.signedTxBuilder("bsc", TxFee{MaxFeePerGas: "5", MaxPriorityFeePerGas: "1"})
+	if _, ok := builder.(*evm1559TxBuilder); !ok {
+		t.Fatalf("1559 record should stay 1559 after the chain switched to legacy, got %T", builder)
+	}
+	builder, _ = bws.signedTxBuilder("ethereum", TxFee{GasPrice: "5"})
+	if _, ok := builder.(*evmLegacyTxBuilder); !ok {
+		t.Fatalf("legacy record should stay legacy after the chain switched to 1559, got %T", builder)
+	}
+	builder, _ = bws.signedTxBuilder("tron", TxFee{MaxFeePerGas: "5"})
+	if _, ok := builder.(*tronTxBuilder); !ok {
+		t.Fatalf("non-evm chains keep their builder, got %T", builder)
+	}
+}
+
+func TestAccountTxBuilders(t *testing.T) {
+	client := &fakeAccountClient{fastFee: "12"}
+	params := &TxParams{Chain: "cosmos", From: "from", To: "to", Amount: "5", Memo: "1001"}
+
+	base64Str, err := (&cosmosTxBuilder{client: client}).Build(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := decodeTx(t, base64Str)
+	if tx["nonce"] != "7" || tx["accountNumber"] != "42" || tx["memo"] != "1001" {
+		t.Fatalf("unexpected cosmos tx %v", tx)
+	}
+
+	params.Chain = "tron"
+	if _, err := (&tronTxBuilder{client: client}).Build(context.Background(), params); err == nil {
+		t.Fatal("tron builder should reject memo")
+	}
+	params.Memo = ""
+	base64Str, err = (&tronTxBuilder{client: client}).Build(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := decodeTx(t, base64Str)["nonce"]; ok {
+		t.Fatal("tron tx has no nonce")
+	}
+}
+
+func TestEvmMultiSendTx(t *testing.T) {
+	client := &fakeAccountClient{fastFee: "100|10|*2"}
+	nonce := uint64(11)
+	params := &TxParams{
+		Chain: "ethereum", ChainId: "1", From: "0xfrom", To: "0xmultisend", Amount: "30", ContractAddress: "0x00",
+		Nonce: &nonce,
+		Recipients: []*MultiSendRecipient{
+			{ToAddress: "0xa", Amount: "10"},
+			{ToAddress: "0xb", Amount: "20"},
+		},
+	}
+	fee, err := (&evm1559TxBuilder{client: client}).Fee(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee.GasLimit != 2*EthGasLimit {
+		t.Fatalf("multi-send gas limit should cover every recipient, got %d", fee.GasLimit)
+	}
+	params.Fee = *fee
+	base64Str, err := (&evm1559TxBuilder{client: client}).Build(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := decodeTx(t, base64Str)
+	recipients, ok := tx["recipients"].([]interface{})
+	if tx["nonce"] != float64(11) || tx["multisend_address"] != "0xmultisend" || !ok || len(recipients) != 2 {
+		t.Fatalf("unexpected multi-send tx %v", tx)
+	}
+
+	params.Chain = "tron"
+	if _, err := (&tronTxBuilder{client: client}).Build(context.Background(), params); err == nil {
+		t.Fatal("tron builder should reject multi-send")
+	}
+}
+
+func TestUsesNonce(t *testing.T) {
+	for family, want := range map[database.TxFamily]bool{
+		database.TxFamilyEVM1559:   true,
+		database.TxFamilyEVMLegacy: true,
+		database.TxFamilyXrp:       true,
+		database.TxFamilyTron:      false,
+		database.TxFamilySolana:    false,
+		database.TxFamilyUTXO:      false,
+	} {
+		if usesNonce(family) != want {
+			t.Errorf("usesNonce(%s) = %v, want %v", family, !want, want)
+		}
+	}
+}
@@ -14,3 +14,60 @@ type Eip1559DynamicFeeTx struct {
 	// erc20 erc721 erc1155 contract_address
 	ContractAddress string `json:"contract_address"`
 }
+
+// UtxoTx UTXO 链的待签名交易，输入按输出点排序，最后一个输出为找零
+type UtxoTx struct {
+	Chain   string          `json:"chain"`
+	Inputs  []*UtxoTxInput  `json:"inputs"`
+	Outputs []*UtxoTxOutput `json:"outputs"`
+	Fee     string          `json:"fee"`
+	FeeRate uint64          `json:"fee_rate"`
+}
+
+type UtxoTxInput struct {
+	TxHash  string `json:"tx_hash"`
+	Vout    uint32 `json:"vout"`
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+}
+
+type UtxoTxOutput struct {
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+}
+
+type LegacyTx struct {
+	ChainId     string `json:"chain_id"`
+	Nonce       uint64 `json:"nonce"`
+	FromAddress string `json:"from_address"`
+	ToAddress   string `json:"to_address"`
+	GasLimit    uint64 `json:"gas_limit"`
+	GasPrice    string `json:"gas_price"`
+
+	// eth/erc20 amount
+	Amount string `json:"amount"`
+	// erc20 erc721 erc1155 contract_address
+	ContractAddress string `json:"contract_address"`
+}
+
+// EvmMultiSendTx 通过 multisend 合约一笔交易支付多个收款地址，Amount 为合计金额，
+// 主币转账时作为交易 value，定价字段按链使用 EIP-1559 或 legacy 其中一组
+type EvmMultiSendTx struct {
+	ChainId              string `json:"chain_id"`
+	Nonce                uint64 `json:"nonce"`
+	FromAddress          string `json:"from_address"`
+	MultiSendAddress     string `json:"multisend_address"`
+	GasLimit             uint64 `json:"gas_limit"`
+	MaxFeePerGas         string `json:"max_fee_per_gas,omitempty"`
+	MaxPriorityFeePerGas string `json:"max_priority_fee_per_gas,omitempty"`
+	GasPrice             string `json:"gas_price,omitempty"`
+
+	Amount          string                `json:"amount"`
+	ContractAddress string                `json:"contract_address"`
+	Recipients      []*MultiSendRecipient `json:"recipients"`
+}
+
+type MultiSendRecipient struct {
+	ToAddress string `json:"to_address"`
+	Amount    string `json:"amount"`
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	"github.com/dapplink-labs/multichain-sync-account/common/json2"
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	dal_wallet_go "github.com/dapplink-labs/multichain-sync-account/protobuf/dal-wallet-go"
+	"github.com/dapplink-labs/multichain-sync-account/rpcclient/chain-account/account"
+	"github.com/dapplink-labs/multichain-sync-account/utxo"
+)
+
+// createUtxoUnSignTransaction 从 from 地址的未花费输出中选币，锁定选中的输出并生成列出所有输入的待签名交易
+func (bws *BusinessMiddleWireServices) createUtxoUnSignTransaction(ctx context.Context, request *dal_wallet_go.UnSignTransactionRequest,
+	guid uuid.UUID, amountBig *big.Int, transactionType database.TransactionType) (*dal_wallet_go.UnSignTransactionResponse, error) {
+	response := &dal_wallet_go.UnSignTransactionResponse{
+		Code:     dal_wallet_go.ReturnCode_ERROR,
+		UnSignTx: "0x00",
+		From:     request.From,
+		To:       request.To,
+	}
+	switch transactionType {
+	case database.TxTypeWithdraw, database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot:
+	default:
+		response.Msg = "Unsupported transaction type"
+		return response, nil
+	}
+	if !database.IsNativeToken(bws.chainName, request.ContractAddress) {
+		response.Msg = "utxo chain only supports native token transfer"
+		return response, nil
+	}
+
+	unsigned, err := bws.buildUtxoUnSignTx(ctx, request, amountBig)
+	if err != nil {
+		var rejected *requestRejectedError
+		if errors.As(err, &rejected) {
+			response.Msg = err.Error()
+			return response, nil
+		}
+		return nil, err
+	}
+	signHash, err := bws.createUnSignTx(ctx, request.Chain, unsigned.base64Tx)
+	if err != nil {
+		log.Error("create un sign utxo transaction fail", "err", err)
+		return nil, err
+	}
+
+	if err := bws.db.Transaction(func(tx *database.DB) error {
+		if transactionType == database.TxTypeWithdraw {
+			withdraw := newWithdraw(request, guid, amountBig, unsigned.fee, transactionType)
+			withdraw.ChangeAddress, withdraw.ChangeAmount = unsigned.changeAddress, unsigned.changeAmount
+			withdraw.UnsignedTx, withdraw.SignHash = unsigned.base64Tx, signHash
+			if err := tx.Withdraws.StoreWithdraw(request.RequestId, bws.chainName, withdraw); err != nil {
+				return err
+			}
+		} else {
+			internal := newInternal(request, guid, amountBig, unsigned.fee, transactionType)
+			internal.ChangeAddress, internal.ChangeAmount = unsigned.changeAddress, unsigned.changeAmount
+			internal.UnsignedTx, internal.SignHash = unsigned.base64Tx, signHash
+			if err := tx.Internals.StoreInternal(request.RequestId, bws.chainName, internal); err != nil {
+				return err
+			}
+		}
+		return tx.Utxos.LockUtxos(request.RequestId, bws.chainName, guid.String(), unsigned.selected)
+	}); err != nil {
+		log.Error("store utxo transaction fail", "err", err)
+		return nil, fmt.Errorf("store utxo transaction failed: %w", err)
+	}
+
+	response.Code = dal_wallet_go.ReturnCode_SUCCESS
+	response.Msg = "submit withdraw and build un sign tranaction success"
+	response.TransactionId = guid.String()
+	response.UnSignTx = signHash
+	return response, nil
+}
+
+// utxoUnSignTx 选币后生成的单笔待签名交易，selected 为需要锁定的输出
+type utxoUnSignTx struct {
+	base64Tx      string
+	fee           *TxFee
+	changeAddress string
+	changeAmount  *big.Int
+	selected      []*database.Utxos
+}
+
+// buildUtxoUnSignTx 从 from 地址选币生成待签名交易，费率、找零地址和余额不足的错误作为业务错误返回
+func (bws *BusinessMiddleWireServices) buildUtxoUnSignTx(ctx context.Context, request *dal_wallet_go.UnSignTransactionRequest, amountBig *big.Int) (*utxoUnSignTx, error) {
+	feeRate, strategy, err := bws.utxoFeeRate(ctx, request)
+	if err != nil {
+		return nil, rejectRequest("%v", err)
+	}
+	changeAddress, err := bws.utxoChangeAddress(request)
+	if err != nil {
+		return nil, rejectRequest("%v", err)
+	}
+	selection, selected, err := bws.selectUtxos(request.RequestId, request.From, amountBig, 1, feeRate)
+	if err != nil {
+		if errors.Is(err, utxo.ErrInsufficientFunds) {
+			return nil, rejectRequest("%v", err)
+		}
+		return nil, err
+	}
+	fee, changeAmount := utxoFee(selection, feeRate, strategy)
+	outputs := []*UtxoTxOutput{{Address: request.To, Amount: amountBig.String()}}
+	return &utxoUnSignTx{
+		base64Tx:      buildUtxoTx(request.Chain, selected, outputs, changeAddress, changeAmount, selection.Fee, feeRate),
+		fee:           fee,
+		changeAddress: changeAddress,
+		changeAmount:  changeAmount,
+		selected:      selected,
+	}, nil
+}
+
+// utxoFee UTXO 链没有 gas，MaxFeePerGas 记录交易手续费，MaxPriorityFeePerGas 记录费率，没有找零时找零金额为 nil
+func utxoFee(selection *utxo.Selection, feeRate uint64, strategy string) (*TxFee, *big.Int) {
+	fee := &TxFee{MaxFeePerGas: selection.Fee.String(), MaxPriorityFeePerGas: strconv.FormatUint(feeRate, 10), Strategy: strategy}
+	if selection.Change.Sign() > 0 {
+		return fee, selection.Change
+	}
+	return fee, nil
+}
+
+// selectUtxos 从 from 地址的未花费输出中为 outputs 个支付输出选币，返回选中的输出记录
+func (bws *BusinessMiddleWireServices) selectUtxos(requestId string, from string, amount *big.Int, outputs int, feeRate uint64) (*utxo.Selection, []*database.Utxos, error) {
+	unspentList, err := bws.db.Utxos.QueryUnspentUtxos(requestId, bws.chainName, from)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query unspent utxos failed: %w", err)
+	}
+	candidates := make([]*utxo.Output, 0, len(unspentList))
+	byOutpoint := make(map[string]*database.Utxos, len(unspentList))
+	for _, unspent := range unspentList {
+		candidates = append(candidates, &utxo.Output{TxHash: unspent.TxHash, Vout: unspent.Vout, Address: unspent.Address, Amount: unspent.Amount})
+		byOutpoint[outpoint(unspent.TxHash, unspent.Vout)] = unspent
+	}
+	selection, err := utxo.SelectOutputs(candidates, amount, outputs, feeRate)
+	if err != nil {
+		if errors.Is(err, utxo.ErrInsufficientFunds) {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("select utxos failed: %w", err)
+	}
+	selected := make([]*database.Utxos, 0, len(selection.Inputs))
+	for _, input := range selection.Inputs {
+		selected = append(selected, byOutpoint[outpoint(input.TxHash, input.Vout)])
+	}
+	return selection, selected, nil
+}
+
+// buildUtxoSignedTx 按锁定的输出重建与待签名时一致的交易数据，spendGuid 为锁定输出的交易 id
+func (bws *BusinessMiddleWireServices) buildUtxoSignedTx(requestId string, chain string, spendGuid string, outputs []*UtxoTxOutput,
+	changeAddress string, changeAmount *big.Int, feeRate uint64) (string, error) {
+	inputs, err := bws.db.Utxos.QueryUtxosBySpendGuid(requestId, bws.chainName, spendGuid)
+	if err != nil {
+		return "", err
+	}
+	if len(inputs) == 0 {
+		return "", fmt.Errorf("no utxo locked by transaction %s", spendGuid)
+	}
+	fee := big.NewInt(0)
+	for _, input := range inputs {
+		fee.Add(fee, input.Amount)
+	}
+	for _, output := range outputs {
+		amount, ok := new(big.Int).SetString(output.Amount, 10)
+		if !ok {
+			return "", fmt.Errorf("invalid output amount %s", output.Amount)
+		}
+		fee.Sub(fee, amount)
+	}
+	if changeAmount != nil {
+		fee.Sub(fee, changeAmount)
+	}
+	return buildUtxoTx(chain, inputs, outputs, changeAddress, changeAmount, fee, feeRate), nil
+}
+
+func buildUtxoTx(chain string, inputs []*database.Utxos, outputs []*UtxoTxOutput,
+	changeAddress string, changeAmount *big.Int, fee *big.Int, feeRate uint64) string {
+	utxoTx := &UtxoTx{
+		Chain:   chain,
+		Outputs: append([]*UtxoTxOutput{}, outputs...),
+		Fee:     fee.String(),
+		FeeRate: feeRate,
+	}
+	for _, input := range sortedUtxos(inputs) {
+		utxoTx.Inputs = append(utxoTx.Inputs, &UtxoTxInput{
+			TxHash:  input.TxHash,
+			Vout:    input.Vout,
+			Address: input.Address,
+			Amount:  input.Amount.String(),
+		})
+	}
+	if changeAmount != nil && changeAmount.Sign() > 0 {
+		utxoTx.Outputs = append(utxoTx.Outputs, &UtxoTxOutput{Address: changeAddress, Amount: changeAmount.String()})
+	}
+	return base64.StdEncoding.EncodeToString(json2.ToJSON(utxoTx))
+}
+
+func sortedUtxos(inputs []*database.Utxos) []*database.Utxos {
+	sorted := make([]*database.Utxos, len(inputs))
+	copy(sorted, inputs)
+	sort.Slice(sorted, func(i, j int) bool {
+		return outpoint(sorted[i].TxHash, sorted[i].Vout) < outpoint(sorted[j].TxHash, sorted[j].Vout)
+	})
+	return sorted
+}
+
+func outpoint(txHash string, vout uint32) string {
+	return fmt.Sprintf("%s:%010d", txHash, vout)
+}
+
+// utxoFeeRate 请求未指定费率时按手续费策略的档位取链上费率，并受费率上限约束
+func (bws *BusinessMiddleWireServices) utxoFeeRate(ctx context.Context, request *dal_wallet_go.UnSignTransactionRequest) (uint64, string, error) {
+	if request.FeeRate > 0 {
+		return request.FeeRate, "request", nil
+	}
+	strategy := bws.FeeStrategies.Resolve(request.Chain, "")
+	feeResponse, err := bws.accountClient.AccountRpClient.GetFee(ctx, &account.FeeRequest{
+		Chain:   request.Chain,
+		Network: database.GetNetwork(request.Chain),
+		Address: request.From,
+	})
+	if err != nil {
+		return 0, "", fmt.Errorf("get fee failed: %w", err)
+	}
+	fee := strategy.pick(feeResponse)
+	feeRate, err := strconv.ParseUint(fee, 10, 64)
+	if err != nil || feeRate == 0 {
+		return 0, "", fmt.Errorf("fee rate is required, chain %s fee %q is not a sat/vB rate", strategy.Speed, fee)
+	}
+	capped, isCapped := capFee(new(big.Int).SetUint64(feeRate), strategy.MaxFeeCap)
+	return capped.Uint64(), strategy.label(isCapped), nil
+}
+
+// utxoChangeAddress 找零地址必须是业务自己的地址，未指定时从热钱包出款的找零回出款地址，其余找零到最早登记的热钱包
+func (bws *BusinessMiddleWireServices) utxoChangeAddress(request *dal_wallet_go.UnSignTransactionRequest) (string, error) {
+	if request.ChangeAddress != "" {
+		if exist, _ := bws.db.Addresses.AddressExist(request.RequestId, bws.chainName, request.ChangeAddress); !exist {
+			return "", fmt.Errorf("change address %s is not a business address", request.ChangeAddress)
+		}
+		return request.ChangeAddress, nil
+	}
+	hotWallets, err := bws.db.Addresses.QueryHotWallets(request.RequestId, bws.chainName)
+	if err != nil {
+		return "", fmt.Errorf("query hot wallet failed: %w", err)
+	}
+	if len(hotWallets) == 0 {
+		return request.From, nil
+	}
+	for _, hotWallet := range hotWallets {
+		if database.NormalizeAddress(bws.chainName, hotWallet.Address) == request.From {
+			return request.From, nil
+		}
+	}
+	return hotWallets[0].Address, nil
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/dapplink-labs/multichain-sync-account/common/json2"
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	dal_wallet_go "github.com/dapplink-labs/multichain-sync-account/protobuf/dal-wallet-go"
+	"github.com/dapplink-labs/multichain-sync-account/rpcclient/chain-account/account"
+	"github.com/dapplink-labs/multichain-sync-account/rpcclient/chain-account/common"
+)
+
+// txSummary 交易数据中参与防篡改校验的字段，各链族 JSON 字段名不同，统一解析到这里，为空表示交易数据中没有该字段
+type txSummary struct {
+	From                 string
+	To                   string
+	Amount               string
+	Token                string
+	Nonce                string
+	GasLimit             string
+	MaxFeePerGas         string
+	MaxPriorityFeePerGas string
+	GasPrice             string
+	Recipients           []*MultiSendRecipient
+	Inputs               []*UtxoTxInput
+	Outputs              []*UtxoTxOutput
+}
+
+// summaryKeys 字段在各链族交易数据中的 JSON 名称，accountTx 和 UtxoTx 的 fee 都记录在 MaxFeePerGas
+var summaryKeys = []struct {
+	keys  []string
+	field func(s *txSummary) *string
+}{
+	{[]string{"from_address", "from"}, func(s *txSummary) *string { return &s.From }},
+	{[]string{"to_address", "multisend_address", "to"}, func(s *txSummary) *string { return &s.To }},
+	{[]string{"amount", "value"}, func(s *txSummary) *string { return &s.Amount }},
+	{[]string{"contract_address", "contractAddress"}, func(s *txSummary) *string { return &s.Token }},
+	{[]string{"nonce", "sequence"}, func(s *txSummary) *string { return &s.Nonce }},
+	{[]string{"gas_limit", "gasLimit"}, func(s *txSummary) *string { return &s.GasLimit }},
+	{[]string{"max_fee_per_gas", "fee"}, func(s *txSummary) *string { return &s.MaxFeePerGas }},
+	{[]string{"max_priority_fee_per_gas", "fee_rate"}, func(s *txSummary) *string { return &s.MaxPriorityFeePerGas }},
+	{[]string{"gas_price"}, func(s *txSummary) *string { return &s.GasPrice }},
+}
+
+// parseTxSummary 解析 base64 编码的 JSON 交易数据
+func parseTxSummary(base64Tx string) (*txSummary, error) {
+	raw, err := base64.StdEncoding.DecodeString(base64Tx)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64 transaction failed: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var fields map[string]json.RawMessage
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("decode transaction json failed: %w", err)
+	}
+
+	summary := &txSummary{}
+	for _, item := range summaryKeys {
+		for _, key := range item.keys {
+			value, ok := fields[key]
+			if !ok {
+				continue
+			}
+			text, err := jsonScalar(value)
+			if err != nil {
+				return nil, fmt.Errorf("decode transaction field %s failed: %w", key, err)
+			}
+			if text != "" {
+				*item.field(summary) = text
+				break
+			}
+		}
+	}
+	for key, target := range map[string]interface{}{
+		"recipients": &summary.Recipients,
+		"inputs":     &summary.Inputs,
+		"outputs":    &summary.Outputs,
+	} {
+		if value, ok := fields[key]; ok {
+			if err := json.Unmarshal(value, target); err != nil {
+				return nil, fmt.Errorf("decode transaction field %s failed: %w", key, err)
+			}
+		}
+	}
+	return summary, nil
+}
+
+// jsonScalar 数字和字符串统一为字符串，null 为空
+func jsonScalar(value json.RawMessage) (string, error) {
+	var scalar interface{}
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&scalar); err != nil {
+		return "", err
+	}
+	switch v := scalar.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("unexpected value %s", string(value))
+	}
+}
+
+// recordSummary 按交易记录还原应有的交易字段，UTXO 链比较输出，multi-send 比较收款列表
+func recordSummary(chain string, from, to string, amount *big.Int, token string, nonce *uint64, fee TxFee) *txSummary {
+	summary := &txSummary{
+		From:                 from,
+		Token:                token,
+		MaxFeePerGas:         fee.MaxFeePerGas,
+		MaxPriorityFeePerGas: fee.MaxPriorityFeePerGas,
+		GasPrice:             fee.GasPrice,
+	}
+	if fee.GasLimit > 0 {
+		summary.GasLimit = strconv.FormatUint(fee.GasLimit, 10)
+	}
+	if nonce != nil {
+		summary.Nonce = strconv.FormatUint(*nonce, 10)
+	}
+	if database.IsUTXOChain(chain) {
+		summary.From, summary.Token = "", ""
+		if to != "" {
+			summary.Outputs = []*UtxoTxOutput{{Address: to, Amount: amount.String()}}
+		}
+		return summary
+	}
+	summary.To = to
+	if amount != nil {
+		summary.Amount = amount.String()
+	}
+	return summary
+}
+
+// withChange UTXO 交易最后一个输出为找零
+func (s *txSummary) withChange(changeAddress string, changeAmount *big.Int) *txSummary {
+	if changeAmount != nil && changeAmount.Sign() > 0 {
+		s.Outputs = append(s.Outputs, &UtxoTxOutput{Address: changeAddress, Amount: changeAmount.String()})
+	}
+	return s
+}
+
+// matchTxSummary 校验 got 与 want 一致，want 中为空的字段不校验，want 有而 got 缺少的字段视为不一致，
+// 主币转账的合约地址允许缺省
+func matchTxSummary(chain string, want, got *txSummary) error {
+	compare := func(name, expected, actual string, equal func(a, b string) bool) error {
+		if expected == "" {
+			return nil
+		}
+		if actual == "" {
+			return fmt.Errorf("%s is missing, expected %s", name, expected)
+		}
+		if !equal(expected, actual) {
+			return fmt.Errorf("%s mismatch, expected %s got %s", name, expected, actual)
+		}
+		return nil
+	}
+	address := func(a, b string) bool {
+		return database.NormalizeAddress(chain, a) == database.NormalizeAddress(chain, b)
+	}
+	token := func(a, b string) bool {
+		if database.IsNativeToken(chain, a) && database.IsNativeToken(chain, b) {
+			return true
+		}
+		return address(a, b)
+	}
+	number := func(a, b string) bool {
+		x, okX := new(big.Int).SetString(a, 10)
+		y, okY := new(big.Int).SetString(b, 10)
+		if okX && okY {
+			return x.Cmp(y) == 0
+		}
+		return a == b
+	}
+
+	if want.Token != "" && got.Token == "" && database.IsNativeToken(chain, want.Token) {
+		copied := *got
+		copied.Token = want.Token
+		got = &copied
+	}
+	for _, check := range []struct {
+		name           string
+		expected, real string
+		equal          func(a, b string) bool
+	}{
+		{"from", want.From, got.From, address},
+		{"to", want.To, got.To, address},
+		{"amount", want.Amount, got.Amount, number},
+		{"token", want.Token, got.Token, token},
+		{"nonce", want.Nonce, got.Nonce, number},
+		{"gas limit", want.GasLimit, got.GasLimit, number},
+		{"max fee", want.MaxFeePerGas, got.MaxFeePerGas, number},
+		{"max priority fee", want.MaxPriorityFeePerGas, got.MaxPriorityFeePerGas, number},
+		{"gas price", want.GasPrice, got.GasPrice, number},
+	} {
+		if err := compare(check.name, check.expected, check.real, check.equal); err != nil {
+			return err
+		}
+	}
+
+	if len(want.Recipients) > 0 {
+		if len(want.Recipients) != len(got.Recipients) {
+			return fmt.Errorf("recipients mismatch, expected %d got %d", len(want.Recipients), len(got.Recipients))
+		}
+		for i, recipient := range want.Recipients {
+			if !address(recipient.ToAddress, got.Recipients[i].ToAddress) || !number(recipient.Amount, got.Recipients[i].Amount) {
+				return fmt.Errorf("recipient %d mismatch, expected %s %s", i, recipient.ToAddress, recipient.Amount)
+			}
+		}
+	}
+	if len(want.Outputs) > 0 {
+		if len(want.Outputs) != len(got.Outputs) {
+			return fmt.Errorf("outputs mismatch, expected %d got %d", len(want.Outputs), len(got.Outputs))
+		}
+		for i, output := range want.Outputs {
+			if !address(output.Address, got.Outputs[i].Address) || !number(output.Amount, got.Outputs[i].Amount) {
+				return fmt.Errorf("output %d mismatch, expected %s %s", i, output.Address, output.Amount)
+			}
+		}
+	}
+	if len(want.Inputs) > 0 {
+		if len(want.Inputs) != len(got.Inputs) {
+			return fmt.Errorf("inputs mismatch, expected %d got %d", len(want.Inputs), len(got.Inputs))
+		}
+		for i, input := range want.Inputs {
+			if input.TxHash != got.Inputs[i].TxHash || input.Vout != got.Inputs[i].Vout {
+				return fmt.Errorf("input %d mismatch, expected %s:%d", i, input.TxHash, input.Vout)
+			}
+		}
+	}
+	return nil
+}
+
+// signedTxRejectedError 签名交易与交易记录不一致，作为业务错误返回，交易保持待签名状态
+type signedTxRejectedError struct {
+	msg string
+}
+
+func (e *signedTxRejectedError) Error() string {
+	return e.msg
+}
+
+func rejectSignedTx(format string, args ...interface{}) error {
+	return &signedTxRejectedError{msg: fmt.Sprintf(format, args...)}
+}
+
+// signedTxFailure 签名交易校验不通过时返回业务错误，其他错误作为 rpc 错误返回
+func signedTxFailure(response *dal_wallet_go.SignedTransactionResponse, transactionId string, err error) (*dal_wallet_go.SignedTransactionResponse, error) {
+	var rejected *signedTxRejectedError
+	if errors.As(err, &rejected) {
+		log.Warn("signed transaction rejected", "transactionId", transactionId, "err", err)
+		response.Msg = err.Error()
+		return response, nil
+	}
+	return nil, err
+}
+
+// expectedSummary 校验待签名交易数据与交易记录一致，并用待签名数据补全记录中没有的 nonce 和 UTXO 输入
+func expectedSummary(chain string, record *txSummary, base64Tx string) (*txSummary, error) {
+	unsigned, err := parseTxSummary(base64Tx)
+	if err != nil {
+		return nil, rejectSignedTx("unsigned transaction is unreadable: %v", err)
+	}
+	if err := matchTxSummary(chain, record, unsigned); err != nil {
+		return nil, rejectSignedTx("unsigned transaction does not match the stored record: %v", err)
+	}
+	expected := *record
+	if expected.Nonce == "" {
+		expected.Nonce = unsigned.Nonce
+	}
+	if len(expected.Inputs) == 0 {
+		expected.Inputs = unsigned.Inputs
+	}
+	return &expected, nil
+}
+
+// verifySignedTx 解码 chain-account 组装的签名交易并与期望字段逐项比较，再用发送地址的公钥校验签名
+func (bws *BusinessMiddleWireServices) verifySignedTx(ctx context.Context, requestId string, chain string, expected *txSummary, from string, signedTx string) error {
+	decoded, err := bws.accountClient.AccountRpClient.DecodeTransaction(ctx, &account.DecodeTransactionRequest{
+		Chain:   chain,
+		Network: database.GetNetwork(chain),
+		RawTx:   signedTx,
+	})
+	if err != nil {
+		return fmt.Errorf("decode signed transaction failed: %w", err)
+	}
+	if decoded.Code == common.ReturnCode_ERROR {
+		return rejectSignedTx("signed transaction cannot be decoded: %s", decoded.Msg)
+	}
+	summary, err := parseTxSummary(decoded.Base64Tx)
+	if err != nil {
+		return rejectSignedTx("decoded signed transaction is unreadable: %v", err)
+	}
+	if err := matchTxSummary(chain, expected, summary); err != nil {
+		log.Warn("signed transaction does not match record", "chain", chain, "expected", json2.ToJSONString(expected), "decoded", json2.ToJSONString(summary))
+		return rejectSignedTx("signed transaction does not match the stored record: %v", err)
+	}
+
+	signer, err := bws.db.Addresses.QueryAddressesByToAddress(requestId, bws.chainName, from)
+	if err != nil || signer == nil {
+		return rejectSignedTx("public key of sender %s not found", from)
+	}
+	verified, err := bws.accountClient.AccountRpClient.VerifySignedTransaction(ctx, &account.VerifyTransactionRequest{
+		Chain:     chain,
+		Network:   database.GetNetwork(chain),
+		PublicKey: signer.PublicKey,
+		Signature: signedTx,
+	})
+	if err != nil {
+		return fmt.Errorf("verify signed transaction failed: %w", err)
+	}
+	if verified.Code == common.ReturnCode_ERROR || !verified.Verify {
+		return rejectSignedTx("signature of sender %s is invalid: %s", from, verified.Msg)
+	}
+	return nil
+}
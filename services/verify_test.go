@@ -0,0 +1,101 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestVerifyTxSummaryEvm(t *testing.T) {
+	const (
+		from = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+		to   = "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"
+	)
+	fee := TxFee{GasLimit: 21000, MaxFeePerGas: "100", MaxPriorityFeePerGas: "10"}
+	unsigned := encodeTx(&Eip1559DynamicFeeTx{
+		ChainId: "1", Nonce: 7, FromAddress: from, ToAddress: to, GasLimit: 21000,
+		MaxFeePerGas: "100", MaxPriorityFeePerGas: "10", Amount: "1000", ContractAddress: "0x00",
+	})
+	record := recordSummary("ethereum", from, to, big.NewInt(1000), "0x0000000000000000000000000000000000000000", nil, fee)
+
+	expected, err := expectedSummary("ethereum", record, unsigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected.Nonce != "7" {
+		t.Fatalf("nonce should come from the unsigned payload, got %q", expected.Nonce)
+	}
+
+	// chain-account 解码出的地址大小写不同、合约地址缺省时仍视为一致
+	decoded := encodeTx(map[string]interface{}{
+		"from_address": "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "to_address": to, "nonce": 7,
+		"gas_limit": 21000, "max_fee_per_gas": "100", "max_priority_fee_per_gas": "10", "amount": "1000",
+	})
+	summary, err := parseTxSummary(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := matchTxSummary("ethereum", expected, summary); err != nil {
+		t.Fatalf("matching signed tx rejected: %v", err)
+	}
+
+	for name, tampered := range map[string]map[string]interface{}{
+		"amount":        {"from_address": from, "to_address": to, "nonce": 7, "gas_limit": 21000, "max_fee_per_gas": "100", "max_priority_fee_per_gas": "10", "amount": "1001"},
+		"to":            {"from_address": from, "to_address": from, "nonce": 7, "gas_limit": 21000, "max_fee_per_gas": "100", "max_priority_fee_per_gas": "10", "amount": "1000"},
+		"nonce":         {"from_address": from, "to_address": to, "nonce": 8, "gas_limit": 21000, "max_fee_per_gas": "100", "max_priority_fee_per_gas": "10", "amount": "1000"},
+		"fee":           {"from_address": from, "to_address": to, "nonce": 7, "gas_limit": 21000, "max_fee_per_gas": "900", "max_priority_fee_per_gas": "10", "amount": "1000"},
+		"token":         {"from_address": from, "to_address": to, "nonce": 7, "gas_limit": 21000, "max_fee_per_gas": "100", "max_priority_fee_per_gas": "10", "amount": "1000", "contract_address": to},
+		"missing nonce": {"from_address": from, "to_address": to, "gas_limit": 21000, "max_fee_per_gas": "100", "max_priority_fee_per_gas": "10", "amount": "1000"},
+	} {
+		summary, err := parseTxSummary(encodeTx(tampered))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := matchTxSummary("ethereum", expected, summary); err == nil {
+			t.Errorf("tampered %s accepted", name)
+		}
+	}
+
+	// 落库的待签名数据与交易记录不一致时拒绝签名
+	record.Amount = "2000"
+	if _, err := expectedSummary("ethereum", record, unsigned); err == nil {
+		t.Error("unsigned payload that does not match the record accepted")
+	}
+}
+
+func TestVerifyTxSummaryUtxo(t *testing.T) {
+	const (
+		to     = "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"
+		change = "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"
+	)
+	unsigned := encodeTx(&UtxoTx{
+		Chain:   "bitcoin",
+		Inputs:  []*UtxoTxInput{{TxHash: "aa", Vout: 1, Address: change, Amount: "5000"}},
+		Outputs: []*UtxoTxOutput{{Address: to, Amount: "3000"}, {Address: change, Amount: "1800"}},
+		Fee:     "200",
+		FeeRate: 2,
+	})
+	fee := TxFee{MaxFeePerGas: "200", MaxPriorityFeePerGas: "2"}
+	record := recordSummary("bitcoin", change, to, big.NewInt(3000), "0x00", nil, fee).withChange(change, big.NewInt(1800))
+
+	expected, err := expectedSummary("bitcoin", record, unsigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary, err := parseTxSummary(unsigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := matchTxSummary("bitcoin", expected, summary); err != nil {
+		t.Fatalf("matching signed tx rejected: %v", err)
+	}
+
+	summary.Outputs[1].Address = to
+	if err := matchTxSummary("bitcoin", expected, summary); err == nil {
+		t.Error("redirected change output accepted")
+	}
+	summary.Outputs[1].Address = change
+	summary.Inputs[0].Vout = 0
+	if err := matchTxSummary("bitcoin", expected, summary); err == nil {
+		t.Error("replaced input accepted")
+	}
+}
@@ -0,0 +1,86 @@
+package utxo
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// 按 P2WPKH 估算交易虚拟大小，单位 vbyte
+const (
+	TxOverheadVSize = 11
+	InputVSize      = 68
+	OutputVSize     = 31
+)
+
+// DustLimit 低于该金额的找零直接并入手续费
+var DustLimit = big.NewInt(546)
+
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// Output 可花费的未花费输出
+type Output struct {
+	TxHash  string
+	Vout    uint32
+	Address string
+	Amount  *big.Int
+}
+
+// Selection 选币结果，Change 为 0 时交易没有找零输出
+type Selection struct {
+	Inputs []*Output
+	Total  *big.Int
+	Fee    *big.Int
+	Change *big.Int
+}
+
+// EstimateFee 按费率(sat/vB)估算手续费
+func EstimateFee(inputs, outputs int, feeRate uint64) *big.Int {
+	vsize := TxOverheadVSize + inputs*InputVSize + outputs*OutputVSize
+	return new(big.Int).Mul(big.NewInt(int64(vsize)), new(big.Int).SetUint64(feeRate))
+}
+
+// Select 从大到小选币直到覆盖金额和手续费，找零低于 DustLimit 时不找零
+func Select(candidates []*Output, amount *big.Int, feeRate uint64) (*Selection, error) {
+	return SelectOutputs(candidates, amount, 1, feeRate)
+}
+
+// SelectOutputs 与 Select 相同，amount 为 outputs 个支付输出的合计，用于一笔交易多个收款地址
+func SelectOutputs(candidates []*Output, amount *big.Int, outputs int, feeRate uint64) (*Selection, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount: %v", amount)
+	}
+	if outputs <= 0 {
+		return nil, fmt.Errorf("invalid output count: %d", outputs)
+	}
+	if feeRate == 0 {
+		return nil, errors.New("fee rate must be positive")
+	}
+	sorted := make([]*Output, len(candidates))
+	copy(sorted, candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Amount.Cmp(sorted[j].Amount) > 0
+	})
+
+	total := big.NewInt(0)
+	var inputs []*Output
+	for _, candidate := range sorted {
+		inputs = append(inputs, candidate)
+		total.Add(total, candidate.Amount)
+
+		// 先按带找零输出计算，找零太小时去掉找零输出
+		fee := EstimateFee(len(inputs), outputs+1, feeRate)
+		change := new(big.Int).Sub(total, amount)
+		change.Sub(change, fee)
+		if change.Cmp(DustLimit) >= 0 {
+			return &Selection{Inputs: inputs, Total: new(big.Int).Set(total), Fee: fee, Change: change}, nil
+		}
+		fee = EstimateFee(len(inputs), outputs, feeRate)
+		remain := new(big.Int).Sub(total, amount)
+		if remain.Cmp(fee) >= 0 {
+			return &Selection{Inputs: inputs, Total: new(big.Int).Set(total), Fee: remain, Change: big.NewInt(0)}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: have %s, need %s plus fee", ErrInsufficientFunds, total, amount)
+}
@@ -0,0 +1,83 @@
+package utxo
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func outputs(amounts ...int64) []*Output {
+	list := make([]*Output, 0, len(amounts))
+	for i, amount := range amounts {
+		list = append(list, &Output{TxHash: "tx", Vout: uint32(i), Amount: big.NewInt(amount)})
+	}
+	return list
+}
+
+func TestSelectWithChange(t *testing.T) {
+	selection, err := Select(outputs(10_000, 50_000, 20_000), big.NewInt(30_000), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selection.Inputs) != 1 || selection.Inputs[0].Amount.Int64() != 50_000 {
+		t.Fatalf("expected the largest output only, got %d inputs", len(selection.Inputs))
+	}
+	fee := EstimateFee(1, 2, 10)
+	if selection.Fee.Cmp(fee) != 0 {
+		t.Fatalf("fee = %s, want %s", selection.Fee, fee)
+	}
+	want := big.NewInt(50_000 - 30_000 - fee.Int64())
+	if selection.Change.Cmp(want) != 0 {
+		t.Fatalf("change = %s, want %s", selection.Change, want)
+	}
+}
+
+func TestSelectDropsDustChange(t *testing.T) {
+	fee := EstimateFee(1, 1, 10).Int64()
+	selection, err := Select(outputs(30_000+fee+100), big.NewInt(30_000), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if selection.Change.Sign() != 0 {
+		t.Fatalf("expected no change, got %s", selection.Change)
+	}
+	if selection.Fee.Int64() != fee+100 {
+		t.Fatalf("fee = %s, want %d", selection.Fee, fee+100)
+	}
+}
+
+func TestSelectMultipleInputs(t *testing.T) {
+	selection, err := Select(outputs(20_000, 20_000, 20_000), big.NewInt(35_000), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selection.Inputs) != 2 {
+		t.Fatalf("expected 2 inputs, got %d", len(selection.Inputs))
+	}
+	sum := new(big.Int).Add(selection.Fee, selection.Change)
+	sum.Add(sum, big.NewInt(35_000))
+	if sum.Cmp(selection.Total) != 0 {
+		t.Fatalf("amount + fee + change = %s, inputs = %s", sum, selection.Total)
+	}
+}
+
+func TestSelectInsufficientFunds(t *testing.T) {
+	_, err := Select(outputs(1_000, 2_000), big.NewInt(3_000), 1)
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("expected insufficient funds, got %v", err)
+	}
+}
+
+func TestSelectOutputsChargesEveryOutput(t *testing.T) {
+	selection, err := SelectOutputs(outputs(100_000), big.NewInt(30_000), 3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fee := EstimateFee(1, 4, 10)
+	if selection.Fee.Cmp(fee) != 0 {
+		t.Fatalf("fee = %s, want %s", selection.Fee, fee)
+	}
+	if _, err := SelectOutputs(outputs(100_000), big.NewInt(30_000), 0, 10); err == nil {
+		t.Fatal("zero outputs should be rejected")
+	}
+}
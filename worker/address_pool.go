@@ -0,0 +1,159 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	"github.com/dapplink-labs/multichain-sync-account/common/retry"
+	"github.com/dapplink-labs/multichain-sync-account/common/tasks"
+	"github.com/dapplink-labs/multichain-sync-account/config"
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	"github.com/dapplink-labs/multichain-sync-account/rpcclient"
+)
+
+// addressPoolBatchSize 每个业务每轮最多生成的地址数
+const addressPoolBatchSize = 200
+
+// AddressPool 从业务方上传的公钥生成地址，登记为未分配的充值地址
+type AddressPool struct {
+	rpcClient      *rpcclient.WalletChainAccountClient
+	db             *database.DB
+	resourceCtx    context.Context
+	resourceCancel context.CancelFunc
+	tasks          tasks.Group
+	ticker         *time.Ticker
+	chainName      string
+}
+
+func NewAddressPool(cfg *config.Config, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*AddressPool, error) {
+	resCtx, resCancel := context.WithCancel(context.Background())
+	return &AddressPool{
+		rpcClient:      rpcClient,
+		db:             db,
+		resourceCtx:    resCtx,
+		resourceCancel: resCancel,
+		tasks: tasks.Group{HandleCrit: func(err error) {
+			shutdown(fmt.Errorf("critical error in address pool: %w", err))
+		}},
+		ticker:    time.NewTicker(cfg.ChainNode.WorkerInterval),
+		chainName: rpcClient.ChainName,
+	}, nil
+}
+
+func (ap *AddressPool) Close() error {
+	var result error
+	ap.resourceCancel()
+	ap.ticker.Stop()
+	log.Info("stop address pool......")
+	if err := ap.tasks.Wait(); err != nil {
+		result = errors.Join(result, fmt.Errorf("failed to await address pool %w", err))
+		return result
+	}
+	log.Info("stop address pool success")
+	return nil
+}
+
+func (ap *AddressPool) Start() error {
+	log.Info("start address pool......")
+	ap.tasks.Go(func() error {
+		for {
+			select {
+			case <-ap.ticker.C:
+				businessList, err := ap.db.Business.QueryActiveBusinessList()
+				if err != nil {
+					log.Error("query business list fail", "err", err)
+					continue
+				}
+				for _, business := range businessList {
+					if err := ap.derivePoolAddresses(business.BusinessUid); err != nil {
+						return err
+					}
+				}
+			case <-ap.resourceCtx.Done():
+				log.Info("stop address pool in worker")
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+// derivePoolAddresses 生成的地址和零余额与地址池状态在同一个事务中写入，生成失败的公钥标记为 failed
+func (ap *AddressPool) derivePoolAddresses(businessUid string) error {
+	poolList, err := ap.db.AddressPool.QueryPendingPoolKeys(businessUid, ap.chainName, addressPoolBatchSize)
+	if err != nil {
+		log.Error("query pending pool keys fail", "businessId", businessUid, "err", err)
+		return nil
+	}
+	if len(poolList) == 0 {
+		return nil
+	}
+
+	var (
+		addressList []*database.Addresses
+		balanceList []*database.Balances
+	)
+	for _, pool := range poolList {
+		address := database.NormalizeAddress(ap.chainName, ap.rpcClient.ExportAddressByPubKey(pool.AddressFormat, pool.PublicKey))
+		if address == "" {
+			log.Warn("derive pool address fail", "businessId", businessUid, "publicKey", pool.PublicKey)
+			pool.Status = database.AddressPoolFailed
+			continue
+		}
+		pool.Address = address
+		// 已通过 exportAddressesByPublicKeys 导出的地址不进入地址池，避免重复登记和重复分配
+		if exist, _ := ap.db.Addresses.AddressExist(businessUid, ap.chainName, address); exist {
+			log.Warn("pool address already registered", "businessId", businessUid, "address", address)
+			pool.Status = database.AddressPoolFailed
+			continue
+		}
+		pool.Status = database.AddressPoolAvailable
+		addressList = append(addressList, &database.Addresses{
+			GUID:        uuid.New(),
+			Address:     address,
+			AddressType: database.AddressTypeEOA,
+			PublicKey:   pool.PublicKey,
+			Timestamp:   uint64(time.Now().Unix()),
+		})
+		// 新生成的地址没有历史余额，按零初始化
+		balanceList = append(balanceList, &database.Balances{
+			GUID:         uuid.New(),
+			Address:      address,
+			TokenAddress: common.Address{}.String(),
+			AddressType:  database.AddressTypeEOA,
+			Balance:      big.NewInt(0),
+			LockBalance:  big.NewInt(0),
+			Timestamp:    uint64(time.Now().Unix()),
+		})
+	}
+
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	if _, err := retry.Do[interface{}](ap.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
+		if err := ap.db.Transaction(func(tx *database.DB) error {
+			if len(addressList) > 0 {
+				if err := tx.Addresses.StoreAddresses(businessUid, ap.chainName, addressList); err != nil {
+					return err
+				}
+				if err := tx.Balances.StoreBalances(businessUid, ap.chainName, balanceList); err != nil {
+					return err
+				}
+			}
+			return tx.AddressPool.UpdatePoolKeysDerived(businessUid, ap.chainName, poolList)
+		}); err != nil {
+			log.Error("unable to persist pool addresses", "err", err)
+			return nil, err
+		}
+		return nil, nil
+	}); err != nil {
+		return err
+	}
+	log.Info("derive pool addresses", "businessId", businessUid, "total", len(poolList), "derived", len(addressList))
+	return nil
+}
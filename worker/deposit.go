@@ -12,6 +12,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/dapplink-labs/multichain-sync-account/alerting"
+	"github.com/dapplink-labs/multichain-sync-account/common/bigint"
 	"github.com/dapplink-labs/multichain-sync-account/common/retry"
 	"github.com/dapplink-labs/multichain-sync-account/common/tasks"
 	"github.com/dapplink-labs/multichain-sync-account/config"
@@ -31,7 +33,7 @@ type Deposit struct {
 	chainName      string
 }
 
-func NewDeposit(cfg *config.Config, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*Deposit, error) {
+func NewDeposit(cfg *config.Config, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, alerter *alerting.Alerter, shutdown context.CancelCauseFunc) (*Deposit, error) {
 	dbLatestBlockHeader, err := db.Blocks.LatestBlocks()
 	if err != nil {
 		log.Error("get latest block from database fail")
@@ -67,6 +69,7 @@ func NewDeposit(cfg *config.Config, db *database.DB, rpcClient *rpcclient.Wallet
 		rpcClient:        rpcClient,
 		blockBatch:       rpcclient.NewBatchBlock(rpcClient, fromHeader, big.NewInt(int64(cfg.ChainNode.Confirmations))),
 		database:         db,
+		alerter:          alerter,
 	}
 
 	resCtx, resCancel := context.WithCancel(context.Background())
@@ -115,14 +118,14 @@ func (deposit *Deposit) Start() error {
 }
 
 func (deposit *Deposit) handleBatch(batch map[string]*TransactionsChannel) error {
-	businessList, err := deposit.database.Business.QueryBusinessList()
+	businessList, err := deposit.database.Business.QueryScanBusinessList()
 	if err != nil {
 		log.Error("query business list fail", "err", err)
 		return err
 	}
-	if businessList == nil || len(businessList) <= 0 {
-		err := fmt.Errorf("QueryBusinessList businessList is nil")
-		return err
+	if len(businessList) <= 0 {
+		log.Warn("no business to scan, skip batch", "batch length", len(batch))
+		return nil
 	}
 
 	for _, business := range businessList {
@@ -137,6 +140,13 @@ func (deposit *Deposit) handleBatch(batch map[string]*TransactionsChannel) error
 			withdrawList        []*database.Withdraws
 			internals           []*database.Internals
 			balances            []*database.TokenBalance
+			quarantinedList     []*database.QuarantinedDeposits
+			creditedDust        []*database.Deposits
+			utxoList            []*database.Utxos
+			utxoSpends          []*utxoSpend
+			incidents           []*database.OutflowIncidents
+			listedTokens        = make(map[string]*listedToken)
+			pendingDust         = make(map[string][]*database.Deposits)
 		)
 
 		log.Info("handle business flow", "businessId", business.BusinessUid, "chainLatestBlock", batch[business.BusinessUid].BlockHeight, "txn", len(batch[business.BusinessUid].Transactions))
@@ -152,18 +162,129 @@ func (deposit *Deposit) handleBatch(batch map[string]*TransactionsChannel) error
 				err := fmt.Errorf("GetTransactionByHash txItem is nil: TxHash = %s", tx.Hash)
 				return err
 			}
+			if database.IsUTXOChain(deposit.chainName) {
+				utxoList = append(utxoList, deposit.HandleUtxos(business.BusinessUid, tx, txItem)...)
+			}
+			var (
+				unlisted    bool
+				belowMin    bool
+				depositRule *database.Tokens
+			)
+			if tx.TxType == database.TxTypeDeposit {
+				tokenAddress := txItem.ContractAddress
+				if tokenAddress == "" {
+					tokenAddress = tx.TokenAddress
+				}
+				rule, ok := listedTokens[tokenAddress]
+				if !ok {
+					token, listed, err := deposit.database.Tokens.QueryListedToken(business.BusinessUid, deposit.chainName, tokenAddress)
+					if err != nil {
+						log.Error("query listed token fail", "token", tokenAddress, "err", err)
+						return err
+					}
+					rule = &listedToken{token: token, listed: listed}
+					listedTokens[tokenAddress] = rule
+				}
+				if !rule.listed {
+					policy := business.TokenPolicy()
+					log.Warn("deposit of unlisted token", "businessId", business.BusinessUid, "txHash", tx.Hash, "token", tokenAddress, "policy", policy)
+					switch policy {
+					case database.UnlistedTokenIgnore:
+						continue
+					case database.UnlistedTokenQuarantine:
+						quarantinedList = append(quarantinedList, deposit.HandleQuarantine(tx, txItem, tokenAddress))
+						continue
+					}
+					unlisted = true
+				}
+				depositRule = rule.token
+			}
+
 			amountBigInt, _ := new(big.Int).SetString(txItem.Values[0].Value, 10)
 			log.Info("Transaction amount", "amountBigInt", amountBigInt, "FromAddress", tx.FromAddress, "TokenAddress", tx.TokenAddress, "TokenAddress", tx.ToAddress)
-			balances = append(
-				balances,
-				&database.TokenBalance{
-					FromAddress:  tx.FromAddress,
-					ToAddress:    txItem.Tos[0].Address,
-					TokenAddress: txItem.ContractAddress,
-					Balance:      amountBigInt,
-					TxType:       tx.TxType,
-				},
-			)
+
+			incident, err := deposit.HandleOutflow(business.BusinessUid, tx, txItem, amountBigInt)
+			if err != nil {
+				log.Error("check outflow fail", "txHash", tx.Hash, "err", err)
+				return err
+			}
+			if incident != nil {
+				incidents = append(incidents, incident)
+			}
+			if database.IsUTXOChain(deposit.chainName) {
+				spend, err := deposit.HandleUtxoSpend(business.BusinessUid, tx, incident)
+				if err != nil {
+					log.Error("resolve utxo spend fail", "txHash", tx.Hash, "err", err)
+					return err
+				}
+				if spend != nil {
+					utxoSpends = append(utxoSpends, spend)
+				}
+			}
+
+			var depositItem *database.Deposits
+			if tx.TxType == database.TxTypeDeposit {
+				depositItem, _ = deposit.HandleDeposit(tx, txItem)
+				depositItem.UnlistedToken = unlisted
+				depositItem.Memo = tx.Memo
+				depositItem.UserId = tx.UserId
+				// suspense 充值无法归属到用户，不参与零散充值累计
+				if !unlisted && !tx.MemoSuspense && depositRule != nil && depositRule.MinDeposit != nil && depositRule.MinDeposit.Sign() > 0 {
+					depositItem.MinDeposit = depositRule.MinDeposit
+					if amountBigInt.Cmp(depositRule.MinDeposit) < 0 {
+						belowMin = true
+						if depositRule.AggregateDust {
+							key := depositItem.ToAddress + ":" + depositItem.Memo + ":" + depositItem.TokenAddress
+							pending, ok := pendingDust[key]
+							if !ok {
+								pending, err = deposit.database.Deposits.QueryBelowMinimumDeposits(business.BusinessUid, deposit.chainName, depositItem.ToAddress, depositItem.Memo, depositItem.TokenAddress)
+								if err != nil {
+									log.Error("query below minimum deposits fail", "address", depositItem.ToAddress, "err", err)
+									return err
+								}
+							}
+							if dustTotal(pending, amountBigInt).Cmp(depositRule.MinDeposit) >= 0 {
+								log.Info("dust deposits reach minimum, credit them", "address", depositItem.ToAddress, "token", depositItem.TokenAddress, "count", len(pending)+1)
+								for _, dust := range pending {
+									dust.Status = database.TxStatusBroadcasted
+									dust.DustAggregated = true
+									balances = append(balances, dustBalance(dust))
+								}
+								creditedDust = append(creditedDust, pending...)
+								pendingDust[key] = nil
+								depositItem.DustAggregated = true
+								belowMin = false
+							} else {
+								pendingDust[key] = append(pending, depositItem)
+							}
+						}
+					}
+				}
+				if belowMin {
+					depositItem.Status = database.TxStatusBelowMinimum
+				} else if tx.MemoSuspense {
+					depositItem.Status = database.TxStatusSuspense
+				}
+			}
+
+			// 未登记代币和低于最小充值额的充值不计入余额，suspense 充值资金已在共用地址上，照常计入地址余额
+			if !unlisted && !belowMin {
+				balances = append(
+					balances,
+					&database.TokenBalance{
+						FromAddress:  tx.FromAddress,
+						ToAddress:    txItem.Tos[0].Address,
+						TokenAddress: txItem.ContractAddress,
+						Balance:      amountBigInt,
+						TxType:       tx.TxType,
+						TxHash:       tx.Hash,
+						BlockNumber:  tx.BlockNumber,
+						Fee:          bigint.StringToBigInt(txItem.Fee),
+						FromRole:     tx.FromRole,
+						ToRole:       tx.ToRole,
+					},
+				)
+			}
 
 			log.Info("get transaction success", "txHash", txItem.Hash)
 			transactionFlow, err := deposit.BuildTransaction(tx, txItem)
@@ -175,7 +296,6 @@ func (deposit *Deposit) handleBatch(batch map[string]*TransactionsChannel) error
 
 			switch tx.TxType {
 			case database.TxTypeDeposit:
-				depositItem, _ := deposit.HandleDeposit(tx, txItem)
 				depositList = append(depositList, depositItem)
 				break
 			case database.TxTypeWithdraw:
@@ -200,6 +320,31 @@ func (deposit *Deposit) handleBatch(batch map[string]*TransactionsChannel) error
 					}
 				}
 
+				if len(utxoList) > 0 {
+					if err := tx.Utxos.StoreUtxos(business.BusinessUid, deposit.chainName, utxoList); err != nil {
+						return err
+					}
+				}
+
+				for _, spend := range utxoSpends {
+					if err := spend.mark(tx.Utxos, business.BusinessUid, deposit.chainName); err != nil {
+						return err
+					}
+				}
+
+				if len(creditedDust) > 0 {
+					if err := tx.Deposits.CreditDustDeposits(business.BusinessUid, deposit.chainName, creditedDust); err != nil {
+						return err
+					}
+				}
+
+				if len(quarantinedList) > 0 {
+					log.Info("Store quarantined deposit success", "totalTx", len(quarantinedList))
+					if err := tx.Quarantined.StoreQuarantinedDeposits(business.BusinessUid, deposit.chainName, quarantinedList); err != nil {
+						return err
+					}
+				}
+
 				if err := tx.Deposits.UpdateDepositsComfirms(business.BusinessUid, deposit.chainName, batch[business.BusinessUid].BlockHeight, uint64(deposit.confirms)); err != nil {
 					log.Info("Handle confims fail", "totalTx", "err", err)
 					return err
@@ -224,6 +369,22 @@ func (deposit *Deposit) handleBatch(batch map[string]*TransactionsChannel) error
 					}
 				}
 
+				if len(incidents) > 0 {
+					pause := business.IncidentPolicy() == database.OutflowPause
+					for _, incident := range incidents {
+						incident.WithdrawPaused = pause
+					}
+					if err := tx.Incidents.StoreIncidents(incidents); err != nil {
+						return err
+					}
+					if pause && !business.WithdrawPaused {
+						log.Warn("pause withdraws for unauthorized outflow", "businessId", business.BusinessUid, "incidents", len(incidents))
+						if err := tx.Business.UpdateWithdrawPaused(business.BusinessUid, true); err != nil {
+							return err
+						}
+					}
+				}
+
 				if len(transactionFlowList) > 0 {
 					if err := tx.Transactions.StoreTransactions(business.BusinessUid, deposit.chainName, transactionFlowList, uint64(len(transactionFlowList))); err != nil {
 						return err
@@ -263,6 +424,121 @@ func (deposit *Deposit) HandleDeposit(tx *Transaction, txMsg *account.TxMessage)
 	return depositTx, nil
 }
 
+// listedToken 批次内缓存的代币登记结果，原生代币未登记时 token 为 nil
+type listedToken struct {
+	token  *database.Tokens
+	listed bool
+}
+
+func dustTotal(pending []*database.Deposits, amount *big.Int) *big.Int {
+	total := new(big.Int).Set(amount)
+	for _, dust := range pending {
+		total.Add(total, dust.Amount)
+	}
+	return total
+}
+
+func dustBalance(dust *database.Deposits) *database.TokenBalance {
+	return &database.TokenBalance{
+		FromAddress:  dust.FromAddress,
+		ToAddress:    dust.ToAddress,
+		TokenAddress: dust.TokenAddress,
+		Balance:      dust.Amount,
+		TxType:       database.TxTypeDeposit,
+		TxHash:       dust.TxHash.String(),
+		BlockNumber:  dust.BlockNumber,
+	}
+}
+
+// HandleUtxos 记录交易中转入业务地址的输出，包括充值、归集和找零，输出序号与 Tos 的顺序一致
+func (deposit *Deposit) HandleUtxos(businessUid string, tx *Transaction, txMsg *account.TxMessage) []*database.Utxos {
+	var utxoList []*database.Utxos
+	for i, to := range txMsg.Tos {
+		if i >= len(txMsg.Values) {
+			break
+		}
+		if exist, _ := deposit.database.Addresses.AddressExist(businessUid, deposit.chainName, to.Address); !exist {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(txMsg.Values[i].Value, 10)
+		if !ok || amount.Sign() <= 0 {
+			continue
+		}
+		utxoList = append(utxoList, &database.Utxos{
+			GUID:        uuid.New(),
+			Address:     to.Address,
+			TxHash:      tx.Hash,
+			Vout:        uint32(i),
+			Amount:      amount,
+			BlockNumber: tx.BlockNumber,
+			Status:      database.UtxoUnspent,
+			Timestamp:   uint64(time.Now().Unix()),
+		})
+	}
+	return utxoList
+}
+
+// utxoSpend 扫描到的从登记地址花费输出的交易，spendGuid 为系统创建的交易锁定输出时的交易 id，
+// 为空表示不是系统创建的交易
+type utxoSpend struct {
+	address     string
+	txHash      string
+	blockNumber *big.Int
+	spendGuid   string
+}
+
+func (s *utxoSpend) mark(utxos database.UtxosDB, businessUid string, chainName string) error {
+	if s.spendGuid != "" {
+		return utxos.MarkUtxosSpent(businessUid, chainName, s.spendGuid, s.txHash)
+	}
+	return utxos.MarkAddressUtxosSpent(businessUid, chainName, s.address, s.blockNumber, s.txHash)
+}
+
+// HandleUtxoSpend chain-account 的交易数据不包含输入的输出点：系统创建的交易按锁定输出时的交易 id 标记已花费，
+// 交易哈希尚未回写的由广播流程标记；未授权转出无法确定花费了哪些输出，地址上该区块之前的输出全部标记为已花费
+func (deposit *Deposit) HandleUtxoSpend(businessUid string, tx *Transaction, incident *database.OutflowIncidents) (*utxoSpend, error) {
+	if !managedSender(tx) {
+		return nil, nil
+	}
+	spend := &utxoSpend{address: tx.FromAddress, txHash: tx.Hash, blockNumber: tx.BlockNumber}
+	if incident != nil {
+		return spend, nil
+	}
+	txHash := common.HexToHash(tx.Hash)
+	withdraw, err := deposit.database.Withdraws.QueryWithdrawsByHash(businessUid, deposit.chainName, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if withdraw != nil {
+		spend.spendGuid = withdraw.SpendGuid()
+		return spend, nil
+	}
+	internal, err := deposit.database.Internals.QueryInternalsByTxHash(businessUid, deposit.chainName, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if internal != nil {
+		spend.spendGuid = internal.GUID.String()
+		return spend, nil
+	}
+	return nil, nil
+}
+
+func (deposit *Deposit) HandleQuarantine(tx *Transaction, txMsg *account.TxMessage, tokenAddress string) *database.QuarantinedDeposits {
+	txAmount, _ := new(big.Int).SetString(txMsg.Values[0].Value, 10)
+	return &database.QuarantinedDeposits{
+		GUID:         uuid.New(),
+		BlockNumber:  tx.BlockNumber,
+		TxHash:       common.HexToHash(tx.Hash),
+		FromAddress:  tx.FromAddress,
+		ToAddress:    tx.ToAddress,
+		TokenAddress: tokenAddress,
+		Amount:       txAmount,
+		Fee:          txMsg.Fee,
+		Timestamp:    uint64(time.Now().Unix()),
+	}
+}
+
 func (deposit *Deposit) HandleWithdraw(tx *Transaction, txMsg *account.TxMessage) (*database.Withdraws, error) {
 	//txFee, _ := new(big.Int).SetString(txMsg.Fee, 10)
 	txAmount, _ := new(big.Int).SetString(txMsg.Values[0].Value, 10)
@@ -41,7 +41,7 @@ func setupDeposit(t *testing.T) *Deposit {
 
 	// 设置账户客户端
 	client := account.NewWalletAccountServiceClient(conn)
-	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, "Ethereum")
+	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, "Ethereum", database.GetNetwork("Ethereum"))
 	assert.NoError(t, err)
 
 	// 设置配置
@@ -65,7 +65,7 @@ func setupDeposit(t *testing.T) *Deposit {
 	}
 
 	// 创建 Deposit worker
-	deposit, err := NewDeposit(cfg, db, accountClient, shutdown)
+	deposit, err := NewDeposit(cfg, db, accountClient, nil, shutdown)
 	assert.NoError(t, err)
 
 	return deposit
@@ -109,7 +109,7 @@ func TestDeposit_SendTransaction(t *testing.T) {
 
 	depositTxId := "818e6568-17ee-463b-ad29-ea05adcc664d"
 
-	dbDeposit, err := deposit.database.Deposits.QueryDepositsById(strconv.Itoa(CurrentRequestId), depositTxId)
+	dbDeposit, err := deposit.database.Deposits.QueryDepositsById(strconv.Itoa(CurrentRequestId), CurrentChain, depositTxId)
 	assert.NoError(t, err)
 
 	// 模拟发送交易上链
@@ -119,7 +119,7 @@ func TestDeposit_SendTransaction(t *testing.T) {
 	dbDeposit.TxHash = common.HexToHash(sendTx)
 	dbDeposit.Status = database.TxStatusBroadcasted
 
-	err = deposit.database.Deposits.UpdateDepositListById(strconv.Itoa(CurrentRequestId), []*database.Deposits{dbDeposit})
+	err = deposit.database.Deposits.UpdateDepositListById(strconv.Itoa(CurrentRequestId), CurrentChain, []*database.Deposits{dbDeposit})
 	assert.NoError(t, err)
 }
 func TestDeposit_depostit(t *testing.T) {
@@ -139,7 +139,7 @@ func TestDeposit_depostit(t *testing.T) {
 		},
 	)
 
-	err := deposit.database.Balances.UpdateOrCreate("xiaohuolong", balances)
+	err := deposit.database.Balances.UpdateOrCreate("xiaohuolong", CurrentChain, balances)
 
 	assert.NoError(t, err)
 
@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/dapplink-labs/multichain-sync-account/common/retry"
+	"github.com/dapplink-labs/multichain-sync-account/common/tasks"
+	"github.com/dapplink-labs/multichain-sync-account/config"
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	"github.com/dapplink-labs/multichain-sync-account/rpcclient"
+)
+
+// Expiry 提现和内部交易创建后超时仍未签名或未广播成功时标记为 expired，释放锁定的 UTXO，
+// 不再计入热钱包的在途出款和待用 nonce
+type Expiry struct {
+	db             *database.DB
+	resourceCtx    context.Context
+	resourceCancel context.CancelFunc
+	tasks          tasks.Group
+	ticker         *time.Ticker
+	chainName      string
+	ttl            time.Duration
+}
+
+func NewExpiry(cfg *config.Config, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*Expiry, error) {
+	resCtx, resCancel := context.WithCancel(context.Background())
+	return &Expiry{
+		db:             db,
+		resourceCtx:    resCtx,
+		resourceCancel: resCancel,
+		tasks: tasks.Group{HandleCrit: func(err error) {
+			shutdown(fmt.Errorf("critical error in expiry: %w", err))
+		}},
+		ticker:    time.NewTicker(time.Minute),
+		chainName: rpcClient.ChainName,
+		ttl:       cfg.ChainNode.TxExpiry,
+	}, nil
+}
+
+func (e *Expiry) Close() error {
+	var result error
+	e.resourceCancel()
+	e.ticker.Stop()
+	log.Info("stop expiry......")
+	if err := e.tasks.Wait(); err != nil {
+		result = errors.Join(result, fmt.Errorf("failed to await expiry %w", err))
+		return result
+	}
+	log.Info("stop expiry success")
+	return nil
+}
+
+func (e *Expiry) Start() error {
+	log.Info("start expiry......", "ttl", e.ttl)
+	e.tasks.Go(func() error {
+		for {
+			select {
+			case <-e.ticker.C:
+				// 暂停的业务同样需要过期交易并释放锁定的 UTXO
+				businessList, err := e.db.Business.QueryScanBusinessList()
+				if err != nil {
+					log.Error("query business list fail", "err", err)
+					continue
+				}
+				createdBefore := uint64(time.Now().Add(-e.ttl).Unix())
+				for _, business := range businessList {
+					if err := e.expireBusiness(business.BusinessUid, createdBefore); err != nil {
+						return err
+					}
+				}
+			case <-e.resourceCtx.Done():
+				log.Info("stop expiry in worker")
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+func (e *Expiry) expireBusiness(businessUid string, createdBefore uint64) error {
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	_, err := retry.Do[interface{}](e.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
+		if err := e.db.Transaction(func(tx *database.DB) error {
+			withdraws, err := tx.Withdraws.ExpireWithdraws(businessUid, e.chainName, createdBefore)
+			if err != nil {
+				return err
+			}
+			internals, err := tx.Internals.ExpireInternals(businessUid, e.chainName, createdBefore)
+			if err != nil {
+				return err
+			}
+			if len(withdraws) == 0 && len(internals) == 0 {
+				return nil
+			}
+			log.Warn("expire stale transactions", "businessUid", businessUid, "withdraws", len(withdraws), "internals", len(internals))
+			if !database.IsUTXOChain(e.chainName) {
+				return nil
+			}
+			spendGuids := make([]string, 0, len(withdraws)+len(internals))
+			for _, withdraw := range withdraws {
+				spendGuids = append(spendGuids, withdraw.SpendGuid())
+			}
+			for _, internal := range internals {
+				spendGuids = append(spendGuids, internal.GUID.String())
+			}
+			return tx.Utxos.UnlockUtxos(businessUid, e.chainName, spendGuids)
+		}); err != nil {
+			log.Error("expire stale transactions fail", "businessUid", businessUid, "err", err)
+			return nil, err
+		}
+		return nil, nil
+	})
+	return err
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/dapplink-labs/multichain-sync-account/alerting"
 	"github.com/dapplink-labs/multichain-sync-account/common/retry"
 	"github.com/dapplink-labs/multichain-sync-account/common/tasks"
 	"github.com/dapplink-labs/multichain-sync-account/config"
@@ -24,9 +25,10 @@ type Internal struct {
 	tasks          tasks.Group
 	ticker         *time.Ticker
 	chainName      string
+	alerter        *alerting.Alerter
 }
 
-func NewInternal(cfg *config.Config, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*Internal, error) {
+func NewInternal(cfg *config.Config, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, alerter *alerting.Alerter, shutdown context.CancelCauseFunc) (*Internal, error) {
 	resCtx, resCancel := context.WithCancel(context.Background())
 	return &Internal{
 		rpcClient:      rpcClient,
@@ -38,6 +40,7 @@ func NewInternal(cfg *config.Config, db *database.DB, rpcClient *rpcclient.Walle
 		}},
 		ticker:    time.NewTicker(cfg.ChainNode.WorkerInterval),
 		chainName: rpcClient.ChainName,
+		alerter:   alerter,
 	}, nil
 }
 
@@ -61,7 +64,7 @@ func (w *Internal) Start() error {
 			select {
 			case <-w.ticker.C:
 				log.Info("collection and hot to cold")
-				businessList, err := w.db.Business.QueryBusinessList()
+				businessList, err := w.db.Business.QueryActiveBusinessList()
 				if err != nil {
 					log.Error("query business list fail", "err", err)
 					continue
@@ -78,22 +81,31 @@ func (w *Internal) Start() error {
 						continue
 					}
 
-					var balanceList []*database.Balances
+					var (
+						balanceList []*database.TokenBalance
+						spentUtxos  = make(map[string]string)
+					)
 
 					for _, unSendInternalTx := range unSendTransactionList {
 						txHash, err := w.rpcClient.SendTx(unSendInternalTx.TxSignHex)
+						w.alerter.BroadcastResult(w.chainName, err)
 						if err != nil {
 							log.Error("send transaction fail", "err", err)
 							continue
 						} else {
-							balanceItem := &database.Balances{
+							balanceItem := &database.TokenBalance{
+								FromAddress:  unSendInternalTx.FromAddress,
 								TokenAddress: unSendInternalTx.TokenAddress,
-								Address:      unSendInternalTx.FromAddress,
-								LockBalance:  unSendInternalTx.Amount,
+								Balance:      unSendInternalTx.Amount,
+								TxType:       unSendInternalTx.TxType,
+								TxHash:       txHash,
 							}
 							balanceList = append(balanceList, balanceItem)
 
 							unSendInternalTx.TxHash = common.HexToHash(txHash)
+							if database.IsUTXOChain(w.chainName) {
+								spentUtxos[unSendInternalTx.GUID.String()] = txHash
+							}
 							unSendInternalTx.Status = database.TxStatusBroadcasted
 						}
 					}
@@ -101,9 +113,14 @@ func (w *Internal) Start() error {
 					retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
 					if _, err := retry.Do[interface{}](w.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
 						if err := w.db.Transaction(func(tx *database.DB) error {
+							for spendGuid, txHash := range spentUtxos {
+								if err := tx.Utxos.MarkUtxosSpent(businessId.BusinessUid, w.chainName, spendGuid, txHash); err != nil {
+									return err
+								}
+							}
 							if len(balanceList) > 0 {
 								log.Info("Update address balance", "totalTx", len(balanceList))
-								if err := tx.Balances.UpdateBalanceListByTwoAddress(businessId.BusinessUid, w.chainName, balanceList); err != nil {
+								if err := tx.Balances.LockBalances(businessId.BusinessUid, w.chainName, balanceList); err != nil {
 									log.Error("Update address balance fail", "err", err)
 									return err
 								}
@@ -30,7 +30,7 @@ func setupInternal(t *testing.T) *Internal {
 
 	// 设置账户客户端
 	client := account.NewWalletAccountServiceClient(conn)
-	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, "Ethereum")
+	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, "Ethereum", database.GetNetwork("Ethereum"))
 	assert.NoError(t, err)
 
 	// 设置配置
@@ -46,7 +46,7 @@ func setupInternal(t *testing.T) *Internal {
 	}
 
 	// 创建 Withdraw worker
-	internal, err := NewInternal(cfg, db, accountClient, shutdown)
+	internal, err := NewInternal(cfg, db, accountClient, nil, shutdown)
 	assert.NoError(t, err)
 
 	return internal
@@ -0,0 +1,282 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	"github.com/dapplink-labs/multichain-sync-account/alerting"
+	"github.com/dapplink-labs/multichain-sync-account/common/retry"
+	"github.com/dapplink-labs/multichain-sync-account/common/tasks"
+	"github.com/dapplink-labs/multichain-sync-account/config"
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	"github.com/dapplink-labs/multichain-sync-account/rpcclient"
+)
+
+// Liquidity 监控热钱包余额，预计余额低于代币配置的下限时创建 cold2hot 内部交易，由业务方冷钱包签名补充
+type Liquidity struct {
+	db             *database.DB
+	resourceCtx    context.Context
+	resourceCancel context.CancelFunc
+	tasks          tasks.Group
+	ticker         *time.Ticker
+	chainName      string
+	alerter        *alerting.Alerter
+}
+
+func NewLiquidity(cfg *config.Config, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, alerter *alerting.Alerter, shutdown context.CancelCauseFunc) (*Liquidity, error) {
+	resCtx, resCancel := context.WithCancel(context.Background())
+	return &Liquidity{
+		db:             db,
+		resourceCtx:    resCtx,
+		resourceCancel: resCancel,
+		tasks: tasks.Group{HandleCrit: func(err error) {
+			shutdown(fmt.Errorf("critical error in liquidity: %w", err))
+		}},
+		ticker:    time.NewTicker(cfg.ChainNode.WorkerInterval),
+		chainName: rpcClient.ChainName,
+		alerter:   alerter,
+	}, nil
+}
+
+func (l *Liquidity) Close() error {
+	var result error
+	l.resourceCancel()
+	l.ticker.Stop()
+	log.Info("stop liquidity......")
+	if err := l.tasks.Wait(); err != nil {
+		result = errors.Join(result, fmt.Errorf("failed to await liquidity %w", err))
+		return result
+	}
+	log.Info("stop liquidity success")
+	return nil
+}
+
+func (l *Liquidity) Start() error {
+	log.Info("start liquidity......")
+	l.tasks.Go(func() error {
+		for {
+			select {
+			case <-l.ticker.C:
+				businessList, err := l.db.Business.QueryActiveBusinessList()
+				if err != nil {
+					log.Error("query business list fail", "err", err)
+					continue
+				}
+				for _, business := range businessList {
+					if err := l.checkBusiness(business.BusinessUid); err != nil {
+						return err
+					}
+				}
+			case <-l.resourceCtx.Done():
+				log.Info("stop liquidity in worker")
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+// hotLiquidity 热钱包某个代币的余额和在途金额，outflow 为已创建未广播的出款(广播后已转入锁定余额)，
+// inflow 为尚未确认的 cold2hot 补充
+type hotLiquidity struct {
+	balance *big.Int
+	outflow *big.Int
+	inflow  *big.Int
+}
+
+func (h *hotLiquidity) projected() *big.Int {
+	projected := new(big.Int).Sub(h.balance, h.outflow)
+	return projected.Add(projected, h.inflow)
+}
+
+// topUpAmount 预计余额低于下限时补充到目标余额，目标余额未配置或低于下限时补充到下限，不需要补充时返回 nil
+func topUpAmount(projected, minBalance, targetBalance *big.Int) *big.Int {
+	if projected.Cmp(minBalance) >= 0 {
+		return nil
+	}
+	target := minBalance
+	if targetBalance != nil && targetBalance.Cmp(minBalance) > 0 {
+		target = targetBalance
+	}
+	return new(big.Int).Sub(target, projected)
+}
+
+func liquidityKey(chainName string, address string, tokenAddress string) string {
+	token := ""
+	if !database.IsNativeToken(chainName, tokenAddress) {
+		token = database.NormalizeAddress(chainName, tokenAddress)
+	}
+	return database.NormalizeAddress(chainName, address) + "/" + token
+}
+
+func (l *Liquidity) checkBusiness(businessUid string) error {
+	tokenList, err := l.db.Tokens.QueryLiquidityTokens(businessUid, l.chainName)
+	if err != nil {
+		log.Error("query liquidity tokens fail", "businessUid", businessUid, "err", err)
+		return nil
+	}
+	if len(tokenList) == 0 {
+		return nil
+	}
+	hotWallets, err := l.db.Addresses.QueryHotWallets(businessUid, l.chainName)
+	if err != nil {
+		log.Error("query hot wallets fail", "businessUid", businessUid, "err", err)
+		return nil
+	}
+	if len(hotWallets) == 0 {
+		return nil
+	}
+	coldWallet, err := l.db.Addresses.QueryColdWalletInfo(businessUid, l.chainName)
+	if err != nil {
+		log.Error("query cold wallet fail", "businessUid", businessUid, "err", err)
+		return nil
+	}
+	if coldWallet == nil {
+		log.Warn("hot wallet liquidity configured without cold wallet", "businessUid", businessUid)
+		return nil
+	}
+
+	liquidity, err := l.loadLiquidity(businessUid)
+	if err != nil {
+		log.Error("load hot wallet liquidity fail", "businessUid", businessUid, "err", err)
+		return nil
+	}
+
+	var (
+		internals []*database.Internals
+		topUps    []*database.LiquidityTopUps
+	)
+	now := uint64(time.Now().Unix())
+	for _, hotWallet := range hotWallets {
+		for _, token := range tokenList {
+			current := liquidity[liquidityKey(l.chainName, hotWallet.Address, token.TokenAddress)]
+			if current == nil {
+				current = &hotLiquidity{balance: big.NewInt(0), outflow: big.NewInt(0), inflow: big.NewInt(0)}
+			}
+			// 告警按扣除在途出款后的可用余额评估，补充交易确认前不恢复
+			l.alerter.HotWalletBalance(l.chainName, businessUid, hotWallet.Address, token.TokenAddress,
+				new(big.Int).Sub(current.balance, current.outflow), token.HotMinBalance)
+			amount := topUpAmount(current.projected(), token.HotMinBalance, token.HotTargetBalance)
+			if amount == nil {
+				continue
+			}
+			internal := &database.Internals{
+				GUID:         uuid.New(),
+				Timestamp:    now,
+				Status:       database.TxStatusCreateUnsigned,
+				BlockHash:    common.Hash{},
+				BlockNumber:  big.NewInt(1),
+				TxHash:       common.Hash{},
+				TxType:       database.TxTypeCold2Hot,
+				FromAddress:  coldWallet.Address,
+				ToAddress:    hotWallet.Address,
+				Amount:       amount,
+				TokenType:    database.GetTokenType(l.chainName, database.IsNativeToken(l.chainName, token.TokenAddress)),
+				TokenAddress: token.TokenAddress,
+			}
+			internals = append(internals, internal)
+			topUps = append(topUps, &database.LiquidityTopUps{
+				GUID:           uuid.New(),
+				BusinessUid:    businessUid,
+				ChainName:      l.chainName,
+				TransactionId:  internal.GUID.String(),
+				HotAddress:     hotWallet.Address,
+				ColdAddress:    coldWallet.Address,
+				TokenAddress:   token.TokenAddress,
+				Balance:        current.balance,
+				PendingOutflow: current.outflow,
+				PendingInflow:  current.inflow,
+				MinBalance:     token.HotMinBalance,
+				TargetBalance:  token.HotTargetBalance,
+				Amount:         amount,
+				NotifyStatus:   database.LiquidityNotifyPending,
+				Timestamp:      now,
+			})
+			log.Warn("hot wallet below minimum balance", "businessUid", businessUid, "address", hotWallet.Address, "token", token.TokenAddress,
+				"projected", current.projected(), "min", token.HotMinBalance, "topUp", amount)
+		}
+	}
+	if len(internals) == 0 {
+		return nil
+	}
+
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	if _, err := retry.Do[interface{}](l.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
+		if err := l.db.Transaction(func(tx *database.DB) error {
+			for _, internal := range internals {
+				if err := tx.Internals.StoreInternal(businessUid, l.chainName, internal); err != nil {
+					return err
+				}
+			}
+			return tx.LiquidityTopUps.StoreTopUps(topUps)
+		}); err != nil {
+			log.Error("unable to persist liquidity top ups", "err", err)
+			return nil, err
+		}
+		return nil, nil
+	}); err != nil {
+		return err
+	}
+	log.Info("create hot wallet top ups", "businessUid", businessUid, "count", len(internals))
+	return nil
+}
+
+// loadLiquidity 汇总热钱包的余额、未广播的出款和未确认的 cold2hot 补充，未确认的补充也计入预计余额，避免重复创建
+func (l *Liquidity) loadLiquidity(businessUid string) (map[string]*hotLiquidity, error) {
+	liquidity := make(map[string]*hotLiquidity)
+	entry := func(address string, tokenAddress string) *hotLiquidity {
+		key := liquidityKey(l.chainName, address, tokenAddress)
+		if liquidity[key] == nil {
+			liquidity[key] = &hotLiquidity{balance: big.NewInt(0), outflow: big.NewInt(0), inflow: big.NewInt(0)}
+		}
+		return liquidity[key]
+	}
+
+	balances, err := l.db.Balances.QueryBalancesByAddressType(businessUid, l.chainName, database.AddressTypeHot)
+	if err != nil {
+		return nil, err
+	}
+	for _, balance := range balances {
+		current := entry(balance.Address, balance.TokenAddress)
+		current.balance.Add(current.balance, balance.Balance)
+	}
+
+	withdraws, err := l.db.Withdraws.QueryPendingWithdraws(businessUid, l.chainName)
+	if err != nil {
+		return nil, err
+	}
+	for _, withdraw := range withdraws {
+		if withdraw.Status == database.TxStatusBroadcasted || withdraw.Amount == nil {
+			continue
+		}
+		current := entry(withdraw.FromAddress, withdraw.TokenAddress)
+		current.outflow.Add(current.outflow, withdraw.Amount)
+	}
+
+	internals, err := l.db.Internals.QueryPendingInternals(businessUid, l.chainName)
+	if err != nil {
+		return nil, err
+	}
+	for _, internal := range internals {
+		if internal.Amount == nil {
+			continue
+		}
+		if internal.TxType == database.TxTypeCold2Hot {
+			current := entry(internal.ToAddress, internal.TokenAddress)
+			current.inflow.Add(current.inflow, internal.Amount)
+			continue
+		}
+		if internal.Status != database.TxStatusBroadcasted {
+			current := entry(internal.FromAddress, internal.TokenAddress)
+			current.outflow.Add(current.outflow, internal.Amount)
+		}
+	}
+	return liquidity, nil
+}
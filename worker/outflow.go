@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	"github.com/dapplink-labs/multichain-sync-account/rpcclient/chain-account/account"
+)
+
+// managedSender 发送方是业务登记的地址
+func managedSender(tx *Transaction) bool {
+	return tx.FromRole != "" && tx.FromRole != database.AddressRoleExternal
+}
+
+// HandleOutflow 登记地址转出的交易在 withdraws 和 internals 中都找不到对应记录时，说明不是系统创建的，
+// 可能是地址私钥泄露，返回待记录的事件
+func (deposit *Deposit) HandleOutflow(businessUid string, tx *Transaction, txMsg *account.TxMessage, amount *big.Int) (*database.OutflowIncidents, error) {
+	if !managedSender(tx) {
+		return nil, nil
+	}
+	txHash := common.HexToHash(tx.Hash)
+	withdraw, err := deposit.database.Withdraws.QueryWithdrawsByHash(businessUid, deposit.chainName, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if withdraw != nil {
+		return nil, nil
+	}
+	internal, err := deposit.database.Internals.QueryInternalsByTxHash(businessUid, deposit.chainName, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if internal != nil {
+		return nil, nil
+	}
+	// 广播后回写交易哈希之前扫描到的交易，按签名完成的记录匹配
+	signed, err := deposit.signedOutflow(businessUid, tx, amount)
+	if err != nil || signed {
+		return nil, err
+	}
+
+	tokenAddress := txMsg.ContractAddress
+	if tokenAddress == "" {
+		tokenAddress = tx.TokenAddress
+	}
+	if amount == nil {
+		amount = big.NewInt(0)
+	}
+	log.Warn("Found unauthorized outflow", "businessId", businessUid, "txHash", tx.Hash, "from", tx.FromAddress, "fromRole", tx.FromRole,
+		"to", tx.ToAddress, "token", tokenAddress, "amount", amount)
+	return &database.OutflowIncidents{
+		GUID:         uuid.New(),
+		BusinessUid:  businessUid,
+		ChainName:    deposit.chainName,
+		TxHash:       tx.Hash,
+		BlockNumber:  tx.BlockNumber,
+		FromAddress:  tx.FromAddress,
+		FromRole:     tx.FromRole,
+		ToAddress:    tx.ToAddress,
+		ToRole:       tx.ToRole,
+		TokenAddress: tokenAddress,
+		Amount:       amount,
+		TxType:       tx.TxType,
+		NotifyStatus: database.IncidentNotifyPending,
+		Timestamp:    uint64(time.Now().Unix()),
+	}, nil
+}
+
+// signedOutflow 是否有已签名、尚未记录交易哈希的出款与交易的双方和金额一致，
+// multi-send 批次按 batch_id 汇总，交易的接收方为 multisend 合约，金额为批次合计
+func (deposit *Deposit) signedOutflow(businessUid string, tx *Transaction, amount *big.Int) (bool, error) {
+	matches := func(from, to string, value *big.Int, status database.TxStatus) bool {
+		return status == database.TxStatusSigned && value != nil && amount != nil && value.Cmp(amount) == 0 &&
+			database.NormalizeAddress(deposit.chainName, from) == tx.FromAddress &&
+			database.NormalizeAddress(deposit.chainName, to) == tx.ToAddress
+	}
+	withdraws, err := deposit.database.Withdraws.QueryPendingWithdraws(businessUid, deposit.chainName)
+	if err != nil {
+		return false, err
+	}
+	batchTotals := make(map[string]*big.Int)
+	batchFroms := make(map[string]string)
+	for _, withdraw := range withdraws {
+		if withdraw.MultiSend {
+			if withdraw.Status != database.TxStatusSigned || withdraw.Amount == nil {
+				continue
+			}
+			if _, ok := batchTotals[withdraw.BatchId]; !ok {
+				batchTotals[withdraw.BatchId] = new(big.Int)
+				batchFroms[withdraw.BatchId] = withdraw.FromAddress
+			}
+			batchTotals[withdraw.BatchId].Add(batchTotals[withdraw.BatchId], withdraw.Amount)
+			continue
+		}
+		if matches(withdraw.FromAddress, withdraw.ToAddress, withdraw.Amount, withdraw.Status) {
+			return true, nil
+		}
+	}
+	if chainConfig, ok := database.GetChainConfig(deposit.chainName); ok && chainConfig.MultiSendContract != "" {
+		for batchId, total := range batchTotals {
+			if matches(batchFroms[batchId], chainConfig.MultiSendContract, total, database.TxStatusSigned) {
+				return true, nil
+			}
+		}
+	}
+	internals, err := deposit.database.Internals.QueryPendingInternals(businessUid, deposit.chainName)
+	if err != nil {
+		return false, err
+	}
+	for _, internal := range internals {
+		if matches(internal.FromAddress, internal.ToAddress, internal.Amount, internal.Status) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
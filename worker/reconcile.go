@@ -0,0 +1,285 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	"github.com/dapplink-labs/multichain-sync-account/alerting"
+	"github.com/dapplink-labs/multichain-sync-account/common/retry"
+	"github.com/dapplink-labs/multichain-sync-account/common/tasks"
+	"github.com/dapplink-labs/multichain-sync-account/config"
+	"github.com/dapplink-labs/multichain-sync-account/database"
+	"github.com/dapplink-labs/multichain-sync-account/rpcclient"
+)
+
+type Reconcile struct {
+	rpcClient       *rpcclient.WalletChainAccountClient
+	db              *database.DB
+	resourceCtx     context.Context
+	resourceCancel  context.CancelFunc
+	tasks           tasks.Group
+	ticker          *time.Ticker
+	chainName       string
+	autoCorrect     bool
+	tolerance       *big.Int
+	notifyThreshold *big.Int
+	confirmations   uint64
+	alerter         *alerting.Alerter
+}
+
+func NewReconcile(cfg *config.Config, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, alerter *alerting.Alerter, shutdown context.CancelCauseFunc) (*Reconcile, error) {
+	tolerance, ok := new(big.Int).SetString(cfg.Reconcile.Tolerance, 10)
+	if !ok || tolerance.Sign() < 0 {
+		return nil, fmt.Errorf("invalid reconcile tolerance: %q", cfg.Reconcile.Tolerance)
+	}
+	notifyThreshold, ok := new(big.Int).SetString(cfg.Reconcile.NotifyThreshold, 10)
+	if !ok || notifyThreshold.Sign() < 0 {
+		return nil, fmt.Errorf("invalid reconcile notify threshold: %q", cfg.Reconcile.NotifyThreshold)
+	}
+	resCtx, resCancel := context.WithCancel(context.Background())
+	return &Reconcile{
+		rpcClient:      rpcClient,
+		db:             db,
+		resourceCtx:    resCtx,
+		resourceCancel: resCancel,
+		tasks: tasks.Group{HandleCrit: func(err error) {
+			shutdown(fmt.Errorf("critical error in reconcile: %w", err))
+		}},
+		ticker:          time.NewTicker(cfg.Reconcile.Interval),
+		chainName:       rpcClient.ChainName,
+		autoCorrect:     cfg.Reconcile.AutoCorrect,
+		tolerance:       tolerance,
+		notifyThreshold: notifyThreshold,
+		confirmations:   uint64(cfg.ChainNode.Confirmations),
+		alerter:         alerter,
+	}, nil
+}
+
+func (r *Reconcile) Close() error {
+	var result error
+	r.resourceCancel()
+	r.ticker.Stop()
+	log.Info("stop reconcile......")
+	if err := r.tasks.Wait(); err != nil {
+		result = errors.Join(result, fmt.Errorf("failed to await reconcile %w", err))
+		return result
+	}
+	log.Info("stop reconcile success")
+	return nil
+}
+
+func (r *Reconcile) Start() error {
+	log.Info("start reconcile......")
+	r.tasks.Go(func() error {
+		for {
+			select {
+			case <-r.ticker.C:
+				businessList, err := r.db.Business.QueryActiveBusinessList()
+				if err != nil {
+					log.Error("query business list fail", "err", err)
+					continue
+				}
+				for _, business := range businessList {
+					if err := r.reconcileBusiness(business.BusinessUid); err != nil {
+						return err
+					}
+				}
+			case <-r.resourceCtx.Done():
+				log.Info("stop reconcile in worker")
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+func (r *Reconcile) reconcileBusiness(businessUid string) error {
+	syncedHeader, err := r.db.Blocks.LatestBlocks()
+	if err != nil || syncedHeader == nil {
+		log.Warn("no synced block yet, skip reconcile", "businessUid", businessUid, "err", err)
+		return nil
+	}
+	balanceList, err := r.db.Balances.QueryBalanceList(businessUid, r.chainName)
+	if err != nil {
+		log.Error("query balance list fail", "businessUid", businessUid, "err", err)
+		return nil
+	}
+
+	openList, err := r.db.Reconciliations.QueryOpenMismatches(businessUid, r.chainName)
+	if err != nil {
+		log.Error("query open mismatches fail", "businessUid", businessUid, "err", err)
+		return nil
+	}
+	openMismatches := make(map[string]*database.Reconciliations, len(openList))
+	for _, open := range openList {
+		openMismatches[mismatchKey(open.Address, open.TokenAddress)] = open
+	}
+
+	var (
+		reconciliations []*database.Reconciliations
+		corrections     []*database.Reconciliations
+		correctionGuids []uuid.UUID
+		resolved        []uuid.UUID
+		// supersedes 新记录取代的未解决差异，新记录落库时一并标记为已解决
+		supersedes = make(map[uuid.UUID]uuid.UUID)
+	)
+	for _, balance := range balanceList {
+		// 有在途交易锁定余额时，链上余额与数据库余额本来就不一致，等交易确认后再对账
+		if balance.LockBalance != nil && balance.LockBalance.Sign() != 0 {
+			continue
+		}
+		chainBalance, err := r.rpcClient.GetAccountBalance(balance.Address, balance.TokenAddress)
+		r.alerter.RpcResult(r.chainName, "GetAccountBalance", err)
+		if err != nil {
+			log.Warn("get chain balance fail, skip reconcile", "address", balance.Address, "token", balance.TokenAddress, "err", err)
+			continue
+		}
+		difference := new(big.Int).Sub(chainBalance, balance.Balance)
+		open := openMismatches[mismatchKey(balance.Address, balance.TokenAddress)]
+		if difference.Sign() == 0 {
+			r.alerter.BalanceMismatch(r.chainName, businessUid, balance.Address, balance.TokenAddress, balance.Balance, chainBalance, false)
+			if open != nil {
+				resolved = append(resolved, open.GUID)
+			}
+			continue
+		}
+		// 链上余额只能按最新高度查询，地址在扫描高度附近还有未入账或未确认的交易时，差额并不是真实的差异
+		if r.hasUnsettledActivity(businessUid, balance, syncedHeader.Number) {
+			continue
+		}
+		status, notifyStatus := classifyDifference(difference, r.autoCorrect, r.tolerance, r.notifyThreshold)
+		// 已自动修正或低于通知阈值的差异不告警
+		r.alerter.BalanceMismatch(r.chainName, businessUid, balance.Address, balance.TokenAddress, balance.Balance, chainBalance,
+			status == database.ReconcileStatusMismatch && notifyStatus == database.ReconcileNotifyPending)
+		if open != nil {
+			// 同一差异已经记录并通知过，差异消失或变化之前不重复记录
+			if status == database.ReconcileStatusMismatch && open.Difference.Cmp(difference) == 0 {
+				continue
+			}
+		}
+		reconciliation := &database.Reconciliations{
+			GUID:          uuid.New(),
+			BusinessUid:   businessUid,
+			ChainName:     r.chainName,
+			Address:       balance.Address,
+			TokenAddress:  balance.TokenAddress,
+			AddressType:   balance.AddressType,
+			StoredBalance: balance.Balance,
+			ChainBalance:  chainBalance,
+			Difference:    difference,
+			Status:        status,
+			NotifyStatus:  notifyStatus,
+			Timestamp:     uint64(time.Now().Unix()),
+		}
+		log.Warn("balance mismatch", "businessUid", businessUid, "address", balance.Address, "token", balance.TokenAddress,
+			"stored", balance.Balance, "chain", chainBalance, "status", status)
+		reconciliations = append(reconciliations, reconciliation)
+		if open != nil {
+			supersedes[reconciliation.GUID] = open.GUID
+		}
+		if status == database.ReconcileStatusCorrected {
+			corrections = append(corrections, reconciliation)
+			correctionGuids = append(correctionGuids, balance.GUID)
+		}
+	}
+	if len(reconciliations) == 0 && len(resolved) == 0 {
+		return nil
+	}
+
+	var corrected int
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	if _, err := retry.Do[interface{}](r.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
+		if err := r.db.Transaction(func(tx *database.DB) error {
+			// 只修正读取后没有变动的余额行，有变动的不记录，下一轮重新对账
+			skipped := make(map[uuid.UUID]bool)
+			corrected = 0
+			for i, correction := range corrections {
+				ok, err := tx.Balances.CorrectBalance(businessUid, r.chainName, correctionGuids[i], correction.StoredBalance, correction.ChainBalance)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					log.Info("balance changed since read, skip correction", "address", correction.Address, "token", correction.TokenAddress)
+					skipped[correction.GUID] = true
+					continue
+				}
+				corrected++
+			}
+			stored := make([]*database.Reconciliations, 0, len(reconciliations))
+			resolvedList := append([]uuid.UUID(nil), resolved...)
+			for _, reconciliation := range reconciliations {
+				if skipped[reconciliation.GUID] {
+					continue
+				}
+				stored = append(stored, reconciliation)
+				if openGuid, ok := supersedes[reconciliation.GUID]; ok {
+					resolvedList = append(resolvedList, openGuid)
+				}
+			}
+			if err := tx.Reconciliations.ResolveReconciliations(resolvedList); err != nil {
+				return err
+			}
+			return tx.Reconciliations.StoreReconciliations(stored)
+		}); err != nil {
+			log.Error("unable to persist reconciliations", "err", err)
+			return nil, err
+		}
+		return nil, nil
+	}); err != nil {
+		return err
+	}
+	log.Info("reconcile business done", "businessUid", businessUid, "mismatch", len(reconciliations)-len(corrections), "corrected", corrected, "resolved", len(resolved))
+	return nil
+}
+
+// hasUnsettledActivity 地址有确认数不足的充值，或最近的链上交易不早于扫描高度减去确认数时返回 true，
+// 查询失败时同样跳过，等下一轮对账
+func (r *Reconcile) hasUnsettledActivity(businessUid string, balance *database.Balances, syncedHeight *big.Int) bool {
+	unconfirmed, err := r.db.Deposits.HasUnconfirmedDeposits(businessUid, r.chainName, balance.Address)
+	if err != nil {
+		log.Warn("query unconfirmed deposits fail, skip reconcile", "address", balance.Address, "err", err)
+		return true
+	}
+	if unconfirmed {
+		return true
+	}
+	tokenAddress := balance.TokenAddress
+	if database.IsNativeToken(r.chainName, tokenAddress) {
+		tokenAddress = ""
+	}
+	latestHeight, err := r.rpcClient.LatestTxHeight(balance.Address, tokenAddress)
+	r.alerter.RpcResult(r.chainName, "GetTxByAddress", err)
+	if err != nil {
+		log.Warn("get latest tx height fail, skip reconcile", "address", balance.Address, "token", balance.TokenAddress, "err", err)
+		return true
+	}
+	if latestHeight == nil {
+		return false
+	}
+	settled := new(big.Int).Sub(syncedHeight, new(big.Int).SetUint64(r.confirmations))
+	return latestHeight.Cmp(settled) > 0
+}
+
+// classifyDifference 差额在容忍范围内且开启自动修正时直接修正，差额不小于通知阈值时通知业务方
+func classifyDifference(difference *big.Int, autoCorrect bool, tolerance, notifyThreshold *big.Int) (database.ReconcileStatus, database.ReconcileNotifyStatus) {
+	abs := new(big.Int).Abs(difference)
+	status := database.ReconcileStatusMismatch
+	if autoCorrect && abs.Cmp(tolerance) <= 0 {
+		status = database.ReconcileStatusCorrected
+	}
+	notifyStatus := database.ReconcileNotifyNone
+	if abs.Cmp(notifyThreshold) >= 0 {
+		notifyStatus = database.ReconcileNotifyPending
+	}
+	return status, notifyStatus
+}
+
+func mismatchKey(address string, tokenAddress string) string {
+	return address + "/" + tokenAddress
+}
@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapplink-labs/multichain-sync-account/database"
+)
+
+func TestClassifyDifference(t *testing.T) {
+	tolerance := big.NewInt(10)
+	notifyThreshold := big.NewInt(100)
+	tests := []struct {
+		name         string
+		difference   int64
+		autoCorrect  bool
+		status       database.ReconcileStatus
+		notifyStatus database.ReconcileNotifyStatus
+	}{
+		{"within tolerance corrected", 5, true, database.ReconcileStatusCorrected, database.ReconcileNotifyNone},
+		{"negative within tolerance corrected", -10, true, database.ReconcileStatusCorrected, database.ReconcileNotifyNone},
+		{"within tolerance without auto correct", 5, false, database.ReconcileStatusMismatch, database.ReconcileNotifyNone},
+		{"above tolerance below threshold", 11, true, database.ReconcileStatusMismatch, database.ReconcileNotifyNone},
+		{"at notify threshold", 100, true, database.ReconcileStatusMismatch, database.ReconcileNotifyPending},
+		{"negative above notify threshold", -150, false, database.ReconcileStatusMismatch, database.ReconcileNotifyPending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, notifyStatus := classifyDifference(big.NewInt(tt.difference), tt.autoCorrect, tolerance, notifyThreshold)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.notifyStatus, notifyStatus)
+		})
+	}
+
+	// 容忍范围大于通知阈值时，自动修正的差异同样通知业务方
+	status, notifyStatus := classifyDifference(big.NewInt(50), true, big.NewInt(100), big.NewInt(20))
+	assert.Equal(t, database.ReconcileStatusCorrected, status)
+	assert.Equal(t, database.ReconcileNotifyPending, notifyStatus)
+}
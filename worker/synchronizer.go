@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/dapplink-labs/multichain-sync-account/alerting"
 	"github.com/dapplink-labs/multichain-sync-account/common/clock"
 	"github.com/dapplink-labs/multichain-sync-account/database"
 	"github.com/dapplink-labs/multichain-sync-account/rpcclient"
@@ -22,6 +24,14 @@ type Transaction struct {
 	TokenAddress   string
 	ContractWallet string
 	TxType         database.TransactionType
+	// FromRole/ToRole 双方在交易分类中的地址角色，未登记的地址为 external
+	FromRole database.AddressType
+	ToRole   database.AddressType
+	// Memo 共用充值地址上的 memo / destination tag，MemoSuspense 为 true 表示 memo 缺失或未登记
+	Memo         string
+	MemoSuspense bool
+	// UserId 充值地址从地址池分配时绑定的用户 id
+	UserId string
 }
 
 type Config struct {
@@ -43,6 +53,7 @@ type BaseSynchronizer struct {
 
 	headers []rpcclient.BlockHeader
 	worker  *clock.LoopFn
+	alerter *alerting.Alerter
 }
 
 type TransactionsChannel struct {
@@ -75,6 +86,7 @@ func (syncer *BaseSynchronizer) tick(_ context.Context) {
 		log.Info("retrying previous batch")
 	} else {
 		newHeaders, err := syncer.blockBatch.NextHeaders(syncer.headerBufferSize)
+		syncer.alerter.RpcResult(syncer.rpcClient.ChainName, "GetBlockHeader", err)
 		if err != nil {
 			log.Error("error querying for headers", "err", err)
 		} else if len(newHeaders) == 0 {
@@ -87,6 +99,24 @@ func (syncer *BaseSynchronizer) tick(_ context.Context) {
 	if err == nil {
 		syncer.headers = nil
 	}
+	syncer.checkSyncLag()
+}
+
+// checkSyncLag 已同步高度为未处理完的批次之前的区块，没有待处理批次时为最后遍历的区块
+func (syncer *BaseSynchronizer) checkSyncLag() {
+	latest := syncer.blockBatch.LatestHeader()
+	if latest == nil {
+		return
+	}
+	var synced *big.Int
+	if len(syncer.headers) > 0 {
+		synced = new(big.Int).Sub(syncer.headers[0].Number, big.NewInt(1))
+	} else if traversed := syncer.blockBatch.LastTraversedHeader(); traversed != nil {
+		synced = traversed.Number
+	} else {
+		return
+	}
+	syncer.alerter.SyncLag(syncer.rpcClient.ChainName, latest.Number.Uint64(), synced.Uint64())
 }
 
 func (syncer *BaseSynchronizer) processBatch(headers []rpcclient.BlockHeader) error {
@@ -100,12 +130,13 @@ func (syncer *BaseSynchronizer) processBatch(headers []rpcclient.BlockHeader) er
 		log.Info("Sync block data", "height", headers[i].Number)
 		blockHeaders[i] = database.Blocks{Hash: headers[i].Hash, ParentHash: headers[i].ParentHash, Number: headers[i].Number, Timestamp: headers[i].Timestamp}
 		txList, err := syncer.rpcClient.GetBlockInfo(headers[i].Number)
+		syncer.alerter.RpcResult(syncer.rpcClient.ChainName, "GetBlockInfo", err)
 		if err != nil {
 			log.Error("get block info fail", "err", err)
 			return err
 		}
 
-		businessList, err := syncer.database.Business.QueryBusinessList()
+		businessList, err := syncer.database.Business.QueryScanBusinessList()
 		if err != nil {
 			log.Error("query business list fail", "err", err)
 			return err
@@ -114,56 +145,45 @@ func (syncer *BaseSynchronizer) processBatch(headers []rpcclient.BlockHeader) er
 		for _, businessId := range businessList {
 			var businessTransactions []*Transaction
 			for _, tx := range txList {
-				toAddress := tx.To
-				fromAddress := tx.From
+				toAddress := database.NormalizeAddress(syncer.rpcClient.ChainName, tx.To)
+				fromAddress := database.NormalizeAddress(syncer.rpcClient.ChainName, tx.From)
 				existToAddress, toAddressType := syncer.database.Addresses.AddressExist(businessId.BusinessUid, syncer.rpcClient.ChainName, toAddress)
-				existFromAddress, FromAddressType := syncer.database.Addresses.AddressExist(businessId.BusinessUid, syncer.rpcClient.ChainName, fromAddress)
+				existFromAddress, fromAddressType := syncer.database.Addresses.AddressExist(businessId.BusinessUid, syncer.rpcClient.ChainName, fromAddress)
 				if !existToAddress && !existFromAddress {
 					continue
 				}
 
-				log.Info("Found transaction", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
+				// 按双方地址角色查分类表确定交易类型，未命中的流水照常入库和记账，不阻塞批次
+				rule := database.ClassifyFlow(database.AddressRole(existFromAddress, fromAddressType), database.AddressRole(existToAddress, toAddressType))
+				if !rule.Classified() {
+					log.Warn("Found unclassified transaction", "txHash", tx.Hash, "from", fromAddress, "fromRole", rule.FromRole, "to", toAddress, "toRole", rule.ToRole)
+				} else {
+					log.Info("Found transaction", "txHash", tx.Hash, "type", rule.TxType, "from", fromAddress, "to", toAddress)
+				}
 				txItem := &Transaction{
 					BusinessId:     businessId.BusinessUid,
 					BlockNumber:    headers[i].Number,
-					FromAddress:    tx.From,
-					ToAddress:      tx.To,
+					FromAddress:    fromAddress,
+					ToAddress:      toAddress,
 					Hash:           tx.Hash,
-					TokenAddress:   tx.TokenAddress,
+					TokenAddress:   database.NormalizeAddress(syncer.rpcClient.ChainName, tx.TokenAddress),
 					ContractWallet: tx.ContractWallet,
-					TxType:         database.TxTypeUnKnow,
-				}
-
-				/*
-				 * If the 'from' address is an external address and the 'to' address is an internal user address, it is a deposit; call the callback interface to notifier the business side.
-				 * If the 'from' address is a user address and the 'to' address is a hot wallet address, it is consolidation; call the callback interface to notifier the business side.
-				 * If the 'from' address is a hot wallet address and the 'to' address is an external user address, it is a withdrawal; call the callback interface to notifier the business side.
-				 * If the 'from' address is a hot wallet address and the 'to' address is a cold wallet address, it is a hot-to-cold transfer; call the callback interface to notifier the business side.
-				 * If the 'from' address is a cold wallet address and the 'to' address is a hot wallet address, it is a cold-to-hot transfer; call the callback interface to notifier the business side.
-				 */
-				if !existFromAddress && (existToAddress && toAddressType == database.AddressTypeEOA) { // 充值
-					log.Info("Found deposit transaction", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = database.TxTypeDeposit
-				}
-
-				if (existFromAddress && FromAddressType == database.AddressTypeHot) && !existToAddress { // 提现
-					log.Info("Found withdraw transaction", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = database.TxTypeWithdraw
-				}
-
-				if (existFromAddress && FromAddressType == database.AddressTypeEOA) && (existToAddress && toAddressType == database.AddressTypeHot) { // 归集
-					log.Info("Found collection transaction", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = database.TxTypeCollection
-				}
-
-				if (existFromAddress && FromAddressType == database.AddressTypeHot) && (existToAddress && toAddressType == database.AddressTypeCold) { // 热转冷
-					log.Info("Found hot2cold transaction", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = database.TxTypeHot2Cold
+					TxType:         rule.TxType,
+					FromRole:       rule.FromRole,
+					ToRole:         rule.ToRole,
 				}
-
-				if (existFromAddress && FromAddressType == database.AddressTypeCold) && (existToAddress && toAddressType == database.AddressTypeHot) { // 热转冷
-					log.Info("Found cold2hot transaction", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = database.TxTypeCold2Hot
+				if rule.TxType == database.TxTypeDeposit {
+					toAddressEntry, err := syncer.database.Addresses.QueryAddressesByToAddress(businessId.BusinessUid, syncer.rpcClient.ChainName, toAddress)
+					if err != nil {
+						log.Error("query deposit address fail", "address", toAddress, "err", err)
+						return err
+					}
+					txItem.UserId = toAddressEntry.UserId
+					if database.SupportsMemo(syncer.rpcClient.ChainName) {
+						if err := syncer.attributeMemo(businessId.BusinessUid, txItem); err != nil {
+							return err
+						}
+					}
 				}
 				businessTransactions = append(businessTransactions, txItem)
 			}
@@ -194,3 +214,38 @@ func (syncer *BaseSynchronizer) processBatch(headers []rpcclient.BlockHeader) er
 
 	return nil
 }
+
+// attributeMemo 共用充值地址的充值按交易 data 中的 memo 归属到用户，memo 缺失或未登记时进入 suspense
+func (syncer *BaseSynchronizer) attributeMemo(businessUid string, txItem *Transaction) error {
+	memoAddress, err := syncer.database.Memos.IsMemoAddress(businessUid, syncer.rpcClient.ChainName, txItem.ToAddress)
+	if err != nil {
+		log.Error("query memo address fail", "address", txItem.ToAddress, "err", err)
+		return err
+	}
+	if !memoAddress {
+		return nil
+	}
+	txMessage, err := syncer.rpcClient.GetTransactionByHash(txItem.Hash)
+	if err != nil {
+		log.Error("get transaction memo fail", "txHash", txItem.Hash, "err", err)
+		return err
+	}
+	if txMessage != nil {
+		txItem.Memo = strings.TrimSpace(txMessage.Data)
+	}
+	if txItem.Memo == "" {
+		log.Warn("deposit to memo address without memo", "txHash", txItem.Hash, "to", txItem.ToAddress)
+		txItem.MemoSuspense = true
+		return nil
+	}
+	exist, err := syncer.database.Memos.MemoExist(businessUid, syncer.rpcClient.ChainName, txItem.ToAddress, txItem.Memo)
+	if err != nil {
+		log.Error("query memo fail", "memo", txItem.Memo, "err", err)
+		return err
+	}
+	if !exist {
+		log.Warn("deposit with unknown memo", "txHash", txItem.Hash, "to", txItem.ToAddress, "memo", txItem.Memo)
+		txItem.MemoSuspense = true
+	}
+	return nil
+}
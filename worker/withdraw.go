@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/dapplink-labs/multichain-sync-account/alerting"
 	"github.com/dapplink-labs/multichain-sync-account/common/retry"
 	"github.com/dapplink-labs/multichain-sync-account/common/tasks"
 	"github.com/dapplink-labs/multichain-sync-account/config"
@@ -24,9 +25,10 @@ type Withdraw struct {
 	tasks          tasks.Group
 	ticker         *time.Ticker
 	chainName      string
+	alerter        *alerting.Alerter
 }
 
-func NewWithdraw(cfg *config.Config, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*Withdraw, error) {
+func NewWithdraw(cfg *config.Config, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, alerter *alerting.Alerter, shutdown context.CancelCauseFunc) (*Withdraw, error) {
 	resCtx, resCancel := context.WithCancel(context.Background())
 	return &Withdraw{
 		rpcClient:      rpcClient,
@@ -38,6 +40,7 @@ func NewWithdraw(cfg *config.Config, db *database.DB, rpcClient *rpcclient.Walle
 		}},
 		ticker:    time.NewTicker(cfg.ChainNode.WorkerInterval),
 		chainName: rpcClient.ChainName,
+		alerter:   alerter,
 	}, nil
 }
 
@@ -60,13 +63,17 @@ func (w *Withdraw) Start() error {
 		for {
 			select {
 			case <-w.ticker.C:
-				businessList, err := w.db.Business.QueryBusinessList()
+				businessList, err := w.db.Business.QueryActiveBusinessList()
 				if err != nil {
 					log.Error("query business list fail", "err", err)
 					continue
 				}
 
 				for _, businessId := range businessList {
+					if businessId.WithdrawPaused {
+						log.Warn("withdraws paused, skip broadcast", "businessId", businessId.BusinessUid)
+						continue
+					}
 					unSendTransactionList, err := w.db.Withdraws.UnSendWithdrawsList(businessId.BusinessUid, w.chainName)
 					if err != nil {
 						log.Error("Query un send withdraws list fail", "err", err)
@@ -77,22 +84,37 @@ func (w *Withdraw) Start() error {
 						continue
 					}
 
-					var balanceList []*database.Balances
+					var (
+						balanceList []*database.TokenBalance
+						spentUtxos  = make(map[string]string)
+						sentTxs     = make(map[string]string) // multi-send 批次内的条目共用一笔签名交易，只广播一次
+					)
 
 					for _, unSendTransaction := range unSendTransactionList {
-						txHash, err := w.rpcClient.SendTx(unSendTransaction.TxSignHex)
+						txHash, sent := sentTxs[unSendTransaction.TxSignHex]
+						var err error
+						if !sent {
+							txHash, err = w.rpcClient.SendTx(unSendTransaction.TxSignHex)
+							w.alerter.BroadcastResult(w.chainName, err)
+						}
 						if err != nil {
 							log.Error("send transaction fail", "err", err)
 							continue
 						} else {
-							balanceItem := &database.Balances{
+							sentTxs[unSendTransaction.TxSignHex] = txHash
+							balanceItem := &database.TokenBalance{
+								FromAddress:  unSendTransaction.FromAddress,
 								TokenAddress: unSendTransaction.TokenAddress,
-								Address:      unSendTransaction.FromAddress,
-								LockBalance:  unSendTransaction.Amount,
+								Balance:      unSendTransaction.Amount,
+								TxType:       database.TxTypeWithdraw,
+								TxHash:       txHash,
 							}
 							balanceList = append(balanceList, balanceItem)
 
 							unSendTransaction.TxHash = common.HexToHash(txHash)
+							if database.IsUTXOChain(w.chainName) {
+								spentUtxos[unSendTransaction.SpendGuid()] = txHash
+							}
 							unSendTransaction.Status = database.TxStatusBroadcasted
 						}
 					}
@@ -100,9 +122,14 @@ func (w *Withdraw) Start() error {
 					retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
 					if _, err := retry.Do[interface{}](w.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
 						if err := w.db.Transaction(func(tx *database.DB) error {
+							for spendGuid, txHash := range spentUtxos {
+								if err := tx.Utxos.MarkUtxosSpent(businessId.BusinessUid, w.chainName, spendGuid, txHash); err != nil {
+									return err
+								}
+							}
 							if len(balanceList) > 0 {
 								log.Info("Update address balance", "totalTx", len(balanceList))
-								if err := tx.Balances.UpdateBalanceListByTwoAddress(businessId.BusinessUid, w.chainName, balanceList); err != nil {
+								if err := tx.Balances.LockBalances(businessId.BusinessUid, w.chainName, balanceList); err != nil {
 									log.Error("Update address balance fail", "err", err)
 									return err
 								}
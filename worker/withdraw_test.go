@@ -30,7 +30,7 @@ func setupWithdraw(t *testing.T) *Withdraw {
 
 	// 设置账户客户端
 	client := account.NewWalletAccountServiceClient(conn)
-	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, "Ethereum")
+	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, "Ethereum", database.GetNetwork("Ethereum"))
 	assert.NoError(t, err)
 
 	// 设置配置
@@ -46,7 +46,7 @@ func setupWithdraw(t *testing.T) *Withdraw {
 	}
 
 	// 创建 Withdraw worker
-	withdraw, err := NewWithdraw(cfg, db, accountClient, shutdown)
+	withdraw, err := NewWithdraw(cfg, db, accountClient, nil, shutdown)
 	assert.NoError(t, err)
 
 	return withdraw